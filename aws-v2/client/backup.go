@@ -0,0 +1,310 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+const (
+	defaultRegion       = "us-east-1"
+	defaultAccountID    = "000000000000"
+	maxListBackupsLimit = 100
+)
+
+func (fd *Client) tableArn(tableName string) string {
+	return fmt.Sprintf("arn:aws:dynamodb:%s:%s:table/%s", fd.region, fd.accountID, tableName)
+}
+
+// backupArn builds an ARN following the format DynamoDB uses for on-demand backups
+func (fd *Client) backupArn(tableName string, createdAt time.Time) string {
+	fd.backupSeq++
+
+	return fmt.Sprintf("%s/backup/%014d-%08x", fd.tableArn(tableName), createdAt.UnixMilli(), fd.backupSeq)
+}
+
+// getBackup resolves a backup ARN, callers must hold fd.mu
+func (fd *Client) getBackup(arn string) (*core.Backup, error) {
+	backup, ok := fd.backups[arn]
+	if !ok {
+		return nil, &types.BackupNotFoundException{Message: aws.String("Backup not found: " + arn)}
+	}
+
+	return backup, nil
+}
+
+func backupDetails(arn string, status types.BackupStatus, backup *core.Backup) *types.BackupDetails {
+	return &types.BackupDetails{
+		BackupArn:              aws.String(arn),
+		BackupName:             aws.String(backup.Name),
+		BackupCreationDateTime: aws.Time(backup.CreatedAt),
+		BackupSizeBytes:        aws.Int64(backup.SizeBytes),
+		BackupStatus:           status,
+		BackupType:             types.BackupTypeUser,
+	}
+}
+
+func (fd *Client) backupDescription(arn string, status types.BackupStatus, backup *core.Backup) *types.BackupDescription {
+	source := mapTypesToDynamoTableDescription(backup.Source)
+
+	desc := &types.BackupDescription{
+		BackupDetails: backupDetails(arn, status, backup),
+		SourceTableDetails: &types.SourceTableDetails{
+			TableName:      aws.String(backup.TableName),
+			TableArn:       aws.String(fd.tableArn(backup.TableName)),
+			KeySchema:      source.KeySchema,
+			ItemCount:      aws.Int64(backup.ItemCount),
+			TableSizeBytes: aws.Int64(backup.SizeBytes),
+			BillingMode:    types.BillingMode(aws.ToString(backup.BillingMode)),
+		},
+		SourceTableFeatureDetails: &types.SourceTableFeatureDetails{},
+	}
+
+	for _, gsi := range source.GlobalSecondaryIndexes {
+		desc.SourceTableFeatureDetails.GlobalSecondaryIndexes = append(desc.SourceTableFeatureDetails.GlobalSecondaryIndexes, types.GlobalSecondaryIndexInfo{
+			IndexName:  gsi.IndexName,
+			KeySchema:  gsi.KeySchema,
+			Projection: gsi.Projection,
+		})
+	}
+
+	for _, lsi := range source.LocalSecondaryIndexes {
+		desc.SourceTableFeatureDetails.LocalSecondaryIndexes = append(desc.SourceTableFeatureDetails.LocalSecondaryIndexes, types.LocalSecondaryIndexInfo{
+			IndexName:  lsi.IndexName,
+			KeySchema:  lsi.KeySchema,
+			Projection: lsi.Projection,
+		})
+	}
+
+	return desc
+}
+
+// CreateBackup copies the schema, indexes and items of a table into a new on-demand backup
+func (fd *Client) CreateBackup(ctx context.Context, input *dynamodb.CreateBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.CreateBackupOutput, error) {
+	fd.mu.Lock()
+	defer fd.publishChanges()
+	defer fd.mu.Unlock()
+
+	tableName := aws.ToString(input.TableName)
+
+	if err := fd.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	if err := core.ValidateBackupName(aws.ToString(input.BackupName)); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	table, ok := fd.tables[tableName]
+	if !ok {
+		return nil, &types.TableNotFoundException{Message: aws.String("Table not found: " + tableName)}
+	}
+
+	fd.sweepOnAccess(table)
+
+	now := fd.clock.Now()
+	backup := table.Backup(aws.ToString(input.BackupName), now)
+	arn := fd.backupArn(tableName, now)
+	fd.backups[arn] = backup
+
+	return &dynamodb.CreateBackupOutput{BackupDetails: backupDetails(arn, types.BackupStatusAvailable, backup)}, nil
+}
+
+// DescribeBackup returns the details of a backup and of the table it was taken from
+func (fd *Client) DescribeBackup(ctx context.Context, input *dynamodb.DescribeBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeBackupOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	if fd.forceFailureErr != nil {
+		return nil, fd.forceFailureErr
+	}
+
+	arn := aws.ToString(input.BackupArn)
+
+	backup, err := fd.getBackup(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.DescribeBackupOutput{BackupDescription: fd.backupDescription(arn, types.BackupStatusAvailable, backup)}, nil
+}
+
+// DeleteBackup removes a backup, the response describes it with the DELETED status
+func (fd *Client) DeleteBackup(ctx context.Context, input *dynamodb.DeleteBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.DeleteBackupOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	if fd.forceFailureErr != nil {
+		return nil, fd.forceFailureErr
+	}
+
+	arn := aws.ToString(input.BackupArn)
+
+	backup, err := fd.getBackup(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(fd.backups, arn)
+
+	return &dynamodb.DeleteBackupOutput{BackupDescription: fd.backupDescription(arn, types.BackupStatusDeleted, backup)}, nil
+}
+
+// ListBackups returns the backups ordered by creation time, filtered by table, creation time
+// range and backup type. Every backup made by CreateBackup has the USER type
+func (fd *Client) ListBackups(ctx context.Context, input *dynamodb.ListBackupsInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListBackupsOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	tableName := aws.ToString(input.TableName)
+
+	if err := fd.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	limit := maxListBackupsLimit
+	if input.Limit != nil {
+		if *input.Limit < 1 || *input.Limit > maxListBackupsLimit {
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value between 1 and %d", *input.Limit, maxListBackupsLimit)}
+		}
+
+		limit = int(*input.Limit)
+	}
+
+	switch input.BackupType {
+	case "", types.BackupTypeFilterUser, types.BackupTypeFilterAll:
+	case types.BackupTypeFilterSystem, types.BackupTypeFilterAwsBackup:
+		return &dynamodb.ListBackupsOutput{BackupSummaries: []types.BackupSummary{}}, nil
+	default:
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%s' at 'backupType' failed to satisfy constraint: Member must satisfy enum value set: [USER, SYSTEM, AWS_BACKUP, ALL]", input.BackupType)}
+	}
+
+	summaries := []types.BackupSummary{}
+
+	for arn, backup := range fd.backups {
+		if tableName != "" && backup.TableName != tableName {
+			continue
+		}
+
+		if input.TimeRangeLowerBound != nil && backup.CreatedAt.Before(*input.TimeRangeLowerBound) {
+			continue
+		}
+
+		if input.TimeRangeUpperBound != nil && !backup.CreatedAt.Before(*input.TimeRangeUpperBound) {
+			continue
+		}
+
+		details := backupDetails(arn, types.BackupStatusAvailable, backup)
+		summaries = append(summaries, types.BackupSummary{
+			BackupArn:              details.BackupArn,
+			BackupName:             details.BackupName,
+			BackupCreationDateTime: details.BackupCreationDateTime,
+			BackupSizeBytes:        details.BackupSizeBytes,
+			BackupStatus:           details.BackupStatus,
+			BackupType:             details.BackupType,
+			TableArn:               aws.String(fd.tableArn(backup.TableName)),
+			TableName:              aws.String(backup.TableName),
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		ti, tj := aws.ToTime(summaries[i].BackupCreationDateTime), aws.ToTime(summaries[j].BackupCreationDateTime)
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+
+		return aws.ToString(summaries[i].BackupArn) < aws.ToString(summaries[j].BackupArn)
+	})
+
+	start := 0
+
+	if exclusiveStart := aws.ToString(input.ExclusiveStartBackupArn); exclusiveStart != "" {
+		for i, s := range summaries {
+			if aws.ToString(s.BackupArn) == exclusiveStart {
+				start = i + 1
+
+				break
+			}
+		}
+	}
+
+	end := min(start+limit, len(summaries))
+	output := &dynamodb.ListBackupsOutput{BackupSummaries: summaries[start:end]}
+
+	if end < len(summaries) {
+		output.LastEvaluatedBackupArn = summaries[end-1].BackupArn
+	}
+
+	return output, nil
+}
+
+// RestoreTableFromBackup creates a new table with the schema, indexes and items of a backup
+func (fd *Client) RestoreTableFromBackup(ctx context.Context, input *dynamodb.RestoreTableFromBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.RestoreTableFromBackupOutput, error) {
+	fd.mu.Lock()
+	defer fd.publishChanges()
+	defer fd.mu.Unlock()
+
+	targetName := aws.ToString(input.TargetTableName)
+
+	if err := fd.failureErrFor(targetName, ""); err != nil {
+		return nil, err
+	}
+
+	arn := aws.ToString(input.BackupArn)
+
+	backup, err := fd.getBackup(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := fd.restoreTable(targetName, backup, restoreOverrides(input.BillingModeOverride, input.GlobalSecondaryIndexOverride, input.LocalSecondaryIndexOverride), &core.RestoreSummary{
+		SourceBackupArn: arn,
+		SourceTableArn:  fd.tableArn(backup.TableName),
+		RestoreDateTime: backup.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.RestoreTableFromBackupOutput{TableDescription: fd.tableDescription(targetName, table)}, nil
+}
+
+// restoreOverrides maps the overrides of a restore request, an empty index override drops the
+// indexes of that kind
+func restoreOverrides(billingMode types.BillingMode, gsi []types.GlobalSecondaryIndex, lsi []types.LocalSecondaryIndex) core.RestoreInput {
+	input := core.RestoreInput{
+		GlobalSecondaryIndexes: mapDynamoToTypesGlobalSecondaryIndexes(gsi),
+		LocalSecondaryIndexes:  mapDynamoToTypesLocalSecondaryIndexes(lsi),
+	}
+
+	if billingMode != "" {
+		input.BillingMode = aws.String(string(billingMode))
+	}
+
+	return input
+}
+
+// restoreTable creates the target table of a restore from the backup, callers must hold fd.mu
+func (fd *Client) restoreTable(targetName string, backup *core.Backup, input core.RestoreInput, summary *core.RestoreSummary) (*core.Table, error) {
+	if _, ok := fd.tables[targetName]; ok {
+		return nil, &types.TableAlreadyExistsException{Message: aws.String("Table already exists: " + targetName)}
+	}
+
+	table := fd.newTable(targetName)
+
+	if err := backup.Restore(table, input); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	table.RestoreSummary = summary
+	fd.tables[targetName] = table
+
+	return table, nil
+}
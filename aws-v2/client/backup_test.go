@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func TestBackups(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	SetClock(client, clock)
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(ensurePokemonTypeIndex(client))
+	c.NoError(createPokemon(client, pokemon{ID: "25", Type: "electric", Name: "pikachu"}))
+	c.NoError(createPokemon(client, pokemon{ID: "4", Type: "fire", Name: "charmander"}))
+
+	created, err := client.CreateBackup(ctx, &dynamodb.CreateBackupInput{TableName: aws.String(tableName), BackupName: aws.String("daily")})
+	c.NoError(err)
+
+	backupArn := aws.ToString(created.BackupDetails.BackupArn)
+	c.Contains(backupArn, "arn:aws:dynamodb:us-east-1:000000000000:table/pokemons/backup/")
+	c.Equal(dynamodbtypes.BackupStatusAvailable, created.BackupDetails.BackupStatus)
+	c.Equal(dynamodbtypes.BackupTypeUser, created.BackupDetails.BackupType)
+	c.Equal(clock.Now(), aws.ToTime(created.BackupDetails.BackupCreationDateTime))
+	c.Positive(aws.ToInt64(created.BackupDetails.BackupSizeBytes))
+
+	// changes made after the backup are not restored
+	c.NoError(createPokemon(client, pokemon{ID: "7", Type: "water", Name: "squirtle"}))
+
+	described, err := client.DescribeBackup(ctx, &dynamodb.DescribeBackupInput{BackupArn: aws.String(backupArn)})
+	c.NoError(err)
+	c.Equal(tableName, aws.ToString(described.BackupDescription.SourceTableDetails.TableName))
+	c.EqualValues(2, aws.ToInt64(described.BackupDescription.SourceTableDetails.ItemCount))
+	c.Len(described.BackupDescription.SourceTableFeatureDetails.GlobalSecondaryIndexes, 1)
+
+	restored, err := client.RestoreTableFromBackup(ctx, &dynamodb.RestoreTableFromBackupInput{
+		BackupArn:       aws.String(backupArn),
+		TargetTableName: aws.String("pokemons-restored"),
+	})
+	c.NoError(err)
+	c.EqualValues(2, aws.ToInt64(restored.TableDescription.ItemCount))
+	c.Equal(backupArn, aws.ToString(restored.TableDescription.RestoreSummary.SourceBackupArn))
+	c.Equal(clock.Now(), aws.ToTime(restored.TableDescription.RestoreSummary.RestoreDateTime))
+
+	query, err := client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String("pokemons-restored"),
+		IndexName:                 aws.String("by-type"),
+		KeyConditionExpression:    aws.String("#type = :type"),
+		ExpressionAttributeNames:  map[string]string{"#type": "type"},
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{":type": &dynamodbtypes.AttributeValueMemberS{Value: "electric"}},
+	})
+	c.NoError(err)
+	c.Len(query.Items, 1)
+
+	withoutIndexes, err := client.RestoreTableFromBackup(ctx, &dynamodb.RestoreTableFromBackupInput{
+		BackupArn:                    aws.String(backupArn),
+		TargetTableName:              aws.String("pokemons-lean"),
+		GlobalSecondaryIndexOverride: []dynamodbtypes.GlobalSecondaryIndex{},
+	})
+	c.NoError(err)
+	c.Empty(withoutIndexes.TableDescription.GlobalSecondaryIndexes)
+
+	_, err = client.RestoreTableFromBackup(ctx, &dynamodb.RestoreTableFromBackupInput{
+		BackupArn:       aws.String(backupArn),
+		TargetTableName: aws.String(tableName),
+	})
+
+	var existsErr *dynamodbtypes.TableAlreadyExistsException
+	c.True(errors.As(err, &existsErr))
+
+	clock.Advance(time.Hour)
+
+	_, err = client.CreateBackup(ctx, &dynamodb.CreateBackupInput{TableName: aws.String("pokemons-restored"), BackupName: aws.String("restored")})
+	c.NoError(err)
+
+	listed, err := client.ListBackups(ctx, &dynamodb.ListBackupsInput{Limit: aws.Int32(1)})
+	c.NoError(err)
+	c.Len(listed.BackupSummaries, 1)
+	c.Equal(backupArn, aws.ToString(listed.LastEvaluatedBackupArn))
+
+	listed, err = client.ListBackups(ctx, &dynamodb.ListBackupsInput{ExclusiveStartBackupArn: listed.LastEvaluatedBackupArn})
+	c.NoError(err)
+	c.Len(listed.BackupSummaries, 1)
+	c.Equal("pokemons-restored", aws.ToString(listed.BackupSummaries[0].TableName))
+	c.Nil(listed.LastEvaluatedBackupArn)
+
+	listed, err = client.ListBackups(ctx, &dynamodb.ListBackupsInput{TimeRangeLowerBound: aws.Time(clock.Now())})
+	c.NoError(err)
+	c.Len(listed.BackupSummaries, 1)
+	c.Equal("restored", aws.ToString(listed.BackupSummaries[0].BackupName))
+
+	deleted, err := client.DeleteBackup(ctx, &dynamodb.DeleteBackupInput{BackupArn: aws.String(backupArn)})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.BackupStatusDeleted, deleted.BackupDescription.BackupDetails.BackupStatus)
+
+	_, err = client.DeleteBackup(ctx, &dynamodb.DeleteBackupInput{BackupArn: aws.String(backupArn)})
+
+	var notFoundErr *dynamodbtypes.BackupNotFoundException
+	c.True(errors.As(err, &notFoundErr))
+}
+
+func TestCreateBackupErrors(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	_, err := client.CreateBackup(ctx, &dynamodb.CreateBackupInput{TableName: aws.String(tableName), BackupName: aws.String("daily")})
+
+	var tableErr *dynamodbtypes.TableNotFoundException
+	c.True(errors.As(err, &tableErr))
+
+	c.NoError(ensurePokemonTable(client))
+
+	_, err = client.CreateBackup(ctx, &dynamodb.CreateBackupInput{TableName: aws.String(tableName), BackupName: aws.String("ab")})
+
+	var apiErr smithy.APIError
+	c.True(errors.As(err, &apiErr))
+	c.Equal("ValidationException", apiErr.ErrorCode())
+
+	_, err = client.ListBackups(ctx, &dynamodb.ListBackupsInput{Limit: aws.Int32(0)})
+	c.True(errors.As(err, &apiErr))
+	c.Equal("ValidationException", apiErr.ErrorCode())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"math"
 	"regexp"
 	"sort"
@@ -23,6 +24,7 @@ import (
 const (
 	batchRequestsLimit                              = 25
 	batchGetItemRequestsLimit                       = 100
+	maxListTablesLimit                              = 100
 	unusedExpressionAttributeNamesMsg               = "Value provided in ExpressionAttributeNames unused in expressions"
 	unusedExpressionAttributeValuesMsg              = "Value provided in ExpressionAttributeValues unused in expressions"
 	expressionAttributeValuesOnlyWithExpressionsMsg = "ExpressionAttributeValues can only be specified when using expressions"
@@ -45,6 +47,7 @@ type FakeClient interface {
 	DeleteTable(ctx context.Context, input *dynamodb.DeleteTableInput, opt ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error)
 	UpdateTable(ctx context.Context, input *dynamodb.UpdateTableInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error)
 	DescribeTable(ctx context.Context, input *dynamodb.DescribeTableInput, ops ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	ListTables(ctx context.Context, input *dynamodb.ListTablesInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error)
 	PutItem(ctx context.Context, input *dynamodb.PutItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
 	DeleteItem(ctx context.Context, input *dynamodb.DeleteItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
 	UpdateItem(ctx context.Context, input *dynamodb.UpdateItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
@@ -55,6 +58,31 @@ type FakeClient interface {
 	BatchGetItem(ctx context.Context, input *dynamodb.BatchGetItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
 	TransactWriteItems(ctx context.Context, input *dynamodb.TransactWriteItemsInput, opts ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
 	TransactGetItems(ctx context.Context, input *dynamodb.TransactGetItemsInput, opts ...func(*dynamodb.Options)) (*dynamodb.TransactGetItemsOutput, error)
+	UpdateTimeToLive(ctx context.Context, input *dynamodb.UpdateTimeToLiveInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)
+	DescribeTimeToLive(ctx context.Context, input *dynamodb.DescribeTimeToLiveInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)
+	ExecuteStatement(ctx context.Context, input *dynamodb.ExecuteStatementInput, opts ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error)
+	BatchExecuteStatement(ctx context.Context, input *dynamodb.BatchExecuteStatementInput, opts ...func(*dynamodb.Options)) (*dynamodb.BatchExecuteStatementOutput, error)
+	ExecuteTransaction(ctx context.Context, input *dynamodb.ExecuteTransactionInput, opts ...func(*dynamodb.Options)) (*dynamodb.ExecuteTransactionOutput, error)
+	CreateBackup(ctx context.Context, input *dynamodb.CreateBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.CreateBackupOutput, error)
+	DescribeBackup(ctx context.Context, input *dynamodb.DescribeBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeBackupOutput, error)
+	ListBackups(ctx context.Context, input *dynamodb.ListBackupsInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListBackupsOutput, error)
+	DeleteBackup(ctx context.Context, input *dynamodb.DeleteBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.DeleteBackupOutput, error)
+	RestoreTableFromBackup(ctx context.Context, input *dynamodb.RestoreTableFromBackupInput, opts ...func(*dynamodb.Options)) (*dynamodb.RestoreTableFromBackupOutput, error)
+	UpdateContinuousBackups(ctx context.Context, input *dynamodb.UpdateContinuousBackupsInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateContinuousBackupsOutput, error)
+	DescribeContinuousBackups(ctx context.Context, input *dynamodb.DescribeContinuousBackupsInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeContinuousBackupsOutput, error)
+	RestoreTableToPointInTime(ctx context.Context, input *dynamodb.RestoreTableToPointInTimeInput, opts ...func(*dynamodb.Options)) (*dynamodb.RestoreTableToPointInTimeOutput, error)
+	ExportTableToPointInTime(ctx context.Context, input *dynamodb.ExportTableToPointInTimeInput, opts ...func(*dynamodb.Options)) (*dynamodb.ExportTableToPointInTimeOutput, error)
+	DescribeExport(ctx context.Context, input *dynamodb.DescribeExportInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeExportOutput, error)
+	ListExports(ctx context.Context, input *dynamodb.ListExportsInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListExportsOutput, error)
+	ImportTable(ctx context.Context, input *dynamodb.ImportTableInput, opts ...func(*dynamodb.Options)) (*dynamodb.ImportTableOutput, error)
+	DescribeImport(ctx context.Context, input *dynamodb.DescribeImportInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeImportOutput, error)
+	ListImports(ctx context.Context, input *dynamodb.ListImportsInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListImportsOutput, error)
+	TagResource(ctx context.Context, input *dynamodb.TagResourceInput, opts ...func(*dynamodb.Options)) (*dynamodb.TagResourceOutput, error)
+	UntagResource(ctx context.Context, input *dynamodb.UntagResourceInput, opts ...func(*dynamodb.Options)) (*dynamodb.UntagResourceOutput, error)
+	ListTagsOfResource(ctx context.Context, input *dynamodb.ListTagsOfResourceInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListTagsOfResourceOutput, error)
+	UpdateContributorInsights(ctx context.Context, input *dynamodb.UpdateContributorInsightsInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateContributorInsightsOutput, error)
+	DescribeContributorInsights(ctx context.Context, input *dynamodb.DescribeContributorInsightsInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeContributorInsightsOutput, error)
+	ListContributorInsights(ctx context.Context, input *dynamodb.ListContributorInsightsInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListContributorInsightsOutput, error)
 }
 
 // Client define a mock struct to be used
@@ -69,6 +97,25 @@ type Client struct {
 	tableFailureErrs      map[string]error
 	unprocessedMatchers   map[string]func(int, map[string]types.AttributeValue) bool
 	indexActivationDelay  time.Duration
+	tableStatusDelay      time.Duration
+	deletingTables        map[string]*core.Table
+	region                string
+	accountID             string
+	subscriptions         map[string][]*StreamSubscription
+	pendingChanges        []StreamRecord
+	streamSequence        uint64
+	clock                 core.Clock
+	sweepExpiredOnAccess  bool
+	transactionTokens     *core.RequestTokens[*dynamodb.ExecuteTransactionOutput]
+	transactWriteTokens   *core.RequestTokens[*dynamodb.TransactWriteItemsOutput]
+	backups               map[string]*core.Backup
+	backupSeq             int
+	exportSink            core.ExportSink
+	exports               map[string]*core.Export
+	exportSeq             int
+	importSource          fs.FS
+	imports               map[string]*tableImport
+	importSeq             int
 }
 
 // NewClient initializes dynamodb client with a mock
@@ -80,6 +127,16 @@ func NewClient() *Client {
 		langInterpreter:     &interpreter.Language{},
 		tableFailureErrs:    map[string]error{},
 		unprocessedMatchers: map[string]func(int, map[string]types.AttributeValue) bool{},
+		deletingTables:      map[string]*core.Table{},
+		subscriptions:       map[string][]*StreamSubscription{},
+		region:              defaultRegion,
+		accountID:           defaultAccountID,
+		clock:               core.SystemClock,
+		transactionTokens:   core.NewRequestTokens[*dynamodb.ExecuteTransactionOutput](),
+		transactWriteTokens: core.NewRequestTokens[*dynamodb.TransactWriteItemsOutput](),
+		backups:             map[string]*core.Backup{},
+		exports:             map[string]*core.Export{},
+		imports:             map[string]*tableImport{},
 	}
 
 	return &fake
@@ -241,6 +298,36 @@ func (fd *Client) setIndexActivationDelay(delay time.Duration) {
 	}
 }
 
+func (fd *Client) setTableStatusDelay(delay time.Duration) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.tableStatusDelay = delay
+
+	for _, table := range fd.tables {
+		table.StatusDelay = delay
+	}
+}
+
+func (fd *Client) setClientRequestTokenTTL(ttl time.Duration) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.transactionTokens.SetTTL(ttl)
+	fd.transactWriteTokens.SetTTL(ttl)
+}
+
+func (fd *Client) setAccount(region, accountID string) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.region, fd.accountID = region, accountID
+
+	for name, table := range fd.tables {
+		table.Arn = fd.tableArn(name)
+	}
+}
+
 // SetInterpreter assigns a native interpreter
 func (fd *Client) SetInterpreter(i interpreter.Interpreter) {
 	native, ok := i.(*interpreter.Native)
@@ -260,6 +347,74 @@ func (fd *Client) GetNativeInterpreter() *interpreter.Native {
 	return fd.nativeInterpreter
 }
 
+// newTable creates an empty table using the interpreters configured in the client
+func (fd *Client) newTable(tableName string) *core.Table {
+	table := core.NewTable(tableName)
+	table.Arn = fd.tableArn(tableName)
+	table.NativeInterpreter = *fd.nativeInterpreter
+	table.UseNativeInterpreter = fd.useNativeInterpreter
+	table.LangInterpreter = *fd.langInterpreter
+	table.IndexActivationDelay = fd.indexActivationDelay
+	table.StatusDelay = fd.tableStatusDelay
+	table.Clock = fd.clock
+	table.ChangeListener = fd.streamChangeListener(tableName)
+	table.BeginStatus(core.TableStatusCreating)
+
+	return table
+}
+
+// deletingTable returns a table whose deletion is still in progress, callers must hold fd.mu
+func (fd *Client) deletingTable(tableName string) (*core.Table, bool) {
+	table, ok := fd.deletingTables[tableName]
+	if ok && table.Deleted() {
+		delete(fd.deletingTables, tableName)
+
+		return nil, false
+	}
+
+	return table, ok
+}
+
+// changingTableError is returned by the operations that change tables while the table is
+// still being created or updated
+func changingTableError(tableName, status string) error {
+	action := "updated"
+	if status == core.TableStatusCreating {
+		action = "created"
+	}
+
+	return &types.ResourceInUseException{Message: aws.String(fmt.Sprintf("Attempt to change a resource which is still in use: Table is being %s: %s", action, tableName))}
+}
+
+// tableDescription maps the table description adding the restore summary
+func (fd *Client) tableDescription(tableName string, table *core.Table) *types.TableDescription {
+	desc := mapTypesToDynamoTableDescription(table.Description(tableName))
+
+	if stream := table.LatestStream(); stream != nil && stream.Enabled {
+		desc.StreamSpecification = &types.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: types.StreamViewType(stream.ViewType),
+		}
+	}
+
+	if summary := table.RestoreSummary; summary != nil {
+		desc.RestoreSummary = &types.RestoreSummary{
+			RestoreDateTime:   aws.Time(summary.RestoreDateTime),
+			RestoreInProgress: aws.Bool(false),
+		}
+
+		if summary.SourceBackupArn != "" {
+			desc.RestoreSummary.SourceBackupArn = aws.String(summary.SourceBackupArn)
+		}
+
+		if summary.SourceTableArn != "" {
+			desc.RestoreSummary.SourceTableArn = aws.String(summary.SourceTableArn)
+		}
+	}
+
+	return desc
+}
+
 // CreateTable creates a new table
 func (fd *Client) CreateTable(ctx context.Context, input *dynamodb.CreateTableInput, opt ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
 	tableName := aws.ToString(input.TableName)
@@ -267,13 +422,13 @@ func (fd *Client) CreateTable(ctx context.Context, input *dynamodb.CreateTableIn
 		return nil, &types.ResourceInUseException{Message: aws.String("Cannot create preexisting table")}
 	}
 
-	newTable := core.NewTable(tableName)
+	if _, ok := fd.deletingTable(tableName); ok {
+		return nil, &types.ResourceInUseException{Message: aws.String("Cannot create preexisting table")}
+	}
+
+	newTable := fd.newTable(tableName)
 	newTable.SetAttributeDefinition(mapDynamoToTypesAttributeDefinitionSlice(input.AttributeDefinitions))
 	newTable.BillingMode = aws.String(string(input.BillingMode))
-	newTable.NativeInterpreter = *fd.nativeInterpreter
-	newTable.UseNativeInterpreter = fd.useNativeInterpreter
-	newTable.LangInterpreter = *fd.langInterpreter
-	newTable.IndexActivationDelay = fd.indexActivationDelay
 
 	if err := newTable.CreatePrimaryIndex(mapDynamoToTypesCreateTableInput(input)); err != nil {
 		return nil, mapKnownError(err)
@@ -287,26 +442,65 @@ func (fd *Client) CreateTable(ctx context.Context, input *dynamodb.CreateTableIn
 		return nil, mapKnownError(err)
 	}
 
+	// a new table has no stream to disable
+	if spec := input.StreamSpecification; spec != nil && aws.ToBool(spec.StreamEnabled) {
+		if err := fd.applyStreamSpecification(newTable, spec); err != nil {
+			return nil, mapKnownError(err)
+		}
+	}
+
+	if err := newTable.SetSettings(core.TableSettings{
+		ProvisionedThroughput:     mapDynamoToTypesProvisionedThroughput(input.ProvisionedThroughput),
+		OnDemandThroughput:        mapDynamoToCoreOnDemandThroughput(input.OnDemandThroughput),
+		WarmThroughput:            mapDynamoToCoreWarmThroughput(input.WarmThroughput),
+		DeletionProtectionEnabled: input.DeletionProtectionEnabled,
+		TableClass:                toString(string(input.TableClass)),
+		SSE:                       fd.mapDynamoToCoreSSE(input.SSESpecification),
+	}); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if len(input.Tags) > 0 {
+		if err := newTable.TagResource(mapDynamoToCoreTags(input.Tags)); err != nil {
+			return nil, mapKnownError(err)
+		}
+	}
+
 	fd.tables[tableName] = newTable
 
 	return &dynamodb.CreateTableOutput{
-		TableDescription: mapTypesToDynamoTableDescription(newTable.Description(tableName)),
+		TableDescription: fd.tableDescription(tableName, newTable),
 	}, nil
 }
 
-// DeleteTable deletes a table
+// DeleteTable deletes a table, the table is described as DELETING until the table status
+// delay passes
 func (fd *Client) DeleteTable(ctx context.Context, input *dynamodb.DeleteTableInput, opt ...func(*dynamodb.Options)) (*dynamodb.DeleteTableOutput, error) {
 	tableName := aws.ToString(input.TableName)
 
-	table, err := fd.getTable(tableName)
-	if err != nil {
+	table, ok := fd.tables[tableName]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("Cannot do operations on a non-existent table")}
+	}
+
+	if status := table.Status(); status != core.TableStatusActive {
+		return nil, changingTableError(tableName, status)
+	}
+
+	if err := table.ValidateDeletion(); err != nil {
 		return nil, mapKnownError(err)
 	}
 
-	desc := mapTypesToDynamoTableDescription(table.Description(tableName))
+	table.BeginStatus(core.TableStatusDeleting)
+
+	desc := fd.tableDescription(tableName, table)
 
 	delete(fd.tables, tableName)
 
+	if !table.Deleted() {
+		fd.deletingTables[tableName] = table
+	}
+
 	return &dynamodb.DeleteTableOutput{
 		TableDescription: desc,
 	}, nil
@@ -321,34 +515,127 @@ func (fd *Client) UpdateTable(ctx context.Context, input *dynamodb.UpdateTableIn
 		return nil, &types.ResourceNotFoundException{Message: aws.String("Cannot do operations on a non-existent table")}
 	}
 
+	if status := table.Status(); status != core.TableStatusActive {
+		return nil, changingTableError(tableName, status)
+	}
+
+	// global tables need several regions, only the HTTP server hosts them
+	if len(input.ReplicaUpdates) > 0 || input.MultiRegionConsistency != "" {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "ReplicaUpdates and MultiRegionConsistency are not supported by the fake client, use the HTTP server to simulate global tables"}
+	}
+
+	update := core.TableSettings{
+		BillingMode:               toString(string(input.BillingMode)),
+		ProvisionedThroughput:     mapDynamoToTypesProvisionedThroughput(input.ProvisionedThroughput),
+		OnDemandThroughput:        mapDynamoToCoreOnDemandThroughput(input.OnDemandThroughput),
+		WarmThroughput:            mapDynamoToCoreWarmThroughput(input.WarmThroughput),
+		DeletionProtectionEnabled: input.DeletionProtectionEnabled,
+		TableClass:                toString(string(input.TableClass)),
+		SSE:                       fd.mapDynamoToCoreSSE(input.SSESpecification),
+	}
+
+	if update == (core.TableSettings{}) && len(input.GlobalSecondaryIndexUpdates) == 0 && input.StreamSpecification == nil {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "At least one of ProvisionedThroughput, BillingMode, UpdateStreamEnabled, GlobalSecondaryIndexUpdates or SSESpecification or ReplicaUpdates is required"}
+	}
+
+	// a failure in any part of the request restores the settings, attribute definitions and indexes
+	settings := table.SnapshotSettings()
+
+	if err := table.UpdateSettings(update); err != nil {
+		return nil, mapKnownError(err)
+	}
+
 	if input.AttributeDefinitions != nil {
 		table.SetAttributeDefinition(mapDynamoToTypesAttributeDefinitionSlice(input.AttributeDefinitions))
 	}
 
 	for _, change := range input.GlobalSecondaryIndexUpdates {
 		if err := table.ApplyIndexChange(mapDynamoTotypesGlobalSecondaryIndexUpdate(change)); err != nil {
+			table.RestoreSettings(settings)
+
 			return &dynamodb.UpdateTableOutput{
-				TableDescription: mapTypesToDynamoTableDescription(table.Description(tableName)),
+				TableDescription: fd.tableDescription(tableName, table),
 			}, mapKnownError(err)
 		}
 	}
 
+	if err := fd.applyStreamSpecification(table, input.StreamSpecification); err != nil {
+		table.RestoreSettings(settings)
+
+		return nil, mapKnownError(err)
+	}
+
+	if table.SettingsChanged(settings) || len(input.GlobalSecondaryIndexUpdates) > 0 || input.StreamSpecification != nil {
+		table.BeginStatus(core.TableStatusUpdating)
+	}
+
 	return &dynamodb.UpdateTableOutput{
-		TableDescription: mapTypesToDynamoTableDescription(table.Description(tableName)),
+		TableDescription: fd.tableDescription(tableName, table),
 	}, nil
 }
 
-// DescribeTable returns information about the table
+// DescribeTable returns information about the table, including the tables still being
+// created or deleted
 func (fd *Client) DescribeTable(ctx context.Context, input *dynamodb.DescribeTableInput, ops ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	defer fd.publishChanges()
+
 	tableName := aws.ToString(input.TableName)
 
-	table, err := fd.getTable(tableName)
-	if err != nil {
-		return nil, mapKnownError(err)
+	table, ok := fd.tables[tableName]
+	if !ok {
+		table, ok = fd.deletingTable(tableName)
+	}
+
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("Cannot do operations on a non-existent table")}
 	}
 
+	fd.sweepOnAccess(table)
+
 	output := &dynamodb.DescribeTableOutput{
-		Table: mapTypesToDynamoTableDescription(table.Description(tableName)),
+		Table: fd.tableDescription(tableName, table),
+	}
+
+	return output, nil
+}
+
+// ListTables returns the table names in ascending order, at most 100 per page
+func (fd *Client) ListTables(ctx context.Context, input *dynamodb.ListTablesInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	if fd.forceFailureErr != nil {
+		return nil, fd.forceFailureErr
+	}
+
+	limit := maxListTablesLimit
+	if input.Limit != nil {
+		switch {
+		case *input.Limit < 1:
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value greater than or equal to 1", *input.Limit)}
+		case *input.Limit > maxListTablesLimit:
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value less than or equal to %d", *input.Limit, maxListTablesLimit)}
+		}
+
+		limit = int(*input.Limit)
+	}
+
+	exclusiveStart := aws.ToString(input.ExclusiveStartTableName)
+
+	names := make([]string, 0, len(fd.tables))
+	for name := range fd.tables {
+		if name > exclusiveStart {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	output := &dynamodb.ListTablesOutput{TableNames: names}
+
+	if len(names) > limit {
+		output.TableNames = names[:limit]
+		output.LastEvaluatedTableName = aws.String(names[limit-1])
 	}
 
 	return output, nil
@@ -357,6 +644,7 @@ func (fd *Client) DescribeTable(ctx context.Context, input *dynamodb.DescribeTab
 // PutItem mock response for dynamodb
 func (fd *Client) PutItem(ctx context.Context, input *dynamodb.PutItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
 	fd.mu.Lock()
+	defer fd.publishChanges()
 	defer fd.mu.Unlock()
 
 	if ferr := fd.failureErrFor(aws.ToString(input.TableName), ""); ferr != nil {
@@ -383,6 +671,7 @@ func (fd *Client) PutItem(ctx context.Context, input *dynamodb.PutItemInput, opt
 // DeleteItem mock response for dynamodb
 func (fd *Client) DeleteItem(ctx context.Context, input *dynamodb.DeleteItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
 	fd.mu.Lock()
+	defer fd.publishChanges()
 	defer fd.mu.Unlock()
 
 	if ferr := fd.failureErrFor(aws.ToString(input.TableName), ""); ferr != nil {
@@ -434,6 +723,7 @@ func (fd *Client) DeleteItem(ctx context.Context, input *dynamodb.DeleteItemInpu
 // UpdateItem mock response for dynamodb
 func (fd *Client) UpdateItem(ctx context.Context, input *dynamodb.UpdateItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
 	fd.mu.Lock()
+	defer fd.publishChanges()
 	defer fd.mu.Unlock()
 
 	if ferr := fd.failureErrFor(aws.ToString(input.TableName), ""); ferr != nil {
@@ -471,6 +761,7 @@ func (fd *Client) UpdateItem(ctx context.Context, input *dynamodb.UpdateItemInpu
 // GetItem mock response for dynamodb
 func (fd *Client) GetItem(ctx context.Context, input *dynamodb.GetItemInput, opt ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
 	fd.mu.Lock()
+	defer fd.publishChanges()
 	defer fd.mu.Unlock()
 
 	if ferr := fd.failureErrFor(aws.ToString(input.TableName), ""); ferr != nil {
@@ -482,6 +773,11 @@ func (fd *Client) GetItem(ctx context.Context, input *dynamodb.GetItemInput, opt
 		return nil, mapKnownError(err)
 	}
 
+	projection, aliases, err := core.ProjectionForAttributesToGet(input.AttributesToGet, aws.ToString(input.ProjectionExpression), input.ExpressionAttributeNames)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
 	table, err := fd.getTable(aws.ToString(input.TableName))
 	if err != nil {
 		return nil, mapKnownError(err)
@@ -501,9 +797,11 @@ func (fd *Client) GetItem(ctx context.Context, input *dynamodb.GetItemInput, opt
 		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: err.Error()}
 	}
 
+	table.RecordAccess(keyMap)
+
 	stored := table.Data[key]
 
-	item, err := getItemAttributesForOutput(table, stored, aws.ToString(input.ProjectionExpression), input.ExpressionAttributeNames)
+	item, err := getItemAttributesForOutput(table, stored, projection, aliases)
 	if err != nil {
 		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: err.Error()}
 	}
@@ -518,6 +816,7 @@ func (fd *Client) GetItem(ctx context.Context, input *dynamodb.GetItemInput, opt
 // Query mock response for dynamodb
 func (fd *Client) Query(ctx context.Context, input *dynamodb.QueryInput, opt ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
 	fd.mu.Lock()
+	defer fd.publishChanges()
 	defer fd.mu.Unlock()
 
 	if ferr := fd.failureErrFor(aws.ToString(input.TableName), aws.ToString(input.IndexName)); ferr != nil {
@@ -562,6 +861,7 @@ func (fd *Client) Query(ctx context.Context, input *dynamodb.QueryInput, opt ...
 // Scan mock scan operation
 func (fd *Client) Scan(ctx context.Context, input *dynamodb.ScanInput, opt ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
 	fd.mu.Lock()
+	defer fd.publishChanges()
 	defer fd.mu.Unlock()
 
 	if ferr := fd.failureErrFor(aws.ToString(input.TableName), aws.ToString(input.IndexName)); ferr != nil {
@@ -590,6 +890,11 @@ func (fd *Client) Scan(ctx context.Context, input *dynamodb.ScanInput, opt ...fu
 		ProjectionExpression:      aws.ToString(input.ProjectionExpression),
 		ScanIndexForward:          true,
 		Scan:                      true,
+		ScanFilter:                mapDynamoToTypesConditionMap(input.ScanFilter),
+		ConditionalOperator:       toString(string(input.ConditionalOperator)),
+		AttributesToGet:           input.AttributesToGet,
+		Segment:                   toInt64(input.Segment),
+		TotalSegments:             toInt64(input.TotalSegments),
 	})
 	if err != nil {
 		return nil, mapKnownError(err)
@@ -643,8 +948,9 @@ func (fd *Client) BatchWriteItem(ctx context.Context, input *dynamodb.BatchWrite
 
 	for table, reqs := range input.RequestItems {
 		for i, req := range reqs {
-			if emulation.unprocessed(table, i, batchWriteRequestKey(req)) {
+			if raw := batchWriteRequestKey(req); emulation.unprocessed(table, i, raw) {
 				unprocessed[table] = append(unprocessed[table], req)
+				fd.recordThrottle(table, raw)
 
 				continue
 			}
@@ -702,6 +1008,7 @@ func (fd *Client) BatchGetItem(ctx context.Context, input *dynamodb.BatchGetItem
 		for i, req := range reqs.Keys {
 			if emulation.unprocessed(tableName, i, req) {
 				unprocessedKeys = append(unprocessedKeys, req)
+				fd.recordThrottle(tableName, req)
 
 				continue
 			}
@@ -819,48 +1126,98 @@ func (fd *Client) prepareTransact(items []types.TransactWriteItem) (map[string]c
 			continue
 		}
 
-		if ferr := fd.failureErrFor(tableName, ""); ferr != nil {
-			return nil, ferr
+		table, err := fd.snapshotTransactTable(snapshots, tableName)
+		if err != nil {
+			return nil, err
 		}
 
-		if _, alreadySnapped := snapshots[tableName]; !alreadySnapped {
-			table, err := fd.getTable(tableName)
-			if err != nil {
-				return nil, mapKnownError(err)
-			}
+		internalKeyMap := mapDynamoToTypesMapItem(rawKeyMap)
 
-			snapshots[tableName] = table.Snapshot()
+		if key, err := table.KeySchema.GetKey(table.AttributesDef, internalKeyMap); err == nil {
+			if err := markTransactKey(seenKeys, tableName, key); err != nil {
+				return nil, err
+			}
 		}
+	}
 
-		table := fd.tables[tableName]
-		internalKeyMap := mapDynamoToTypesMapItem(rawKeyMap)
+	return snapshots, nil
+}
 
-		if key, err := table.KeySchema.GetKey(table.AttributesDef, internalKeyMap); err == nil {
-			id := tableName + "|" + key
+// snapshotTransactTable snapshots the table the first time a transaction touches it, so the
+// transaction can be rolled back
+func (fd *Client) snapshotTransactTable(snapshots map[string]core.TableSnapshot, tableName string) (*core.Table, error) {
+	if ferr := fd.failureErrFor(tableName, ""); ferr != nil {
+		return nil, ferr
+	}
 
-			if _, exists := seenKeys[id]; exists {
-				return nil, &smithy.GenericAPIError{
-					Code:    "ValidationException",
-					Message: "Transaction request cannot include multiple operations on one item",
-				}
-			}
+	table, err := fd.lookupTable(tableName)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if _, alreadySnapped := snapshots[tableName]; !alreadySnapped {
+		snapshots[tableName] = table.Snapshot()
+	}
+
+	return table, nil
+}
 
-			seenKeys[id] = struct{}{}
+// restoreSnapshots rolls back a cancelled transaction, including the stream records it left
+// pending since published
+func (fd *Client) restoreSnapshots(snapshots map[string]core.TableSnapshot, published int) {
+	for name, snap := range snapshots {
+		fd.tables[name].Restore(snap)
+	}
+
+	fd.pendingChanges = fd.pendingChanges[:published]
+}
+
+func markTransactKey(seenKeys map[string]struct{}, tableName, key string) error {
+	id := tableName + "|" + key
+
+	if _, exists := seenKeys[id]; exists {
+		return &smithy.GenericAPIError{
+			Code:    "ValidationException",
+			Message: "Transaction request cannot include multiple operations on one item",
 		}
 	}
 
-	return snapshots, nil
+	seenKeys[id] = struct{}{}
+
+	return nil
 }
 
 // TransactWriteItems mock response for dynamodb
 func (fd *Client) TransactWriteItems(ctx context.Context, input *dynamodb.TransactWriteItemsInput, opts ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
 	fd.mu.Lock()
+	defer fd.publishChanges()
 	defer fd.mu.Unlock()
 
 	if fd.forceFailureErr != nil {
 		return nil, fd.forceFailureErr
 	}
 
+	token := aws.ToString(input.ClientRequestToken)
+
+	var fingerprint string
+
+	if input.ClientRequestToken != nil {
+		fingerprint = core.RequestFingerprint(transactItemsFingerprint(input.TransactItems))
+
+		if err := core.ValidateClientRequestToken(token); err != nil {
+			return nil, mapKnownError(err)
+		}
+
+		out, ok, err := fd.transactWriteTokens.Lookup(token, fingerprint, fd.clock.Now())
+		if err != nil {
+			return nil, mapKnownError(err)
+		}
+
+		if ok {
+			return out, nil
+		}
+	}
+
 	snapshots, err := fd.prepareTransact(input.TransactItems)
 	if err != nil {
 		return nil, err
@@ -868,11 +1225,11 @@ func (fd *Client) TransactWriteItems(ctx context.Context, input *dynamodb.Transa
 
 	var execErr error
 
+	published := len(fd.pendingChanges)
+
 	defer func() {
 		if execErr != nil {
-			for name, snap := range snapshots {
-				fd.tables[name].Restore(snap)
-			}
+			fd.restoreSnapshots(snapshots, published)
 		}
 	}()
 
@@ -885,7 +1242,54 @@ func (fd *Client) TransactWriteItems(ctx context.Context, input *dynamodb.Transa
 		}
 	}
 
-	return &dynamodb.TransactWriteItemsOutput{}, nil
+	out := &dynamodb.TransactWriteItemsOutput{}
+
+	if input.ClientRequestToken != nil {
+		fd.transactWriteTokens.Store(token, fingerprint, out, fd.clock.Now())
+	}
+
+	return out, nil
+}
+
+// transactItemsFingerprint converts the transaction items to minidyn types, the SDK attribute
+// value interfaces lose their member type when encoded
+func transactItemsFingerprint(items []types.TransactWriteItem) any {
+	type conditionCheck struct {
+		TableName                 string
+		Key                       map[string]*mtypes.Item
+		ConditionExpression       string
+		ExpressionAttributeNames  map[string]string
+		ExpressionAttributeValues map[string]*mtypes.Item
+	}
+
+	type transactItem struct {
+		Put            *mtypes.PutItemInput
+		Update         *mtypes.UpdateItemInput
+		Delete         *mtypes.DeleteItemInput
+		ConditionCheck *conditionCheck
+	}
+
+	fingerprint := make([]transactItem, len(items))
+
+	for i, item := range items {
+		fingerprint[i] = transactItem{
+			Put:    mapDynamoToTypesTransactPut(item.Put),
+			Update: mapDynamoToTypesTransactUpdate(item.Update),
+			Delete: mapDynamoToTypesTransactDelete(item.Delete),
+		}
+
+		if check := item.ConditionCheck; check != nil {
+			fingerprint[i].ConditionCheck = &conditionCheck{
+				TableName:                 aws.ToString(check.TableName),
+				Key:                       mapDynamoToTypesMapItem(check.Key),
+				ConditionExpression:       aws.ToString(check.ConditionExpression),
+				ExpressionAttributeNames:  check.ExpressionAttributeNames,
+				ExpressionAttributeValues: mapDynamoToTypesMapItem(check.ExpressionAttributeValues),
+			}
+		}
+	}
+
+	return fingerprint
 }
 
 func validateTransactGetItemsInput(fd *Client, input *dynamodb.TransactGetItemsInput) error {
@@ -928,7 +1332,7 @@ func validateTransactGetItemsInput(fd *Client, input *dynamodb.TransactGetItemsI
 			}
 		}
 
-		table, err := fd.getTable(tableName)
+		table, err := fd.lookupTable(tableName)
 		if err != nil {
 			return mapKnownError(err)
 		}
@@ -1013,7 +1417,7 @@ func (fd *Client) runTransactPut(i, n int, put *types.Put) error {
 		return vErr
 	}
 
-	table, tErr := fd.getTable(aws.ToString(put.TableName))
+	table, tErr := fd.lookupTable(aws.ToString(put.TableName))
 	if tErr != nil {
 		return mapKnownError(tErr)
 	}
@@ -1030,7 +1434,7 @@ func (fd *Client) runTransactUpdate(i, n int, update *types.Update) error {
 		return vErr
 	}
 
-	table, tErr := fd.getTable(aws.ToString(update.TableName))
+	table, tErr := fd.lookupTable(aws.ToString(update.TableName))
 	if tErr != nil {
 		return mapKnownError(tErr)
 	}
@@ -1052,7 +1456,7 @@ func (fd *Client) runTransactDelete(i, n int, del *types.Delete) error {
 		return vErr
 	}
 
-	table, tErr := fd.getTable(aws.ToString(del.TableName))
+	table, tErr := fd.lookupTable(aws.ToString(del.TableName))
 	if tErr != nil {
 		return mapKnownError(tErr)
 	}
@@ -1069,7 +1473,7 @@ func (fd *Client) runTransactConditionCheck(i, n int, check *types.ConditionChec
 		return vErr
 	}
 
-	table, tErr := fd.getTable(aws.ToString(check.TableName))
+	table, tErr := fd.lookupTable(aws.ToString(check.TableName))
 	if tErr != nil {
 		return mapKnownError(tErr)
 	}
@@ -1088,6 +1492,8 @@ func (fd *Client) runTransactConditionCheck(i, n int, check *types.ConditionChec
 		return &smithy.GenericAPIError{Code: "ValidationException", Message: kErr.Error()}
 	}
 
+	table.RecordAccess(keyMap)
+
 	stored := table.Data[key]
 	if stored == nil {
 		stored = map[string]*mtypes.Item{}
@@ -1147,8 +1553,21 @@ func newTransactionCancelledError(i, n int, opErr error) error {
 }
 
 func (fd *Client) getTable(tableName string) (*core.Table, error) {
+	table, err := fd.lookupTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	fd.sweepOnAccess(table)
+
+	return table, nil
+}
+
+// lookupTable returns a table without sweeping its expired items, write transactions use it
+// so no sweep happens between their snapshot and their rollback
+func (fd *Client) lookupTable(tableName string) (*core.Table, error) {
 	table, ok := fd.tables[tableName]
-	if !ok {
+	if !ok || table.Status() == core.TableStatusCreating {
 		return nil, &types.ResourceNotFoundException{Message: aws.String("Cannot do operations on a non-existent table")}
 	}
 
@@ -17,6 +17,7 @@ import (
 	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
 	"github.com/truora/minidyn/interpreter"
 	"github.com/truora/minidyn/types"
 )
@@ -958,6 +959,63 @@ func TestPutItemWithConditions(t *testing.T) {
 	c.Contains(err.Error(), invalidExpressionAttributeValue)
 }
 
+func TestPutItemWithExpected(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+
+	client := setupClient(tableName)
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+
+	item := map[string]dynamodbtypes.AttributeValue{
+		"id":   &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+		"type": &dynamodbtypes.AttributeValueMemberS{Value: "poison"},
+	}
+
+	_, err := client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item:      item,
+		Expected: map[string]dynamodbtypes.ExpectedAttributeValue{
+			"type": {Value: &dynamodbtypes.AttributeValueMemberS{Value: "fire"}},
+			"name": {ComparisonOperator: dynamodbtypes.ComparisonOperatorBeginsWith, AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "Bulba"}}},
+		},
+		ConditionalOperator: dynamodbtypes.ConditionalOperatorAnd,
+	})
+
+	var checkErr *dynamodbtypes.ConditionalCheckFailedException
+	c.ErrorAs(err, &checkErr)
+
+	_, err = client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item:      item,
+		Expected: map[string]dynamodbtypes.ExpectedAttributeValue{
+			"type": {Value: &dynamodbtypes.AttributeValueMemberS{Value: "fire"}},
+			"name": {ComparisonOperator: dynamodbtypes.ComparisonOperatorBeginsWith, AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "Bulba"}}},
+		},
+		ConditionalOperator: dynamodbtypes.ConditionalOperatorOr,
+	})
+	c.NoError(err)
+
+	_, err = client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		Expected:            map[string]dynamodbtypes.ExpectedAttributeValue{"id": {Exists: aws.Bool(true), Value: item["id"]}},
+	})
+
+	var apiErr smithy.APIError
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+	c.Contains(apiErr.ErrorMessage(), "Can not use both expression and non-expression parameters in the same request")
+
+	_, err = client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableName),
+		Key:       map[string]dynamodbtypes.AttributeValue{"id": item["id"]},
+		Expected:  map[string]dynamodbtypes.ExpectedAttributeValue{"type": {Exists: aws.Bool(false)}},
+	})
+	c.ErrorAs(err, &checkErr)
+}
+
 func TestUpdateItem(t *testing.T) {
 	c := require.New(t)
 	client := setupClient(tableName)
@@ -1032,6 +1090,47 @@ func TestUpdateItem(t *testing.T) {
 	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "water"}, item["second_type"])
 }
 
+func TestUpdateItemWithAttributeUpdates(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+
+	client := setupClient(tableName)
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+
+	key := map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"}}
+
+	out, err := client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key:       key,
+		AttributeUpdates: map[string]dynamodbtypes.AttributeValueUpdate{
+			"moves": {Action: dynamodbtypes.AttributeActionAdd, Value: &dynamodbtypes.AttributeValueMemberSS{Value: []string{"vine whip", "tackle"}}},
+			"type":  {Action: dynamodbtypes.AttributeActionPut, Value: &dynamodbtypes.AttributeValueMemberS{Value: "poison"}},
+		},
+		Expected:     map[string]dynamodbtypes.ExpectedAttributeValue{"type": {Value: &dynamodbtypes.AttributeValueMemberS{Value: "grass"}}},
+		ReturnValues: dynamodbtypes.ReturnValueUpdatedNew,
+	})
+	c.NoError(err)
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "poison"}, out.Attributes["type"])
+	c.ElementsMatch([]string{"vine whip", "tackle"}, out.Attributes["moves"].(*dynamodbtypes.AttributeValueMemberSS).Value)
+
+	out, err = client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(tableName),
+		Key:              key,
+		AttributeUpdates: map[string]dynamodbtypes.AttributeValueUpdate{"moves": {Action: dynamodbtypes.AttributeActionDelete, Value: &dynamodbtypes.AttributeValueMemberSS{Value: []string{"tackle"}}}},
+		ReturnValues:     dynamodbtypes.ReturnValueAllNew,
+	})
+	c.NoError(err)
+	c.Equal(&dynamodbtypes.AttributeValueMemberSS{Value: []string{"vine whip"}}, out.Attributes["moves"])
+
+	_, err = client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(tableName),
+		Key:              key,
+		AttributeUpdates: map[string]dynamodbtypes.AttributeValueUpdate{"id": {Value: &dynamodbtypes.AttributeValueMemberS{Value: "002"}}},
+	})
+	c.ErrorContains(err, "Cannot update attribute id. This attribute is part of the key")
+}
+
 func TestUpdateItemReturnValuesMatrix(t *testing.T) {
 	c := require.New(t)
 	client := setupClient(tableName)
@@ -1295,6 +1394,71 @@ func TestUpdateItemError(t *testing.T) {
 	c.Nil(output)
 }
 
+func TestNumberPrecision(t *testing.T) {
+	c := require.New(t)
+
+	client := setupClient(tableName)
+
+	err := ensurePokemonTable(client)
+	c.NoError(err)
+
+	_, err = client.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item: map[string]dynamodbtypes.AttributeValue{
+			"id":     &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+			"wins":   &dynamodbtypes.AttributeValueMemberN{Value: "9007199254740993"},
+			"weight": &dynamodbtypes.AttributeValueMemberN{Value: "6.90"},
+		},
+	})
+	c.NoError(err)
+
+	key := map[string]dynamodbtypes.AttributeValue{
+		"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"},
+	}
+
+	output, err := client.UpdateItem(context.Background(), &dynamodb.UpdateItemInput{
+		TableName:        aws.String(tableName),
+		Key:              key,
+		ReturnValues:     dynamodbtypes.ReturnValueUpdatedNew,
+		UpdateExpression: aws.String("ADD wins :one SET weight = weight + :gain"),
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{
+			":one":  &dynamodbtypes.AttributeValueMemberN{Value: "1"},
+			":gain": &dynamodbtypes.AttributeValueMemberN{Value: "0.10"},
+		},
+	})
+	c.NoError(err)
+	c.Equal("9007199254740994", output.Attributes["wins"].(*dynamodbtypes.AttributeValueMemberN).Value)
+	c.Equal("7", output.Attributes["weight"].(*dynamodbtypes.AttributeValueMemberN).Value)
+
+	_, err = client.UpdateItem(context.Background(), &dynamodb.UpdateItemInput{
+		TableName:        aws.String(tableName),
+		Key:              key,
+		UpdateExpression: aws.String("SET wins = wins + :tiny"),
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{
+			":tiny": &dynamodbtypes.AttributeValueMemberN{Value: "1E-30"},
+		},
+	})
+	c.ErrorContains(err, "ValidationException: Attempting to store more than 38 significant digits in a Number")
+
+	invalid := map[string]string{
+		"1E+126":   "Number overflow. Attempting to store a number with magnitude larger than supported range",
+		"1E-131":   "Number underflow. Attempting to store a number with magnitude smaller than supported range",
+		"one":      "A value provided cannot be converted into a number",
+		"1.0E+200": "Number overflow. Attempting to store a number with magnitude larger than supported range",
+	}
+
+	for value, msg := range invalid {
+		_, err = client.PutItem(context.Background(), &dynamodb.PutItemInput{
+			TableName: aws.String(tableName),
+			Item: map[string]dynamodbtypes.AttributeValue{
+				"id":   &dynamodbtypes.AttributeValueMemberS{Value: "002"},
+				"wins": &dynamodbtypes.AttributeValueMemberN{Value: value},
+			},
+		})
+		c.ErrorContains(err, "ValidationException: "+msg, value)
+	}
+}
+
 func TestUpdateExpressions(t *testing.T) {
 	c := require.New(t)
 	db := []pokemon{
@@ -1708,6 +1872,182 @@ func TestQuerySyntaxError(t *testing.T) {
 	c.Equal("ValidationException", apiErr.ErrorCode())
 }
 
+func TestSearchWithLegacyConditions(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+
+	client := setupClient(tableName)
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(ensurePokemonTypeIndex(client))
+
+	for _, creature := range []pokemon{
+		{ID: "001", Type: "grass", Name: "Bulbasaur", Level: 5},
+		{ID: "002", Type: "grass", Name: "Ivysaur", Level: 16},
+		{ID: "004", Type: "fire", Name: "Charmander", Level: 5},
+	} {
+		c.NoError(createPokemon(client, creature))
+	}
+
+	query, err := client.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String(tableName),
+		IndexName: aws.String("by-type"),
+		KeyConditions: map[string]dynamodbtypes.Condition{
+			"type": {ComparisonOperator: dynamodbtypes.ComparisonOperatorEq, AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "grass"}}},
+			"id":   {ComparisonOperator: dynamodbtypes.ComparisonOperatorGt, AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "001"}}},
+		},
+	})
+	c.NoError(err)
+	c.Len(query.Items, 1)
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "Ivysaur"}, query.Items[0]["name"])
+
+	query, err = client.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String(tableName),
+		IndexName: aws.String("by-type"),
+		KeyConditions: map[string]dynamodbtypes.Condition{
+			"type": {ComparisonOperator: dynamodbtypes.ComparisonOperatorEq, AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "grass"}}},
+		},
+		QueryFilter: map[string]dynamodbtypes.Condition{
+			"lvl": {ComparisonOperator: dynamodbtypes.ComparisonOperatorLt, AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberN{Value: "10"}}},
+		},
+	})
+	c.NoError(err)
+	c.Len(query.Items, 1)
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "Bulbasaur"}, query.Items[0]["name"])
+
+	scan, err := client.Scan(ctx, &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+		ScanFilter: map[string]dynamodbtypes.Condition{
+			"lvl":  {ComparisonOperator: dynamodbtypes.ComparisonOperatorEq, AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberN{Value: "5"}}},
+			"type": {ComparisonOperator: dynamodbtypes.ComparisonOperatorEq, AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "grass"}}},
+		},
+		ConditionalOperator: dynamodbtypes.ConditionalOperatorAnd,
+	})
+	c.NoError(err)
+	c.Len(scan.Items, 1)
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "Bulbasaur"}, scan.Items[0]["name"])
+
+	_, err = client.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String(tableName),
+		KeyConditions: map[string]dynamodbtypes.Condition{
+			"id": {ComparisonOperator: dynamodbtypes.ComparisonOperatorEq, AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "001"}}},
+		},
+		QueryFilter: map[string]dynamodbtypes.Condition{
+			"id": {ComparisonOperator: dynamodbtypes.ComparisonOperatorNotNull},
+		},
+	})
+	c.ErrorContains(err, "QueryFilter can only contain non-primary key attributes: Primary key attribute: id")
+
+	_, err = client.Scan(ctx, &dynamodb.ScanInput{
+		TableName:        aws.String(tableName),
+		FilterExpression: aws.String("attribute_exists(lvl)"),
+		ScanFilter: map[string]dynamodbtypes.Condition{
+			"lvl": {ComparisonOperator: dynamodbtypes.ComparisonOperatorNotNull},
+		},
+	})
+	c.ErrorContains(err, "Non-expression parameters: {ScanFilter} Expression parameters: {FilterExpression}")
+}
+
+func TestReadsWithAttributesToGet(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+
+	client := setupClient(tableName)
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(ensurePokemonTypeIndex(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur", Level: 5}))
+
+	key := map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "001"}}
+	bulbasaur := map[string]dynamodbtypes.AttributeValue{
+		"name": &dynamodbtypes.AttributeValueMemberS{Value: "Bulbasaur"},
+		"lvl":  &dynamodbtypes.AttributeValueMemberN{Value: "5"},
+	}
+
+	got, err := client.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String(tableName), Key: key, AttributesToGet: []string{"name", "lvl"}})
+	c.NoError(err)
+	c.Equal(bulbasaur, got.Item)
+
+	batch, err := client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]dynamodbtypes.KeysAndAttributes{tableName: {Keys: []map[string]dynamodbtypes.AttributeValue{key}, AttributesToGet: []string{"name", "lvl"}}},
+	})
+	c.NoError(err)
+	c.Equal([]map[string]dynamodbtypes.AttributeValue{bulbasaur}, batch.Responses[tableName])
+
+	query, err := client.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String(tableName),
+		IndexName: aws.String("by-type"),
+		KeyConditions: map[string]dynamodbtypes.Condition{
+			"type": {ComparisonOperator: dynamodbtypes.ComparisonOperatorEq, AttributeValueList: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "grass"}}},
+		},
+		AttributesToGet: []string{"name", "lvl"},
+	})
+	c.NoError(err)
+	c.Equal([]map[string]dynamodbtypes.AttributeValue{bulbasaur}, query.Items)
+
+	scan, err := client.Scan(ctx, &dynamodb.ScanInput{TableName: aws.String(tableName), AttributesToGet: []string{"name", "lvl"}})
+	c.NoError(err)
+	c.Equal([]map[string]dynamodbtypes.AttributeValue{bulbasaur}, scan.Items)
+
+	_, err = client.Scan(ctx, &dynamodb.ScanInput{TableName: aws.String(tableName), AttributesToGet: []string{"name"}, ProjectionExpression: aws.String("lvl")})
+	c.ErrorContains(err, "Non-expression parameters: {AttributesToGet} Expression parameters: {ProjectionExpression}")
+}
+
+func TestParallelScan(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+
+	client := setupClient(tableName)
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(ensurePokemonTypeIndex(client))
+
+	kinds := []string{"grass", "fire", "water", "electric"}
+
+	for i := range 30 {
+		c.NoError(createPokemon(client, pokemon{ID: fmt.Sprintf("%03d", i), Type: kinds[i%len(kinds)], Name: fmt.Sprintf("pokemon %d", i)}))
+	}
+
+	for _, indexName := range []*string{nil, aws.String("by-type")} {
+		ids := map[string]int{}
+
+		for segment := range int32(3) {
+			input := &dynamodb.ScanInput{
+				TableName:     aws.String(tableName),
+				IndexName:     indexName,
+				Limit:         aws.Int32(4),
+				Segment:       aws.Int32(segment),
+				TotalSegments: aws.Int32(3),
+			}
+
+			for {
+				out, err := client.Scan(ctx, input)
+				c.NoError(err)
+
+				for _, item := range out.Items {
+					ids[item["id"].(*dynamodbtypes.AttributeValueMemberS).Value]++
+				}
+
+				if len(out.LastEvaluatedKey) == 0 {
+					break
+				}
+
+				input.ExclusiveStartKey = out.LastEvaluatedKey
+			}
+		}
+
+		// every pokemon is read once by a single segment
+		c.Len(ids, 30)
+
+		for id, reads := range ids {
+			c.Equal(1, reads, id)
+		}
+	}
+
+	_, err := client.Scan(ctx, &dynamodb.ScanInput{TableName: aws.String(tableName), Segment: aws.Int32(3), TotalSegments: aws.Int32(3)})
+	c.ErrorContains(err, "Segment: 3 is not less than TotalSegments: 3")
+
+	_, err = client.Scan(ctx, &dynamodb.ScanInput{TableName: aws.String(tableName), Segment: aws.Int32(0), TotalSegments: aws.Int32(1000001)})
+	c.ErrorContains(err, "Member must have value less than or equal to 1000000")
+}
+
 func TestScan(t *testing.T) {
 	c := require.New(t)
 
@@ -2014,6 +2354,69 @@ func TestDescribeTableFail(t *testing.T) {
 	c.Empty(output)
 }
 
+func TestListTables(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	output, err := client.ListTables(ctx, &dynamodb.ListTablesInput{})
+	c.NoError(err)
+	c.Empty(output.TableNames)
+	c.Nil(output.LastEvaluatedTableName)
+
+	for _, name := range []string{"trainers", "badges", "pokemons", "gyms", "moves"} {
+		c.NoError(AddTable(ctx, client, name, "id", ""))
+	}
+
+	output, err = client.ListTables(ctx, &dynamodb.ListTablesInput{Limit: aws.Int32(2)})
+	c.NoError(err)
+	c.Equal([]string{"badges", "gyms"}, output.TableNames)
+	c.Equal("gyms", aws.ToString(output.LastEvaluatedTableName))
+
+	output, err = client.ListTables(ctx, &dynamodb.ListTablesInput{ExclusiveStartTableName: aws.String("moves")})
+	c.NoError(err)
+	c.Equal([]string{"pokemons", "trainers"}, output.TableNames)
+	c.Nil(output.LastEvaluatedTableName)
+
+	var names []string
+
+	paginator := dynamodb.NewListTablesPaginator(client, &dynamodb.ListTablesInput{Limit: aws.Int32(2)})
+	for paginator.HasMorePages() {
+		page, perr := paginator.NextPage(ctx)
+		c.NoError(perr)
+
+		names = append(names, page.TableNames...)
+	}
+
+	c.Equal([]string{"badges", "gyms", "moves", "pokemons", "trainers"}, names)
+
+	_, err = client.ListTables(ctx, &dynamodb.ListTablesInput{Limit: aws.Int32(101)})
+	c.EqualError(err, "api error ValidationException: 1 validation error detected: Value '101' at 'limit' failed to satisfy constraint: Member must have value less than or equal to 100")
+
+	_, err = client.ListTables(ctx, &dynamodb.ListTablesInput{Limit: aws.Int32(0)})
+	c.Error(err)
+}
+
+func TestListTablesPageCap(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	for i := range 105 {
+		c.NoError(AddTable(ctx, client, fmt.Sprintf("table-%03d", i), "id", ""))
+	}
+
+	output, err := client.ListTables(ctx, &dynamodb.ListTablesInput{})
+	c.NoError(err)
+	c.Len(output.TableNames, 100)
+	c.Equal("table-099", aws.ToString(output.LastEvaluatedTableName))
+
+	output, err = client.ListTables(ctx, &dynamodb.ListTablesInput{ExclusiveStartTableName: output.LastEvaluatedTableName})
+	c.NoError(err)
+	c.Equal([]string{"table-100", "table-101", "table-102", "table-103", "table-104"}, output.TableNames)
+	c.Nil(output.LastEvaluatedTableName)
+}
+
 func TestBatchWriteItem(t *testing.T) {
 	c := require.New(t)
 	client := setupClient(tableName)
@@ -3312,6 +3715,74 @@ func TestTransactWriteItems(t *testing.T) {
 	})
 }
 
+func TestTransactWriteItemsClientRequestToken(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	SetClock(client, clock)
+
+	c.NoError(ensurePokemonTable(client))
+
+	key := map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "wallet"}}
+	input := &dynamodb.TransactWriteItemsInput{
+		ClientRequestToken: aws.String("charge-1"),
+		TransactItems: []dynamodbtypes.TransactWriteItem{
+			{
+				Update: &dynamodbtypes.Update{
+					TableName:                 aws.String(tableName),
+					Key:                       key,
+					UpdateExpression:          aws.String("ADD charges :one"),
+					ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{":one": &dynamodbtypes.AttributeValueMemberN{Value: "1"}},
+				},
+			},
+		},
+	}
+
+	charges := func() string {
+		out, err := client.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String(tableName), Key: key})
+		c.NoError(err)
+
+		return out.Item["charges"].(*dynamodbtypes.AttributeValueMemberN).Value
+	}
+
+	_, err := client.TransactWriteItems(ctx, input)
+	c.NoError(err)
+
+	// a retry within the window does not charge twice
+	_, err = client.TransactWriteItems(ctx, input)
+	c.NoError(err)
+	c.Equal("1", charges())
+
+	input.TransactItems[0].Update.ExpressionAttributeValues[":one"] = &dynamodbtypes.AttributeValueMemberS{Value: "1"}
+
+	_, err = client.TransactWriteItems(ctx, input)
+
+	var mismatch *dynamodbtypes.IdempotentParameterMismatchException
+	c.ErrorAs(err, &mismatch)
+
+	input.TransactItems[0].Update.ExpressionAttributeValues[":one"] = &dynamodbtypes.AttributeValueMemberN{Value: "1"}
+
+	clock.Advance(core.ClientRequestTokenTTL)
+
+	_, err = client.TransactWriteItems(ctx, input)
+	c.NoError(err)
+	c.Equal("2", charges())
+
+	SetClientRequestTokenTTL(client, time.Minute)
+
+	input.ClientRequestToken = aws.String("charge-2")
+
+	_, err = client.TransactWriteItems(ctx, input)
+	c.NoError(err)
+
+	clock.Advance(time.Minute)
+
+	_, err = client.TransactWriteItems(ctx, input)
+	c.NoError(err)
+	c.Equal("4", charges())
+}
+
 func TestTransactGetItems(t *testing.T) {
 	t.Run("single item", func(t *testing.T) {
 		c := require.New(t)
@@ -0,0 +1,227 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+const maxListContributorInsightsResults = 100
+
+// Contributor is a key ranked by Contributor Insights along with the accesses it made
+type Contributor struct {
+	Key   map[string]types.AttributeValue
+	Count int64
+}
+
+// ContributorRanking ranks the keys that made the most accesses in a window. Total counts
+// the accesses of every key, so the share of the top keys can be checked
+type ContributorRanking struct {
+	Contributors []Contributor
+	Total        int64
+}
+
+// UpdateContributorInsights enables or disables Contributor Insights for a table or a global
+// secondary index
+func (fd *Client) UpdateContributorInsights(ctx context.Context, input *dynamodb.UpdateContributorInsightsInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateContributorInsightsOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	tableName, indexName := aws.ToString(input.TableName), aws.ToString(input.IndexName)
+
+	if err := fd.failureErrFor(tableName, indexName); err != nil {
+		return nil, err
+	}
+
+	var enable bool
+
+	switch input.ContributorInsightsAction {
+	case types.ContributorInsightsActionEnable:
+		enable = true
+	case types.ContributorInsightsActionDisable:
+	case "":
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "1 validation error detected: Value null at 'contributorInsightsAction' failed to satisfy constraint: Member must not be null"}
+	default:
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%s' at 'contributorInsightsAction' failed to satisfy constraint: Member must satisfy enum value set: [ENABLE, DISABLE]", input.ContributorInsightsAction)}
+	}
+
+	table, err := fd.getTable(tableName)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	status, err := table.UpdateContributorInsights(indexName, enable)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	return &dynamodb.UpdateContributorInsightsOutput{
+		TableName:                 input.TableName,
+		IndexName:                 input.IndexName,
+		ContributorInsightsStatus: types.ContributorInsightsStatus(status),
+	}, nil
+}
+
+// DescribeContributorInsights describes Contributor Insights for a table or a global
+// secondary index
+func (fd *Client) DescribeContributorInsights(ctx context.Context, input *dynamodb.DescribeContributorInsightsInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeContributorInsightsOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	tableName, indexName := aws.ToString(input.TableName), aws.ToString(input.IndexName)
+
+	if err := fd.failureErrFor(tableName, indexName); err != nil {
+		return nil, err
+	}
+
+	table, err := fd.getTable(tableName)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	desc, err := table.DescribeContributorInsights(indexName)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	output := &dynamodb.DescribeContributorInsightsOutput{
+		TableName:                   input.TableName,
+		IndexName:                   input.IndexName,
+		ContributorInsightsRuleList: desc.Rules,
+		ContributorInsightsStatus:   types.ContributorInsightsStatus(desc.Status),
+	}
+
+	if !desc.LastUpdateTime.IsZero() {
+		output.LastUpdateDateTime = aws.Time(desc.LastUpdateTime)
+	}
+
+	return output, nil
+}
+
+// ListContributorInsights lists the tables and global secondary indexes Contributor
+// Insights was enabled for, ordered by table and index
+func (fd *Client) ListContributorInsights(ctx context.Context, input *dynamodb.ListContributorInsightsInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListContributorInsightsOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	tableName := aws.ToString(input.TableName)
+
+	if err := fd.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	if input.MaxResults < 0 || input.MaxResults > maxListContributorInsightsResults {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'maxResults' failed to satisfy constraint: Member must have value between 0 and %d", input.MaxResults, maxListContributorInsightsResults)}
+	}
+
+	limit := maxListContributorInsightsResults
+	if input.MaxResults > 0 {
+		limit = int(input.MaxResults)
+	}
+
+	tableNames := slices.Sorted(maps.Keys(fd.tables))
+
+	if tableName != "" {
+		if _, err := fd.getTable(tableName); err != nil {
+			return nil, mapKnownError(err)
+		}
+
+		tableNames = []string{tableName}
+	}
+
+	summaries := []types.ContributorInsightsSummary{}
+
+	for _, name := range tableNames {
+		table := fd.tables[name]
+		if table.Status() == core.TableStatusCreating {
+			continue
+		}
+
+		for _, desc := range table.ListContributorInsights() {
+			summary := types.ContributorInsightsSummary{TableName: aws.String(name), ContributorInsightsStatus: types.ContributorInsightsStatus(desc.Status)}
+			if desc.IndexName != "" {
+				summary.IndexName = aws.String(desc.IndexName)
+			}
+
+			summaries = append(summaries, summary)
+		}
+	}
+
+	start := 0
+
+	if token := aws.ToString(input.NextToken); token != "" {
+		for i, summary := range summaries {
+			if contributorInsightsToken(summary) == token {
+				start = i + 1
+
+				break
+			}
+		}
+	}
+
+	end := min(start+limit, len(summaries))
+	output := &dynamodb.ListContributorInsightsOutput{ContributorInsightsSummaries: summaries[start:end]}
+
+	if end < len(summaries) {
+		output.NextToken = aws.String(contributorInsightsToken(summaries[end-1]))
+	}
+
+	return output, nil
+}
+
+func contributorInsightsToken(summary types.ContributorInsightsSummary) string {
+	return aws.ToString(summary.TableName) + "/" + aws.ToString(summary.IndexName)
+}
+
+// TopContributors ranks the keys of a Contributor Insights rule of a table, or of one of
+// its global secondary indexes when indexName is not empty, by the accesses they made in
+// the last window, which is counted in whole minutes following the clock given to
+// SetClock. Every item read or written is an access, and batch requests left unprocessed
+// by EmulateUnprocessedItems count as throttled. Every key is ranked when limit is not
+// positive. Contributor Insights must be enabled with UpdateContributorInsights
+func TopContributors(client FakeClient, tableName, indexName string, rule core.ContributorInsightsRule, window time.Duration, limit int) (ContributorRanking, error) {
+	fakeClient, ok := client.(*Client)
+	if !ok {
+		panic("TopContributors: invalid client type")
+	}
+
+	fakeClient.mu.Lock()
+	defer fakeClient.mu.Unlock()
+
+	table, err := fakeClient.getTable(tableName)
+	if err != nil {
+		return ContributorRanking{}, mapKnownError(err)
+	}
+
+	ranking, err := table.TopContributors(indexName, rule, window, limit)
+	if err != nil {
+		return ContributorRanking{}, mapKnownError(err)
+	}
+
+	output := ContributorRanking{Contributors: make([]Contributor, 0, len(ranking.Contributors)), Total: ranking.Total}
+
+	for _, contributor := range ranking.Contributors {
+		output.Contributors = append(output.Contributors, Contributor{Key: mapTypesToDynamoMapItem(contributor.Key), Count: contributor.Count})
+	}
+
+	return output, nil
+}
+
+// recordThrottle counts a batch request left unprocessed as a throttled request of the item
+// it was for
+func (fd *Client) recordThrottle(tableName string, raw map[string]types.AttributeValue) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	if table, ok := fd.tables[tableName]; ok {
+		table.RecordThrottle(mapDynamoToTypesMapItem(raw))
+	}
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func TestContributorInsights(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	SetClock(client, clock)
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(ensurePokemonTypeIndex(client))
+
+	_, err := TopContributors(client, tableName, "", core.MostAccessedPartitionKeys, time.Minute, 0)
+	c.ErrorContains(err, "not enabled")
+
+	updated, err := client.UpdateContributorInsights(ctx, &dynamodb.UpdateContributorInsightsInput{
+		TableName:                 aws.String(tableName),
+		ContributorInsightsAction: dynamodbtypes.ContributorInsightsActionEnable,
+	})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.ContributorInsightsStatusEnabling, updated.ContributorInsightsStatus)
+
+	described, err := client.DescribeContributorInsights(ctx, &dynamodb.DescribeContributorInsightsInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.ContributorInsightsStatusEnabled, described.ContributorInsightsStatus)
+	c.Equal(clock.Now(), aws.ToTime(described.LastUpdateDateTime))
+	c.Len(described.ContributorInsightsRuleList, 2)
+
+	described, err = client.DescribeContributorInsights(ctx, &dynamodb.DescribeContributorInsightsInput{TableName: aws.String(tableName), IndexName: aws.String("by-type")})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.ContributorInsightsStatusDisabled, described.ContributorInsightsStatus)
+
+	listed, err := client.ListContributorInsights(ctx, &dynamodb.ListContributorInsightsInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Len(listed.ContributorInsightsSummaries, 1)
+
+	for _, id := range []string{"1", "4", "7"} {
+		c.NoError(createPokemon(client, pokemon{ID: id, Type: "grass", Name: "pokemon " + id}))
+	}
+
+	for range 5 {
+		_, err = client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(tableName),
+			Key:                       map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "1"}},
+			UpdateExpression:          aws.String("SET #name = :name"),
+			ExpressionAttributeNames:  map[string]string{"#name": "name"},
+			ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{":name": &dynamodbtypes.AttributeValueMemberS{Value: "bulbasaur"}},
+		})
+		c.NoError(err)
+	}
+
+	EmulateUnprocessedItems(client, tableName, func(n int, _ map[string]dynamodbtypes.AttributeValue) bool {
+		return n == 0
+	})
+
+	_, err = client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]dynamodbtypes.WriteRequest{tableName: {
+			{DeleteRequest: &dynamodbtypes.DeleteRequest{Key: map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "1"}}}},
+		}},
+	})
+	c.NoError(err)
+
+	ranking, err := TopContributors(client, tableName, "", core.MostAccessedPartitionKeys, time.Minute, 2)
+	c.NoError(err)
+	c.Equal(int64(8), ranking.Total)
+	c.Len(ranking.Contributors, 2)
+	c.Equal(map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "1"}}, ranking.Contributors[0].Key)
+	c.Equal(int64(6), ranking.Contributors[0].Count)
+
+	ranking, err = TopContributors(client, tableName, "", core.MostThrottledPartitionKeys, time.Minute, 0)
+	c.NoError(err)
+	c.Equal(int64(1), ranking.Total)
+
+	_, err = TopContributors(client, tableName, "by-type", core.MostAccessedPartitionKeys, time.Minute, 0)
+	c.ErrorContains(err, "not enabled")
+}
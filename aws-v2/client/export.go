@@ -0,0 +1,222 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+const maxListExportsResults = 25
+
+// SetExportSink sets where ExportTableToPointInTime writes the files it would put in
+// S3, use a core.DirectoryExportSink to write them under a local directory. Without
+// a sink exports fail with the S3NoSuchBucket failure code
+func SetExportSink(client FakeClient, sink core.ExportSink) {
+	fakeClient, ok := client.(*Client)
+	if !ok {
+		panic("SetExportSink: invalid client type")
+	}
+
+	fakeClient.mu.Lock()
+	defer fakeClient.mu.Unlock()
+
+	fakeClient.exportSink = sink
+}
+
+// exportArn builds an ARN following the format DynamoDB uses for table exports
+func (fd *Client) exportArn(tableName string, startTime time.Time) string {
+	fd.exportSeq++
+
+	return fmt.Sprintf("%s/export/%014d-%08x", fd.tableArn(tableName), startTime.UnixMilli(), fd.exportSeq)
+}
+
+func exportDescription(export *core.Export) *types.ExportDescription {
+	desc := &types.ExportDescription{
+		ExportArn:       aws.String(export.ExportArn),
+		ExportStatus:    types.ExportStatus(export.Status),
+		ExportType:      types.ExportType(export.Type),
+		ExportFormat:    types.ExportFormat(export.Format),
+		ExportManifest:  toString(export.Manifest),
+		StartTime:       aws.Time(export.StartTime),
+		EndTime:         aws.Time(export.EndTime),
+		TableArn:        aws.String(export.TableArn),
+		ClientToken:     toString(export.ClientToken),
+		S3Bucket:        aws.String(export.S3Bucket),
+		S3Prefix:        toString(export.S3Prefix),
+		S3SseAlgorithm:  types.S3SseAlgorithmAes256,
+		ItemCount:       aws.Int64(export.ItemCount),
+		BilledSizeBytes: aws.Int64(export.BilledSizeBytes),
+		FailureCode:     toString(export.FailureCode),
+		FailureMessage:  toString(export.FailureMessage),
+	}
+
+	if export.Type == core.ExportTypeIncremental {
+		desc.IncrementalExportSpecification = &types.IncrementalExportSpecification{
+			ExportFromTime: aws.Time(export.ExportFromTime),
+			ExportToTime:   aws.Time(export.ExportToTime),
+			ExportViewType: types.ExportViewType(export.ViewType),
+		}
+	} else {
+		desc.ExportTime = aws.Time(export.ExportTime)
+	}
+
+	return desc
+}
+
+// ExportTableToPointInTime writes the items of a table to the export sink
+func (fd *Client) ExportTableToPointInTime(ctx context.Context, input *dynamodb.ExportTableToPointInTimeInput, opts ...func(*dynamodb.Options)) (*dynamodb.ExportTableToPointInTimeOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	tableArn := aws.ToString(input.TableArn)
+	_, tableName, _ := strings.Cut(tableArn, ":table/")
+
+	if err := fd.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	if tableArn == "" || aws.ToString(input.S3Bucket) == "" {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "TableArn and S3Bucket must be specified"}
+	}
+
+	table, err := fd.getBackupTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := fd.clock.Now()
+	exportInput := core.ExportInput{
+		ClientToken: aws.ToString(input.ClientToken),
+		TableArn:    tableArn,
+		S3Bucket:    aws.ToString(input.S3Bucket),
+		S3Prefix:    aws.ToString(input.S3Prefix),
+		Format:      string(input.ExportFormat),
+		Type:        string(input.ExportType),
+		ExportTime:  aws.ToTime(input.ExportTime),
+	}
+
+	if input.ExportTime == nil {
+		exportInput.ExportTime = now
+	}
+
+	if spec := input.IncrementalExportSpecification; spec != nil {
+		exportInput.ViewType = string(spec.ExportViewType)
+		exportInput.ExportFromTime = aws.ToTime(spec.ExportFromTime)
+		exportInput.ExportToTime = aws.ToTime(spec.ExportToTime)
+	}
+
+	if err := core.ValidateExportInput(&exportInput); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if exportInput.Type == core.ExportTypeIncremental && (exportInput.ExportFromTime.IsZero() || exportInput.ExportToTime.IsZero()) {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "IncrementalExportSpecification must specify ExportFromTime and ExportToTime for an incremental export"}
+	}
+
+	exportInput.ExportArn = fd.exportArn(tableName, now)
+
+	export, err := table.Export(fd.exportSink, exportInput, now)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	fd.exports[export.ExportArn] = export
+
+	return &dynamodb.ExportTableToPointInTimeOutput{ExportDescription: exportDescription(export)}, nil
+}
+
+// DescribeExport returns the description of a table export
+func (fd *Client) DescribeExport(ctx context.Context, input *dynamodb.DescribeExportInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeExportOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	if fd.forceFailureErr != nil {
+		return nil, fd.forceFailureErr
+	}
+
+	arn := aws.ToString(input.ExportArn)
+
+	export, ok := fd.exports[arn]
+	if !ok {
+		return nil, &types.ExportNotFoundException{Message: aws.String("Export not found: " + arn)}
+	}
+
+	return &dynamodb.DescribeExportOutput{ExportDescription: exportDescription(export)}, nil
+}
+
+// ListExports lists the table exports, oldest first
+func (fd *Client) ListExports(ctx context.Context, input *dynamodb.ListExportsInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListExportsOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	tableArn := aws.ToString(input.TableArn)
+	_, tableName, _ := strings.Cut(tableArn, ":table/")
+
+	if err := fd.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	limit := maxListExportsResults
+	if input.MaxResults != nil {
+		if *input.MaxResults < 1 || *input.MaxResults > maxListExportsResults {
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'maxResults' failed to satisfy constraint: Member must have value between 1 and %d", *input.MaxResults, maxListExportsResults)}
+		}
+
+		limit = int(*input.MaxResults)
+	}
+
+	exports := []*core.Export{}
+
+	for _, export := range fd.exports {
+		if tableArn != "" && export.TableArn != tableArn {
+			continue
+		}
+
+		exports = append(exports, export)
+	}
+
+	sort.Slice(exports, func(i, j int) bool {
+		if !exports[i].StartTime.Equal(exports[j].StartTime) {
+			return exports[i].StartTime.Before(exports[j].StartTime)
+		}
+
+		return exports[i].ExportArn < exports[j].ExportArn
+	})
+
+	start := 0
+
+	if token := aws.ToString(input.NextToken); token != "" {
+		for i, export := range exports {
+			if export.ExportArn == token {
+				start = i + 1
+
+				break
+			}
+		}
+	}
+
+	end := min(start+limit, len(exports))
+	output := &dynamodb.ListExportsOutput{ExportSummaries: make([]types.ExportSummary, 0, end-start)}
+
+	for _, export := range exports[start:end] {
+		output.ExportSummaries = append(output.ExportSummaries, types.ExportSummary{
+			ExportArn:    aws.String(export.ExportArn),
+			ExportStatus: types.ExportStatus(export.Status),
+			ExportType:   types.ExportType(export.Type),
+		})
+	}
+
+	if end < len(exports) {
+		output.NextToken = aws.String(exports[end-1].ExportArn)
+	}
+
+	return output, nil
+}
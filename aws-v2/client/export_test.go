@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func TestExports(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	tableArn := aws.String("arn:aws:dynamodb:us-east-1:000000000000:table/" + tableName)
+
+	SetClock(client, clock)
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "25", Type: "electric", Name: "pikachu"}))
+
+	_, err := client.ExportTableToPointInTime(ctx, &dynamodb.ExportTableToPointInTimeInput{TableArn: tableArn, S3Bucket: aws.String("exports")})
+
+	var unavailableErr *dynamodbtypes.PointInTimeRecoveryUnavailableException
+	c.True(errors.As(err, &unavailableErr))
+
+	_, err = client.UpdateContinuousBackups(ctx, &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodbtypes.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+
+	enabledAt := clock.Now()
+	clock.Advance(time.Hour)
+
+	c.NoError(createPokemon(client, pokemon{ID: "4", Type: "fire", Name: "charmander"}))
+
+	failed, err := client.ExportTableToPointInTime(ctx, &dynamodb.ExportTableToPointInTimeInput{TableArn: tableArn, S3Bucket: aws.String("exports")})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.ExportStatusFailed, failed.ExportDescription.ExportStatus)
+
+	dir := t.TempDir()
+	SetExportSink(client, core.DirectoryExportSink(dir))
+
+	full, err := client.ExportTableToPointInTime(ctx, &dynamodb.ExportTableToPointInTimeInput{
+		TableArn:     tableArn,
+		S3Bucket:     aws.String("exports"),
+		ExportFormat: dynamodbtypes.ExportFormatIon,
+		ExportTime:   aws.Time(enabledAt),
+	})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.ExportStatusCompleted, full.ExportDescription.ExportStatus)
+	c.EqualValues(1, aws.ToInt64(full.ExportDescription.ItemCount))
+	c.Equal(enabledAt, aws.ToTime(full.ExportDescription.ExportTime))
+
+	manifest := filepath.Join(dir, "exports", filepath.FromSlash(aws.ToString(full.ExportDescription.ExportManifest)))
+	_, err = os.Stat(manifest)
+	c.NoError(err)
+
+	dataFiles, err := filepath.Glob(filepath.Join(filepath.Dir(manifest), "data", "*.ion.gz"))
+	c.NoError(err)
+	c.Len(dataFiles, 1)
+
+	incremental, err := client.ExportTableToPointInTime(ctx, &dynamodb.ExportTableToPointInTimeInput{
+		TableArn:   tableArn,
+		S3Bucket:   aws.String("exports"),
+		ExportType: dynamodbtypes.ExportTypeIncrementalExport,
+		IncrementalExportSpecification: &dynamodbtypes.IncrementalExportSpecification{
+			ExportFromTime: aws.Time(enabledAt),
+			ExportToTime:   aws.Time(clock.Now()),
+		},
+	})
+	c.NoError(err)
+	c.EqualValues(1, aws.ToInt64(incremental.ExportDescription.ItemCount))
+	c.Equal(dynamodbtypes.ExportViewTypeNewAndOldImages, incremental.ExportDescription.IncrementalExportSpecification.ExportViewType)
+
+	_, err = client.ExportTableToPointInTime(ctx, &dynamodb.ExportTableToPointInTimeInput{
+		TableArn:   tableArn,
+		S3Bucket:   aws.String("exports"),
+		ExportType: dynamodbtypes.ExportTypeIncrementalExport,
+	})
+	c.ErrorContains(err, "IncrementalExportSpecification")
+
+	described, err := client.DescribeExport(ctx, &dynamodb.DescribeExportInput{ExportArn: full.ExportDescription.ExportArn})
+	c.NoError(err)
+	c.Equal(full.ExportDescription.ExportManifest, described.ExportDescription.ExportManifest)
+
+	_, err = client.DescribeExport(ctx, &dynamodb.DescribeExportInput{ExportArn: aws.String("unknown")})
+
+	var notFoundErr *dynamodbtypes.ExportNotFoundException
+	c.True(errors.As(err, &notFoundErr))
+
+	listed, err := client.ListExports(ctx, &dynamodb.ListExportsInput{TableArn: tableArn})
+	c.NoError(err)
+	c.Len(listed.ExportSummaries, 3)
+	c.Equal(dynamodbtypes.ExportTypeIncrementalExport, listed.ExportSummaries[2].ExportType)
+	c.Nil(listed.NextToken)
+
+	listed, err = client.ListExports(ctx, &dynamodb.ListExportsInput{TableArn: aws.String("arn:aws:dynamodb:us-east-1:000000000000:table/other")})
+	c.NoError(err)
+	c.Empty(listed.ExportSummaries)
+}
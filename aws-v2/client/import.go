@@ -0,0 +1,264 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+const maxListImportsPageSize = 25
+
+// tableImport keeps an import with the parts of its request echoed by its description
+type tableImport struct {
+	*core.Import
+
+	tableArn           string
+	bucketSource       *types.S3BucketSource
+	formatOptions      *types.InputFormatOptions
+	creationParameters *types.TableCreationParameters
+}
+
+// SetImportSource sets where ImportTable reads the objects it would read from S3,
+// every bucket being a directory of source, like os.DirFS(dir). Without a source
+// imports fail with the S3NoSuchBucket failure code
+func SetImportSource(client FakeClient, source fs.FS) {
+	fakeClient, ok := client.(*Client)
+	if !ok {
+		panic("SetImportSource: invalid client type")
+	}
+
+	fakeClient.mu.Lock()
+	defer fakeClient.mu.Unlock()
+
+	fakeClient.importSource = source
+}
+
+// ImportErrors returns the items an import could not read or write, which DynamoDB
+// logs to CloudWatch
+func ImportErrors(client FakeClient, importArn string) []core.ImportError {
+	fakeClient, ok := client.(*Client)
+	if !ok {
+		panic("ImportErrors: invalid client type")
+	}
+
+	fakeClient.mu.Lock()
+	defer fakeClient.mu.Unlock()
+
+	imp, ok := fakeClient.imports[importArn]
+	if !ok {
+		return nil
+	}
+
+	return imp.Errors
+}
+
+// importArn builds an ARN following the format DynamoDB uses for table imports
+func (fd *Client) importArn(tableName string, startTime time.Time) string {
+	fd.importSeq++
+
+	return fmt.Sprintf("%s/import/%014d-%08x", fd.tableArn(tableName), startTime.UnixMilli(), fd.importSeq)
+}
+
+// importLogGroupArn is the CloudWatch log group where DynamoDB logs the import errors
+func (fd *Client) importLogGroupArn() string {
+	return fmt.Sprintf("arn:aws:logs:%s:%s:log-group:/aws-dynamodb/imports:*", fd.region, fd.accountID)
+}
+
+func (fd *Client) importDescription(imp *tableImport) *types.ImportTableDescription {
+	return &types.ImportTableDescription{
+		ImportArn:               aws.String(imp.ImportArn),
+		ImportStatus:            types.ImportStatus(imp.Status),
+		TableArn:                aws.String(imp.tableArn),
+		ClientToken:             toString(imp.ClientToken),
+		S3BucketSource:          imp.bucketSource,
+		CloudWatchLogGroupArn:   aws.String(fd.importLogGroupArn()),
+		InputFormat:             types.InputFormat(imp.Format),
+		InputFormatOptions:      imp.formatOptions,
+		InputCompressionType:    types.InputCompressionType(imp.Compression),
+		TableCreationParameters: imp.creationParameters,
+		StartTime:               aws.Time(imp.StartTime),
+		EndTime:                 aws.Time(imp.EndTime),
+		ProcessedSizeBytes:      aws.Int64(imp.ProcessedSizeBytes),
+		ProcessedItemCount:      imp.ProcessedItemCount,
+		ImportedItemCount:       imp.ImportedItemCount,
+		ErrorCount:              imp.ErrorCount,
+		FailureCode:             toString(imp.FailureCode),
+		FailureMessage:          toString(imp.FailureMessage),
+	}
+}
+
+// ImportTable creates a new table with the items read from the import source
+func (fd *Client) ImportTable(ctx context.Context, input *dynamodb.ImportTableInput, opts ...func(*dynamodb.Options)) (*dynamodb.ImportTableOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	params := input.TableCreationParameters
+	if params == nil {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "1 validation error detected: Value null at 'tableCreationParameters' failed to satisfy constraint: Member must not be null"}
+	}
+
+	tableName := aws.ToString(params.TableName)
+
+	if err := fd.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	importInput := core.ImportInput{
+		ClientToken: aws.ToString(input.ClientToken),
+		Format:      string(input.InputFormat),
+		Compression: string(input.InputCompressionType),
+	}
+
+	if input.S3BucketSource != nil {
+		importInput.S3Bucket = aws.ToString(input.S3BucketSource.S3Bucket)
+		importInput.S3KeyPrefix = aws.ToString(input.S3BucketSource.S3KeyPrefix)
+	}
+
+	if input.InputFormatOptions != nil && input.InputFormatOptions.Csv != nil {
+		importInput.CSVDelimiter = aws.ToString(input.InputFormatOptions.Csv.Delimiter)
+		importInput.CSVHeader = input.InputFormatOptions.Csv.HeaderList
+	}
+
+	if err := core.ValidateImportInput(&importInput); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if _, ok := fd.tables[tableName]; ok {
+		return nil, &types.ResourceInUseException{Message: aws.String("Table already exists: " + tableName)}
+	}
+
+	if _, err := fd.CreateTable(ctx, &dynamodb.CreateTableInput{
+		AttributeDefinitions:   params.AttributeDefinitions,
+		KeySchema:              params.KeySchema,
+		TableName:              params.TableName,
+		BillingMode:            params.BillingMode,
+		GlobalSecondaryIndexes: params.GlobalSecondaryIndexes,
+		ProvisionedThroughput:  params.ProvisionedThroughput,
+		OnDemandThroughput:     params.OnDemandThroughput,
+		SSESpecification:       params.SSESpecification,
+	}); err != nil {
+		return nil, err
+	}
+
+	now := fd.clock.Now()
+	importInput.ImportArn = fd.importArn(tableName, now)
+
+	imp := &tableImport{
+		Import:             fd.tables[tableName].Import(fd.importSource, importInput, now),
+		tableArn:           fd.tableArn(tableName),
+		bucketSource:       input.S3BucketSource,
+		formatOptions:      input.InputFormatOptions,
+		creationParameters: params,
+	}
+
+	// like DynamoDB, a failed import leaves no table behind
+	if imp.Status == core.ImportStatusFailed {
+		delete(fd.tables, tableName)
+	}
+
+	fd.imports[imp.ImportArn] = imp
+
+	return &dynamodb.ImportTableOutput{ImportTableDescription: fd.importDescription(imp)}, nil
+}
+
+// DescribeImport returns the description of a table import
+func (fd *Client) DescribeImport(ctx context.Context, input *dynamodb.DescribeImportInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeImportOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	if fd.forceFailureErr != nil {
+		return nil, fd.forceFailureErr
+	}
+
+	arn := aws.ToString(input.ImportArn)
+
+	imp, ok := fd.imports[arn]
+	if !ok {
+		return nil, &types.ImportNotFoundException{Message: aws.String("Import not found: " + arn)}
+	}
+
+	return &dynamodb.DescribeImportOutput{ImportTableDescription: fd.importDescription(imp)}, nil
+}
+
+// ListImports lists the table imports, oldest first
+func (fd *Client) ListImports(ctx context.Context, input *dynamodb.ListImportsInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListImportsOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	tableArn := aws.ToString(input.TableArn)
+	_, tableName, _ := strings.Cut(tableArn, ":table/")
+
+	if err := fd.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	pageSize := maxListImportsPageSize
+	if input.PageSize != nil {
+		if *input.PageSize < 1 || *input.PageSize > maxListImportsPageSize {
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'pageSize' failed to satisfy constraint: Member must have value between 1 and %d", *input.PageSize, maxListImportsPageSize)}
+		}
+
+		pageSize = int(*input.PageSize)
+	}
+
+	imports := []*tableImport{}
+
+	for _, imp := range fd.imports {
+		if tableArn != "" && imp.tableArn != tableArn {
+			continue
+		}
+
+		imports = append(imports, imp)
+	}
+
+	sort.Slice(imports, func(i, j int) bool {
+		if !imports[i].StartTime.Equal(imports[j].StartTime) {
+			return imports[i].StartTime.Before(imports[j].StartTime)
+		}
+
+		return imports[i].ImportArn < imports[j].ImportArn
+	})
+
+	start := 0
+
+	if token := aws.ToString(input.NextToken); token != "" {
+		for i, imp := range imports {
+			if imp.ImportArn == token {
+				start = i + 1
+
+				break
+			}
+		}
+	}
+
+	end := min(start+pageSize, len(imports))
+	output := &dynamodb.ListImportsOutput{ImportSummaryList: make([]types.ImportSummary, 0, end-start)}
+
+	for _, imp := range imports[start:end] {
+		output.ImportSummaryList = append(output.ImportSummaryList, types.ImportSummary{
+			ImportArn:             aws.String(imp.ImportArn),
+			ImportStatus:          types.ImportStatus(imp.Status),
+			TableArn:              aws.String(imp.tableArn),
+			S3BucketSource:        imp.bucketSource,
+			CloudWatchLogGroupArn: aws.String(fd.importLogGroupArn()),
+			InputFormat:           types.InputFormat(imp.Format),
+			StartTime:             aws.Time(imp.StartTime),
+			EndTime:               aws.Time(imp.EndTime),
+		})
+	}
+
+	if end < len(imports) {
+		output.NextToken = aws.String(imports[end-1].ImportArn)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func TestImports(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	SetClock(client, core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "25", Type: "electric", Name: "pikachu", Moves: []string{"thunder"}}))
+	c.NoError(createPokemon(client, pokemon{ID: "4", Type: "fire", Name: "charmander", Level: 5}))
+
+	_, err := client.UpdateContinuousBackups(ctx, &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodbtypes.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+
+	dir := t.TempDir()
+	SetExportSink(client, core.DirectoryExportSink(dir))
+
+	export, err := client.ExportTableToPointInTime(ctx, &dynamodb.ExportTableToPointInTimeInput{
+		TableArn:     aws.String("arn:aws:dynamodb:us-east-1:000000000000:table/" + tableName),
+		S3Bucket:     aws.String("exports"),
+		ExportFormat: dynamodbtypes.ExportFormatIon,
+	})
+	c.NoError(err)
+
+	input := &dynamodb.ImportTableInput{
+		S3BucketSource: &dynamodbtypes.S3BucketSource{
+			S3Bucket:    aws.String("exports"),
+			S3KeyPrefix: aws.String(path.Dir(aws.ToString(export.ExportDescription.ExportManifest)) + "/data/"),
+		},
+		InputFormat:          dynamodbtypes.InputFormatIon,
+		InputCompressionType: dynamodbtypes.InputCompressionTypeGzip,
+		TableCreationParameters: &dynamodbtypes.TableCreationParameters{
+			TableName:            aws.String("pokedex"),
+			AttributeDefinitions: []dynamodbtypes.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: dynamodbtypes.ScalarAttributeTypeS}},
+			KeySchema:            []dynamodbtypes.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: dynamodbtypes.KeyTypeHash}},
+			BillingMode:          dynamodbtypes.BillingModePayPerRequest,
+		},
+	}
+
+	failed, err := client.ImportTable(ctx, input)
+	c.NoError(err)
+	c.Equal(dynamodbtypes.ImportStatusFailed, failed.ImportTableDescription.ImportStatus)
+
+	SetImportSource(client, os.DirFS(dir))
+
+	imported, err := client.ImportTable(ctx, input)
+	c.NoError(err)
+
+	desc := imported.ImportTableDescription
+	c.Equal(dynamodbtypes.ImportStatusCompleted, desc.ImportStatus)
+	c.EqualValues(2, desc.ImportedItemCount)
+	c.Zero(desc.ErrorCount)
+	c.Empty(ImportErrors(client, aws.ToString(desc.ImportArn)))
+
+	original, err := getPokemon(client, "25")
+	c.NoError(err)
+
+	copied, err := client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String("pokedex"),
+		Key:       map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "25"}},
+	})
+	c.NoError(err)
+	c.Equal(original, copied.Item)
+
+	_, err = client.ImportTable(ctx, &dynamodb.ImportTableInput{S3BucketSource: input.S3BucketSource, InputFormat: dynamodbtypes.InputFormatIon})
+	c.ErrorContains(err, "tableCreationParameters")
+
+	_, err = client.ImportTable(ctx, input)
+
+	var inUseErr *dynamodbtypes.ResourceInUseException
+	c.True(errors.As(err, &inUseErr))
+
+	described, err := client.DescribeImport(ctx, &dynamodb.DescribeImportInput{ImportArn: desc.ImportArn})
+	c.NoError(err)
+	c.Equal(desc.ImportArn, described.ImportTableDescription.ImportArn)
+
+	_, err = client.DescribeImport(ctx, &dynamodb.DescribeImportInput{ImportArn: aws.String("unknown")})
+
+	var notFoundErr *dynamodbtypes.ImportNotFoundException
+	c.True(errors.As(err, &notFoundErr))
+
+	listed, err := client.ListImports(ctx, &dynamodb.ListImportsInput{TableArn: desc.TableArn})
+	c.NoError(err)
+	c.Len(listed.ImportSummaryList, 2)
+	c.Equal(dynamodbtypes.ImportStatusCompleted, listed.ImportSummaryList[1].ImportStatus)
+	c.Nil(listed.NextToken)
+
+	listed, err = client.ListImports(ctx, &dynamodb.ListImportsInput{TableArn: aws.String("arn:aws:dynamodb:us-east-1:000000000000:table/other")})
+	c.NoError(err)
+	c.Empty(listed.ImportSummaryList)
+}
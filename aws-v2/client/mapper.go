@@ -211,6 +211,7 @@ func mapDynamoToTypesPutItemInput(input *dynamodb.PutItemInput) *types.PutItemIn
 	return &types.PutItemInput{
 		ConditionExpression:         input.ConditionExpression,
 		ConditionalOperator:         toString(string(input.ConditionalOperator)),
+		Expected:                    mapDynamoToTypesExpectedAttributeValueMap(input.Expected),
 		ExpressionAttributeNames:    input.ExpressionAttributeNames,
 		ExpressionAttributeValues:   mapDynamoToTypesMapItem(input.ExpressionAttributeValues),
 		Item:                        mapDynamoToTypesMapItem(input.Item),
@@ -380,12 +381,17 @@ func mapDynamoToTypesTransactDelete(input *dynamodbtypes.Delete) *types.DeleteIt
 }
 
 func mapDynamoToTypesExpectedAttributeValue(input dynamodbtypes.ExpectedAttributeValue) *types.ExpectedAttributeValue {
-	return &types.ExpectedAttributeValue{
+	output := &types.ExpectedAttributeValue{
 		AttributeValueList: mapDynamoToTypesSliceItem(input.AttributeValueList),
 		ComparisonOperator: toString(string(input.ComparisonOperator)),
 		Exists:             input.Exists,
-		Value:              mapDynamoToTypesItem(input.Value),
 	}
+
+	if input.Value != nil {
+		output.Value = mapDynamoToTypesItem(input.Value)
+	}
+
+	return output
 }
 
 func mapDynamoToTypesExpectedAttributeValueMap(input map[string]dynamodbtypes.ExpectedAttributeValue) map[string]*types.ExpectedAttributeValue {
@@ -402,8 +408,44 @@ func mapDynamoToTypesExpectedAttributeValueMap(input map[string]dynamodbtypes.Ex
 	return output
 }
 
+func mapDynamoToTypesConditionMap(input map[string]dynamodbtypes.Condition) map[string]*types.Condition {
+	if len(input) == 0 {
+		return nil
+	}
+
+	output := map[string]*types.Condition{}
+
+	for key, condition := range input {
+		output[key] = &types.Condition{
+			AttributeValueList: mapDynamoToTypesSliceItem(condition.AttributeValueList),
+			ComparisonOperator: toString(string(condition.ComparisonOperator)),
+		}
+	}
+
+	return output
+}
+
+func mapDynamoToTypesAttributeValueUpdateMap(input map[string]dynamodbtypes.AttributeValueUpdate) map[string]*types.AttributeValueUpdate {
+	if len(input) == 0 {
+		return nil
+	}
+
+	output := map[string]*types.AttributeValueUpdate{}
+
+	for key, update := range input {
+		output[key] = &types.AttributeValueUpdate{Action: toString(string(update.Action))}
+
+		if update.Value != nil {
+			output[key].Value = mapDynamoToTypesItem(update.Value)
+		}
+	}
+
+	return output
+}
+
 func mapDynamoToTypesUpdateItemInput(input *dynamodb.UpdateItemInput) *types.UpdateItemInput {
 	return &types.UpdateItemInput{
+		AttributeUpdates:                    mapDynamoToTypesAttributeValueUpdateMap(input.AttributeUpdates),
 		ConditionExpression:                 input.ConditionExpression,
 		ConditionalOperator:                 toString(string(input.ConditionalOperator)),
 		Expected:                            mapDynamoToTypesExpectedAttributeValueMap(input.Expected),
@@ -414,7 +456,7 @@ func mapDynamoToTypesUpdateItemInput(input *dynamodb.UpdateItemInput) *types.Upd
 		ReturnItemCollectionMetrics:         toString(string(input.ReturnItemCollectionMetrics)),
 		ReturnValues:                        toString(string(input.ReturnValues)),
 		TableName:                           input.TableName,
-		UpdateExpression:                    *input.UpdateExpression,
+		UpdateExpression:                    aws.ToString(input.UpdateExpression),
 		ReturnValuesOnConditionCheckFailure: toString(string(input.ReturnValuesOnConditionCheckFailure)),
 	}
 }
@@ -425,6 +467,10 @@ func mapDynamoToTypesQueryInput(input *dynamodb.QueryInput, indexName string) co
 		ExpressionAttributeValues: mapDynamoToTypesMapItem(input.ExpressionAttributeValues),
 		Aliases:                   input.ExpressionAttributeNames,
 		ExclusiveStartKey:         mapDynamoToTypesMapItem(input.ExclusiveStartKey),
+		KeyConditions:             mapDynamoToTypesConditionMap(input.KeyConditions),
+		QueryFilter:               mapDynamoToTypesConditionMap(input.QueryFilter),
+		ConditionalOperator:       toString(string(input.ConditionalOperator)),
+		AttributesToGet:           input.AttributesToGet,
 	}
 
 	if input.Limit != nil {
@@ -457,13 +503,65 @@ func mapTypesToDynamoTableDescription(input *types.TableDescription) *dynamodbty
 		return nil
 	}
 
-	return &dynamodbtypes.TableDescription{
-		TableName:              toString(input.TableName),
-		ItemCount:              aws.Int64(input.ItemCount),
-		KeySchema:              mapTypesToDynamoKeySchemaElements(input.KeySchema),
-		GlobalSecondaryIndexes: mapTypesToDynamoTypesGlobalSecondaryIndexes(input.GlobalSecondaryIndexes),
-		LocalSecondaryIndexes:  mapTypesToDynamoLocalSecondaryIndexes(input.LocalSecondaryIndexes),
+	output := &dynamodbtypes.TableDescription{
+		TableName:                 toString(input.TableName),
+		TableArn:                  toString(input.TableArn),
+		TableStatus:               dynamodbtypes.TableStatus(input.TableStatus),
+		ItemCount:                 aws.Int64(input.ItemCount),
+		KeySchema:                 mapTypesToDynamoKeySchemaElements(input.KeySchema),
+		GlobalSecondaryIndexes:    mapTypesToDynamoTypesGlobalSecondaryIndexes(input.GlobalSecondaryIndexes),
+		LocalSecondaryIndexes:     mapTypesToDynamoLocalSecondaryIndexes(input.LocalSecondaryIndexes),
+		DeletionProtectionEnabled: aws.Bool(input.DeletionProtectionEnabled),
+		LatestStreamArn:           toString(input.LatestStreamArn),
+		LatestStreamLabel:         toString(input.LatestStreamLabel),
+	}
+
+	if input.BillingModeSummary != nil {
+		output.BillingModeSummary = &dynamodbtypes.BillingModeSummary{
+			BillingMode:                       dynamodbtypes.BillingMode(input.BillingModeSummary.BillingMode),
+			LastUpdateToPayPerRequestDateTime: input.BillingModeSummary.LastUpdateToPayPerRequestDateTime,
+		}
+	}
+
+	if input.TableClassSummary != nil {
+		output.TableClassSummary = &dynamodbtypes.TableClassSummary{
+			TableClass:         dynamodbtypes.TableClass(input.TableClassSummary.TableClass),
+			LastUpdateDateTime: input.TableClassSummary.LastUpdateDateTime,
+		}
+	}
+
+	if input.ProvisionedThroughput != nil {
+		output.ProvisionedThroughput = &dynamodbtypes.ProvisionedThroughputDescription{
+			NumberOfDecreasesToday: aws.Int64(input.ProvisionedThroughput.NumberOfDecreasesToday),
+			ReadCapacityUnits:      aws.Int64(input.ProvisionedThroughput.ReadCapacityUnits),
+			WriteCapacityUnits:     aws.Int64(input.ProvisionedThroughput.WriteCapacityUnits),
+		}
+	}
+
+	if input.OnDemandThroughput != nil {
+		output.OnDemandThroughput = &dynamodbtypes.OnDemandThroughput{
+			MaxReadRequestUnits:  aws.Int64(input.OnDemandThroughput.MaxReadRequestUnits),
+			MaxWriteRequestUnits: aws.Int64(input.OnDemandThroughput.MaxWriteRequestUnits),
+		}
 	}
+
+	if input.WarmThroughput != nil {
+		output.WarmThroughput = &dynamodbtypes.TableWarmThroughputDescription{
+			ReadUnitsPerSecond:  aws.Int64(input.WarmThroughput.ReadUnitsPerSecond),
+			WriteUnitsPerSecond: aws.Int64(input.WarmThroughput.WriteUnitsPerSecond),
+			Status:              dynamodbtypes.TableStatus(input.WarmThroughput.Status),
+		}
+	}
+
+	if input.SSEDescription != nil {
+		output.SSEDescription = &dynamodbtypes.SSEDescription{
+			KMSMasterKeyArn: toString(input.SSEDescription.KMSMasterKeyArn),
+			SSEType:         dynamodbtypes.SSEType(input.SSEDescription.SSEType),
+			Status:          dynamodbtypes.SSEStatus(input.SSEDescription.Status),
+		}
+	}
+
+	return output
 }
 
 func mapTypesToDynamoKeySchemaElements(input []types.KeySchemaElement) []dynamodbtypes.KeySchemaElement {
@@ -570,6 +668,7 @@ func mapTypesToDynamoTypesGlobalSecondaryIndexes(input []types.GlobalSecondaryIn
 func mapTypesToDynamoLocalSecondaryIndex(input types.LocalSecondaryIndexDescription) dynamodbtypes.LocalSecondaryIndexDescription {
 	return dynamodbtypes.LocalSecondaryIndexDescription{
 		IndexName:  input.IndexName,
+		IndexArn:   input.IndexArn,
 		KeySchema:  mapTypesToDynamoKeySchemaElements(input.KeySchema),
 		Projection: mapTypesToDynamoProjection(input.Projection),
 	}
@@ -708,6 +807,14 @@ func toString(str string) *string {
 	return aws.String(str)
 }
 
+func toInt64(i *int32) *int64 {
+	if i == nil {
+		return nil
+	}
+
+	return new(int64(*i))
+}
+
 func mapKnownError(err error) error {
 	intErr, ok := errors.AsType[types.Error](err)
 	if !ok {
@@ -727,6 +834,16 @@ func mapKnownError(err error) error {
 		return checkErr
 	case "ResourceNotFoundException":
 		return &dynamodbtypes.ResourceNotFoundException{Message: aws.String(intErr.Message())}
+	case "DuplicateItemException":
+		return &dynamodbtypes.DuplicateItemException{Message: aws.String(intErr.Message())}
+	case "IdempotentParameterMismatchException":
+		return &dynamodbtypes.IdempotentParameterMismatchException{Message: aws.String(intErr.Message())}
+	case "PointInTimeRecoveryUnavailableException":
+		return &dynamodbtypes.PointInTimeRecoveryUnavailableException{Message: aws.String(intErr.Message())}
+	case "InvalidRestoreTimeException":
+		return &dynamodbtypes.InvalidRestoreTimeException{Message: aws.String(intErr.Message())}
+	case "InvalidExportTimeException":
+		return &dynamodbtypes.InvalidExportTimeException{Message: aws.String(intErr.Message())}
 	default:
 		return &smithy.GenericAPIError{Code: intErr.Code(), Message: intErr.Message()}
 	}
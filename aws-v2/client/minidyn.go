@@ -188,6 +188,42 @@ func SetIndexActivationDelay(client FakeClient, delay time.Duration) {
 	fakeClient.setIndexActivationDelay(delay)
 }
 
+// SetTableStatusDelay configures how long tables report CREATING, UPDATING or DELETING
+// before the change completes. Data-plane calls fail with ResourceNotFoundException
+// while a table is CREATING, and UpdateTable and DeleteTable fail with
+// ResourceInUseException until the table is ACTIVE again.
+func SetTableStatusDelay(client FakeClient, delay time.Duration) {
+	fakeClient, ok := client.(*Client)
+	if !ok {
+		panic("SetTableStatusDelay: invalid client type")
+	}
+
+	fakeClient.setTableStatusDelay(delay)
+}
+
+// SetClientRequestTokenTTL configures how long the ClientRequestToken of
+// TransactWriteItems and ExecuteTransaction keeps a request idempotent, 10 minutes by
+// default. The window follows the clock given to SetClock.
+func SetClientRequestTokenTTL(client FakeClient, ttl time.Duration) {
+	fakeClient, ok := client.(*Client)
+	if !ok {
+		panic("SetClientRequestTokenTTL: invalid client type")
+	}
+
+	fakeClient.setClientRequestTokenTTL(ttl)
+}
+
+// SetAccount sets the region and the account ID of the ARNs built by the client, which
+// default to us-east-1 and 000000000000. The ARNs of the existing tables change too.
+func SetAccount(client FakeClient, region, accountID string) {
+	fakeClient, ok := client.(*Client)
+	if !ok {
+		panic("SetAccount: invalid client type")
+	}
+
+	fakeClient.setAccount(region, accountID)
+}
+
 // ClearTable removes all data from a specific table
 func ClearTable(client FakeClient, tableName string) error {
 	fakeClient, ok := client.(*Client)
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+// getBackupTable resolves the table of a continuous backups request, callers must hold fd.mu
+func (fd *Client) getBackupTable(tableName string) (*core.Table, error) {
+	table, ok := fd.tables[tableName]
+	if !ok {
+		return nil, &types.TableNotFoundException{Message: aws.String("Table not found: " + tableName)}
+	}
+
+	fd.sweepOnAccess(table)
+
+	return table, nil
+}
+
+func (fd *Client) continuousBackupsDescription(table *core.Table) *types.ContinuousBackupsDescription {
+	pitr := table.PointInTimeRecovery(fd.clock.Now())
+
+	desc := &types.ContinuousBackupsDescription{
+		ContinuousBackupsStatus:        types.ContinuousBackupsStatusEnabled,
+		PointInTimeRecoveryDescription: &types.PointInTimeRecoveryDescription{PointInTimeRecoveryStatus: types.PointInTimeRecoveryStatusDisabled},
+	}
+
+	if pitr.Enabled {
+		desc.PointInTimeRecoveryDescription = &types.PointInTimeRecoveryDescription{
+			PointInTimeRecoveryStatus:  types.PointInTimeRecoveryStatusEnabled,
+			RecoveryPeriodInDays:       aws.Int32(int32(pitr.RecoveryPeriodInDays)), //nolint:gosec // at most 35 days
+			EarliestRestorableDateTime: aws.Time(pitr.EarliestRestorableDateTime),
+			LatestRestorableDateTime:   aws.Time(pitr.LatestRestorableDateTime),
+		}
+	}
+
+	return desc
+}
+
+// UpdateContinuousBackups enables or disables the point in time recovery of a table
+func (fd *Client) UpdateContinuousBackups(ctx context.Context, input *dynamodb.UpdateContinuousBackupsInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateContinuousBackupsOutput, error) {
+	fd.mu.Lock()
+	defer fd.publishChanges()
+	defer fd.mu.Unlock()
+
+	tableName := aws.ToString(input.TableName)
+
+	if err := fd.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	spec := input.PointInTimeRecoverySpecification
+	if spec == nil || spec.PointInTimeRecoveryEnabled == nil {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "1 validation error detected: Value null at 'pointInTimeRecoverySpecification.pointInTimeRecoveryEnabled' failed to satisfy constraint: Member must not be null"}
+	}
+
+	table, err := fd.getBackupTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !aws.ToBool(spec.PointInTimeRecoveryEnabled) {
+		table.DisablePointInTimeRecovery()
+	} else {
+		days := core.DefaultRecoveryPeriodInDays
+		if spec.RecoveryPeriodInDays != nil {
+			days = int(*spec.RecoveryPeriodInDays)
+		}
+
+		if err := table.EnablePointInTimeRecovery(days, fd.clock.Now()); err != nil {
+			return nil, mapKnownError(err)
+		}
+	}
+
+	return &dynamodb.UpdateContinuousBackupsOutput{ContinuousBackupsDescription: fd.continuousBackupsDescription(table)}, nil
+}
+
+// DescribeContinuousBackups returns the point in time recovery settings of a table
+func (fd *Client) DescribeContinuousBackups(ctx context.Context, input *dynamodb.DescribeContinuousBackupsInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeContinuousBackupsOutput, error) {
+	fd.mu.Lock()
+	defer fd.publishChanges()
+	defer fd.mu.Unlock()
+
+	tableName := aws.ToString(input.TableName)
+
+	if err := fd.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	table, err := fd.getBackupTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.DescribeContinuousBackupsOutput{ContinuousBackupsDescription: fd.continuousBackupsDescription(table)}, nil
+}
+
+// RestoreTableToPointInTime creates a new table with the items a table had at a point in time
+func (fd *Client) RestoreTableToPointInTime(ctx context.Context, input *dynamodb.RestoreTableToPointInTimeInput, opts ...func(*dynamodb.Options)) (*dynamodb.RestoreTableToPointInTimeOutput, error) {
+	fd.mu.Lock()
+	defer fd.publishChanges()
+	defer fd.mu.Unlock()
+
+	targetName := aws.ToString(input.TargetTableName)
+
+	if err := fd.failureErrFor(targetName, ""); err != nil {
+		return nil, err
+	}
+
+	sourceName := aws.ToString(input.SourceTableName)
+	if arn := aws.ToString(input.SourceTableArn); arn != "" {
+		_, sourceName, _ = strings.Cut(arn, ":table/")
+	}
+
+	if sourceName == "" {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "Either SourceTableName or SourceTableArn must be specified"}
+	}
+
+	source, err := fd.getBackupTable(sourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := fd.clock.Now()
+	restoreTime := now
+
+	if !aws.ToBool(input.UseLatestRestorableTime) {
+		if input.RestoreDateTime == nil {
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "RestoreDateTime must be specified when UseLatestRestorableTime is not true"}
+		}
+
+		restoreTime = *input.RestoreDateTime
+	}
+
+	backup, err := source.BackupAt(restoreTime, now)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	table, err := fd.restoreTable(targetName, backup, restoreOverrides(input.BillingModeOverride, input.GlobalSecondaryIndexOverride, input.LocalSecondaryIndexOverride), &core.RestoreSummary{
+		SourceTableArn:  fd.tableArn(sourceName),
+		RestoreDateTime: restoreTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.RestoreTableToPointInTimeOutput{TableDescription: fd.tableDescription(targetName, table)}, nil
+}
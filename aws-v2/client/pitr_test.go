@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func TestPointInTimeRecovery(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	SetClock(client, clock)
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "25", Type: "electric", Name: "pikachu"}))
+
+	_, err := client.UpdateContinuousBackups(ctx, &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodbtypes.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true), RecoveryPeriodInDays: aws.Int32(40)},
+	})
+
+	var apiErr smithy.APIError
+	c.True(errors.As(err, &apiErr))
+	c.Equal("ValidationException", apiErr.ErrorCode())
+
+	updated, err := client.UpdateContinuousBackups(ctx, &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodbtypes.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+
+	pitr := updated.ContinuousBackupsDescription.PointInTimeRecoveryDescription
+	c.Equal(dynamodbtypes.PointInTimeRecoveryStatusEnabled, pitr.PointInTimeRecoveryStatus)
+	c.EqualValues(core.DefaultRecoveryPeriodInDays, aws.ToInt32(pitr.RecoveryPeriodInDays))
+
+	clock.Advance(time.Minute)
+	healthy := clock.Now()
+	clock.Advance(time.Minute)
+
+	c.NoError(createPokemon(client, pokemon{ID: "25", Type: "corrupted", Name: "pikachu"}))
+	c.NoError(createPokemon(client, pokemon{ID: "4", Type: "fire", Name: "charmander"}))
+
+	clock.Advance(time.Minute)
+
+	described, err := client.DescribeContinuousBackups(ctx, &dynamodb.DescribeContinuousBackupsInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(clock.Now(), aws.ToTime(described.ContinuousBackupsDescription.PointInTimeRecoveryDescription.LatestRestorableDateTime))
+
+	restored, err := client.RestoreTableToPointInTime(ctx, &dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName: aws.String(tableName),
+		TargetTableName: aws.String("pokemons-healthy"),
+		RestoreDateTime: aws.Time(healthy),
+	})
+	c.NoError(err)
+	c.EqualValues(1, aws.ToInt64(restored.TableDescription.ItemCount))
+	c.Equal(healthy, aws.ToTime(restored.TableDescription.RestoreSummary.RestoreDateTime))
+
+	item, err := client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String("pokemons-healthy"),
+		Key:       map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "25"}},
+	})
+	c.NoError(err)
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "electric"}, item.Item["type"])
+
+	_, err = client.RestoreTableToPointInTime(ctx, &dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName: aws.String(tableName),
+		TargetTableName: aws.String("pokemons-future"),
+		RestoreDateTime: aws.Time(clock.Now().Add(time.Hour)),
+	})
+
+	var restoreTimeErr *dynamodbtypes.InvalidRestoreTimeException
+	c.True(errors.As(err, &restoreTimeErr))
+
+	_, err = client.RestoreTableToPointInTime(ctx, &dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName:         aws.String(tableName),
+		TargetTableName:         aws.String("pokemons-healthy"),
+		UseLatestRestorableTime: aws.Bool(true),
+	})
+
+	var existsErr *dynamodbtypes.TableAlreadyExistsException
+	c.True(errors.As(err, &existsErr))
+
+	_, err = client.UpdateContinuousBackups(ctx, &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodbtypes.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(false)},
+	})
+	c.NoError(err)
+
+	_, err = client.RestoreTableToPointInTime(ctx, &dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName:         aws.String(tableName),
+		TargetTableName:         aws.String("pokemons-latest"),
+		UseLatestRestorableTime: aws.Bool(true),
+	})
+
+	var unavailableErr *dynamodbtypes.PointInTimeRecoveryUnavailableException
+	c.True(errors.As(err, &unavailableErr))
+}
@@ -0,0 +1,208 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func TestTableSettings(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	SetClock(client, clock)
+
+	c.NoError(ensurePokemonTable(client))
+
+	described, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.False(aws.ToBool(described.Table.DeletionProtectionEnabled))
+	c.Equal(dynamodbtypes.BillingModePayPerRequest, described.Table.BillingModeSummary.BillingMode)
+	c.Zero(aws.ToInt64(described.Table.ProvisionedThroughput.ReadCapacityUnits))
+	c.Nil(described.Table.SSEDescription)
+
+	provisioned := &dynamodb.UpdateTableInput{
+		TableName:             aws.String(tableName),
+		BillingMode:           dynamodbtypes.BillingModeProvisioned,
+		ProvisionedThroughput: &dynamodbtypes.ProvisionedThroughput{ReadCapacityUnits: aws.Int64(10), WriteCapacityUnits: aws.Int64(5)},
+	}
+	onDemand := &dynamodb.UpdateTableInput{TableName: aws.String(tableName), BillingMode: dynamodbtypes.BillingModePayPerRequest}
+
+	for range 4 {
+		_, err = client.UpdateTable(ctx, provisioned)
+		c.NoError(err)
+
+		_, err = client.UpdateTable(ctx, onDemand)
+		c.NoError(err)
+	}
+
+	_, err = client.UpdateTable(ctx, provisioned)
+	c.NoError(err)
+
+	_, err = client.UpdateTable(ctx, onDemand)
+
+	var apiErr smithy.APIError
+	c.True(errors.As(err, &apiErr))
+	c.Equal("LimitExceededException", apiErr.ErrorCode())
+
+	_, err = client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:                 aws.String(tableName),
+		DeletionProtectionEnabled: aws.Bool(true),
+		WarmThroughput:            &dynamodbtypes.WarmThroughput{ReadUnitsPerSecond: aws.Int64(15000), WriteUnitsPerSecond: aws.Int64(5000)},
+		SSESpecification:          &dynamodbtypes.SSESpecification{Enabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+
+	described, err = client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.True(aws.ToBool(described.Table.DeletionProtectionEnabled))
+	c.Equal(dynamodbtypes.BillingModeProvisioned, described.Table.BillingModeSummary.BillingMode)
+	c.Equal(clock.Now(), aws.ToTime(described.Table.BillingModeSummary.LastUpdateToPayPerRequestDateTime))
+	c.Equal(int64(10), aws.ToInt64(described.Table.ProvisionedThroughput.ReadCapacityUnits))
+	c.Equal(int64(15000), aws.ToInt64(described.Table.WarmThroughput.ReadUnitsPerSecond))
+	c.Equal("arn:aws:kms:us-east-1:000000000000:alias/aws/dynamodb", aws.ToString(described.Table.SSEDescription.KMSMasterKeyArn))
+
+	_, err = client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:             aws.String(tableName),
+		ProvisionedThroughput: &dynamodbtypes.ProvisionedThroughput{ReadCapacityUnits: aws.Int64(10), WriteCapacityUnits: aws.Int64(5)},
+	})
+	c.ErrorContains(err, "will not change")
+
+	_, err = client.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+	c.ErrorContains(err, "protected against deletion")
+
+	clock.Advance(24 * time.Hour)
+
+	_, err = client.UpdateTable(ctx, onDemand)
+	c.NoError(err)
+}
+
+func TestUpdateTableFailureKeepsSettings(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	SetClock(client, clock)
+
+	c.NoError(ensurePokemonTable(client))
+
+	_, err := client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:             aws.String(tableName),
+		BillingMode:           dynamodbtypes.BillingModeProvisioned,
+		ProvisionedThroughput: &dynamodbtypes.ProvisionedThroughput{ReadCapacityUnits: aws.Int64(10), WriteCapacityUnits: aws.Int64(5)},
+	})
+	c.NoError(err)
+
+	_, err = client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:                 aws.String(tableName),
+		BillingMode:               dynamodbtypes.BillingModePayPerRequest,
+		DeletionProtectionEnabled: aws.Bool(true),
+		GlobalSecondaryIndexUpdates: []dynamodbtypes.GlobalSecondaryIndexUpdate{{Create: &dynamodbtypes.CreateGlobalSecondaryIndexAction{
+			IndexName:  aws.String("by-region"),
+			KeySchema:  []dynamodbtypes.KeySchemaElement{{AttributeName: aws.String("region"), KeyType: dynamodbtypes.KeyTypeHash}},
+			Projection: &dynamodbtypes.Projection{ProjectionType: dynamodbtypes.ProjectionTypeAll},
+		}}},
+	})
+
+	var apiErr smithy.APIError
+	c.True(errors.As(err, &apiErr))
+	c.Equal("ValidationException", apiErr.ErrorCode())
+
+	described, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.BillingModeProvisioned, described.Table.BillingModeSummary.BillingMode)
+	c.Nil(described.Table.BillingModeSummary.LastUpdateToPayPerRequestDateTime)
+	c.False(aws.ToBool(described.Table.DeletionProtectionEnabled))
+	c.Equal(int64(10), aws.ToInt64(described.Table.ProvisionedThroughput.ReadCapacityUnits))
+	c.Empty(described.Table.GlobalSecondaryIndexes)
+
+	// the failed switch to on-demand is not counted toward the daily limit
+	for range 4 {
+		_, err = client.UpdateTable(ctx, &dynamodb.UpdateTableInput{TableName: aws.String(tableName), BillingMode: dynamodbtypes.BillingModePayPerRequest})
+		c.NoError(err)
+
+		_, err = client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+			TableName:             aws.String(tableName),
+			BillingMode:           dynamodbtypes.BillingModeProvisioned,
+			ProvisionedThroughput: &dynamodbtypes.ProvisionedThroughput{ReadCapacityUnits: aws.Int64(10), WriteCapacityUnits: aws.Int64(5)},
+		})
+		c.NoError(err)
+	}
+}
+
+func TestUpdateTableReplicaUpdates(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+
+	inputs := []*dynamodb.UpdateTableInput{
+		{
+			TableName:      aws.String(tableName),
+			ReplicaUpdates: []dynamodbtypes.ReplicationGroupUpdate{{Create: &dynamodbtypes.CreateReplicationGroupMemberAction{RegionName: aws.String("eu-west-1")}}},
+		},
+		{TableName: aws.String(tableName), MultiRegionConsistency: dynamodbtypes.MultiRegionConsistencyStrong},
+	}
+
+	for _, input := range inputs {
+		_, err := client.UpdateTable(ctx, input)
+
+		var apiErr smithy.APIError
+		c.True(errors.As(err, &apiErr))
+		c.Equal("ValidationException", apiErr.ErrorCode())
+		c.ErrorContains(err, "use the HTTP server to simulate global tables")
+	}
+
+	described, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.TableStatusActive, described.Table.TableStatus)
+	c.Empty(described.Table.Replicas)
+}
+
+func TestUpdateTableFailureKeepsIndexes(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+
+	createRegionIndex := dynamodbtypes.GlobalSecondaryIndexUpdate{Create: &dynamodbtypes.CreateGlobalSecondaryIndexAction{
+		IndexName:             aws.String("by-region"),
+		KeySchema:             []dynamodbtypes.KeySchemaElement{{AttributeName: aws.String("region"), KeyType: dynamodbtypes.KeyTypeHash}},
+		Projection:            &dynamodbtypes.Projection{ProjectionType: dynamodbtypes.ProjectionTypeAll},
+		ProvisionedThroughput: &dynamodbtypes.ProvisionedThroughput{ReadCapacityUnits: aws.Int64(1), WriteCapacityUnits: aws.Int64(1)},
+	}}
+
+	_, err := client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:            aws.String(tableName),
+		AttributeDefinitions: []dynamodbtypes.AttributeDefinition{{AttributeName: aws.String("region"), AttributeType: dynamodbtypes.ScalarAttributeTypeS}},
+		GlobalSecondaryIndexUpdates: []dynamodbtypes.GlobalSecondaryIndexUpdate{
+			createRegionIndex,
+			{Delete: &dynamodbtypes.DeleteGlobalSecondaryIndexAction{IndexName: aws.String("missing")}},
+		},
+	})
+
+	var notFound *dynamodbtypes.ResourceNotFoundException
+	c.ErrorAs(err, &notFound)
+
+	described, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Empty(described.Table.GlobalSecondaryIndexes)
+
+	// the attribute definitions of the failed request are gone as well
+	_, err = client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:                   aws.String(tableName),
+		GlobalSecondaryIndexUpdates: []dynamodbtypes.GlobalSecondaryIndexUpdate{createRegionIndex},
+	})
+	c.Error(err)
+}
@@ -0,0 +1,414 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+	"github.com/truora/minidyn/interpreter/partiql"
+	mtypes "github.com/truora/minidyn/types"
+)
+
+const (
+	batchExecuteStatementLimit = 25
+	executeTransactionLimit    = 100
+)
+
+// ExecuteStatement runs a PartiQL statement
+func (fd *Client) ExecuteStatement(ctx context.Context, input *dynamodb.ExecuteStatementInput, opts ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+	fd.mu.Lock()
+	defer fd.publishChanges()
+	defer fd.mu.Unlock()
+
+	if input.Statement == nil {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "1 validation error detected: Value null at 'statement' failed to satisfy constraint: Member must not be null"}
+	}
+
+	if input.Limit != nil && *input.Limit < 1 {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value greater than or equal to 1", *input.Limit)}
+	}
+
+	stmt, err := partiql.Parse(aws.ToString(input.Statement), len(input.Parameters))
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if ferr := fd.failureErrFor(stmt.TableName(), ""); ferr != nil {
+		return nil, ferr
+	}
+
+	table, err := fd.getTable(stmt.TableName())
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	out, err := table.ExecuteStatement(stmt, core.StatementInput{
+		Parameters: mapDynamoToTypesSliceItem(input.Parameters),
+		Limit:      int64(aws.ToInt32(input.Limit)),
+		NextToken:  aws.ToString(input.NextToken),
+	})
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	output := &dynamodb.ExecuteStatementOutput{
+		Items:            mapTypesToDynamoSliceMapItem(out.Items),
+		LastEvaluatedKey: mapTypesToDynamoMapItem(out.LastEvaluatedKey),
+	}
+
+	if out.NextToken != "" {
+		output.NextToken = aws.String(out.NextToken)
+	}
+
+	return output, nil
+}
+
+// BatchExecuteStatement runs up to 25 PartiQL statements, either all reads or all writes. A
+// statement that fails reports its error in its response without stopping the rest of the batch
+func (fd *Client) BatchExecuteStatement(ctx context.Context, input *dynamodb.BatchExecuteStatementInput, opts ...func(*dynamodb.Options)) (*dynamodb.BatchExecuteStatementOutput, error) {
+	fd.mu.Lock()
+	defer fd.publishChanges()
+	defer fd.mu.Unlock()
+
+	if err := validateStatementCount("statements", len(input.Statements), batchExecuteStatementLimit); err != nil {
+		return nil, err
+	}
+
+	if err := validateBatchReads(input.Statements); err != nil {
+		return nil, err
+	}
+
+	if fd.forceFailureErr != nil {
+		return nil, fd.forceFailureErr
+	}
+
+	responses := make([]types.BatchStatementResponse, len(input.Statements))
+
+	for i, req := range input.Statements {
+		resp, err := fd.runBatchStatement(req)
+		if err != nil {
+			return nil, err
+		}
+
+		responses[i] = resp
+	}
+
+	return &dynamodb.BatchExecuteStatementOutput{Responses: responses}, nil
+}
+
+func (fd *Client) runBatchStatement(req types.BatchStatementRequest) (types.BatchStatementResponse, error) {
+	stmt, err := partiql.Parse(aws.ToString(req.Statement), len(req.Parameters))
+	if err != nil {
+		return types.BatchStatementResponse{Error: newBatchStatementError(err)}, nil
+	}
+
+	resp := types.BatchStatementResponse{TableName: aws.String(stmt.TableName())}
+
+	if ferr := fd.failureErrFor(stmt.TableName(), ""); ferr != nil {
+		return resp, ferr
+	}
+
+	table, err := fd.getTable(stmt.TableName())
+	if err != nil {
+		resp.Error = newBatchStatementError(err)
+
+		return resp, nil
+	}
+
+	params := mapDynamoToTypesSliceItem(req.Parameters)
+
+	if _, err := table.StatementItemKey(stmt, params); err != nil {
+		resp.Error = newBatchStatementError(err)
+
+		return resp, nil
+	}
+
+	out, err := table.ExecuteStatement(stmt, core.StatementInput{Parameters: params})
+	if err != nil {
+		resp.Error = newBatchStatementError(err)
+
+		return resp, nil
+	}
+
+	if _, ok := stmt.(*partiql.SelectStatement); ok && len(out.Items) > 0 {
+		resp.Item = mapTypesToDynamoMapItem(out.Items[0])
+	}
+
+	return resp, nil
+}
+
+// validateBatchReads rejects batches mixing reads and writes, statements that do not parse
+// are left to report their error in their own response
+func validateBatchReads(reqs []types.BatchStatementRequest) error {
+	reads, writes := 0, 0
+
+	for _, req := range reqs {
+		stmt, err := partiql.Parse(aws.ToString(req.Statement), len(req.Parameters))
+		if err != nil {
+			continue
+		}
+
+		if _, ok := stmt.(*partiql.SelectStatement); ok {
+			reads++
+		} else {
+			writes++
+		}
+	}
+
+	if reads > 0 && writes > 0 {
+		return &smithy.GenericAPIError{Code: "ValidationException", Message: "The entire batch must consist of either read statements or write statements"}
+	}
+
+	return nil
+}
+
+func newBatchStatementError(err error) *types.BatchStatementError {
+	if notFound, ok := errors.AsType[*types.ResourceNotFoundException](err); ok {
+		return &types.BatchStatementError{Code: types.BatchStatementErrorCodeEnumResourceNotFound, Message: notFound.Message}
+	}
+
+	intErr, ok := errors.AsType[mtypes.Error](err)
+	if !ok {
+		return &types.BatchStatementError{Code: types.BatchStatementErrorCodeEnumInternalServerError, Message: aws.String(err.Error())}
+	}
+
+	code := types.BatchStatementErrorCodeEnumValidationError
+
+	switch intErr.Code() {
+	case "ConditionalCheckFailedException":
+		code = types.BatchStatementErrorCodeEnumConditionalCheckFailed
+	case "DuplicateItemException":
+		code = types.BatchStatementErrorCodeEnumDuplicateItem
+	case "ResourceNotFoundException":
+		code = types.BatchStatementErrorCodeEnumResourceNotFound
+	case "InternalServerError":
+		code = types.BatchStatementErrorCodeEnumInternalServerError
+	}
+
+	return &types.BatchStatementError{Code: code, Message: aws.String(intErr.Message())}
+}
+
+// ExecuteTransaction runs up to 100 PartiQL statements as a single all-or-nothing transaction.
+// Transactions either read items with SELECT or write them with INSERT, UPDATE, DELETE and
+// EXISTS conditions. Retries with the same ClientRequestToken return the first outcome
+func (fd *Client) ExecuteTransaction(ctx context.Context, input *dynamodb.ExecuteTransactionInput, opts ...func(*dynamodb.Options)) (*dynamodb.ExecuteTransactionOutput, error) {
+	fd.mu.Lock()
+	defer fd.publishChanges()
+	defer fd.mu.Unlock()
+
+	if err := validateStatementCount("transactStatements", len(input.TransactStatements), executeTransactionLimit); err != nil {
+		return nil, err
+	}
+
+	if fd.forceFailureErr != nil {
+		return nil, fd.forceFailureErr
+	}
+
+	token := aws.ToString(input.ClientRequestToken)
+
+	var fingerprint string
+
+	if input.ClientRequestToken != nil {
+		fingerprint = core.RequestFingerprint(transactStatementsFingerprint(input.TransactStatements))
+
+		if err := core.ValidateClientRequestToken(token); err != nil {
+			return nil, mapKnownError(err)
+		}
+
+		out, ok, err := fd.transactionTokens.Lookup(token, fingerprint, fd.clock.Now())
+		if err != nil {
+			return nil, mapKnownError(err)
+		}
+
+		if ok {
+			return out, nil
+		}
+	}
+
+	statements, read, err := fd.prepareTransactStatements(input.TransactStatements)
+	if err != nil {
+		return nil, err
+	}
+
+	var out *dynamodb.ExecuteTransactionOutput
+
+	if read {
+		out, err = fd.runReadTransaction(statements)
+	} else {
+		out, err = fd.runWriteTransaction(statements)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if input.ClientRequestToken != nil {
+		fd.transactionTokens.Store(token, fingerprint, out, fd.clock.Now())
+	}
+
+	return out, nil
+}
+
+// transactStatementsFingerprint converts the statements to minidyn types, the SDK attribute
+// value interfaces lose their member type when encoded
+func transactStatementsFingerprint(reqs []types.ParameterizedStatement) any {
+	type statement struct {
+		Statement  string
+		Parameters []*mtypes.Item
+	}
+
+	statements := make([]statement, len(reqs))
+	for i, req := range reqs {
+		statements[i] = statement{Statement: aws.ToString(req.Statement), Parameters: mapDynamoToTypesSliceItem(req.Parameters)}
+	}
+
+	return statements
+}
+
+type transactStatement struct {
+	stmt   partiql.Statement
+	table  *core.Table
+	params []*mtypes.Item
+}
+
+// prepareTransactStatements parses the statements of a transaction and checks all of them
+// target a single item, it reports whether the transaction only reads
+func (fd *Client) prepareTransactStatements(reqs []types.ParameterizedStatement) ([]transactStatement, bool, error) {
+	statements := make([]transactStatement, 0, len(reqs))
+	seenKeys := make(map[string]struct{}, len(reqs))
+	reads := 0
+
+	for _, req := range reqs {
+		stmt, err := partiql.Parse(aws.ToString(req.Statement), len(req.Parameters))
+		if err != nil {
+			return nil, false, mapKnownError(err)
+		}
+
+		if ferr := fd.failureErrFor(stmt.TableName(), ""); ferr != nil {
+			return nil, false, ferr
+		}
+
+		table, err := fd.lookupTable(stmt.TableName())
+		if err != nil {
+			return nil, false, mapKnownError(err)
+		}
+
+		params := mapDynamoToTypesSliceItem(req.Parameters)
+
+		key, err := table.StatementItemKey(stmt, params)
+		if err != nil {
+			return nil, false, mapKnownError(err)
+		}
+
+		if err := markTransactKey(seenKeys, stmt.TableName(), key); err != nil {
+			return nil, false, err
+		}
+
+		if _, ok := stmt.(*partiql.SelectStatement); ok {
+			reads++
+		}
+
+		statements = append(statements, transactStatement{stmt: stmt, table: table, params: params})
+	}
+
+	if reads > 0 && reads < len(statements) {
+		return nil, false, &smithy.GenericAPIError{Code: "ValidationException", Message: "Transactions must only contain read statements or only write statements"}
+	}
+
+	return statements, reads > 0, nil
+}
+
+func (fd *Client) runReadTransaction(statements []transactStatement) (*dynamodb.ExecuteTransactionOutput, error) {
+	responses := make([]types.ItemResponse, len(statements))
+
+	for i, ts := range statements {
+		out, err := ts.table.ExecuteStatement(ts.stmt, core.StatementInput{Parameters: ts.params})
+		if err != nil {
+			return nil, mapKnownError(err)
+		}
+
+		if len(out.Items) > 0 {
+			responses[i].Item = mapTypesToDynamoMapItem(out.Items[0])
+		}
+	}
+
+	return &dynamodb.ExecuteTransactionOutput{Responses: responses}, nil
+}
+
+func (fd *Client) runWriteTransaction(statements []transactStatement) (*dynamodb.ExecuteTransactionOutput, error) {
+	snapshots := map[string]core.TableSnapshot{}
+
+	for _, ts := range statements {
+		if _, err := fd.snapshotTransactTable(snapshots, ts.stmt.TableName()); err != nil {
+			return nil, err
+		}
+	}
+
+	published := len(fd.pendingChanges)
+	n := len(statements)
+
+	for i, ts := range statements {
+		var err error
+
+		if exists, ok := ts.stmt.(*partiql.ExistsStatement); ok {
+			err = ts.table.CheckExists(exists, core.StatementInput{Parameters: ts.params})
+		} else {
+			_, err = ts.table.ExecuteStatement(ts.stmt, core.StatementInput{Parameters: ts.params})
+		}
+
+		if err != nil {
+			fd.restoreSnapshots(snapshots, published)
+
+			return nil, newStatementTransactionCancelledError(i, n, err)
+		}
+	}
+
+	return &dynamodb.ExecuteTransactionOutput{}, nil
+}
+
+// newStatementTransactionCancelledError extends newTransactionCancelledError with the
+// duplicate item reason of INSERT statements
+func newStatementTransactionCancelledError(i, n int, opErr error) error {
+	intErr, ok := errors.AsType[mtypes.Error](opErr)
+	if !ok || intErr.Code() != "DuplicateItemException" {
+		return newTransactionCancelledError(i, n, opErr)
+	}
+
+	reasons := make([]types.CancellationReason, n)
+	for j := range reasons {
+		reasons[j] = types.CancellationReason{Code: aws.String("None")}
+	}
+
+	reasons[i] = types.CancellationReason{
+		Code:    aws.String("DuplicateItem"),
+		Message: aws.String(intErr.Message()),
+	}
+
+	return &types.TransactionCanceledException{
+		Message:             aws.String("Transaction cancelled, please refer cancellation reasons for specific reasons [DuplicateItem]"),
+		CancellationReasons: reasons,
+	}
+}
+
+func validateStatementCount(member string, count, limit int) error {
+	var constraint string
+
+	switch {
+	case count == 0:
+		constraint = "Member must have length greater than or equal to 1"
+	case count > limit:
+		constraint = fmt.Sprintf("Member must have length less than or equal to %d", limit)
+	default:
+		return nil
+	}
+
+	return &smithy.GenericAPIError{
+		Code:    "ValidationException",
+		Message: fmt.Sprintf("1 validation error detected: Value at '%s' failed to satisfy constraint: %s", member, constraint),
+	}
+}
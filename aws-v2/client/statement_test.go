@@ -0,0 +1,297 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteStatementSelect(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(ensurePokemonTypeIndex(client))
+
+	for _, p := range []pokemon{
+		{ID: "001", Type: "grass", Name: "Bulbasaur", Level: 5, Moves: []string{"tackle", "growl"}},
+		{ID: "002", Type: "grass", Name: "Ivysaur", Level: 16},
+		{ID: "004", Type: "fire", Name: "Charmander", Level: 5},
+		{ID: "007", Type: "water", Name: "Squirtle", Level: 5},
+	} {
+		c.NoError(createPokemon(client, p))
+	}
+
+	out, err := client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement:  aws.String(`SELECT name, lvl FROM pokemons WHERE id = ?`),
+		Parameters: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "001"}},
+	})
+	c.NoError(err)
+	c.Equal([]map[string]dynamodbtypes.AttributeValue{{
+		"name": &dynamodbtypes.AttributeValueMemberS{Value: "Bulbasaur"},
+		"lvl":  &dynamodbtypes.AttributeValueMemberN{Value: "5"},
+	}}, out.Items)
+
+	out, err = client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`SELECT id FROM pokemons."by-type" WHERE "type" = 'grass' ORDER BY id DESC`),
+	})
+	c.NoError(err)
+	c.Equal([]map[string]dynamodbtypes.AttributeValue{pokemonKey("002"), pokemonKey("001")}, out.Items)
+
+	out, err = client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`SELECT id FROM pokemons WHERE contains(name, 'ulba') OR (lvl > 10 AND begins_with(name, 'Ivy'))`),
+	})
+	c.NoError(err)
+	c.ElementsMatch([]map[string]dynamodbtypes.AttributeValue{pokemonKey("001"), pokemonKey("002")}, out.Items)
+
+	out, err = client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`SELECT id FROM pokemons WHERE moves IS MISSING AND "type" IN ['fire', 'water']`),
+	})
+	c.NoError(err)
+	c.ElementsMatch([]map[string]dynamodbtypes.AttributeValue{pokemonKey("004"), pokemonKey("007")}, out.Items)
+}
+
+func TestExecuteStatementPaging(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+
+	for _, id := range []string{"001", "004", "007", "025", "133"} {
+		c.NoError(createPokemon(client, pokemon{ID: id}))
+	}
+
+	input := &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`SELECT id FROM pokemons`),
+		Limit:     aws.Int32(2),
+	}
+
+	var items []map[string]dynamodbtypes.AttributeValue
+
+	pages := 0
+
+	for {
+		out, err := client.ExecuteStatement(ctx, input)
+		c.NoError(err)
+
+		pages++
+
+		items = append(items, out.Items...)
+
+		if out.NextToken == nil {
+			break
+		}
+
+		input.NextToken = out.NextToken
+	}
+
+	c.Equal(3, pages)
+	c.Len(items, 5)
+}
+
+func TestExecuteStatementErrors(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+
+	tests := map[string]string{
+		`SELECT * FROM`:                         "Statement wasn't well formed, can't be processed: expected identifier, found end of statement",
+		`SELECT * FROM pokemons WHERE id = ?`:   "Number of parameters in request and statement don't match.",
+		`SELECT * FROM pokemons ORDER BY id`:    "ORDER BY is only supported on the sort key with a WHERE clause that pins the partition key",
+		`EXISTS(SELECT * FROM pokemons)`:        "EXISTS is only supported as a condition in ExecuteTransaction",
+		`SELECT * FROM pokemons WHERE a = <<>>`: "Statement wasn't well formed, can't be processed: an empty set is not allowed",
+	}
+
+	for statement, message := range tests {
+		_, err := client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{Statement: aws.String(statement)})
+
+		var apiErr smithy.APIError
+		c.ErrorAs(err, &apiErr, statement)
+		c.Equal("ValidationException", apiErr.ErrorCode(), statement)
+		c.Equal(message, apiErr.ErrorMessage(), statement)
+	}
+
+	_, err := client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{Statement: aws.String(`SELECT * FROM "missing"`)})
+
+	var notFound *dynamodbtypes.ResourceNotFoundException
+	c.ErrorAs(err, &notFound)
+}
+
+func TestExecuteStatementDML(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(ensurePokemonTypeIndex(client))
+
+	_, err := client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement:  aws.String(`INSERT INTO pokemons VALUE {'id': ?, 'type': 'electric', 'name': 'Pikachu', 'lvl': 5}`),
+		Parameters: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "025"}},
+	})
+	c.NoError(err)
+
+	_, err = client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`INSERT INTO pokemons VALUE {'id': '025', 'type': 'electric'}`),
+	})
+
+	var duplicate *dynamodbtypes.DuplicateItemException
+	c.ErrorAs(err, &duplicate)
+
+	out, err := client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`UPDATE pokemons SET lvl = lvl + 1 REMOVE name WHERE id = '025' RETURNING MODIFIED NEW *`),
+	})
+	c.NoError(err)
+	c.Equal([]map[string]dynamodbtypes.AttributeValue{{
+		"lvl": &dynamodbtypes.AttributeValueMemberN{Value: "6"},
+	}}, out.Items)
+
+	out, err = client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`SELECT * FROM pokemons."by-type" WHERE "type" = 'electric'`),
+	})
+	c.NoError(err)
+	c.Len(out.Items, 1)
+	c.NotContains(out.Items[0], "name")
+
+	_, err = client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`DELETE FROM pokemons WHERE "type" = 'electric'`),
+	})
+
+	var apiErr smithy.APIError
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+
+	_, err = client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`DELETE FROM pokemons WHERE id = '025'`),
+	})
+	c.NoError(err)
+
+	out, err = client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`SELECT * FROM pokemons WHERE id = '025'`),
+	})
+	c.NoError(err)
+	c.Empty(out.Items)
+}
+
+func TestBatchExecuteStatement(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur", Level: 5}))
+
+	out, err := client.BatchExecuteStatement(ctx, &dynamodb.BatchExecuteStatementInput{
+		Statements: []dynamodbtypes.BatchStatementRequest{
+			{Statement: aws.String(`UPDATE pokemons SET lvl = 6 WHERE id = '001'`)},
+			{Statement: aws.String(`INSERT INTO pokemons VALUE {'id': '001', 'type': 'grass'}`)},
+			{Statement: aws.String(`DELETE FROM pokemons WHERE "type" = 'grass'`)},
+		},
+	})
+	c.NoError(err)
+	c.Len(out.Responses, 3)
+	c.Nil(out.Responses[0].Error)
+	c.Equal(dynamodbtypes.BatchStatementErrorCodeEnumDuplicateItem, out.Responses[1].Error.Code)
+	c.Equal(dynamodbtypes.BatchStatementErrorCodeEnumValidationError, out.Responses[2].Error.Code)
+
+	out, err = client.BatchExecuteStatement(ctx, &dynamodb.BatchExecuteStatementInput{
+		Statements: []dynamodbtypes.BatchStatementRequest{
+			{Statement: aws.String(`SELECT lvl FROM pokemons WHERE id = ?`), Parameters: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "001"}}},
+			{Statement: aws.String(`SELECT * FROM "missing" WHERE id = '001'`)},
+		},
+	})
+	c.NoError(err)
+	c.Len(out.Responses, 2)
+	c.Equal(map[string]dynamodbtypes.AttributeValue{"lvl": &dynamodbtypes.AttributeValueMemberN{Value: "6"}}, out.Responses[0].Item)
+	c.Equal(dynamodbtypes.BatchStatementErrorCodeEnumResourceNotFound, out.Responses[1].Error.Code)
+
+	_, err = client.BatchExecuteStatement(ctx, &dynamodb.BatchExecuteStatementInput{})
+
+	var apiErr smithy.APIError
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+
+	// batches mixing reads and writes are rejected before running any statement
+	_, err = client.BatchExecuteStatement(ctx, &dynamodb.BatchExecuteStatementInput{
+		Statements: []dynamodbtypes.BatchStatementRequest{
+			{Statement: aws.String(`UPDATE pokemons SET lvl = 7 WHERE id = '001'`)},
+			{Statement: aws.String(`SELECT lvl FROM pokemons WHERE id = '001'`)},
+		},
+	})
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+	c.ErrorContains(err, "The entire batch must consist of either read statements or write statements")
+
+	got, err := client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{Statement: aws.String(`SELECT lvl FROM pokemons WHERE id = '001'`)})
+	c.NoError(err)
+	c.Equal(map[string]dynamodbtypes.AttributeValue{"lvl": &dynamodbtypes.AttributeValueMemberN{Value: "6"}}, got.Items[0])
+}
+
+func TestExecuteTransaction(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur", Level: 5}))
+
+	_, err := client.ExecuteTransaction(ctx, &dynamodb.ExecuteTransactionInput{
+		TransactStatements: []dynamodbtypes.ParameterizedStatement{
+			{Statement: aws.String(`UPDATE pokemons SET lvl = 6 WHERE id = '001'`)},
+			{Statement: aws.String(`INSERT INTO pokemons VALUE {'id': '004', 'type': 'fire', 'name': 'Charmander'}`)},
+			{Statement: aws.String(`EXISTS(SELECT * FROM pokemons WHERE id = '007')`)},
+		},
+	})
+
+	var canceled *dynamodbtypes.TransactionCanceledException
+	c.ErrorAs(err, &canceled)
+	c.Equal([]string{"None", "None", "ConditionalCheckFailed"}, []string{
+		aws.ToString(canceled.CancellationReasons[0].Code),
+		aws.ToString(canceled.CancellationReasons[1].Code),
+		aws.ToString(canceled.CancellationReasons[2].Code),
+	})
+
+	read, err := client.ExecuteTransaction(ctx, &dynamodb.ExecuteTransactionInput{
+		TransactStatements: []dynamodbtypes.ParameterizedStatement{
+			{Statement: aws.String(`SELECT lvl FROM pokemons WHERE id = '001'`)},
+			{Statement: aws.String(`SELECT * FROM pokemons WHERE id = '004'`)},
+		},
+	})
+	c.NoError(err)
+	c.Equal(map[string]dynamodbtypes.AttributeValue{"lvl": &dynamodbtypes.AttributeValueMemberN{Value: "5"}}, read.Responses[0].Item)
+	c.Empty(read.Responses[1].Item)
+
+	input := &dynamodb.ExecuteTransactionInput{
+		ClientRequestToken: aws.String("level-up"),
+		TransactStatements: []dynamodbtypes.ParameterizedStatement{
+			{Statement: aws.String(`UPDATE pokemons SET lvl = lvl + 1 WHERE id = '001'`)},
+			{Statement: aws.String(`INSERT INTO pokemons VALUE {'id': '004', 'type': 'fire', 'name': 'Charmander'}`)},
+		},
+	}
+
+	_, err = client.ExecuteTransaction(ctx, input)
+	c.NoError(err)
+
+	_, err = client.ExecuteTransaction(ctx, input)
+	c.NoError(err)
+
+	out, err := client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{Statement: aws.String(`SELECT lvl FROM pokemons WHERE id = '001'`)})
+	c.NoError(err)
+	c.Equal([]map[string]dynamodbtypes.AttributeValue{{"lvl": &dynamodbtypes.AttributeValueMemberN{Value: "6"}}}, out.Items)
+
+	input.TransactStatements = input.TransactStatements[:1]
+
+	_, err = client.ExecuteTransaction(ctx, input)
+
+	var mismatch *dynamodbtypes.IdempotentParameterMismatchException
+	c.ErrorAs(err, &mismatch)
+}
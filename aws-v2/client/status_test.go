@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableStatusWaiters(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	SetTableStatusDelay(client, 50*time.Millisecond)
+
+	fastPolling := func(delays ...*time.Duration) {
+		for _, delay := range delays {
+			*delay = 10 * time.Millisecond
+		}
+	}
+
+	c.NoError(AddTable(ctx, client, tableName, "id", ""))
+
+	err := createPokemon(client, pokemon{ID: "25", Name: "pikachu"})
+
+	var notFoundErr *dynamodbtypes.ResourceNotFoundException
+	c.True(errors.As(err, &notFoundErr))
+
+	existsWaiter := dynamodb.NewTableExistsWaiter(client, func(o *dynamodb.TableExistsWaiterOptions) {
+		fastPolling(&o.MinDelay, &o.MaxDelay)
+	})
+	c.NoError(existsWaiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)}, time.Second))
+
+	c.NoError(createPokemon(client, pokemon{ID: "25", Name: "pikachu"}))
+
+	c.NoError(AddIndex(ctx, client, tableName, "by-type", "type", "id"))
+
+	_, err = client.UpdateTable(ctx, &dynamodb.UpdateTableInput{TableName: aws.String(tableName)})
+
+	var inUseErr *dynamodbtypes.ResourceInUseException
+	c.True(errors.As(err, &inUseErr))
+
+	_, err = client.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+	c.True(errors.As(err, &inUseErr))
+
+	c.NoError(existsWaiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)}, time.Second))
+
+	_, err = client.UpdateTable(ctx, &dynamodb.UpdateTableInput{TableName: aws.String(tableName)})
+	c.ErrorContains(err, "At least one of ProvisionedThroughput, BillingMode, UpdateStreamEnabled, GlobalSecondaryIndexUpdates or SSESpecification or ReplicaUpdates is required")
+
+	// an update that changes nothing leaves the table active
+	updated, err := client.UpdateTable(ctx, &dynamodb.UpdateTableInput{TableName: aws.String(tableName), DeletionProtectionEnabled: aws.Bool(false)})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.TableStatusActive, updated.TableDescription.TableStatus)
+
+	deleted, err := client.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.TableStatusDeleting, deleted.TableDescription.TableStatus)
+
+	notExistsWaiter := dynamodb.NewTableNotExistsWaiter(client, func(o *dynamodb.TableNotExistsWaiterOptions) {
+		fastPolling(&o.MinDelay, &o.MaxDelay)
+	})
+	c.NoError(notExistsWaiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)}, time.Second))
+}
@@ -0,0 +1,242 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+// StreamDelivery describes how the records of a stream subscription are delivered.
+type StreamDelivery string
+
+const (
+	// StreamDeliverySync calls the handler on the goroutine that wrote the item,
+	// after the write is applied and before the call returns.
+	StreamDeliverySync StreamDelivery = "sync"
+	// StreamDeliveryAsync calls the handler on a goroutine owned by the subscription,
+	// records are still delivered one at a time in sequence order.
+	StreamDeliveryAsync StreamDelivery = "async"
+)
+
+// StreamRecord is an item change delivered to a stream subscription.
+type StreamRecord struct {
+	TableName string
+	// EventName is INSERT, MODIFY or REMOVE
+	EventName string
+	// SequenceNumber increases with every record published by the client, across tables
+	SequenceNumber              uint64
+	Keys                        map[string]types.AttributeValue
+	NewImage                    map[string]types.AttributeValue
+	OldImage                    map[string]types.AttributeValue
+	ApproximateCreationDateTime time.Time
+	SizeBytes                   int64
+	// ServiceInitiated is set for the items removed by Time To Live
+	ServiceInitiated bool
+}
+
+// StreamSubscription receives the changes of a table until it is closed.
+type StreamSubscription struct {
+	client    *Client
+	tableName string
+	handler   func(StreamRecord)
+	delivery  StreamDelivery
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []StreamRecord
+	pending int
+	closed  bool
+}
+
+// SubscribeStream calls handler with every item change applied to tableName, including
+// the writes done by BatchWriteItem and TransactWriteItems. Cancelled transactions do not
+// publish records and writes that leave an item unchanged are skipped. Records carry both
+// images regardless of the StreamSpecification of the table. Delivery is synchronous
+// unless StreamDeliveryAsync is given.
+func SubscribeStream(client FakeClient, tableName string, handler func(StreamRecord), delivery ...StreamDelivery) (*StreamSubscription, error) {
+	fakeClient, ok := client.(*Client)
+	if !ok {
+		panic("SubscribeStream: invalid client type")
+	}
+
+	fakeClient.mu.Lock()
+	defer fakeClient.mu.Unlock()
+
+	if _, err := fakeClient.getTable(tableName); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	sub := &StreamSubscription{
+		client:    fakeClient,
+		tableName: tableName,
+		handler:   handler,
+		delivery:  StreamDeliverySync,
+	}
+	sub.cond = sync.NewCond(&sub.mu)
+
+	if len(delivery) > 0 {
+		sub.delivery = delivery[0]
+	}
+
+	if sub.delivery == StreamDeliveryAsync {
+		go sub.run()
+	}
+
+	fakeClient.subscriptions[tableName] = append(fakeClient.subscriptions[tableName], sub)
+
+	return sub, nil
+}
+
+// Flush waits until every record published so far has been handled. It returns right
+// away for synchronous subscriptions.
+func (s *StreamSubscription) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.pending > 0 {
+		s.cond.Wait()
+	}
+}
+
+// Close stops the subscription, records already published are still delivered.
+func (s *StreamSubscription) Close() {
+	s.client.mu.Lock()
+
+	subs := s.client.subscriptions[s.tableName]
+	for i, sub := range subs {
+		if sub == s {
+			s.client.subscriptions[s.tableName] = append(subs[:i:i], subs[i+1:]...)
+
+			break
+		}
+	}
+
+	s.client.mu.Unlock()
+
+	s.Flush()
+
+	s.mu.Lock()
+	s.closed = true
+	s.cond.Broadcast()
+	s.mu.Unlock()
+}
+
+func (s *StreamSubscription) enqueue(record StreamRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queue = append(s.queue, record)
+	s.pending++
+	s.cond.Broadcast()
+}
+
+func (s *StreamSubscription) run() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		for len(s.queue) == 0 && !s.closed {
+			s.cond.Wait()
+		}
+
+		if len(s.queue) == 0 {
+			return
+		}
+
+		record := s.queue[0]
+		s.queue = s.queue[1:]
+
+		s.mu.Unlock()
+		s.handler(record)
+		s.mu.Lock()
+
+		s.pending--
+		s.cond.Broadcast()
+	}
+}
+
+// captureChange buffers a change of tableName until the write that made it completes,
+// callers must hold fd.mu.
+func (fd *Client) captureChange(tableName string, change core.StreamRecord) {
+	if len(fd.subscriptions[tableName]) == 0 {
+		return
+	}
+
+	fd.pendingChanges = append(fd.pendingChanges, StreamRecord{
+		TableName:                   tableName,
+		EventName:                   change.EventName,
+		Keys:                        mapTypesToDynamoMapItem(change.Keys),
+		NewImage:                    mapTypesToDynamoMapItem(change.NewImage),
+		OldImage:                    mapTypesToDynamoMapItem(change.OldImage),
+		ApproximateCreationDateTime: change.CreatedAt,
+		SizeBytes:                   change.SizeBytes,
+		ServiceInitiated:            change.ServiceInitiated,
+	})
+}
+
+// publishChanges numbers the buffered changes and delivers them to the subscriptions.
+// It must be called without holding fd.mu so synchronous handlers can use the client.
+func (fd *Client) publishChanges() {
+	type delivery struct {
+		sub    *StreamSubscription
+		record StreamRecord
+	}
+
+	var syncDeliveries []delivery
+
+	fd.mu.Lock()
+
+	for _, record := range fd.pendingChanges {
+		fd.streamSequence++
+		record.SequenceNumber = fd.streamSequence
+
+		for _, sub := range fd.subscriptions[record.TableName] {
+			if sub.delivery == StreamDeliveryAsync {
+				sub.enqueue(record)
+
+				continue
+			}
+
+			syncDeliveries = append(syncDeliveries, delivery{sub: sub, record: record})
+		}
+	}
+
+	fd.pendingChanges = nil
+
+	fd.mu.Unlock()
+
+	for _, d := range syncDeliveries {
+		d.sub.handler(d.record)
+	}
+}
+
+// streamChangeListener routes the changes of a table to the client subscriptions
+func (fd *Client) streamChangeListener(tableName string) func(core.StreamRecord) {
+	return func(change core.StreamRecord) {
+		fd.captureChange(tableName, change)
+	}
+}
+
+// applyStreamSpecification enables or disables the stream of the table as requested, the
+// subscriptions receive the changes of the table whatever its stream
+func (fd *Client) applyStreamSpecification(table *core.Table, spec *types.StreamSpecification) error {
+	if spec == nil {
+		return nil
+	}
+
+	if !aws.ToBool(spec.StreamEnabled) {
+		return table.DisableStream()
+	}
+
+	if spec.StreamViewType == "" {
+		return &smithy.GenericAPIError{Code: "ValidationException", Message: "StreamViewType is required when StreamEnabled is true"}
+	}
+
+	_, err := table.EnableStream(string(spec.StreamViewType), fd.clock.Now())
+
+	return err
+}
@@ -0,0 +1,211 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func pokemonKey(id string) map[string]dynamodbtypes.AttributeValue {
+	return map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: id}}
+}
+
+func TestSubscribeStream(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+
+	var records []StreamRecord
+
+	sub, err := SubscribeStream(client, tableName, func(rec StreamRecord) {
+		records = append(records, rec)
+	})
+	c.NoError(err)
+
+	c.NoError(createPokemon(client, pokemon{ID: "001", Type: "grass", Name: "Bulbasaur"}))
+
+	_, err = client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(tableName),
+		Key:                       pokemonKey("001"),
+		UpdateExpression:          aws.String("SET #n = :n"),
+		ExpressionAttributeNames:  map[string]string{"#n": "name"},
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{":n": &dynamodbtypes.AttributeValueMemberS{Value: "Ivysaur"}},
+	})
+	c.NoError(err)
+
+	_, err = client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]dynamodbtypes.WriteRequest{
+			tableName: {
+				{PutRequest: &dynamodbtypes.PutRequest{Item: pokemonKey("004")}},
+				{DeleteRequest: &dynamodbtypes.DeleteRequest{Key: pokemonKey("001")}},
+			},
+		},
+	})
+	c.NoError(err)
+
+	_, err = client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []dynamodbtypes.TransactWriteItem{
+			{Put: &dynamodbtypes.Put{TableName: aws.String(tableName), Item: pokemonKey("007")}},
+		},
+	})
+	c.NoError(err)
+
+	// a cancelled transaction publishes nothing
+	_, err = client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []dynamodbtypes.TransactWriteItem{
+			{Put: &dynamodbtypes.Put{TableName: aws.String(tableName), Item: pokemonKey("025")}},
+			{ConditionCheck: &dynamodbtypes.ConditionCheck{
+				TableName:           aws.String(tableName),
+				Key:                 pokemonKey("001"),
+				ConditionExpression: aws.String("attribute_exists(id)"),
+			}},
+		},
+	})
+	c.Error(err)
+
+	c.Len(records, 5)
+
+	events := make([]string, 0, len(records))
+	for i, rec := range records {
+		events = append(events, rec.EventName)
+		c.Equal(tableName, rec.TableName)
+		c.Equal(uint64(i+1), rec.SequenceNumber)
+		c.False(rec.ApproximateCreationDateTime.IsZero())
+	}
+
+	c.Equal([]string{"INSERT", "MODIFY", "INSERT", "REMOVE", "INSERT"}, events)
+
+	c.Nil(records[0].OldImage)
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "Bulbasaur"}, records[0].NewImage["name"])
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "Bulbasaur"}, records[1].OldImage["name"])
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "Ivysaur"}, records[1].NewImage["name"])
+	c.Equal(pokemonKey("001"), records[3].Keys)
+	c.Equal(&dynamodbtypes.AttributeValueMemberS{Value: "Ivysaur"}, records[3].OldImage["name"])
+	c.Nil(records[3].NewImage)
+
+	sub.Close()
+
+	c.NoError(createPokemon(client, pokemon{ID: "010", Name: "Caterpie"}))
+	c.Len(records, 5)
+
+	_, err = SubscribeStream(client, "missing", func(StreamRecord) {})
+
+	var notFound *dynamodbtypes.ResourceNotFoundException
+	c.ErrorAs(err, &notFound)
+}
+
+func TestSubscribeStreamAsync(t *testing.T) {
+	c := require.New(t)
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+
+	received := make(chan StreamRecord, 10)
+
+	sub, err := SubscribeStream(client, tableName, func(rec StreamRecord) {
+		received <- rec
+	}, StreamDeliveryAsync)
+	c.NoError(err)
+
+	for _, id := range []string{"001", "004", "007"} {
+		c.NoError(createPokemon(client, pokemon{ID: id}))
+	}
+
+	sub.Flush()
+	c.Len(received, 3)
+
+	for _, want := range []string{"001", "004", "007"} {
+		rec := <-received
+		c.Equal(pokemonKey(want), rec.Keys)
+	}
+
+	c.NoError(createPokemon(client, pokemon{ID: "010"}))
+	sub.Close()
+	c.Len(received, 1)
+}
+
+func TestSubscribeStreamHandlerCanWrite(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(AddTable(ctx, client, "outbox", "id", ""))
+
+	_, err := SubscribeStream(client, tableName, func(rec StreamRecord) {
+		_, perr := client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String("outbox"),
+			Item:      rec.Keys,
+		})
+		c.NoError(perr)
+	})
+	c.NoError(err)
+
+	var outbox []StreamRecord
+
+	_, err = SubscribeStream(client, "outbox", func(rec StreamRecord) {
+		outbox = append(outbox, rec)
+	})
+	c.NoError(err)
+
+	c.NoError(createPokemon(client, pokemon{ID: "001"}))
+
+	c.Len(outbox, 1)
+	c.Equal(uint64(2), outbox[0].SequenceNumber)
+
+	out, err := client.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String("outbox"), Key: pokemonKey("001")})
+	c.NoError(err)
+	c.NotEmpty(out.Item)
+}
+
+func TestUpdateTableStreamSpecification(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+	SetClock(client, core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+
+	c.NoError(ensurePokemonTable(client))
+
+	updated, err := client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:           aws.String(tableName),
+		StreamSpecification: &dynamodbtypes.StreamSpecification{StreamEnabled: aws.Bool(true), StreamViewType: dynamodbtypes.StreamViewTypeNewImage},
+	})
+	c.NoError(err)
+	c.Equal("2024-05-01T10:00:00.000", aws.ToString(updated.TableDescription.LatestStreamLabel))
+	c.Equal("arn:aws:dynamodb:us-east-1:000000000000:table/pokemons/stream/2024-05-01T10:00:00.000", aws.ToString(updated.TableDescription.LatestStreamArn))
+
+	described, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(&dynamodbtypes.StreamSpecification{StreamEnabled: aws.Bool(true), StreamViewType: dynamodbtypes.StreamViewTypeNewImage}, described.Table.StreamSpecification)
+
+	_, err = client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:           aws.String(tableName),
+		StreamSpecification: &dynamodbtypes.StreamSpecification{StreamEnabled: aws.Bool(true), StreamViewType: dynamodbtypes.StreamViewTypeKeysOnly},
+	})
+	c.ErrorContains(err, "Table already has an enabled stream")
+
+	_, err = client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:           aws.String(tableName),
+		StreamSpecification: &dynamodbtypes.StreamSpecification{StreamEnabled: aws.Bool(false)},
+	})
+	c.NoError(err)
+
+	described, err = client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Nil(described.Table.StreamSpecification)
+	c.Equal("2024-05-01T10:00:00.000", aws.ToString(described.Table.LatestStreamLabel))
+
+	_, err = client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:           aws.String(tableName),
+		StreamSpecification: &dynamodbtypes.StreamSpecification{StreamEnabled: aws.Bool(false)},
+	})
+	c.ErrorContains(err, "Table does not have an enabled stream to disable")
+}
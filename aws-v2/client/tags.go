@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+func mapDynamoToCoreTags(input []types.Tag) []core.Tag {
+	output := make([]core.Tag, 0, len(input))
+
+	for _, tag := range input {
+		output = append(output, core.Tag{Key: aws.ToString(tag.Key), Value: aws.ToString(tag.Value)})
+	}
+
+	return output
+}
+
+// getTaggedTable resolves the table ARN given to the tagging operations, callers must hold fd.mu
+func (fd *Client) getTaggedTable(arn string) (*core.Table, error) {
+	if !strings.HasPrefix(arn, "arn:aws:dynamodb:") || !strings.Contains(arn, ":table/") {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "Invalid TableArn: Invalid ResourceArn provided as input " + arn}
+	}
+
+	tableName, ok := strings.CutPrefix(arn, fd.tableArn(""))
+	if ok && !strings.Contains(tableName, "/") {
+		if table, found := fd.tables[tableName]; found {
+			return table, nil
+		}
+	}
+
+	// the typo in ResourcArn is part of the message DynamoDB returns
+	return nil, &types.ResourceNotFoundException{Message: aws.String("Requested resource not found: ResourcArn: " + arn + " not found")}
+}
+
+// TagResource adds tags to a table
+func (fd *Client) TagResource(ctx context.Context, input *dynamodb.TagResourceInput, opts ...func(*dynamodb.Options)) (*dynamodb.TagResourceOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	arn := aws.ToString(input.ResourceArn)
+	_, tableName, _ := strings.Cut(arn, ":table/")
+
+	if err := fd.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	table, err := fd.getTaggedTable(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := table.TagResource(mapDynamoToCoreTags(input.Tags)); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	return &dynamodb.TagResourceOutput{}, nil
+}
+
+// UntagResource removes tags from a table
+func (fd *Client) UntagResource(ctx context.Context, input *dynamodb.UntagResourceInput, opts ...func(*dynamodb.Options)) (*dynamodb.UntagResourceOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	arn := aws.ToString(input.ResourceArn)
+	_, tableName, _ := strings.Cut(arn, ":table/")
+
+	if err := fd.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	table, err := fd.getTaggedTable(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := table.UntagResource(input.TagKeys); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	return &dynamodb.UntagResourceOutput{}, nil
+}
+
+// ListTagsOfResource returns the tags of a table sorted by key, all of them fit in a
+// single page
+func (fd *Client) ListTagsOfResource(ctx context.Context, input *dynamodb.ListTagsOfResourceInput, opts ...func(*dynamodb.Options)) (*dynamodb.ListTagsOfResourceOutput, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	arn := aws.ToString(input.ResourceArn)
+	_, tableName, _ := strings.Cut(arn, ":table/")
+
+	if err := fd.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	table, err := fd.getTaggedTable(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &dynamodb.ListTagsOfResourceOutput{Tags: []types.Tag{}}
+
+	for _, tag := range table.Tags() {
+		output.Tags = append(output.Tags, types.Tag{Key: aws.String(tag.Key), Value: aws.String(tag.Value)})
+	}
+
+	return output, nil
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+
+	c.NoError(ensurePokemonTable(client))
+	c.NoError(ensurePokemonTypeIndex(client))
+
+	tableArn := aws.String("arn:aws:dynamodb:us-east-1:000000000000:table/" + tableName)
+
+	described, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(aws.ToString(tableArn), aws.ToString(described.Table.TableArn))
+	c.Equal(aws.ToString(tableArn)+"/index/by-type", aws.ToString(described.Table.GlobalSecondaryIndexes[0].IndexArn))
+
+	_, err = client.TagResource(ctx, &dynamodb.TagResourceInput{
+		ResourceArn: tableArn,
+		Tags:        []dynamodbtypes.Tag{{Key: aws.String("team"), Value: aws.String("rocket")}, {Key: aws.String("env"), Value: aws.String("test")}},
+	})
+	c.NoError(err)
+
+	listed, err := client.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{ResourceArn: tableArn})
+	c.NoError(err)
+	c.Len(listed.Tags, 2)
+	c.Equal("env", aws.ToString(listed.Tags[0].Key))
+
+	_, err = client.UntagResource(ctx, &dynamodb.UntagResourceInput{ResourceArn: tableArn, TagKeys: []string{"env"}})
+	c.NoError(err)
+
+	listed, err = client.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{ResourceArn: tableArn})
+	c.NoError(err)
+	c.Equal([]dynamodbtypes.Tag{{Key: aws.String("team"), Value: aws.String("rocket")}}, listed.Tags)
+
+	tags := make([]dynamodbtypes.Tag, 50)
+	for i := range tags {
+		tags[i] = dynamodbtypes.Tag{Key: aws.String(string(rune('A' + i))), Value: aws.String("")}
+	}
+
+	_, err = client.TagResource(ctx, &dynamodb.TagResourceInput{ResourceArn: tableArn, Tags: tags})
+	c.ErrorContains(err, "at most 50 tags")
+
+	_, err = client.UntagResource(ctx, &dynamodb.UntagResourceInput{ResourceArn: tableArn, TagKeys: []string{"aws:tag"}})
+	c.ErrorContains(err, "reserved")
+
+	SetAccount(client, "sa-east-1", "111122223333")
+
+	_, err = client.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{ResourceArn: tableArn})
+
+	var notFoundErr *dynamodbtypes.ResourceNotFoundException
+	c.True(errors.As(err, &notFoundErr))
+
+	_, err = client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:            aws.String("moves"),
+		KeySchema:            []dynamodbtypes.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: dynamodbtypes.KeyTypeHash}},
+		AttributeDefinitions: []dynamodbtypes.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: dynamodbtypes.ScalarAttributeTypeS}},
+		BillingMode:          dynamodbtypes.BillingModePayPerRequest,
+		Tags:                 []dynamodbtypes.Tag{{Key: aws.String("owner"), Value: aws.String("oak")}},
+	})
+	c.NoError(err)
+
+	listed, err = client.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{ResourceArn: aws.String("arn:aws:dynamodb:sa-east-1:111122223333:table/moves")})
+	c.NoError(err)
+	c.Equal("oak", aws.ToString(listed.Tags[0].Value))
+
+	backup, err := client.CreateBackup(ctx, &dynamodb.CreateBackupInput{TableName: aws.String(tableName), BackupName: aws.String("snapshot")})
+	c.NoError(err)
+	c.Contains(aws.ToString(backup.BackupDetails.BackupArn), "arn:aws:dynamodb:sa-east-1:111122223333:table/pokemons/backup/")
+}
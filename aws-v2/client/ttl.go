@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+// UpdateTimeToLive enables or disables the Time To Live of a table
+func (fd *Client) UpdateTimeToLive(ctx context.Context, input *dynamodb.UpdateTimeToLiveInput, opts ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	fd.mu.Lock()
+	defer fd.publishChanges()
+	defer fd.mu.Unlock()
+
+	table, err := fd.getTable(aws.ToString(input.TableName))
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	spec := input.TimeToLiveSpecification
+	if spec == nil || spec.Enabled == nil {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "1 validation error detected: Value null at 'timeToLiveSpecification' failed to satisfy constraint: Member must not be null"}
+	}
+
+	if err := table.UpdateTimeToLive(aws.ToBool(spec.Enabled), aws.ToString(spec.AttributeName)); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	return &dynamodb.UpdateTimeToLiveOutput{TimeToLiveSpecification: spec}, nil
+}
+
+// DescribeTimeToLive returns the Time To Live settings of a table
+func (fd *Client) DescribeTimeToLive(ctx context.Context, input *dynamodb.DescribeTimeToLiveInput, opts ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	fd.mu.Lock()
+	defer fd.publishChanges()
+	defer fd.mu.Unlock()
+
+	table, err := fd.getTable(aws.ToString(input.TableName))
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	attributeName, status := table.TimeToLive()
+
+	desc := &types.TimeToLiveDescription{TimeToLiveStatus: types.TimeToLiveStatus(status)}
+	if attributeName != "" {
+		desc.AttributeName = aws.String(attributeName)
+	}
+
+	return &dynamodb.DescribeTimeToLiveOutput{TimeToLiveDescription: desc}, nil
+}
+
+// SetClock replaces the clock used to expire items with Time To Live and to time
+// the point in time recovery history, a core.ManualClock lets tests move time
+// forward without sleeping
+func SetClock(client FakeClient, clock core.Clock) {
+	fakeClient, ok := client.(*Client)
+	if !ok {
+		panic("SetClock: invalid client type")
+	}
+
+	fakeClient.mu.Lock()
+	defer fakeClient.mu.Unlock()
+
+	fakeClient.clock = clock
+
+	for _, table := range fakeClient.tables {
+		table.Clock = clock
+	}
+}
+
+// SweepExpiredOnAccess controls whether expired items are deleted as soon as their table is
+// used, write transactions leave them in place so a rollback never undoes a sweep. By
+// default expired items stay visible to reads until SweepExpiredItems deletes them, like
+// DynamoDB does for up to 48 hours
+func SweepExpiredOnAccess(client FakeClient, sweep bool) {
+	fakeClient, ok := client.(*Client)
+	if !ok {
+		panic("SweepExpiredOnAccess: invalid client type")
+	}
+
+	fakeClient.mu.Lock()
+	defer fakeClient.mu.Unlock()
+
+	fakeClient.sweepExpiredOnAccess = sweep
+}
+
+// SweepExpiredItems deletes the expired items of every table with Time To Live
+// enabled and returns how many were deleted, the removals are published to the
+// stream subscriptions as service initiated records
+func SweepExpiredItems(client FakeClient) int {
+	fakeClient, ok := client.(*Client)
+	if !ok {
+		panic("SweepExpiredItems: invalid client type")
+	}
+
+	fakeClient.mu.Lock()
+	defer fakeClient.publishChanges()
+	defer fakeClient.mu.Unlock()
+
+	swept := 0
+	for _, table := range fakeClient.tables {
+		swept += table.SweepExpired(fakeClient.clock.Now())
+	}
+
+	return swept
+}
+
+// sweepOnAccess deletes the expired items of a table before it is used when the client
+// sweeps expired items on access
+func (fd *Client) sweepOnAccess(table *core.Table) {
+	if !fd.sweepExpiredOnAccess {
+		return
+	}
+
+	table.SweepExpired(fd.clock.Now())
+}
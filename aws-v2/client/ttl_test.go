@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func enablePokemonTTL(t *testing.T, client FakeClient) {
+	t.Helper()
+
+	_, err := client.UpdateTimeToLive(context.Background(), &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(tableName),
+		TimeToLiveSpecification: &dynamodbtypes.TimeToLiveSpecification{
+			AttributeName: aws.String("expires_at"),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	require.NoError(t, err)
+}
+
+func putExpiringPokemon(t *testing.T, client FakeClient, id string, expiresAt time.Time) {
+	t.Helper()
+
+	item := pokemonKey(id)
+	item["expires_at"] = &dynamodbtypes.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)}
+
+	_, err := client.PutItem(context.Background(), &dynamodb.PutItemInput{TableName: aws.String(tableName), Item: item})
+	require.NoError(t, err)
+}
+
+func TestTimeToLive(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	SetClock(client, clock)
+	SweepExpiredOnAccess(client, true)
+	c.NoError(ensurePokemonTable(client))
+
+	out, err := client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.TimeToLiveStatusDisabled, out.TimeToLiveDescription.TimeToLiveStatus)
+	c.Nil(out.TimeToLiveDescription.AttributeName)
+
+	enablePokemonTTL(t, client)
+
+	out, err = client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Equal(dynamodbtypes.TimeToLiveStatusEnabled, out.TimeToLiveDescription.TimeToLiveStatus)
+	c.Equal("expires_at", aws.ToString(out.TimeToLiveDescription.AttributeName))
+
+	_, err = client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{TableName: aws.String(tableName)})
+	c.Error(err)
+
+	_, err = client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: aws.String("missing")})
+
+	var notFound *dynamodbtypes.ResourceNotFoundException
+	c.ErrorAs(err, &notFound)
+
+	var records []StreamRecord
+
+	_, err = SubscribeStream(client, tableName, func(rec StreamRecord) {
+		records = append(records, rec)
+	})
+	c.NoError(err)
+
+	putExpiringPokemon(t, client, "001", clock.Now().Add(time.Minute))
+	putExpiringPokemon(t, client, "004", clock.Now().Add(time.Hour))
+
+	clock.Advance(2 * time.Minute)
+
+	got, err := client.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String(tableName), Key: pokemonKey("001")})
+	c.NoError(err)
+	c.Empty(got.Item)
+
+	c.Len(records, 3)
+	c.Equal("REMOVE", records[2].EventName)
+	c.Equal(pokemonKey("001"), records[2].Keys)
+	c.True(records[2].ServiceInitiated)
+	c.False(records[0].ServiceInitiated)
+
+	got, err = client.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String(tableName), Key: pokemonKey("004")})
+	c.NoError(err)
+	c.NotEmpty(got.Item)
+}
+
+func TestSweepExpiredItems(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	SetClock(client, clock)
+	c.NoError(ensurePokemonTable(client))
+	enablePokemonTTL(t, client)
+
+	putExpiringPokemon(t, client, "001", clock.Now().Add(time.Minute))
+
+	clock.Advance(time.Hour)
+
+	scan, err := client.Scan(ctx, &dynamodb.ScanInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Len(scan.Items, 1)
+
+	c.Equal(1, SweepExpiredItems(client))
+	c.Zero(SweepExpiredItems(client))
+
+	scan, err = client.Scan(ctx, &dynamodb.ScanInput{TableName: aws.String(tableName)})
+	c.NoError(err)
+	c.Empty(scan.Items)
+}
+
+func TestSweepExpiredOnAccessSkipsWriteTransactions(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+	client := NewClient()
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	SetClock(client, clock)
+	SweepExpiredOnAccess(client, true)
+	c.NoError(ensurePokemonTable(client))
+	enablePokemonTTL(t, client)
+
+	putExpiringPokemon(t, client, "001", clock.Now().Add(time.Minute))
+
+	clock.Advance(time.Hour)
+
+	// write transactions never sweep, the expired item is still there until the table is used
+	_, err := client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []dynamodbtypes.TransactWriteItem{{ConditionCheck: &dynamodbtypes.ConditionCheck{
+			TableName:           aws.String(tableName),
+			Key:                 pokemonKey("001"),
+			ConditionExpression: aws.String("attribute_exists(id)"),
+		}}},
+	})
+	c.NoError(err)
+
+	got, err := client.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String(tableName), Key: pokemonKey("001")})
+	c.NoError(err)
+	c.Empty(got.Item)
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	minBackupNameLength = 3
+	maxBackupNameLength = 255
+)
+
+var backupNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// Backup is an on-demand copy of a table, it keeps the schema, the index definitions and the
+// items the table had when the backup was created
+type Backup struct {
+	Name      string
+	TableName string
+	CreatedAt time.Time
+	ItemCount int64
+	SizeBytes int64
+	// Source describes the table at the time of the backup
+	Source *types.TableDescription
+
+	attributesDef map[string]string
+	keySchema     keySchema
+	BillingMode   *string
+	indexes       map[string]*index
+	data          map[string]map[string]*types.Item
+}
+
+// RestoreInput overrides the settings a restored table takes from its backup, nil fields keep
+// the settings of the backup
+type RestoreInput struct {
+	BillingMode            *string
+	GlobalSecondaryIndexes []*types.GlobalSecondaryIndex
+	LocalSecondaryIndexes  []*types.LocalSecondaryIndex
+}
+
+// RestoreSummary records where a restored table came from
+type RestoreSummary struct {
+	SourceBackupArn string
+	SourceTableArn  string
+	// RestoreDateTime is the creation time of the backup or the point in time restored
+	RestoreDateTime time.Time
+}
+
+// ValidateBackupName checks the length and the characters of a backup name
+func ValidateBackupName(name string) error {
+	var constraint string
+
+	switch {
+	case len(name) < minBackupNameLength:
+		constraint = fmt.Sprintf("Member must have length greater than or equal to %d", minBackupNameLength)
+	case len(name) > maxBackupNameLength:
+		constraint = fmt.Sprintf("Member must have length less than or equal to %d", maxBackupNameLength)
+	case !backupNamePattern.MatchString(name):
+		constraint = "Member must satisfy regular expression pattern: [a-zA-Z0-9_.-]+"
+	default:
+		return nil
+	}
+
+	return types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%s' at 'backupName' failed to satisfy constraint: %s", name, constraint), nil)
+}
+
+// Backup copies the table into a new Backup
+func (t *Table) Backup(name string, now time.Time) *Backup {
+	return t.newBackup(name, now, t.Snapshot().data)
+}
+
+// newBackup builds a Backup holding the given items with the schema and index definitions
+// of the table
+func (t *Table) newBackup(name string, createdAt time.Time, data map[string]map[string]*types.Item) *Backup {
+	b := &Backup{
+		Name:          name,
+		TableName:     t.Name,
+		CreatedAt:     createdAt,
+		ItemCount:     int64(len(data)),
+		Source:        t.Description(t.Name),
+		attributesDef: maps.Clone(t.AttributesDef),
+		keySchema:     t.KeySchema,
+		BillingMode:   t.BillingMode,
+		indexes:       make(map[string]*index, len(t.Indexes)),
+		data:          data,
+	}
+
+	for _, item := range data {
+		b.SizeBytes += itemSize(item)
+	}
+
+	b.Source.ItemCount = b.ItemCount
+	b.Source.TableSizeBytes = b.SizeBytes
+
+	for name, idx := range t.Indexes {
+		b.indexes[name] = &index{keySchema: idx.keySchema, typ: idx.typ, projection: idx.projection}
+	}
+
+	return b
+}
+
+// Restore fills t, which must be a new table, with the schema, indexes and items of the backup
+func (b *Backup) Restore(t *Table, input RestoreInput) error {
+	t.AttributesDef = maps.Clone(b.attributesDef)
+	t.KeySchema = b.keySchema
+
+	t.BillingMode = b.BillingMode
+	if input.BillingMode != nil {
+		t.BillingMode = input.BillingMode
+	}
+
+	if err := b.restoreIndexes(t, input); err != nil {
+		return err
+	}
+
+	for key, item := range b.data {
+		item = deepCopyItemMap(item)
+		t.setItem(key, item)
+
+		for _, idx := range t.Indexes {
+			if err := idx.updateData(key, item, nil); err != nil {
+				return types.NewError("ValidationException", err.Error(), nil)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *Backup) restoreIndexes(t *Table, input RestoreInput) error {
+	for name, idx := range b.indexes {
+		if idx.typ == indexTypeGlobal && input.GlobalSecondaryIndexes != nil {
+			continue
+		}
+
+		if idx.typ == indexTypeLocal && input.LocalSecondaryIndexes != nil {
+			continue
+		}
+
+		restored := newIndex(t, idx.typ, idx.keySchema)
+		restored.projection = idx.projection
+		t.Indexes[name] = restored
+	}
+
+	if len(input.GlobalSecondaryIndexes) > 0 {
+		if err := t.AddGlobalIndexes(input.GlobalSecondaryIndexes); err != nil {
+			return err
+		}
+	}
+
+	if len(input.LocalSecondaryIndexes) > 0 {
+		if err := t.AddLocalIndexes(input.LocalSecondaryIndexes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestBackupRestore(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	backup := table.Backup("kanto", now)
+	c.Equal("kanto", backup.Name)
+	c.Equal("trainers", backup.TableName)
+	c.Equal(now, backup.CreatedAt)
+	c.EqualValues(9, backup.ItemCount)
+	c.Positive(backup.SizeBytes)
+	c.Equal(backup.SizeBytes, backup.Source.TableSizeBytes)
+	c.Len(backup.Source.GlobalSecondaryIndexes, 1)
+
+	// changes made after the backup do not reach it
+	_, err := table.Delete(&types.DeleteItemInput{Key: map[string]*types.Item{"trainer": {S: new("ash")}, "pokemon": {S: new("pikachu")}}})
+	c.NoError(err)
+
+	restored := NewTable("trainers-restored")
+	c.NoError(backup.Restore(restored, RestoreInput{}))
+	c.Equal(table.KeySchema, restored.KeySchema)
+	c.Len(restored.Data, 9)
+
+	items, _, err := restored.SearchData(QueryInput{
+		Index:                     "by-type",
+		KeyConditionExpression:    "#t = :t",
+		Aliases:                   map[string]string{"#t": "type"},
+		ExpressionAttributeValues: map[string]*types.Item{":t": {S: new("electric")}},
+		ScanIndexForward:          true,
+	})
+	c.NoError(err)
+	c.Len(items, 1)
+
+	// the restored table does not share items with the backup
+	_, err = restored.Delete(&types.DeleteItemInput{Key: map[string]*types.Item{"trainer": {S: new("misty")}, "pokemon": {S: new("staryu")}}})
+	c.NoError(err)
+
+	again := NewTable("trainers-again")
+	c.NoError(backup.Restore(again, RestoreInput{BillingMode: new("PAY_PER_REQUEST"), GlobalSecondaryIndexes: []*types.GlobalSecondaryIndex{}}))
+	c.Len(again.Data, 9)
+	c.Empty(again.Indexes)
+	c.Equal("PAY_PER_REQUEST", types.StringValue(again.BillingMode))
+}
+
+func TestValidateBackupName(t *testing.T) {
+	c := require.New(t)
+
+	c.NoError(ValidateBackupName("daily-2024.05_01"))
+	c.ErrorContains(ValidateBackupName("ab"), "greater than or equal to 3")
+	c.ErrorContains(ValidateBackupName(strings.Repeat("a", 256)), "less than or equal to 255")
+	c.ErrorContains(ValidateBackupName("daily backup"), "regular expression pattern")
+}
@@ -0,0 +1,24 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManualClock(t *testing.T) {
+	c := require.New(t)
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	c.Equal(start, clock.Now())
+
+	clock.Advance(time.Hour)
+	c.Equal(start.Add(time.Hour), clock.Now())
+
+	clock.Set(start)
+	c.Equal(start, clock.Now())
+
+	c.WithinDuration(time.Now(), SystemClock.Now(), time.Second)
+}
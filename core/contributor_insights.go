@@ -0,0 +1,370 @@
+package core
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// ContributorInsightsStatusEnabling is the status of Contributor Insights while it is being enabled
+	ContributorInsightsStatusEnabling = "ENABLING"
+	// ContributorInsightsStatusEnabled is the status of Contributor Insights while it ranks the keys
+	ContributorInsightsStatusEnabled = "ENABLED"
+	// ContributorInsightsStatusDisabling is the status of Contributor Insights while it is being disabled
+	ContributorInsightsStatusDisabling = "DISABLING"
+	// ContributorInsightsStatusDisabled is the status of Contributor Insights when it does not rank the keys
+	ContributorInsightsStatusDisabled = "DISABLED"
+
+	// contributorInsightsPeriod is the period the accesses are aggregated by, the one of the
+	// CloudWatch rules DynamoDB creates
+	contributorInsightsPeriod = time.Minute
+	// contributorInsightsRetention is how long the accesses are kept
+	contributorInsightsRetention = 24 * time.Hour
+)
+
+// ContributorInsightsRule is one of the rankings Contributor Insights keeps for a table or a
+// global secondary index, named after the CloudWatch rule DynamoDB creates for it
+type ContributorInsightsRule string
+
+const (
+	// MostAccessedPartitionKeys ranks the partition keys by the items read and written
+	MostAccessedPartitionKeys ContributorInsightsRule = "PKC"
+	// MostThrottledPartitionKeys ranks the partition keys by the throttled requests
+	MostThrottledPartitionKeys ContributorInsightsRule = "PKT"
+	// MostAccessedKeys ranks the partition and sort keys by the items read and written, it
+	// only exists when the table or the index has a sort key
+	MostAccessedKeys ContributorInsightsRule = "SKC"
+	// MostThrottledKeys ranks the partition and sort keys by the throttled requests, it only
+	// exists when the table or the index has a sort key
+	MostThrottledKeys ContributorInsightsRule = "SKT"
+)
+
+// ContributorInsights describes Contributor Insights for a table or one of its global
+// secondary indexes
+type ContributorInsights struct {
+	IndexName      string
+	Status         string
+	Rules          []string
+	LastUpdateTime time.Time
+}
+
+// Contributor is a key of a ranking along with the accesses it made
+type Contributor struct {
+	Key   map[string]*types.Item
+	Count int64
+}
+
+// ContributorRanking is a ranking of the keys that made the most accesses in a window,
+// Total counts the accesses of every key so the share of a key can be told
+type ContributorRanking struct {
+	Contributors []Contributor
+	Total        int64
+}
+
+// contributorInsights keeps the accesses made to a table or an index, aggregated by period
+type contributorInsights struct {
+	enabled   bool
+	updatedAt time.Time
+	// periods are ordered from the oldest one
+	periods []*insightsPeriod
+}
+
+// insightsPeriod counts the accesses of every key of every rule made during a period
+type insightsPeriod struct {
+	start  time.Time
+	counts map[ContributorInsightsRule]map[string]*Contributor
+}
+
+// UpdateContributorInsights enables or disables Contributor Insights for the table, or for
+// one of its global secondary indexes when indexName is not empty, and returns the status
+// it moves to. Like the CloudWatch rules DynamoDB deletes, disabling drops the accesses
+func (t *Table) UpdateContributorInsights(indexName string, enable bool) (string, error) {
+	if _, err := t.insightsKeySchema(indexName); err != nil {
+		return "", err
+	}
+
+	state, ok := t.insights[indexName]
+	if ok && state.enabled == enable {
+		return state.status(t.now(), t.StatusDelay), nil
+	}
+
+	if t.insights == nil {
+		t.insights = map[string]*contributorInsights{}
+	}
+
+	t.insights[indexName] = &contributorInsights{enabled: enable, updatedAt: t.now()}
+
+	if enable {
+		return ContributorInsightsStatusEnabling, nil
+	}
+
+	return ContributorInsightsStatusDisabling, nil
+}
+
+// DescribeContributorInsights describes Contributor Insights for the table, or for one of
+// its global secondary indexes when indexName is not empty. Enabling and disabling take
+// StatusDelay
+func (t *Table) DescribeContributorInsights(indexName string) (ContributorInsights, error) {
+	ks, err := t.insightsKeySchema(indexName)
+	if err != nil {
+		return ContributorInsights{}, err
+	}
+
+	state, ok := t.insights[indexName]
+	if !ok {
+		return ContributorInsights{IndexName: indexName, Status: ContributorInsightsStatusDisabled}, nil
+	}
+
+	desc := ContributorInsights{
+		IndexName:      indexName,
+		Status:         state.status(t.now(), t.StatusDelay),
+		LastUpdateTime: state.updatedAt,
+	}
+
+	if state.enabled {
+		for _, rule := range insightsRules(ks) {
+			desc.Rules = append(desc.Rules, t.insightsRuleName(rule, indexName, state.updatedAt))
+		}
+	}
+
+	return desc, nil
+}
+
+// ListContributorInsights describes Contributor Insights for the table and for its global
+// secondary indexes it was ever enabled for, the table goes first and the indexes follow
+// by name
+func (t *Table) ListContributorInsights() []ContributorInsights {
+	list := make([]ContributorInsights, 0, len(t.insights))
+
+	for _, indexName := range slices.Sorted(maps.Keys(t.insights)) {
+		desc, err := t.DescribeContributorInsights(indexName)
+		if err != nil {
+			continue
+		}
+
+		list = append(list, desc)
+	}
+
+	return list
+}
+
+// TopContributors ranks the keys of a rule of the table, or of one of its global secondary
+// indexes when indexName is not empty, by the accesses they made in the window. The window
+// is counted in whole minutes including the current one, and accesses older than 24 hours
+// are dropped. The ranking keeps the first limit keys, every key when limit is not positive
+func (t *Table) TopContributors(indexName string, rule ContributorInsightsRule, window time.Duration, limit int) (ContributorRanking, error) {
+	ks, err := t.insightsKeySchema(indexName)
+	if err != nil {
+		return ContributorRanking{}, err
+	}
+
+	state, ok := t.insights[indexName]
+	if !ok || !state.enabled {
+		return ContributorRanking{}, types.NewError("ValidationException", fmt.Sprintf("Contributor Insights is not enabled for %s", t.insightsResource(indexName)), nil)
+	}
+
+	if !slices.Contains(insightsRules(ks), rule) {
+		return ContributorRanking{}, types.NewError("ValidationException", fmt.Sprintf("Contributor Insights has no rule %s for %s", rule, t.insightsResource(indexName)), nil)
+	}
+
+	if window <= 0 {
+		return ContributorRanking{}, types.NewError("ValidationException", "The window of a Contributor Insights ranking must be positive", nil)
+	}
+
+	periods := (min(window, contributorInsightsRetention) + contributorInsightsPeriod - 1) / contributorInsightsPeriod
+	from := t.now().Truncate(contributorInsightsPeriod).Add(-(periods - 1) * contributorInsightsPeriod)
+
+	totals := map[string]*Contributor{}
+	ranking := ContributorRanking{}
+
+	for _, period := range state.periods {
+		if period.start.Before(from) {
+			continue
+		}
+
+		for key, contributor := range period.counts[rule] {
+			total, ok := totals[key]
+			if !ok {
+				total = &Contributor{Key: deepCopyItemMap(contributor.Key)}
+				totals[key] = total
+			}
+
+			total.Count += contributor.Count
+			ranking.Total += contributor.Count
+		}
+	}
+
+	keys := slices.SortedFunc(maps.Keys(totals), func(a, b string) int {
+		if c := cmp.Compare(totals[b].Count, totals[a].Count); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a, b)
+	})
+
+	if limit > 0 && limit < len(keys) {
+		keys = keys[:limit]
+	}
+
+	ranking.Contributors = make([]Contributor, 0, len(keys))
+	for _, key := range keys {
+		ranking.Contributors = append(ranking.Contributors, *totals[key])
+	}
+
+	return ranking, nil
+}
+
+// RecordAccess counts a read or a write of the item with the given key made outside of the
+// table methods, like the ones of GetItem
+func (t *Table) RecordAccess(key map[string]*types.Item) {
+	t.recordContribution(PrimaryIndexName, key, false)
+}
+
+// RecordThrottle counts a request for the item with the given key that was throttled, like
+// the batch requests left unprocessed
+func (t *Table) RecordThrottle(key map[string]*types.Item) {
+	t.recordContribution(PrimaryIndexName, key, true)
+}
+
+// recordIndexWrites counts a write of the item in the global secondary indexes holding it
+func (t *Table) recordIndexWrites(item map[string]*types.Item) {
+	for indexName := range t.insights {
+		if indexName != PrimaryIndexName {
+			t.recordContribution(indexName, item, false)
+		}
+	}
+}
+
+// recordContribution counts an access to the item in the table or in one of its global
+// secondary indexes, the items an index does not hold are not counted
+func (t *Table) recordContribution(indexName string, item map[string]*types.Item, throttled bool) {
+	state, ok := t.insights[indexName]
+	if !ok || !state.enabled {
+		return
+	}
+
+	now := t.now()
+	if state.status(now, t.StatusDelay) != ContributorInsightsStatusEnabled {
+		return
+	}
+
+	ks, err := t.insightsKeySchema(indexName)
+	if err != nil {
+		return
+	}
+
+	hashKey, rangeKey, err := ks.keyParts(t.AttributesDef, item)
+	if err != nil {
+		return
+	}
+
+	partitionRule, keyRule := MostAccessedPartitionKeys, MostAccessedKeys
+	if throttled {
+		partitionRule, keyRule = MostThrottledPartitionKeys, MostThrottledKeys
+	}
+
+	period := state.period(now)
+	period.add(partitionRule, hashKey, map[string]*types.Item{ks.HashKey: item[ks.HashKey]})
+
+	if ks.RangeKey != "" {
+		period.add(keyRule, joinKeyParts(hashKey, rangeKey), map[string]*types.Item{ks.HashKey: item[ks.HashKey], ks.RangeKey: item[ks.RangeKey]})
+	}
+}
+
+// insightsKeySchema returns the key schema of the table or of the global secondary index
+// Contributor Insights is asked for
+func (t *Table) insightsKeySchema(indexName string) (keySchema, error) {
+	if indexName == PrimaryIndexName {
+		return t.KeySchema, nil
+	}
+
+	idx, ok := t.Indexes[indexName]
+	if !ok {
+		return keySchema{}, types.NewError("ResourceNotFoundException", "Requested resource not found", nil)
+	}
+
+	if idx.typ != indexTypeGlobal {
+		return keySchema{}, types.NewError("ValidationException", fmt.Sprintf("Contributor Insights is not supported for local secondary index: %s", indexName), nil)
+	}
+
+	return idx.keySchema, nil
+}
+
+func (t *Table) insightsResource(indexName string) string {
+	if indexName == PrimaryIndexName {
+		return t.Name
+	}
+
+	return t.Name + "-" + indexName
+}
+
+// insightsRuleName names a rule after the CloudWatch rules DynamoDB creates when
+// Contributor Insights is enabled
+func (t *Table) insightsRuleName(rule ContributorInsightsRule, indexName string, enabledAt time.Time) string {
+	return fmt.Sprintf("DynamoDBContributorInsights-%s-%s-%d", rule, t.insightsResource(indexName), enabledAt.UnixMilli())
+}
+
+func insightsRules(ks keySchema) []ContributorInsightsRule {
+	if ks.RangeKey == "" {
+		return []ContributorInsightsRule{MostAccessedPartitionKeys, MostThrottledPartitionKeys}
+	}
+
+	return []ContributorInsightsRule{MostAccessedPartitionKeys, MostThrottledPartitionKeys, MostAccessedKeys, MostThrottledKeys}
+}
+
+func (s *contributorInsights) status(now time.Time, delay time.Duration) string {
+	inTransition := now.Sub(s.updatedAt) < delay
+
+	switch {
+	case s.enabled && inTransition:
+		return ContributorInsightsStatusEnabling
+	case s.enabled:
+		return ContributorInsightsStatusEnabled
+	case inTransition:
+		return ContributorInsightsStatusDisabling
+	}
+
+	return ContributorInsightsStatusDisabled
+}
+
+// period returns the period the accesses made now are counted in, dropping the periods
+// past the retention
+func (s *contributorInsights) period(now time.Time) *insightsPeriod {
+	start := now.Truncate(contributorInsightsPeriod)
+
+	if n := len(s.periods); n > 0 && !s.periods[n-1].start.Before(start) {
+		return s.periods[n-1]
+	}
+
+	expired := 0
+	for expired < len(s.periods) && !s.periods[expired].start.After(start.Add(-contributorInsightsRetention)) {
+		expired++
+	}
+
+	period := &insightsPeriod{start: start, counts: map[ContributorInsightsRule]map[string]*Contributor{}}
+	s.periods = append(s.periods[expired:], period)
+
+	return period
+}
+
+func (p *insightsPeriod) add(rule ContributorInsightsRule, encodedKey string, key map[string]*types.Item) {
+	counts, ok := p.counts[rule]
+	if !ok {
+		counts = map[string]*Contributor{}
+		p.counts[rule] = counts
+	}
+
+	contributor, ok := counts[encodedKey]
+	if !ok {
+		contributor = &Contributor{Key: deepCopyItemMap(key)}
+		counts[encodedKey] = contributor
+	}
+
+	contributor.Count++
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestContributorInsights(t *testing.T) {
+	c := require.New(t)
+
+	clock := NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	table := createTrainerTable(c)
+	table.Clock = clock
+
+	desc, err := table.DescribeContributorInsights("")
+	c.NoError(err)
+	c.Equal(ContributorInsightsStatusDisabled, desc.Status)
+
+	_, err = table.TopContributors("", MostAccessedPartitionKeys, time.Minute, 0)
+	c.ErrorContains(err, "Contributor Insights is not enabled for trainers")
+
+	_, err = table.UpdateContributorInsights("by-name", true)
+	c.ErrorContains(err, "Requested resource not found")
+
+	status, err := table.UpdateContributorInsights("", true)
+	c.NoError(err)
+	c.Equal(ContributorInsightsStatusEnabling, status)
+
+	status, err = table.UpdateContributorInsights("by-type", true)
+	c.NoError(err)
+	c.Equal(ContributorInsightsStatusEnabling, status)
+
+	desc, err = table.DescribeContributorInsights("")
+	c.NoError(err)
+	c.Equal(ContributorInsightsStatusEnabled, desc.Status)
+	c.Equal(clock.Now(), desc.LastUpdateTime)
+	c.Equal([]string{
+		fmt.Sprintf("DynamoDBContributorInsights-PKC-trainers-%d", clock.Now().UnixMilli()),
+		fmt.Sprintf("DynamoDBContributorInsights-PKT-trainers-%d", clock.Now().UnixMilli()),
+		fmt.Sprintf("DynamoDBContributorInsights-SKC-trainers-%d", clock.Now().UnixMilli()),
+		fmt.Sprintf("DynamoDBContributorInsights-SKT-trainers-%d", clock.Now().UnixMilli()),
+	}, desc.Rules)
+
+	// reads and writes of the items are counted on the table and on the indexes holding them
+	items, _, err := table.SearchData(QueryInput{
+		KeyConditionExpression:    "trainer = :trainer",
+		ExpressionAttributeValues: map[string]*types.Item{":trainer": {S: new("ash")}},
+		ScanIndexForward:          true,
+	})
+	c.NoError(err)
+	c.Len(items, 4)
+
+	staryu := map[string]*types.Item{"trainer": {S: new("misty")}, "pokemon": {S: new("staryu")}}
+
+	_, err = table.Put(&types.PutItemInput{Item: map[string]*types.Item{"trainer": {S: new("misty")}, "pokemon": {S: new("staryu")}, "type": {S: new("psychic")}}})
+	c.NoError(err)
+
+	table.RecordAccess(staryu)
+	table.RecordThrottle(map[string]*types.Item{"trainer": {S: new("brock")}, "pokemon": {S: new("onix")}})
+
+	ranking, err := table.TopContributors("", MostAccessedPartitionKeys, time.Minute, 0)
+	c.NoError(err)
+	c.Equal(int64(6), ranking.Total)
+	c.Equal([]Contributor{
+		{Key: map[string]*types.Item{"trainer": {S: new("ash")}}, Count: 4},
+		{Key: map[string]*types.Item{"trainer": {S: new("misty")}}, Count: 2},
+	}, ranking.Contributors)
+
+	ranking, err = table.TopContributors("", MostAccessedKeys, time.Minute, 1)
+	c.NoError(err)
+	c.Equal([]Contributor{{Key: staryu, Count: 2}}, ranking.Contributors)
+
+	ranking, err = table.TopContributors("", MostThrottledPartitionKeys, time.Minute, 0)
+	c.NoError(err)
+	c.Equal([]Contributor{{Key: map[string]*types.Item{"trainer": {S: new("brock")}}, Count: 1}}, ranking.Contributors)
+
+	ranking, err = table.TopContributors("by-type", MostAccessedPartitionKeys, time.Minute, 0)
+	c.NoError(err)
+	c.Equal([]Contributor{{Key: map[string]*types.Item{"type": {S: new("psychic")}}, Count: 1}}, ranking.Contributors)
+
+	// the window slides by whole minutes
+	clock.Advance(2 * time.Minute)
+
+	_, err = table.Delete(&types.DeleteItemInput{Key: map[string]*types.Item{"trainer": {S: new("brock")}, "pokemon": {S: new("onix")}}})
+	c.NoError(err)
+
+	ranking, err = table.TopContributors("", MostAccessedPartitionKeys, time.Minute, 0)
+	c.NoError(err)
+	c.Equal([]Contributor{{Key: map[string]*types.Item{"trainer": {S: new("brock")}}, Count: 1}}, ranking.Contributors)
+
+	ranking, err = table.TopContributors("", MostAccessedPartitionKeys, 3*time.Minute, 0)
+	c.NoError(err)
+	c.Equal(int64(7), ranking.Total)
+
+	ranking, err = table.TopContributors("by-type", MostAccessedKeys, 3*time.Minute, 0)
+	c.NoError(err)
+	c.Len(ranking.Contributors, 2)
+
+	_, err = table.TopContributors("", MostAccessedPartitionKeys, 0, 0)
+	c.Error(err)
+
+	status, err = table.UpdateContributorInsights("", false)
+	c.NoError(err)
+	c.Equal(ContributorInsightsStatusDisabling, status)
+
+	_, err = table.TopContributors("", MostAccessedPartitionKeys, time.Minute, 0)
+	c.Error(err)
+
+	list := table.ListContributorInsights()
+	c.Len(list, 2)
+	c.Equal(ContributorInsightsStatusDisabled, list[0].Status)
+	c.Empty(list[0].Rules)
+	c.Equal("by-type", list[1].IndexName)
+
+	c.NoError(table.deleteIndex("by-type"))
+	c.Len(table.ListContributorInsights(), 1)
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"container/heap"
+
+	"github.com/truora/minidyn/types"
+)
+
+// expiration is the time an item expires at, in epoch seconds
+type expiration struct {
+	at  types.Decimal
+	key string
+}
+
+// expirationHeap keeps the expirations of the items of a table with Time To Live enabled,
+// the earliest first, so a sweep only visits the items that are due. Entries are not
+// removed when their item changes, a sweep checks the current item before deleting it.
+type expirationHeap []expiration
+
+func (h expirationHeap) Len() int           { return len(h) }
+func (h expirationHeap) Less(i, j int) bool { return h[i].at.Cmp(h[j].at) < 0 }
+func (h expirationHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *expirationHeap) Push(x any) {
+	*h = append(*h, x.(expiration))
+}
+
+func (h *expirationHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return last
+}
+
+// trackExpiration adds the expiration of the item to the heap of the table, items without
+// a numeric Time To Live attribute never expire
+func (t *Table) trackExpiration(key string, item map[string]*types.Item) {
+	at, ok := t.expiresAt(item)
+	if !ok {
+		return
+	}
+
+	heap.Push(&t.expirations, expiration{at: at, key: key})
+
+	// stale entries left by updated and deleted items are dropped once they outnumber the
+	// items of the table
+	if len(t.expirations) > 2*len(t.Data)+1 {
+		t.rebuildExpirations()
+	}
+}
+
+// rebuildExpirations indexes the expiration of every item of the table
+func (t *Table) rebuildExpirations() {
+	t.expirations = nil
+
+	if t.ttlAttribute == "" {
+		return
+	}
+
+	for key, item := range t.Data {
+		if at, ok := t.expiresAt(item); ok {
+			t.expirations = append(t.expirations, expiration{at: at, key: key})
+		}
+	}
+
+	heap.Init(&t.expirations)
+}
+
+// popExpired removes from the heap the entries expiring before now and returns the keys
+// of the items that are still expired
+func (t *Table) popExpired(now types.Decimal) map[string]bool {
+	expired := map[string]bool{}
+
+	for len(t.expirations) > 0 && t.expirations[0].at.Cmp(now) < 0 {
+		e := heap.Pop(&t.expirations).(expiration)
+
+		if item, ok := t.Data[e.key]; ok {
+			if at, ok := t.expiresAt(item); ok && at.Cmp(now) < 0 {
+				expired[e.key] = true
+			}
+		}
+	}
+
+	return expired
+}
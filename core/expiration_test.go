@@ -0,0 +1,81 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestExpirations(t *testing.T) {
+	c := require.New(t)
+
+	table, _ := createStreamTable(t, StreamViewTypeKeysOnly)
+	now := time.Unix(1_700_000_000, 0)
+
+	put := func(id, expiresAt string) {
+		_, err := table.Put(&types.PutItemInput{Item: map[string]*types.Item{"id": {S: new(id)}, "expires_at": {N: new(expiresAt)}}})
+		c.NoError(err)
+	}
+
+	put("old", "1600000000")
+	c.NoError(table.UpdateTimeToLive(true, "expires_at"))
+	c.Len(table.expirations, 1, "enabling Time To Live indexes the stored items")
+
+	for i := range 10 {
+		put(fmt.Sprintf("fresh %d", i), "1800000000")
+	}
+
+	// only the due entries are taken out of the heap
+	c.Equal(1, table.SweepExpired(now))
+	c.Len(table.expirations, 10)
+
+	// an item whose expiration moved later is kept, an item deleted before it expires
+	// leaves a stale entry behind
+	put("extended", "1650000000")
+	put("extended", "1900000000")
+	put("deleted", "1650000000")
+
+	_, err := table.Delete(&types.DeleteItemInput{Key: map[string]*types.Item{"id": {S: new("deleted")}}})
+	c.NoError(err)
+
+	c.Zero(table.SweepExpired(now))
+	c.Contains(table.Data, "extended")
+
+	// updates never let the stale entries outgrow the items
+	for range 50 {
+		put("extended", "1900000000")
+	}
+
+	c.LessOrEqual(len(table.expirations), 2*len(table.Data)+1)
+
+	c.Zero(table.SweepExpired(now))
+	c.Equal(11, table.SweepExpired(time.Unix(2_000_000_000, 0)))
+
+	c.NoError(table.UpdateTimeToLive(false, "expires_at"))
+	c.Empty(table.expirations)
+}
+
+func TestExpirationsRestore(t *testing.T) {
+	c := require.New(t)
+
+	table, _ := createStreamTable(t, StreamViewTypeKeysOnly)
+	c.NoError(table.UpdateTimeToLive(true, "expires_at"))
+
+	snapshot := table.Snapshot()
+
+	_, err := table.Put(&types.PutItemInput{Item: map[string]*types.Item{"id": {S: new("rolled back")}, "expires_at": {N: new("1")}}})
+	c.NoError(err)
+
+	table.Restore(snapshot)
+	c.Empty(table.expirations)
+
+	_, err = table.Put(&types.PutItemInput{Item: map[string]*types.Item{"id": {S: new("kept")}, "expires_at": {N: new("1")}}})
+	c.NoError(err)
+
+	table.Clear()
+	c.Empty(table.expirations)
+	c.Zero(table.SweepExpired(time.Now()))
+}
@@ -0,0 +1,418 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5" //nolint:gosec // S3 reports the md5 of the data files
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// ExportFormatDynamoDBJSON writes each item as a line of DynamoDB JSON
+	ExportFormatDynamoDBJSON = "DYNAMODB_JSON"
+	// ExportFormatIon writes each item as an Amazon Ion text struct
+	ExportFormatIon = "ION"
+	// ExportTypeFull exports the items of the table at a point in time
+	ExportTypeFull = "FULL_EXPORT"
+	// ExportTypeIncremental exports the items changed during a period of time
+	ExportTypeIncremental = "INCREMENTAL_EXPORT"
+	// ExportViewTypeNewImage writes only the new version of the changed items
+	ExportViewTypeNewImage = "NEW_IMAGE"
+	// ExportViewTypeNewAndOldImages writes the new and the old versions of the changed items
+	ExportViewTypeNewAndOldImages = "NEW_AND_OLD_IMAGES"
+	// ExportStatusCompleted is the status of an export written to its sink
+	ExportStatusCompleted = "COMPLETED"
+	// ExportStatusFailed is the status of an export that could not be written
+	ExportStatusFailed = "FAILED"
+
+	exportManifestVersion      = "2020-06-30"
+	exportTimeFormat           = "2006-01-02T15:04:05.000Z"
+	minIncrementalExportPeriod = 15 * time.Minute
+	maxIncrementalExportPeriod = 24 * time.Hour
+)
+
+// ExportSink receives the files of a table export, names are slash separated and start with
+// the S3 bucket of the export
+type ExportSink interface {
+	WriteFile(name string, data []byte) error
+}
+
+// DirectoryExportSink is an ExportSink writing the files under a local directory, each
+// bucket is a subdirectory
+type DirectoryExportSink string
+
+// WriteFile writes data to the file name under the directory, creating its parents
+func (d DirectoryExportSink) WriteFile(name string, data []byte) error {
+	filename := filepath.Join(string(d), filepath.FromSlash(name))
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0o750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0o600)
+}
+
+// ExportInput describes a table export
+type ExportInput struct {
+	ExportArn   string
+	ClientToken string
+	TableArn    string
+	S3Bucket    string
+	S3Prefix    string
+	// Format is ExportFormatDynamoDBJSON or ExportFormatIon
+	Format string
+	// Type is ExportTypeFull or ExportTypeIncremental
+	Type string
+	// ExportTime is the point in time of a full export
+	ExportTime time.Time
+	// ExportFromTime, ExportToTime and ViewType describe an incremental export
+	ExportFromTime time.Time
+	ExportToTime   time.Time
+	ViewType       string
+}
+
+// Export is a table export written to an ExportSink
+type Export struct {
+	ExportInput
+
+	Status         string
+	FailureCode    string
+	FailureMessage string
+
+	StartTime       time.Time
+	EndTime         time.Time
+	ItemCount       int64
+	BilledSizeBytes int64
+	// Manifest is the key of the manifest-summary.json file
+	Manifest string
+}
+
+// exportRecord is a line of a data file, like {"Item":{...}} in a full export or
+// {"Keys":{...},"NewImage":{...}} in an incremental export
+type exportRecord map[string]map[string]*types.Item
+
+// exportDataFile is the content of a data file of an export before it is compressed
+type exportDataFile struct {
+	lines     bytes.Buffer
+	itemCount int64
+}
+
+// ValidateExportInput checks the format and the type of an export, filling their defaults
+func ValidateExportInput(input *ExportInput) error {
+	if input.Format == "" {
+		input.Format = ExportFormatDynamoDBJSON
+	}
+
+	if input.Type == "" {
+		input.Type = ExportTypeFull
+	}
+
+	if input.Type == ExportTypeIncremental && input.ViewType == "" {
+		input.ViewType = ExportViewTypeNewAndOldImages
+	}
+
+	var field, value, allowed string
+
+	switch {
+	case input.Format != ExportFormatDynamoDBJSON && input.Format != ExportFormatIon:
+		field, value, allowed = "exportFormat", input.Format, "[DYNAMODB_JSON, ION]"
+	case input.Type != ExportTypeFull && input.Type != ExportTypeIncremental:
+		field, value, allowed = "exportType", input.Type, "[FULL_EXPORT, INCREMENTAL_EXPORT]"
+	case input.Type == ExportTypeIncremental && input.ViewType != ExportViewTypeNewImage && input.ViewType != ExportViewTypeNewAndOldImages:
+		field, value, allowed = "incrementalExportSpecification.exportViewType", input.ViewType, "[NEW_IMAGE, NEW_AND_OLD_IMAGES]"
+	default:
+		return nil
+	}
+
+	return types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%s' at '%s' failed to satisfy constraint: Member must satisfy enum value set: %s", value, field, allowed), nil)
+}
+
+// Export writes the items of the table to sink following the layout of DynamoDB exports to
+// S3: a manifest-summary.json, a manifest-files.json and gzipped data files under data/, it
+// needs point in time recovery to be enabled. Like the real service, a missing sink or a
+// failed write are reported by the status of the export instead of an error
+func (t *Table) Export(sink ExportSink, input ExportInput, now time.Time) (*Export, error) {
+	if t.history == nil {
+		return nil, types.NewError("PointInTimeRecoveryUnavailableException", fmt.Sprintf("Point in time recovery is not enabled for table '%s'", t.Name), nil)
+	}
+
+	t.history.compact(now)
+
+	data := &exportDataFile{}
+
+	var err error
+
+	switch input.Type {
+	case ExportTypeIncremental:
+		err = t.history.writeIncrementalExport(data, t.KeySchema, input, now)
+	default:
+		err = t.history.writeFullExport(data, input, now)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	export := &Export{
+		ExportInput: input,
+		Status:      ExportStatusFailed,
+		StartTime:   now,
+		EndTime:     now,
+	}
+
+	if sink == nil {
+		export.FailureCode, export.FailureMessage = "S3NoSuchBucket", "The specified bucket does not exist"
+
+		return export, nil
+	}
+
+	export.ItemCount = data.itemCount
+
+	if err := export.write(sink, data); err != nil {
+		export.ItemCount = 0
+		export.FailureCode, export.FailureMessage = "UNKNOWN", err.Error()
+
+		return export, nil
+	}
+
+	export.Status = ExportStatusCompleted
+
+	return export, nil
+}
+
+func (h *pointInTimeHistory) checkExportTime(name string, at, now time.Time) error {
+	if at.Before(h.start) || at.After(now) {
+		return types.NewError("InvalidExportTimeException", fmt.Sprintf("%s must be between %s and %s", name, h.start.UTC().Format(time.RFC3339), now.UTC().Format(time.RFC3339)), nil)
+	}
+
+	return nil
+}
+
+func (h *pointInTimeHistory) writeFullExport(data *exportDataFile, input ExportInput, now time.Time) error {
+	if err := h.checkExportTime("ExportTime", input.ExportTime, now); err != nil {
+		return err
+	}
+
+	items := h.itemsAt(input.ExportTime)
+
+	for _, key := range slices.Sorted(maps.Keys(items)) {
+		if err := data.add(input.Format, exportRecord{"Item": items[key]}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *pointInTimeHistory) writeIncrementalExport(data *exportDataFile, ks keySchema, input ExportInput, now time.Time) error {
+	if err := h.checkExportTime("ExportFromTime", input.ExportFromTime, now); err != nil {
+		return err
+	}
+
+	if err := h.checkExportTime("ExportToTime", input.ExportToTime, now); err != nil {
+		return err
+	}
+
+	period := input.ExportToTime.Sub(input.ExportFromTime)
+	if period < minIncrementalExportPeriod || period > maxIncrementalExportPeriod {
+		return types.NewError("ValidationException", fmt.Sprintf("The incremental export period must be between %s and %s", minIncrementalExportPeriod, maxIncrementalExportPeriod), nil)
+	}
+
+	oldItems := h.itemsAt(input.ExportFromTime)
+	newItems := h.itemsAt(input.ExportToTime)
+
+	written := map[string]time.Time{}
+
+	for _, change := range h.changes {
+		if !change.at.After(input.ExportFromTime) {
+			continue
+		}
+
+		if change.at.After(input.ExportToTime) {
+			break
+		}
+
+		if change.cleared {
+			for key := range oldItems {
+				written[key] = change.at
+			}
+
+			continue
+		}
+
+		written[change.key] = change.at
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(written)) {
+		oldItem, newItem := oldItems[key], newItems[key]
+		if oldItem == nil && newItem == nil {
+			continue
+		}
+
+		source := newItem
+		if source == nil {
+			source = oldItem
+		}
+
+		record := exportRecord{
+			"Metadata": {"WriteTimestampMicros": {N: new(strconv.FormatInt(written[key].UnixMicro(), 10))}},
+			"Keys":     ks.getKeyItem(source),
+		}
+
+		if newItem != nil {
+			record["NewImage"] = newItem
+		}
+
+		if oldItem != nil && input.ViewType == ExportViewTypeNewAndOldImages {
+			record["OldImage"] = oldItem
+		}
+
+		if err := data.add(input.Format, record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// add appends a line with the record to the data file, encoded in the export format
+func (d *exportDataFile) add(format string, record exportRecord) error {
+	d.itemCount++
+
+	if format == ExportFormatIon {
+		var b strings.Builder
+
+		if d.lines.Len() == 0 {
+			b.WriteString("$ion_1_0 ")
+		}
+
+		b.WriteByte('{')
+
+		for i, name := range slices.Sorted(maps.Keys(record)) {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+
+			writeIonFieldName(&b, name)
+			writeIonStruct(&b, record[name])
+		}
+
+		b.WriteString("}\n")
+		d.lines.WriteString(b.String())
+
+		return nil
+	}
+
+	encoded := make(map[string]any, len(record))
+	for name, item := range record {
+		encoded[name] = dynamoDBJSON(item)
+	}
+
+	line, err := marshalJSONLine(encoded)
+	if err != nil {
+		return err
+	}
+
+	d.lines.Write(line)
+
+	return nil
+}
+
+// write compresses the data file and writes it with the manifests to sink
+func (e *Export) write(sink ExportSink, data *exportDataFile) error {
+	exportID := path.Base(e.ExportArn)
+	dir := path.Join(e.S3Prefix, "AWSDynamoDB", exportID)
+
+	extension := ".json.gz"
+	if e.Format == ExportFormatIon {
+		extension = ".ion.gz"
+	}
+
+	var compressed bytes.Buffer
+
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(data.lines.Bytes()); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	e.BilledSizeBytes = int64(data.lines.Len())
+
+	sum := md5.Sum(compressed.Bytes()) //nolint:gosec // S3 reports the md5 of the data files
+	dataKey := path.Join(dir, "data", strings.ReplaceAll(exportID, "-", "")+extension)
+	filesKey := path.Join(dir, "manifest-files.json")
+	e.Manifest = path.Join(dir, "manifest-summary.json")
+
+	files, err := marshalJSONLine(map[string]any{
+		"itemCount":     data.itemCount,
+		"md5Checksum":   base64.StdEncoding.EncodeToString(sum[:]),
+		"etag":          hex.EncodeToString(sum[:]),
+		"dataFileS3Key": dataKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	summary, err := marshalJSONLine(e.manifestSummary(filesKey))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range []struct {
+		key  string
+		data []byte
+	}{
+		{dataKey, compressed.Bytes()},
+		{filesKey, files},
+		{e.Manifest, summary},
+	} {
+		if err := sink.WriteFile(path.Join(e.S3Bucket, file.key), file.data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *Export) manifestSummary(filesKey string) map[string]any {
+	summary := map[string]any{
+		"version":            exportManifestVersion,
+		"exportArn":          e.ExportArn,
+		"startTime":          e.StartTime.UTC().Format(exportTimeFormat),
+		"endTime":            e.EndTime.UTC().Format(exportTimeFormat),
+		"tableArn":           e.TableArn,
+		"s3Bucket":           e.S3Bucket,
+		"s3Prefix":           e.S3Prefix,
+		"s3SseAlgorithm":     "AES256",
+		"manifestFilesS3Key": filesKey,
+		"billedSizeBytes":    e.BilledSizeBytes,
+		"itemCount":          e.ItemCount,
+		"outputFormat":       e.Format,
+		"exportType":         e.Type,
+	}
+
+	if e.Type == ExportTypeIncremental {
+		summary["exportFromTime"] = e.ExportFromTime.UTC().Format(exportTimeFormat)
+		summary["exportToTime"] = e.ExportToTime.UTC().Format(exportTimeFormat)
+		summary["outputView"] = e.ViewType
+	} else {
+		summary["exportTime"] = e.ExportTime.UTC().Format(exportTimeFormat)
+	}
+
+	return summary
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+type memoryExportSink map[string][]byte
+
+func (m memoryExportSink) WriteFile(name string, data []byte) error {
+	m[name] = data
+
+	return nil
+}
+
+func readExportDataFile(c *require.Assertions, sink memoryExportSink, export *Export) []string {
+	var files map[string]any
+
+	filesKey := strings.Replace(export.Manifest, "manifest-summary.json", "manifest-files.json", 1)
+	c.NoError(json.Unmarshal(sink["bucket/"+filesKey], &files))
+
+	zr, err := gzip.NewReader(bytes.NewReader(sink["bucket/"+files["dataFileS3Key"].(string)]))
+	c.NoError(err)
+
+	raw, err := io.ReadAll(zr)
+	c.NoError(err)
+
+	return strings.Split(strings.TrimSuffix(string(raw), "\n"), "\n")
+}
+
+func TestExport(t *testing.T) {
+	c := require.New(t)
+
+	clock := NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	table := createTrainerTable(c)
+	table.Clock = clock
+
+	sink := memoryExportSink{}
+	input := ExportInput{
+		ExportArn: "arn:aws:dynamodb:us-east-1:000000000000:table/trainers/export/01714557600000-00000001",
+		S3Bucket:  "bucket",
+		S3Prefix:  "exports",
+	}
+	c.NoError(ValidateExportInput(&input))
+	c.Equal(ExportFormatDynamoDBJSON, input.Format)
+	c.Equal(ExportTypeFull, input.Type)
+
+	_, err := table.Export(sink, input, clock.Now())
+	c.ErrorContains(err, "PointInTimeRecoveryUnavailableException")
+
+	enabledAt := clock.Now()
+	c.NoError(table.EnablePointInTimeRecovery(DefaultRecoveryPeriodInDays, enabledAt))
+
+	clock.Advance(time.Hour)
+
+	_, err = table.Put(&types.PutItemInput{Item: map[string]*types.Item{"trainer": {S: new("ash")}, "pokemon": {S: new("pikachu")}, "level": {N: new("42")}}})
+	c.NoError(err)
+
+	_, err = table.Delete(&types.DeleteItemInput{Key: map[string]*types.Item{"trainer": {S: new("misty")}, "pokemon": {S: new("staryu")}}})
+	c.NoError(err)
+
+	clock.Advance(time.Minute)
+
+	input.ExportTime = enabledAt
+	export, err := table.Export(nil, input, clock.Now())
+	c.NoError(err)
+	c.Equal(ExportStatusFailed, export.Status)
+	c.Equal("S3NoSuchBucket", export.FailureCode)
+
+	export, err = table.Export(sink, input, clock.Now())
+	c.NoError(err)
+	c.Equal(ExportStatusCompleted, export.Status)
+	c.EqualValues(9, export.ItemCount)
+	c.Equal("exports/AWSDynamoDB/01714557600000-00000001/manifest-summary.json", export.Manifest)
+
+	lines := readExportDataFile(c, sink, export)
+	c.Len(lines, 9)
+
+	var record map[string]map[string]map[string]any
+	c.NoError(json.Unmarshal([]byte(lines[0]), &record))
+	c.Equal("ash", record["Item"]["trainer"]["S"])
+
+	var summary map[string]any
+	c.NoError(json.Unmarshal(sink["bucket/"+export.Manifest], &summary))
+	c.Equal("2020-06-30", summary["version"])
+	c.Equal("FULL_EXPORT", summary["exportType"])
+	c.Equal("2024-05-01T10:00:00.000Z", summary["exportTime"])
+	c.EqualValues(9, summary["itemCount"])
+
+	input.Type = ExportTypeIncremental
+	input.Format = ExportFormatIon
+	input.ExportFromTime = enabledAt
+	input.ExportToTime = clock.Now()
+	c.NoError(ValidateExportInput(&input))
+	c.Equal(ExportViewTypeNewAndOldImages, input.ViewType)
+
+	export, err = table.Export(sink, input, clock.Now())
+	c.NoError(err)
+	c.EqualValues(2, export.ItemCount)
+
+	lines = readExportDataFile(c, sink, export)
+	c.Len(lines, 2)
+	c.True(strings.HasPrefix(lines[0], "$ion_1_0 {Keys:{pokemon:\"pikachu\",trainer:\"ash\"},Metadata:{WriteTimestampMicros:1714561200000000.},NewImage:{level:42.,"))
+	c.Contains(lines[0], "OldImage:{")
+	c.True(strings.HasPrefix(lines[1], "{Keys:{pokemon:\"staryu\",trainer:\"misty\"}"))
+	c.NotContains(lines[1], "NewImage")
+
+	input.ExportToTime = enabledAt.Add(time.Minute)
+	_, err = table.Export(sink, input, clock.Now())
+	c.ErrorContains(err, "incremental export period")
+
+	input.ExportFromTime = enabledAt.Add(-time.Hour)
+	_, err = table.Export(sink, input, clock.Now())
+	c.ErrorContains(err, "InvalidExportTimeException")
+
+	input.ViewType = "KEYS_ONLY"
+	c.ErrorContains(ValidateExportInput(&input), "exportViewType")
+}
+
+func TestDirectoryExportSink(t *testing.T) {
+	c := require.New(t)
+
+	dir := t.TempDir()
+	sink := DirectoryExportSink(dir)
+
+	c.NoError(sink.WriteFile("bucket/prefix/AWSDynamoDB/id/manifest-summary.json", []byte("{}")))
+
+	raw, err := os.ReadFile(filepath.Join(dir, "bucket", "prefix", "AWSDynamoDB", "id", "manifest-summary.json"))
+	c.NoError(err)
+	c.Equal("{}", string(raw))
+}
@@ -0,0 +1,374 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// ImportFormatCSV reads each line as the comma separated values of an item
+	ImportFormatCSV = "CSV"
+	// ImportFormatDynamoDBJSON reads each line as an item in DynamoDB JSON
+	ImportFormatDynamoDBJSON = "DYNAMODB_JSON"
+	// ImportFormatIon reads each line as an item in Amazon Ion text
+	ImportFormatIon = "ION"
+	// ImportCompressionNone reads the objects as they are
+	ImportCompressionNone = "NONE"
+	// ImportCompressionGzip decompresses the objects with gzip
+	ImportCompressionGzip = "GZIP"
+	// ImportCompressionZstd decompresses the objects with zstd
+	ImportCompressionZstd = "ZSTD"
+	// ImportStatusCompleted is the status of an import that read its source
+	ImportStatusCompleted = "COMPLETED"
+	// ImportStatusFailed is the status of an import whose source could not be read
+	ImportStatusFailed = "FAILED"
+
+	csvDelimiters = ",\t:;| "
+)
+
+// ImportInput describes a table import
+type ImportInput struct {
+	ImportArn   string
+	ClientToken string
+	S3Bucket    string
+	S3KeyPrefix string
+	// Format is ImportFormatCSV, ImportFormatDynamoDBJSON or ImportFormatIon
+	Format string
+	// Compression is ImportCompressionNone, ImportCompressionGzip or ImportCompressionZstd
+	Compression string
+	// CSVDelimiter and CSVHeader are the options of the CSV format, without a header the
+	// first line of every object is the header
+	CSVDelimiter string
+	CSVHeader    []string
+}
+
+// ImportError is an item that could not be imported, DynamoDB logs them to CloudWatch with a
+// pointer to the S3 object holding the item
+type ImportError struct {
+	S3Bucket string
+	Key      string
+	// ItemIndex is the position of the item in the object, starting at 0
+	ItemIndex int
+	Message   string
+}
+
+// Import is a table import read from an fs.FS standing in for S3
+type Import struct {
+	ImportInput
+
+	TableName      string
+	Status         string
+	FailureCode    string
+	FailureMessage string
+
+	StartTime          time.Time
+	EndTime            time.Time
+	ProcessedSizeBytes int64
+	ProcessedItemCount int64
+	ImportedItemCount  int64
+	ErrorCount         int64
+	Errors             []ImportError
+}
+
+// ValidateImportInput checks the format, the compression and the CSV options of an import,
+// filling their defaults
+func ValidateImportInput(input *ImportInput) error {
+	if input.Compression == "" {
+		input.Compression = ImportCompressionNone
+	}
+
+	var field, value, constraint string
+
+	switch {
+	case input.S3Bucket == "":
+		return types.NewError("ValidationException", "1 validation error detected: Value null at 's3BucketSource.s3Bucket' failed to satisfy constraint: Member must not be null", nil)
+	case input.Format != ImportFormatCSV && input.Format != ImportFormatDynamoDBJSON && input.Format != ImportFormatIon:
+		field, value, constraint = "inputFormat", input.Format, "Member must satisfy enum value set: [CSV, DYNAMODB_JSON, ION]"
+	case input.Compression != ImportCompressionNone && input.Compression != ImportCompressionGzip && input.Compression != ImportCompressionZstd:
+		field, value, constraint = "inputCompressionType", input.Compression, "Member must satisfy enum value set: [GZIP, ZSTD, NONE]"
+	case input.CSVDelimiter != "" && (len(input.CSVDelimiter) != 1 || !strings.Contains(csvDelimiters, input.CSVDelimiter)):
+		field, value, constraint = "inputFormatOptions.csv.delimiter", input.CSVDelimiter, "Member must satisfy regular expression pattern: [,;:|\\t ]"
+	case input.Format != ImportFormatCSV && (input.CSVDelimiter != "" || input.CSVHeader != nil):
+		return types.NewError("ValidationException", "InputFormatOptions can only be specified with the CSV input format", nil)
+	default:
+		return nil
+	}
+
+	return types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%s' at '%s' failed to satisfy constraint: %s", value, field, constraint), nil)
+}
+
+// Import puts the items of the objects under the key prefix of the bucket into t, the
+// bucket being a directory of source. Like the real service, an unreadable source is
+// reported by the status of the import and items that can't be read or written are
+// counted as errors instead of stopping the import
+func (t *Table) Import(source fs.FS, input ImportInput, now time.Time) *Import {
+	imp := &Import{
+		ImportInput: input,
+		TableName:   t.Name,
+		Status:      ImportStatusFailed,
+		StartTime:   now,
+		EndTime:     now,
+	}
+
+	keys, err := importObjectKeys(source, input.S3Bucket, input.S3KeyPrefix)
+	if err != nil {
+		imp.FailureCode, imp.FailureMessage = "S3NoSuchBucket", "The specified bucket does not exist"
+
+		return imp
+	}
+
+	for _, key := range keys {
+		raw, err := fs.ReadFile(source, path.Join(input.S3Bucket, key))
+		if err != nil {
+			imp.addError(key, 0, err.Error())
+
+			continue
+		}
+
+		imp.ProcessedSizeBytes += int64(len(raw))
+
+		data, err := decompress(raw, input.Compression)
+		if err != nil {
+			imp.addError(key, 0, fmt.Sprintf("Unable to decompress the object with %s: %s", input.Compression, err))
+
+			continue
+		}
+
+		index := 0
+
+		for item, err := range t.importRecords(input, data) {
+			imp.ProcessedItemCount++
+
+			if err == nil {
+				_, err = t.Put(&types.PutItemInput{Item: item})
+			}
+
+			if err != nil {
+				imp.addError(key, index, errorMessage(err))
+			} else {
+				imp.ImportedItemCount++
+			}
+
+			index++
+		}
+	}
+
+	imp.Status = ImportStatusCompleted
+
+	return imp
+}
+
+func (imp *Import) addError(key string, index int, message string) {
+	imp.ErrorCount++
+	imp.Errors = append(imp.Errors, ImportError{S3Bucket: imp.S3Bucket, Key: key, ItemIndex: index, Message: message})
+}
+
+func errorMessage(err error) string {
+	if intErr, ok := errors.AsType[types.Error](err); ok {
+		return intErr.Message()
+	}
+
+	return err.Error()
+}
+
+// importObjectKeys lists the keys under prefix of the files in the bucket directory
+func importObjectKeys(source fs.FS, bucket, prefix string) ([]string, error) {
+	if source == nil {
+		return nil, fs.ErrNotExist
+	}
+
+	if _, err := fs.Stat(source, bucket); err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+
+	err := fs.WalkDir(source, bucket, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		key := strings.TrimPrefix(name, bucket+"/")
+		if entry.Type().IsRegular() && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+
+		return nil
+	})
+
+	return keys, err
+}
+
+func decompress(raw []byte, compression string) ([]byte, error) {
+	switch compression {
+	case ImportCompressionGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+
+		return io.ReadAll(zr)
+	case ImportCompressionZstd:
+		zr, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		defer zr.Close()
+
+		return zr.DecodeAll(raw, nil)
+	default:
+		return raw, nil
+	}
+}
+
+// importRecords reads the items of an object in the import format
+func (t *Table) importRecords(input ImportInput, data []byte) iter.Seq2[map[string]*types.Item, error] {
+	switch input.Format {
+	case ImportFormatCSV:
+		return t.csvRecords(input, data)
+	case ImportFormatIon:
+		return lineRecords(data, parseIonRecord)
+	default:
+		return lineRecords(data, parseDynamoDBJSONRecord)
+	}
+}
+
+// lineRecords parses every non blank line of data as an item
+func lineRecords(data []byte, parse func(line string) (map[string]*types.Item, error)) iter.Seq2[map[string]*types.Item, error] {
+	return func(yield func(map[string]*types.Item, error) bool) {
+		for line := range strings.Lines(string(data)) {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			if !yield(parse(line)) {
+				return
+			}
+		}
+	}
+}
+
+// parseDynamoDBJSONRecord reads a line like {"Item":{"id":{"S":"1"}}}
+func parseDynamoDBJSONRecord(line string) (map[string]*types.Item, error) {
+	var record struct {
+		Item map[string]json.RawMessage
+	}
+
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return nil, err
+	}
+
+	if record.Item == nil {
+		return nil, errors.New("missing the Item of the record")
+	}
+
+	return parseDynamoDBJSON(record.Item)
+}
+
+// parseIonRecord reads a line like {Item:{id:"1"}}, the first one may start with the Ion
+// version marker
+func parseIonRecord(line string) (map[string]*types.Item, error) {
+	r := &ionReader{src: strings.TrimPrefix(strings.TrimSpace(line), "$ion_1_0")}
+
+	record, err := parseIonStruct(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.done() {
+		return nil, fmt.Errorf("unexpected content at offset %d", r.pos)
+	}
+
+	item, ok := record["Item"]
+	if !ok || item.M == nil {
+		return nil, errors.New("missing the Item of the record")
+	}
+
+	return item.M, nil
+}
+
+// csvRecords reads the rows of a CSV object, the attributes in the attribute definitions of
+// the table take their type from it and the rest are strings
+func (t *Table) csvRecords(input ImportInput, data []byte) iter.Seq2[map[string]*types.Item, error] {
+	return func(yield func(map[string]*types.Item, error) bool) {
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+
+		if input.CSVDelimiter != "" {
+			reader.Comma = rune(input.CSVDelimiter[0])
+		}
+
+		header := input.CSVHeader
+
+		for {
+			row, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if err != nil {
+				if _, ok := errors.AsType[*csv.ParseError](err); ok && yield(nil, err) {
+					continue
+				}
+
+				return
+			}
+
+			if header == nil {
+				header = row
+
+				continue
+			}
+
+			if !yield(t.csvItem(header, row)) {
+				return
+			}
+		}
+	}
+}
+
+func (t *Table) csvItem(header, row []string) (map[string]*types.Item, error) {
+	if len(row) != len(header) {
+		return nil, fmt.Errorf("the row has %d values but the header has %d", len(row), len(header))
+	}
+
+	item := map[string]*types.Item{}
+
+	for i, name := range header {
+		value := row[i]
+		if value == "" {
+			continue
+		}
+
+		switch t.AttributesDef[name] {
+		case "N":
+			item[name] = &types.Item{N: &value}
+		case "B":
+			data, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid binary value for %s: %w", name, err)
+			}
+
+			item[name] = &types.Item{B: data}
+		default:
+			item[name] = &types.Item{S: &value}
+		}
+	}
+
+	return item, nil
+}
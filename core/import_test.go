@@ -0,0 +1,118 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func newImportTestTable() *Table {
+	table := NewTable("pokemons")
+	table.AttributesDef = map[string]string{"id": "N"}
+	table.KeySchema = keySchema{HashKey: "id"}
+
+	return table
+}
+
+func importedItem(c *require.Assertions, table *Table, id string) map[string]*types.Item {
+	key, err := table.KeySchema.GetKey(table.AttributesDef, map[string]*types.Item{"id": {N: &id}})
+	c.NoError(err)
+
+	return table.Data[key]
+}
+
+func TestImport(t *testing.T) {
+	c := require.New(t)
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	var gzipped bytes.Buffer
+
+	zw := gzip.NewWriter(&gzipped)
+	_, err := zw.Write([]byte(`{"Item":{"id":{"N":"1"},"name":{"S":"bulbasaur"}}}` + "\n" + `{"Item":{"name":{"S":"missingno"}}}` + "\nnot json\n"))
+	c.NoError(err)
+	c.NoError(zw.Close())
+
+	source := fstest.MapFS{
+		"bucket/seed/part-1.json.gz": {Data: gzipped.Bytes()},
+		"bucket/seed/part-2.json.gz": {Data: []byte("corrupted")},
+		"bucket/other/ignored.json":  {Data: []byte(`{"Item":{"id":{"N":"99"}}}`)},
+	}
+
+	input := ImportInput{S3Bucket: "bucket", S3KeyPrefix: "seed/", Format: ImportFormatDynamoDBJSON, Compression: ImportCompressionGzip}
+	c.NoError(ValidateImportInput(&input))
+
+	table := newImportTestTable()
+	imp := table.Import(source, input, now)
+	c.Equal(ImportStatusCompleted, imp.Status)
+	c.EqualValues(3, imp.ProcessedItemCount)
+	c.EqualValues(1, imp.ImportedItemCount)
+	c.EqualValues(3, imp.ErrorCount)
+	c.EqualValues(len(gzipped.Bytes())+len("corrupted"), imp.ProcessedSizeBytes)
+	c.Len(table.Data, 1)
+
+	c.Equal(ImportError{S3Bucket: "bucket", Key: "seed/part-1.json.gz", ItemIndex: 1, Message: "One of the required keys was not given a value; field: \"id\""}, imp.Errors[0])
+	c.Equal(2, imp.Errors[1].ItemIndex)
+	c.Equal("seed/part-2.json.gz", imp.Errors[2].Key)
+	c.Contains(imp.Errors[2].Message, "Unable to decompress")
+
+	imp = newImportTestTable().Import(source, ImportInput{S3Bucket: "missing", Format: ImportFormatDynamoDBJSON}, now)
+	c.Equal(ImportStatusFailed, imp.Status)
+	c.Equal("S3NoSuchBucket", imp.FailureCode)
+
+	imp = newImportTestTable().Import(nil, input, now)
+	c.Equal(ImportStatusFailed, imp.Status)
+}
+
+func TestImportFormats(t *testing.T) {
+	c := require.New(t)
+
+	encoder, err := zstd.NewWriter(nil)
+	c.NoError(err)
+
+	ion := encoder.EncodeAll([]byte("$ion_1_0 {Item:{id:25.,name:\"pikachu\"}}\n{Item:{id:26,types:$dynamodb_SS::[\"electric\"]}}\n"), nil)
+	c.NoError(encoder.Close())
+
+	source := fstest.MapFS{
+		"bucket/pokemons.ion.zst": {Data: ion},
+		"bucket/pokemons.csv":     {Data: []byte("id;name;weight\n4;charmander;8.5\n5;;19\n")},
+		"bucket/headless.csv":     {Data: []byte("7,squirtle\n8,\"war,tortle\"\n9\n")},
+	}
+
+	table := newImportTestTable()
+	imp := table.Import(source, ImportInput{S3Bucket: "bucket", S3KeyPrefix: "pokemons.ion", Format: ImportFormatIon, Compression: ImportCompressionZstd}, time.Now())
+	c.EqualValues(2, imp.ImportedItemCount)
+	c.Equal("pikachu", types.StringValue(importedItem(c, table, "25")["name"].S))
+	c.Len(importedItem(c, table, "26")["types"].SS, 1)
+
+	imp = table.Import(source, ImportInput{S3Bucket: "bucket", S3KeyPrefix: "pokemons.csv", Format: ImportFormatCSV, CSVDelimiter: ";"}, time.Now())
+	c.EqualValues(2, imp.ImportedItemCount)
+	c.Equal("8.5", types.StringValue(importedItem(c, table, "4")["weight"].S))
+	c.NotContains(importedItem(c, table, "5"), "name")
+
+	imp = table.Import(source, ImportInput{S3Bucket: "bucket", S3KeyPrefix: "headless", Format: ImportFormatCSV, CSVHeader: []string{"id", "name"}}, time.Now())
+	c.EqualValues(3, imp.ProcessedItemCount)
+	c.EqualValues(2, imp.ImportedItemCount)
+	c.Equal("war,tortle", types.StringValue(importedItem(c, table, "8")["name"].S))
+	c.Contains(imp.Errors[0].Message, "header")
+}
+
+func TestValidateImportInput(t *testing.T) {
+	c := require.New(t)
+
+	input := ImportInput{S3Bucket: "bucket", Format: ImportFormatCSV}
+	c.NoError(ValidateImportInput(&input))
+	c.Equal(ImportCompressionNone, input.Compression)
+
+	c.ErrorContains(ValidateImportInput(&ImportInput{Format: ImportFormatCSV}), "s3BucketSource.s3Bucket")
+	c.ErrorContains(ValidateImportInput(&ImportInput{S3Bucket: "bucket", Format: "PARQUET"}), "inputFormat")
+	c.ErrorContains(ValidateImportInput(&ImportInput{S3Bucket: "bucket", Format: ImportFormatCSV, Compression: "BZIP2"}), "inputCompressionType")
+	c.ErrorContains(ValidateImportInput(&ImportInput{S3Bucket: "bucket", Format: ImportFormatCSV, CSVDelimiter: "#"}), "delimiter")
+	c.ErrorContains(ValidateImportInput(&ImportInput{S3Bucket: "bucket", Format: ImportFormatIon, CSVHeader: []string{"id"}}), "CSV input format")
+}
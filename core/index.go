@@ -1,8 +1,8 @@
 package core
 
 import (
+	"errors"
 	"maps"
-	"sort"
 	"time"
 
 	"github.com/truora/minidyn/types"
@@ -17,13 +17,13 @@ const (
 
 type index struct {
 	keySchema  keySchema
-	sortedKeys []string
-	sortedRefs [][2]string // used for searching
+	partitions *partitionMap
 	typ        indexType
 	projection *types.Projection
 	Table      *Table
-	refs       map[string]string
-	createdAt  time.Time
+	// refs maps the primary key of an indexed item to its position in the index
+	refs      map[string]partitionCursor
+	createdAt time.Time
 }
 
 func newIndex(t *Table, typ indexType, ks keySchema) *index {
@@ -31,35 +31,32 @@ func newIndex(t *Table, typ indexType, ks keySchema) *index {
 
 	return &index{
 		keySchema:  ks,
-		sortedKeys: []string{},
+		partitions: newPartitionMap(),
 		typ:        typ,
 		Table:      t,
-		refs:       map[string]string{},
+		refs:       map[string]partitionCursor{},
 	}
 }
 
 func (i *index) Clear() {
-	i.sortedKeys = []string{}
-	i.refs = map[string]string{}
+	i.partitions = newPartitionMap()
+	i.refs = map[string]partitionCursor{}
 }
 
 type indexSnapshot struct {
-	sortedKeys []string
-	refs       map[string]string
+	partitions *partitionMap
+	refs       map[string]partitionCursor
 }
 
 func (i *index) snapshot() indexSnapshot {
-	keys := make([]string, len(i.sortedKeys))
-	copy(keys, i.sortedKeys)
-
-	refs := make(map[string]string, len(i.refs))
+	refs := make(map[string]partitionCursor, len(i.refs))
 	maps.Copy(refs, i.refs)
 
-	return indexSnapshot{sortedKeys: keys, refs: refs}
+	return indexSnapshot{partitions: i.partitions.clone(), refs: refs}
 }
 
 func (i *index) restore(s indexSnapshot) {
-	i.sortedKeys = s.sortedKeys
+	i.partitions = s.partitions
 	i.refs = s.refs
 }
 
@@ -112,128 +109,64 @@ func (i *index) projectInclude(item map[string]*types.Item) map[string]*types.It
 	return out
 }
 
-func (i *index) putData(key string, item map[string]*types.Item) error {
-	indexKey, err := i.keySchema.GetKey(i.Table.AttributesDef, item)
-	if err != nil || indexKey == "" {
-		return err
-	}
-
-	old, exists := i.refs[key]
-
-	i.refs[key] = indexKey
-
-	if !exists {
-		i.sortedKeys = append(i.sortedKeys, indexKey)
-		sort.Strings(i.sortedKeys)
-
-		return nil
+// cursor returns the position of the item in the index, it reports false when the item does
+// not have the index key attributes (secondary indexes are sparse)
+func (i *index) cursor(key string, item map[string]*types.Item) (partitionCursor, bool, error) {
+	hashKey, rangeKey, err := i.keySchema.keyParts(i.Table.AttributesDef, item)
+	if errors.Is(err, errMissingField) {
+		return partitionCursor{}, false, nil
 	}
 
-	// On overwrite, reconcile sortedKeys so a changed index key does not leave
-	// a stale projection behind (and the new projection becomes searchable).
-	if old != indexKey {
-		pos := sort.SearchStrings(i.sortedKeys, old)
-		if pos >= len(i.sortedKeys) {
-			i.sortedKeys = append(i.sortedKeys, indexKey)
-		} else {
-			i.sortedKeys[pos] = indexKey
-		}
-
-		sort.Strings(i.sortedKeys)
+	if err != nil {
+		return partitionCursor{}, false, err
 	}
 
-	return nil
+	return partitionCursor{hashKey: hashKey, entry: keyEntry{rangeKey: rangeKey, pk: key}}, true, nil
 }
 
-func (i *index) updateData(key string, item, oldItem map[string]*types.Item) error {
-	indexKey, err := i.keySchema.GetKey(i.Table.AttributesDef, item)
-	if err != nil || indexKey == "" {
+func (i *index) putData(key string, item map[string]*types.Item) error {
+	c, ok, err := i.cursor(key, item)
+	if err != nil {
 		return err
 	}
 
-	old := i.refs[key]
-	i.refs[key] = indexKey
-
-	if old != indexKey {
-		pos := sort.SearchStrings(i.sortedKeys, old)
-		if pos >= len(i.sortedKeys) {
-			i.sortedKeys = append(i.sortedKeys, indexKey)
-		} else {
-			i.sortedKeys[pos] = indexKey
+	// On overwrite, drop the previous position so a changed (or removed) index key
+	// does not leave a stale projection behind.
+	if old, exists := i.refs[key]; exists {
+		if ok && old == c {
+			return nil
 		}
 
-		sort.Strings(i.sortedKeys)
-	}
-
-	return nil
-}
-
-func (i *index) delete(key string, item map[string]*types.Item) error {
-	delete(i.refs, key)
-
-	indexKey, err := i.keySchema.GetKey(i.Table.AttributesDef, item)
-	if err != nil || indexKey == "" {
-		return err
+		i.partitions.remove(old.hashKey, old.entry)
+		delete(i.refs, key)
 	}
 
-	pos := sort.SearchStrings(i.sortedKeys, indexKey)
-	if pos == len(i.sortedKeys) {
-		return err
+	if !ok {
+		return nil
 	}
 
-	copy(i.sortedKeys[pos:], i.sortedKeys[pos+1:])
-	i.sortedKeys[len(i.sortedKeys)-1] = ""
-	i.sortedKeys = i.sortedKeys[:len(i.sortedKeys)-1]
+	i.refs[key] = c
+	i.partitions.put(c.hashKey, c.entry)
 
 	return nil
 }
 
-func (i *index) lessKey(x, y int) bool {
-	if i.sortedRefs[x][1] < i.sortedRefs[y][1] {
-		return true
-	}
-
-	if i.sortedRefs[x][1] > i.sortedRefs[y][1] {
-		return false
-	}
-
-	return i.sortedRefs[x][0] < i.sortedRefs[y][0]
+func (i *index) updateData(key string, item, oldItem map[string]*types.Item) error {
+	return i.putData(key, item)
 }
 
-func (i *index) startSearch(scanForward bool) {
-	i.sortedRefs = make([][2]string, len(i.refs))
-	pos := 0
-
-	for k, v := range i.refs {
-		i.sortedRefs[pos] = [2]string{k, v}
-		pos++
+func (i *index) delete(key string, item map[string]*types.Item) error {
+	old, exists := i.refs[key]
+	if !exists {
+		return nil
 	}
 
-	sort.Slice(i.sortedRefs, func(x, y int) bool {
-		less := i.lessKey(x, y)
-		if scanForward {
-			return less
-		}
-
-		return !less
-	})
-}
-
-func (i *index) getPrimaryKey(indexKey string) (string, bool) {
-	if len(i.sortedRefs) > 0 {
-		key := i.sortedRefs[0]
-		pk, ik := key[0], key[1]
-
-		i.sortedRefs = i.sortedRefs[1:len(i.sortedRefs)]
-
-		if indexKey == ik {
-			return pk, true
-		}
-	}
+	delete(i.refs, key)
+	i.partitions.remove(old.hashKey, old.entry)
 
-	return "", false
+	return nil
 }
 
 func (i *index) count() int64 {
-	return int64(len(i.sortedKeys))
+	return int64(i.partitions.len())
 }
@@ -0,0 +1,655 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/truora/minidyn/types"
+)
+
+var ionIdentifierPattern = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*$`)
+
+// dynamoDBJSON converts an item into the value encoding/json renders as DynamoDB JSON, like
+// {"id":{"S":"1"}}
+func dynamoDBJSON(item map[string]*types.Item) map[string]any {
+	out := make(map[string]any, len(item))
+
+	for name, value := range item {
+		out[name] = attributeDynamoDBJSON(value)
+	}
+
+	return out
+}
+
+func attributeDynamoDBJSON(v *types.Item) map[string]any {
+	switch {
+	case v.S != nil:
+		return map[string]any{"S": *v.S}
+	case v.N != nil:
+		return map[string]any{"N": *v.N}
+	case v.B != nil:
+		return map[string]any{"B": v.B}
+	case v.BOOL != nil:
+		return map[string]any{"BOOL": *v.BOOL}
+	case v.NULL != nil:
+		return map[string]any{"NULL": *v.NULL}
+	case v.L != nil:
+		list := make([]any, len(v.L))
+		for i, elem := range v.L {
+			list[i] = attributeDynamoDBJSON(elem)
+		}
+
+		return map[string]any{"L": list}
+	case v.M != nil:
+		return map[string]any{"M": dynamoDBJSON(v.M)}
+	case v.SS != nil:
+		return map[string]any{"SS": derefSlice(v.SS)}
+	case v.NS != nil:
+		return map[string]any{"NS": derefSlice(v.NS)}
+	case v.BS != nil:
+		return map[string]any{"BS": v.BS}
+	}
+
+	return map[string]any{}
+}
+
+func derefSlice(values []*string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = types.StringValue(v)
+	}
+
+	return out
+}
+
+// writeIonStruct renders an item as an Amazon Ion text struct, following the type mapping of
+// DynamoDB exports: numbers are decimals and sets are lists annotated with $dynamodb_SS,
+// $dynamodb_NS or $dynamodb_BS
+func writeIonStruct(b *strings.Builder, item map[string]*types.Item) {
+	names := make([]string, 0, len(item))
+	for name := range item {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	b.WriteByte('{')
+
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		writeIonFieldName(b, name)
+		writeIonValue(b, item[name])
+	}
+
+	b.WriteByte('}')
+}
+
+func writeIonFieldName(b *strings.Builder, name string) {
+	if ionIdentifierPattern.MatchString(name) {
+		b.WriteString(name)
+	} else {
+		b.WriteByte('\'')
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(name, `\`, `\\`), `'`, `\'`))
+		b.WriteByte('\'')
+	}
+
+	b.WriteByte(':')
+}
+
+func writeIonValue(b *strings.Builder, v *types.Item) {
+	switch {
+	case v.S != nil:
+		b.WriteString(strconv.Quote(*v.S))
+	case v.N != nil:
+		b.WriteString(ionDecimal(*v.N))
+	case v.B != nil:
+		writeIonBlob(b, v.B)
+	case v.BOOL != nil:
+		b.WriteString(strconv.FormatBool(*v.BOOL))
+	case v.NULL != nil:
+		b.WriteString("null")
+	case v.L != nil:
+		b.WriteByte('[')
+
+		for i, elem := range v.L {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+
+			writeIonValue(b, elem)
+		}
+
+		b.WriteByte(']')
+	case v.M != nil:
+		writeIonStruct(b, v.M)
+	case v.SS != nil:
+		writeIonSet(b, "$dynamodb_SS", len(v.SS), func(i int) { b.WriteString(strconv.Quote(types.StringValue(v.SS[i]))) })
+	case v.NS != nil:
+		writeIonSet(b, "$dynamodb_NS", len(v.NS), func(i int) { b.WriteString(ionDecimal(types.StringValue(v.NS[i]))) })
+	case v.BS != nil:
+		writeIonSet(b, "$dynamodb_BS", len(v.BS), func(i int) { writeIonBlob(b, v.BS[i]) })
+	default:
+		b.WriteString("null")
+	}
+}
+
+func writeIonSet(b *strings.Builder, annotation string, n int, writeElem func(i int)) {
+	b.WriteString(annotation)
+	b.WriteString("::[")
+
+	for i := range n {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		writeElem(i)
+	}
+
+	b.WriteByte(']')
+}
+
+func writeIonBlob(b *strings.Builder, data []byte) {
+	b.WriteString("{{")
+	b.WriteString(base64.StdEncoding.EncodeToString(data))
+	b.WriteString("}}")
+}
+
+// ionDecimal turns a DynamoDB number into an Ion decimal, a number without a decimal point or
+// an exponent would be read back as an Ion int
+func ionDecimal(n string) string {
+	if i := strings.IndexAny(n, "eE"); i >= 0 {
+		return n[:i] + "d" + n[i+1:]
+	}
+
+	if !strings.Contains(n, ".") {
+		return n + "."
+	}
+
+	return n
+}
+
+// marshalJSONLine renders v as a single line of JSON followed by a newline
+func marshalJSONLine(v any) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(raw, '\n'), nil
+}
+
+// parseDynamoDBJSON reads an item written in DynamoDB JSON
+func parseDynamoDBJSON(raw map[string]json.RawMessage) (map[string]*types.Item, error) {
+	item := make(map[string]*types.Item, len(raw))
+
+	for name, value := range raw {
+		attr, err := parseAttributeDynamoDBJSON(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute %s: %w", name, err)
+		}
+
+		item[name] = attr
+	}
+
+	return item, nil
+}
+
+func parseAttributeDynamoDBJSON(raw json.RawMessage) (*types.Item, error) { //nolint:gocyclo // one case per attribute type
+	var typed map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &typed); err != nil {
+		return nil, err
+	}
+
+	if len(typed) != 1 {
+		return nil, errors.New("an attribute value must have exactly one type")
+	}
+
+	attr := &types.Item{}
+
+	for typ, value := range typed {
+		var err error
+
+		switch typ {
+		case "S":
+			err = json.Unmarshal(value, &attr.S)
+		case "N":
+			err = json.Unmarshal(value, &attr.N)
+		case "B":
+			err = json.Unmarshal(value, &attr.B)
+		case "BOOL":
+			err = json.Unmarshal(value, &attr.BOOL)
+		case "NULL":
+			err = json.Unmarshal(value, &attr.NULL)
+		case "SS":
+			err = json.Unmarshal(value, &attr.SS)
+		case "NS":
+			err = json.Unmarshal(value, &attr.NS)
+		case "BS":
+			err = json.Unmarshal(value, &attr.BS)
+		case "L":
+			var list []json.RawMessage
+			if err = json.Unmarshal(value, &list); err != nil {
+				break
+			}
+
+			attr.L = make([]*types.Item, len(list))
+			for i, elem := range list {
+				if attr.L[i], err = parseAttributeDynamoDBJSON(elem); err != nil {
+					break
+				}
+			}
+		case "M":
+			var m map[string]json.RawMessage
+			if err = json.Unmarshal(value, &m); err != nil {
+				break
+			}
+
+			attr.M, err = parseDynamoDBJSON(m)
+		default:
+			err = fmt.Errorf("unknown attribute type %s", typ)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return attr, nil
+}
+
+// ionReader parses the subset of Amazon Ion text used by DynamoDB exports: structs, lists,
+// strings, symbols, numbers, blobs, booleans and nulls, with sets as annotated lists
+type ionReader struct {
+	src string
+	pos int
+}
+
+// parseIonStruct reads a struct of attribute values written in Amazon Ion text
+func parseIonStruct(r *ionReader) (map[string]*types.Item, error) {
+	if err := r.expect('{'); err != nil {
+		return nil, err
+	}
+
+	item := map[string]*types.Item{}
+
+	for fields := 0; ; fields++ {
+		r.skipSpace()
+
+		if r.consume('}') {
+			return item, nil
+		}
+
+		if fields > 0 {
+			if err := r.expect(','); err != nil {
+				return nil, err
+			}
+
+			r.skipSpace()
+
+			// Ion allows a trailing comma
+			if r.consume('}') {
+				return item, nil
+			}
+		}
+
+		name, err := r.fieldName()
+		if err != nil {
+			return nil, err
+		}
+
+		r.skipSpace()
+
+		if err := r.expect(':'); err != nil {
+			return nil, err
+		}
+
+		value, err := r.value()
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute %s: %w", name, err)
+		}
+
+		item[name] = value
+	}
+}
+
+func (r *ionReader) skipSpace() {
+	for r.pos < len(r.src) {
+		switch {
+		case strings.HasPrefix(r.src[r.pos:], "//"):
+			end := strings.IndexByte(r.src[r.pos:], '\n')
+			if end < 0 {
+				r.pos = len(r.src)
+			} else {
+				r.pos += end
+			}
+		case strings.HasPrefix(r.src[r.pos:], "/*"):
+			end := strings.Index(r.src[r.pos+2:], "*/")
+			if end < 0 {
+				r.pos = len(r.src)
+			} else {
+				r.pos += end + 4
+			}
+		case strings.ContainsRune(" \t\r\n\f\v", rune(r.src[r.pos])):
+			r.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (r *ionReader) consume(c byte) bool {
+	if r.pos < len(r.src) && r.src[r.pos] == c {
+		r.pos++
+
+		return true
+	}
+
+	return false
+}
+
+func (r *ionReader) expect(c byte) error {
+	r.skipSpace()
+
+	if !r.consume(c) {
+		return fmt.Errorf("expected '%c' at offset %d", c, r.pos)
+	}
+
+	return nil
+}
+
+func (r *ionReader) done() bool {
+	r.skipSpace()
+
+	return r.pos >= len(r.src)
+}
+
+// fieldName reads an identifier, a quoted symbol or a string
+func (r *ionReader) fieldName() (string, error) {
+	if r.pos >= len(r.src) {
+		return "", io.ErrUnexpectedEOF
+	}
+
+	switch r.src[r.pos] {
+	case '\'', '"':
+		return r.quoted(r.src[r.pos])
+	}
+
+	token := r.token()
+	if token == "" {
+		return "", fmt.Errorf("expected a field name at offset %d", r.pos)
+	}
+
+	return token, nil
+}
+
+// token reads the run of characters that can form an identifier or a number
+func (r *ionReader) token() string {
+	start := r.pos
+
+	for r.pos < len(r.src) {
+		c := r.src[r.pos]
+		if c == '$' || c == '_' || c == '.' || c == '+' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+			r.pos++
+
+			continue
+		}
+
+		break
+	}
+
+	return r.src[start:r.pos]
+}
+
+func (r *ionReader) quoted(quote byte) (string, error) {
+	r.pos++
+
+	var b strings.Builder
+
+	for r.pos < len(r.src) {
+		c := r.src[r.pos]
+
+		switch {
+		case c == quote:
+			r.pos++
+
+			return b.String(), nil
+		case c == '\\':
+			if err := r.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			r.pos++
+		}
+	}
+
+	return "", io.ErrUnexpectedEOF
+}
+
+func (r *ionReader) escape(b *strings.Builder) error {
+	r.pos++
+
+	if r.pos >= len(r.src) {
+		return io.ErrUnexpectedEOF
+	}
+
+	c := r.src[r.pos]
+	r.pos++
+
+	if simple, ok := ionEscapes[c]; ok {
+		b.WriteString(simple)
+
+		return nil
+	}
+
+	digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[c]
+	if digits == 0 || r.pos+digits > len(r.src) {
+		return fmt.Errorf("invalid escape sequence \\%c", c)
+	}
+
+	code, err := strconv.ParseUint(r.src[r.pos:r.pos+digits], 16, 32)
+	if err != nil {
+		return err
+	}
+
+	r.pos += digits
+
+	if c == 'x' {
+		b.WriteByte(byte(code))
+	} else {
+		b.WriteRune(rune(code))
+	}
+
+	return nil
+}
+
+var ionEscapes = map[byte]string{
+	'a': "\a", 'b': "\b", 't': "\t", 'n': "\n", 'f': "\f", 'r': "\r", 'v': "\v",
+	'?': "?", '0': "\x00", '\'': "'", '"': "\"", '/': "/", '\\': "\\",
+}
+
+// value reads an attribute value, sets are lists annotated with $dynamodb_SS, $dynamodb_NS
+// or $dynamodb_BS
+func (r *ionReader) value() (*types.Item, error) { //nolint:gocyclo // one case per Ion type
+	r.skipSpace()
+
+	if r.pos >= len(r.src) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	switch c := r.src[r.pos]; {
+	case c == '"':
+		s, err := r.quoted('"')
+		if err != nil {
+			return nil, err
+		}
+
+		return &types.Item{S: &s}, nil
+	case strings.HasPrefix(r.src[r.pos:], "{{"):
+		data, err := r.blob()
+		if err != nil {
+			return nil, err
+		}
+
+		return &types.Item{B: data}, nil
+	case c == '{':
+		m, err := parseIonStruct(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return &types.Item{M: m}, nil
+	case c == '[':
+		list, err := r.list()
+		if err != nil {
+			return nil, err
+		}
+
+		return &types.Item{L: list}, nil
+	case c == '\'':
+		// a quoted symbol, only valid as an annotation
+		annotation, err := r.quoted('\'')
+		if err != nil {
+			return nil, err
+		}
+
+		return r.annotated(annotation)
+	}
+
+	token := r.token()
+
+	r.skipSpace()
+
+	if strings.HasPrefix(r.src[r.pos:], "::") {
+		return r.annotated(token)
+	}
+
+	switch {
+	case token == "true" || token == "false":
+		return &types.Item{BOOL: new(token == "true")}, nil
+	case token == "null" || strings.HasPrefix(token, "null."):
+		return &types.Item{NULL: new(true)}, nil
+	}
+
+	n, err := ionNumber(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Item{N: &n}, nil
+}
+
+// annotated reads the value following an annotation, turning the set annotations into sets
+func (r *ionReader) annotated(annotation string) (*types.Item, error) {
+	r.skipSpace()
+
+	if !strings.HasPrefix(r.src[r.pos:], "::") {
+		return nil, fmt.Errorf("unexpected symbol %s", annotation)
+	}
+
+	r.pos += 2
+
+	value, err := r.value()
+	if err != nil {
+		return nil, err
+	}
+
+	set := map[string]bool{"$dynamodb_SS": true, "$dynamodb_NS": true, "$dynamodb_BS": true}
+	if !set[annotation] {
+		return value, nil
+	}
+
+	if value.L == nil {
+		return nil, fmt.Errorf("%s must annotate a list", annotation)
+	}
+
+	out := &types.Item{}
+
+	for _, elem := range value.L {
+		switch {
+		case annotation == "$dynamodb_SS" && elem.S != nil:
+			out.SS = append(out.SS, elem.S)
+		case annotation == "$dynamodb_NS" && elem.N != nil:
+			out.NS = append(out.NS, elem.N)
+		case annotation == "$dynamodb_BS" && elem.B != nil:
+			out.BS = append(out.BS, elem.B)
+		default:
+			return nil, fmt.Errorf("invalid element in %s", annotation)
+		}
+	}
+
+	return out, nil
+}
+
+func (r *ionReader) list() ([]*types.Item, error) {
+	r.pos++
+
+	list := []*types.Item{}
+
+	for {
+		r.skipSpace()
+
+		if r.consume(']') {
+			return list, nil
+		}
+
+		if len(list) > 0 {
+			if err := r.expect(','); err != nil {
+				return nil, err
+			}
+
+			r.skipSpace()
+
+			if r.consume(']') {
+				return list, nil
+			}
+		}
+
+		elem, err := r.value()
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, elem)
+	}
+}
+
+func (r *ionReader) blob() ([]byte, error) {
+	end := strings.Index(r.src[r.pos:], "}}")
+	if end < 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	encoded := strings.Join(strings.Fields(r.src[r.pos+2:r.pos+end]), "")
+	r.pos += end + 2
+
+	return base64.StdEncoding.DecodeString(encoded)
+}
+
+// ionNumber turns an Ion int, decimal or float into a DynamoDB number
+func ionNumber(token string) (string, error) {
+	n := strings.ReplaceAll(token, "_", "")
+	n = strings.NewReplacer("d", "E", "D", "E", "e", "E").Replace(n)
+
+	if mantissa, exponent, ok := strings.Cut(n, "E"); ok {
+		n = strings.TrimSuffix(mantissa, ".") + "E" + exponent
+	} else {
+		n = strings.TrimSuffix(n, ".")
+	}
+
+	if _, err := strconv.ParseFloat(n, 64); err != nil || strings.ContainsAny(n, "xXbBnNiI") {
+		return "", fmt.Errorf("invalid number %s", token)
+	}
+
+	return n, nil
+}
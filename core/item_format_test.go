@@ -0,0 +1,111 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestIonDecimal(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal("42.", ionDecimal("42"))
+	c.Equal("-1.5", ionDecimal("-1.5"))
+	c.Equal("1.2d10", ionDecimal("1.2E10"))
+}
+
+func TestItemFormats(t *testing.T) {
+	c := require.New(t)
+
+	item := map[string]*types.Item{
+		"id":       {S: new("001")},
+		"level":    {N: new("5")},
+		"data":     {B: []byte("hi")},
+		"shiny":    {BOOL: new(true)},
+		"nickname": {NULL: new(true)},
+		"moves":    {L: []*types.Item{{S: new("tackle")}, {N: new("1.5")}}},
+		"stats":    {M: map[string]*types.Item{"hp": {N: new("35")}}},
+		"tags":     {SS: []*string{new("cute"), new("yellow")}},
+		"scores":   {NS: []*string{new("1"), new("2")}},
+		"the type": {BS: [][]byte{[]byte("hi")}},
+	}
+
+	raw, err := json.Marshal(dynamoDBJSON(item))
+	c.NoError(err)
+	c.JSONEq(`{
+		"id": {"S": "001"},
+		"level": {"N": "5"},
+		"data": {"B": "aGk="},
+		"shiny": {"BOOL": true},
+		"nickname": {"NULL": true},
+		"moves": {"L": [{"S": "tackle"}, {"N": "1.5"}]},
+		"stats": {"M": {"hp": {"N": "35"}}},
+		"tags": {"SS": ["cute", "yellow"]},
+		"scores": {"NS": ["1", "2"]},
+		"the type": {"BS": ["aGk="]}
+	}`, string(raw))
+
+	var b strings.Builder
+
+	writeIonStruct(&b, item)
+	c.Equal(`{data:{{aGk=}},id:"001",level:5.,moves:["tackle",1.5],nickname:null,scores:$dynamodb_NS::[1.,2.],shiny:true,stats:{hp:35.},tags:$dynamodb_SS::["cute","yellow"],'the type':$dynamodb_BS::[{{aGk=}}]}`, b.String())
+}
+
+func TestItemFormatsRoundTrip(t *testing.T) {
+	c := require.New(t)
+
+	item := map[string]*types.Item{
+		"id":       {S: new("it's \"001\"\n")},
+		"level":    {N: new("5")},
+		"weight":   {N: new("1.5E3")},
+		"data":     {B: []byte("hi")},
+		"shiny":    {BOOL: new(false)},
+		"nickname": {NULL: new(true)},
+		"moves":    {L: []*types.Item{{S: new("tackle")}, {M: map[string]*types.Item{}}}},
+		"stats":    {M: map[string]*types.Item{"hp": {N: new("-35")}}},
+		"tags":     {SS: []*string{new("cute")}},
+		"scores":   {NS: []*string{new("1"), new("2.25")}},
+		"the type": {BS: [][]byte{[]byte("hi")}},
+	}
+
+	raw, err := json.Marshal(map[string]any{"Item": dynamoDBJSON(item)})
+	c.NoError(err)
+
+	parsed, err := parseDynamoDBJSONRecord(string(raw))
+	c.NoError(err)
+	c.Equal(item, parsed)
+
+	var b strings.Builder
+
+	b.WriteString("$ion_1_0 {Item:")
+	writeIonStruct(&b, item)
+	b.WriteString("}")
+
+	parsed, err = parseIonRecord(b.String())
+	c.NoError(err)
+	c.Equal(item, parsed)
+
+	parsed, err = parseIonRecord(`{ Item: { 'id': "1", /* comment */ count: 1_000, ratio: 2.5e-1, tags: $dynamodb_SS::[ "a", "b", ], }, }`)
+	c.NoError(err)
+	c.Equal(map[string]*types.Item{
+		"id":    {S: new("1")},
+		"count": {N: new("1000")},
+		"ratio": {N: new("2.5E-1")},
+		"tags":  {SS: []*string{new("a"), new("b")}},
+	}, parsed)
+
+	_, err = parseIonRecord(`{Item:{id:"1"}} extra`)
+	c.ErrorContains(err, "unexpected content")
+
+	_, err = parseIonRecord(`{Item:{id:$dynamodb_NS::["1"]}}`)
+	c.ErrorContains(err, "invalid element")
+
+	_, err = parseDynamoDBJSONRecord(`{"Item":{"id":{"S":"1","N":"1"}}}`)
+	c.ErrorContains(err, "exactly one type")
+
+	_, err = parseDynamoDBJSONRecord(`{"id":{"S":"1"}}`)
+	c.ErrorContains(err, "missing the Item")
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// keySeparator joins the hash and range parts of a primary key
+	keySeparator = "\x00"
+	// keySeparatorEscape replaces NUL bytes inside a key part. 0xFF never appears in the UTF-8
+	// or ASCII encoded parts, so joined keys are unambiguous and sort by hash key first.
+	keySeparatorEscape = "\x00\xff"
+
+	// numberKeyExponentOffset shifts the decimal exponent of a number into a fixed width
+	// non-negative field so exponents compare as strings
+	numberKeyExponentOffset = 5000
+	numberKeyExponentMax    = 9999
+
+	numberKeyNegative = "0"
+	numberKeyZero     = "1"
+	numberKeyPositive = "2"
+	// numberKeyNegativeEnd sorts after every complemented digit, so a negative number with
+	// fewer significant digits (a smaller magnitude) sorts after one extending it
+	numberKeyNegativeEnd = "~"
+)
+
+//nolint:stylecheck,staticcheck,ST1005 // DynamoDB ValidationException wording (parity)
+var errInvalidNumberKey = errors.New("The parameter cannot be converted to a numeric value")
+
+// encodeKeyValue returns the order preserving encoding of a key attribute value: strings keep
+// their UTF-8 bytes, binaries are hex encoded and numbers are normalized so numerically equal
+// values share an encoding whose string order matches the numeric order.
+func encodeKeyValue(val *types.Item, typ string) (string, error) {
+	switch typ {
+	case "S":
+		return types.StringValue(val.S), nil
+	case "N":
+		return encodeNumberKey(types.StringValue(val.N))
+	case "B":
+		return hex.EncodeToString(val.B), nil
+	}
+
+	return "", ErrInvalidAtrributeValue
+}
+
+// joinKeyParts builds the primary key of an item from its encoded hash and range key
+func joinKeyParts(hashKey, rangeKey string) string {
+	return escapeKeyPart(hashKey) + keySeparator + escapeKeyPart(rangeKey)
+}
+
+func escapeKeyPart(part string) string {
+	return strings.ReplaceAll(part, keySeparator, keySeparatorEscape)
+}
+
+func encodeNumberKey(s string) (string, error) {
+	d, err := types.ParseDecimal(s)
+	if errors.Is(err, types.ErrInvalidNumber) {
+		return "", fmt.Errorf("%w: %s", errInvalidNumberKey, s)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	neg, digits, exp := d.Parts()
+	if digits == "" {
+		return numberKeyZero, nil
+	}
+
+	// the DynamoDB exponent range keeps the shifted exponent within four digits
+	shifted := exp + numberKeyExponentOffset
+
+	if !neg {
+		return fmt.Sprintf("%s%04d%s", numberKeyPositive, shifted, digits), nil
+	}
+
+	complement := []byte(digits)
+	for i, digit := range complement {
+		complement[i] = '9' - digit + '0'
+	}
+
+	return fmt.Sprintf("%s%04d%s%s", numberKeyNegative, numberKeyExponentMax-shifted, complement, numberKeyNegativeEnd), nil
+}
@@ -0,0 +1,216 @@
+package core
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestEncodeNumberKeyOrder(t *testing.T) {
+	c := require.New(t)
+
+	ordered := []string{
+		"-9.9999999999999999999999999999999999999E+125", "-1E+10", "-1000", "-999.99", "-100", "-10.5", "-10", "-2", "-1.01", "-1", "-0.5", "-0.05", "-1e-10", "-1E-130",
+		"0",
+		"1E-130", "1e-10", "0.05", "0.5", "1", "1.01", "2", "10", "10.5", "100", "999.99", "1000", "1E+10", "9.9999999999999999999999999999999999999E+125",
+	}
+
+	encoded := make([]string, 0, len(ordered))
+
+	for _, n := range ordered {
+		e, err := encodeNumberKey(n)
+		c.NoError(err, n)
+
+		encoded = append(encoded, e)
+	}
+
+	c.True(sort.StringsAreSorted(encoded), "%q", encoded)
+	c.Len(slices.Compact(slices.Clone(encoded)), len(encoded))
+}
+
+func TestEncodeNumberKeyNormalization(t *testing.T) {
+	c := require.New(t)
+
+	equivalent := [][]string{
+		{"10", "10.0", "1e1", "1E+1", "+10", "010", "0.1e2", "100e-1"},
+		{"0", "-0", "0.000", "0e10", "+0"},
+		{"-2.5", "-2.50", "-25e-1", "-0.25E1"},
+	}
+
+	for _, group := range equivalent {
+		want, err := encodeNumberKey(group[0])
+		c.NoError(err)
+
+		for _, n := range group[1:] {
+			got, err := encodeNumberKey(n)
+			c.NoError(err, n)
+			c.Equal(want, got, n)
+		}
+	}
+
+	for _, invalid := range []string{"", "abc", "1.2.3", ".", "1e", "1e+", "--1", "1_000", "0x10"} {
+		_, err := encodeNumberKey(invalid)
+		c.ErrorIs(err, errInvalidNumberKey, invalid)
+	}
+
+	_, err := encodeNumberKey("1e99999")
+	c.ErrorIs(err, types.ErrNumberOverflow)
+
+	_, err = encodeNumberKey("-1e-131")
+	c.ErrorIs(err, types.ErrNumberUnderflow)
+}
+
+func TestEncodeKeyValue(t *testing.T) {
+	c := require.New(t)
+
+	s, err := encodeKeyValue(&types.Item{S: new("hello")}, "S")
+	c.NoError(err)
+	c.Equal("hello", s)
+
+	b, err := encodeKeyValue(&types.Item{B: []byte{0x00, 0x7f, 0xff}}, "B")
+	c.NoError(err)
+	c.Equal("007fff", b)
+
+	n, err := encodeKeyValue(&types.Item{N: new("12")}, "N")
+	c.NoError(err)
+	c.Equal("2500212", n)
+
+	_, err = encodeKeyValue(&types.Item{BOOL: new(true)}, "BOOL")
+	c.ErrorIs(err, ErrInvalidAtrributeValue)
+
+	binaries := [][]byte{{}, {0x00}, {0x00, 0x01}, {0x01}, {0x7f}, {0x80}, {0x80, 0x00}, {0xff}}
+	encoded := make([]string, 0, len(binaries))
+
+	for _, bin := range binaries {
+		e, err := encodeKeyValue(&types.Item{B: bin}, "B")
+		c.NoError(err)
+
+		encoded = append(encoded, e)
+	}
+
+	c.True(sort.StringsAreSorted(encoded), "%q", encoded)
+}
+
+func TestJoinKeyParts(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal("a\x00b", joinKeyParts("a", "b"))
+	c.NotEqual(joinKeyParts("a\x00", "b"), joinKeyParts("a", "\x00b"))
+	c.NotEqual(joinKeyParts("a.b", "c"), joinKeyParts("a", "b.c"))
+
+	// joined keys sort by hash key first and range key second
+	keys := []string{
+		joinKeyParts("a", "z"),
+		joinKeyParts("a\x00", "a"),
+		joinKeyParts("ab", "a"),
+		joinKeyParts("b", ""),
+	}
+	c.True(sort.StringsAreSorted(keys), "%q", keys)
+}
+
+func TestSearchDataNumericAndBinaryRangeKeys(t *testing.T) {
+	c := require.New(t)
+
+	table := NewTable("readings")
+	table.AttributesDef = map[string]string{"sensor": "S", "seq": "N", "payload": "B"}
+	table.KeySchema = keySchema{"sensor", "seq", false}
+
+	payloadIndex := "by-payload"
+	allStr := "ALL"
+
+	err := table.AddLocalIndexes([]*types.LocalSecondaryIndex{
+		{
+			IndexName: &payloadIndex,
+			KeySchema: []*types.KeySchemaElement{
+				{AttributeName: "sensor", KeyType: "HASH"},
+				{AttributeName: "payload", KeyType: "RANGE"},
+			},
+			Projection: &types.Projection{ProjectionType: &allStr},
+		},
+	})
+	c.NoError(err)
+
+	readings := []struct {
+		seq     string
+		payload []byte
+	}{
+		{"10", []byte{0x80}},
+		{"9", []byte{0x7f}},
+		{"-3.5", []byte{0xff}},
+		{"100", []byte{0x00, 0x01}},
+		{"0", []byte{0x01}},
+		{"1e-3", []byte{0x00}},
+	}
+
+	for _, r := range readings {
+		_, err := table.Put(&types.PutItemInput{
+			Item: map[string]*types.Item{
+				"sensor":  {S: new("s1")},
+				"seq":     {N: new(r.seq)},
+				"payload": {B: r.payload},
+			},
+		})
+		c.NoError(err)
+	}
+
+	query := func(index, expr string, forward bool, values map[string]*types.Item) []string {
+		values[":s"] = &types.Item{S: new("s1")}
+
+		items, _, err := table.SearchData(QueryInput{
+			Index:                     index,
+			KeyConditionExpression:    expr,
+			ExpressionAttributeValues: values,
+			ScanIndexForward:          forward,
+		})
+		c.NoError(err)
+
+		seqs := make([]string, 0, len(items))
+		for _, item := range items {
+			seqs = append(seqs, *item["seq"].N)
+		}
+
+		return seqs
+	}
+
+	c.Equal([]string{"-3.5", "0", "1e-3", "9", "10", "100"}, query("", "sensor = :s", true, map[string]*types.Item{}))
+	c.Equal([]string{"100", "10", "9", "1e-3", "0", "-3.5"}, query("", "sensor = :s", false, map[string]*types.Item{}))
+	c.Equal([]string{"9", "10"}, query("", "sensor = :s AND seq BETWEEN :a AND :b", true, map[string]*types.Item{
+		":a": {N: new("2")},
+		":b": {N: new("10.0")},
+	}))
+	c.Equal([]string{"100", "10"}, query("", "sensor = :s AND seq > :a", false, map[string]*types.Item{
+		":a": {N: new("9")},
+	}))
+	c.Equal([]string{"10"}, query("", "sensor = :s AND seq = :a", true, map[string]*types.Item{
+		":a": {N: new("1E1")},
+	}))
+
+	// binary keys sort as unsigned bytes
+	c.Equal([]string{"1e-3", "100", "0", "9", "10", "-3.5"}, query(payloadIndex, "sensor = :s", true, map[string]*types.Item{}))
+	c.Equal([]string{"-3.5", "10", "9", "0", "100", "1e-3"}, query(payloadIndex, "sensor = :s", false, map[string]*types.Item{}))
+	c.Equal([]string{"1e-3", "100"}, query(payloadIndex, "sensor = :s AND begins_with(payload, :p)", true, map[string]*types.Item{
+		":p": {B: []byte{0x00}},
+	}))
+
+	// numerically equal keys address the same item
+	_, err = table.Put(&types.PutItemInput{
+		Item: map[string]*types.Item{
+			"sensor":  {S: new("s1")},
+			"seq":     {N: new("10.00")},
+			"payload": {B: []byte{0x80}},
+		},
+	})
+	c.NoError(err)
+	c.Len(table.Data, len(readings))
+
+	_, err = table.Put(&types.PutItemInput{
+		Item: map[string]*types.Item{
+			"sensor": {S: new("s1")},
+			"seq":    {N: new("ten")},
+		},
+	})
+	c.EqualError(err, "ValidationException: A value provided cannot be converted into a number")
+}
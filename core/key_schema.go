@@ -3,7 +3,6 @@ package core
 import (
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/truora/minidyn/types"
 )
@@ -60,29 +59,44 @@ func (ks keySchema) GetKey(attrs map[string]string, item map[string]*types.Item)
 }
 
 func (ks keySchema) getKeyValue(attrs map[string]string, item map[string]*types.Item) (string, error) {
-	key := []string{}
-
-	val, err := getItemValue(item, ks.HashKey, attrs[ks.HashKey])
+	hashKey, rangeKey, err := ks.keyParts(attrs, item)
 	if err != nil {
 		return "", err
 	}
 
-	hashKeyStr := fmt.Sprintf("%v", val)
-
 	if ks.RangeKey == "" {
-		return hashKeyStr, nil
+		return hashKey, nil
 	}
 
-	key = append(key, hashKeyStr)
+	return joinKeyParts(hashKey, rangeKey), nil
+}
 
-	val, err = getItemValue(item, ks.RangeKey, attrs[ks.RangeKey])
+// keyParts returns the hash and range key values of the item as they are stored in the
+// partition map; the range key value is empty when the schema does not define one.
+func (ks keySchema) keyParts(attrs map[string]string, item map[string]*types.Item) (string, string, error) {
+	hashKey, err := encodeKeyAttribute(item, ks.HashKey, attrs[ks.HashKey])
 	if err != nil {
-		return "", err
+		return "", "", err
+	}
+
+	if ks.RangeKey == "" {
+		return hashKey, "", nil
 	}
 
-	key = append(key, fmt.Sprintf("%v", val))
+	rangeKey, err := encodeKeyAttribute(item, ks.RangeKey, attrs[ks.RangeKey])
+	if err != nil {
+		return "", "", err
+	}
+
+	return hashKey, rangeKey, nil
+}
+
+func encodeKeyAttribute(item map[string]*types.Item, field, typ string) (string, error) {
+	if _, err := getItemValue(item, field, typ); err != nil {
+		return "", err
+	}
 
-	return strings.Join(key, "."), nil
+	return encodeKeyValue(item[field], typ)
 }
 
 func (ks *keySchema) describe() []types.KeySchemaElement {
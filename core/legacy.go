@@ -0,0 +1,631 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	conditionalOperatorAnd = "AND"
+	conditionalOperatorOr  = "OR"
+)
+
+// legacyTranslator converts the legacy conditional parameters into a condition expression,
+// like the PartiQL statements are translated, every attribute and value is replaced by a
+// placeholder registered in names and values
+type legacyTranslator struct {
+	names  map[string]string
+	values map[string]*types.Item
+}
+
+func newLegacyTranslator() *legacyTranslator {
+	return &legacyTranslator{
+		names:  map[string]string{},
+		values: map[string]*types.Item{},
+	}
+}
+
+func (tr *legacyTranslator) name(attr string) string {
+	placeholder := fmt.Sprintf("#l%d", len(tr.names))
+	tr.names[placeholder] = attr
+
+	return placeholder
+}
+
+func (tr *legacyTranslator) value(item *types.Item) string {
+	placeholder := fmt.Sprintf(":l%d", len(tr.values))
+	tr.values[placeholder] = item
+
+	return placeholder
+}
+
+// expected translates the Expected parameter, its conditions are joined with the
+// ConditionalOperator, AND by default
+func (tr *legacyTranslator) expected(expected map[string]*types.ExpectedAttributeValue, operator *string) (string, error) {
+	joiner, err := conditionalOperator(operator, len(expected))
+	if err != nil {
+		return "", err
+	}
+
+	conditions := make([]string, 0, len(expected))
+
+	for _, attr := range slices.Sorted(maps.Keys(expected)) {
+		condition, err := tr.expectedAttribute(attr, expected[attr])
+		if err != nil {
+			return "", err
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	return strings.Join(conditions, " "+joiner+" "), nil
+}
+
+func (tr *legacyTranslator) expectedAttribute(attr string, expected *types.ExpectedAttributeValue) (string, error) {
+	if expected == nil {
+		expected = &types.ExpectedAttributeValue{}
+	}
+
+	if expected.ComparisonOperator != nil {
+		if expected.Value != nil || expected.Exists != nil {
+			return "", legacyValidationError("Exists and Value cannot be used with ComparisonOperator and AttributeValueList for Attribute: %s", attr)
+		}
+
+		return tr.compare(attr, *expected.ComparisonOperator, expected.AttributeValueList)
+	}
+
+	if len(expected.AttributeValueList) > 0 {
+		return "", legacyValidationError("AttributeValueList can only be used with a ComparisonOperator for Attribute: %s", attr)
+	}
+
+	exists := expected.Exists == nil || *expected.Exists
+
+	switch {
+	case exists && expected.Value == nil && expected.Exists == nil:
+		return "", legacyValidationError("Value must be provided when Exists is null for Attribute: %s", attr)
+	case exists && expected.Value == nil:
+		return "", legacyValidationError("Value must be provided when Exists is true for Attribute: %s", attr)
+	case !exists && expected.Value != nil:
+		return "", legacyValidationError("Cannot expect an attribute to have a specified value while expecting it to not exist")
+	case !exists:
+		return "attribute_not_exists(" + tr.name(attr) + ")", nil
+	}
+
+	if err := types.ValidateItemAttributeValue(expected.Value); err != nil {
+		return "", types.NewError("ValidationException", err.Error(), nil)
+	}
+
+	return "(" + tr.name(attr) + " = " + tr.value(expected.Value) + ")", nil
+}
+
+// compare translates the comparison of an attribute against the values of an
+// AttributeValueList, validating them like DynamoDB does for each ComparisonOperator
+func (tr *legacyTranslator) compare(attr, operator string, values []*types.Item) (string, error) {
+	if err := validateComparison(operator, values); err != nil {
+		return "", err
+	}
+
+	name := tr.name(attr)
+
+	placeholders := make([]string, 0, len(values))
+	for _, v := range values {
+		placeholders = append(placeholders, tr.value(v))
+	}
+
+	switch operator {
+	case "EQ":
+		return "(" + name + " = " + placeholders[0] + ")", nil
+	case "NE":
+		return "(" + name + " <> " + placeholders[0] + ")", nil
+	case "LE":
+		return "(" + name + " <= " + placeholders[0] + ")", nil
+	case "LT":
+		return "(" + name + " < " + placeholders[0] + ")", nil
+	case "GE":
+		return "(" + name + " >= " + placeholders[0] + ")", nil
+	case "GT":
+		return "(" + name + " > " + placeholders[0] + ")", nil
+	case "NOT_NULL":
+		return "attribute_exists(" + name + ")", nil
+	case "NULL":
+		return "attribute_not_exists(" + name + ")", nil
+	case "CONTAINS":
+		return "contains(" + name + ", " + placeholders[0] + ")", nil
+	case "NOT_CONTAINS":
+		return "(NOT contains(" + name + ", " + placeholders[0] + "))", nil
+	case "BEGINS_WITH":
+		return "begins_with(" + name + ", " + placeholders[0] + ")", nil
+	case "IN":
+		return "(" + name + " IN (" + strings.Join(placeholders, ", ") + "))", nil
+	}
+
+	return "(" + name + " BETWEEN " + placeholders[0] + " AND " + placeholders[1] + ")", nil
+}
+
+// keyConditions translates the KeyConditions parameter of a query into a key condition
+// expression, the partition key must be compared with EQ and the sort key with one of the
+// operators key condition expressions support
+func (tr *legacyTranslator) keyConditions(conditions map[string]*types.Condition, ks keySchema, attributesDef map[string]string) (string, error) {
+	if len(conditions) > 2 {
+		return "", types.NewError("ValidationException", "Conditions can be of length 1 or 2 only", nil)
+	}
+
+	if _, ok := conditions[ks.HashKey]; !ok {
+		return "", types.NewError("ValidationException", "Query condition missed key schema element: "+ks.HashKey, nil)
+	}
+
+	keyConditions := make([]string, 0, len(conditions))
+
+	for _, attr := range []string{ks.HashKey, ks.RangeKey} {
+		condition, ok := conditions[attr]
+		if !ok {
+			continue
+		}
+
+		keyCondition, err := tr.keyCondition(attr, condition, attr == ks.HashKey, attributesDef[attr])
+		if err != nil {
+			return "", err
+		}
+
+		keyConditions = append(keyConditions, keyCondition)
+	}
+
+	if len(keyConditions) != len(conditions) {
+		return "", types.NewError("ValidationException", "Query key condition not supported", nil)
+	}
+
+	return strings.Join(keyConditions, " AND "), nil
+}
+
+func (tr *legacyTranslator) keyCondition(attr string, condition *types.Condition, hashKey bool, attrType string) (string, error) {
+	operator, values, err := conditionOperands(attr, condition)
+	if err != nil {
+		return "", err
+	}
+
+	if err := validateComparison(operator, values); err != nil {
+		return "", err
+	}
+
+	supported := []string{"EQ", "LE", "LT", "GE", "GT", "BEGINS_WITH", "BETWEEN"}
+	if hashKey {
+		supported = []string{"EQ"}
+	}
+
+	if !slices.Contains(supported, operator) {
+		return "", types.NewError("ValidationException", "Query key condition not supported", nil)
+	}
+
+	for _, v := range values {
+		if attributeValueType(v) != attrType {
+			return "", legacyValidationError("Condition parameter type does not match schema type")
+		}
+	}
+
+	name := tr.name(attr)
+
+	placeholders := make([]string, 0, len(values))
+	for _, v := range values {
+		placeholders = append(placeholders, tr.value(v))
+	}
+
+	switch operator {
+	case "BEGINS_WITH":
+		return "begins_with(" + name + ", " + placeholders[0] + ")", nil
+	case "BETWEEN":
+		return name + " BETWEEN " + placeholders[0] + " AND " + placeholders[1], nil
+	}
+
+	return name + " " + keyConditionOperators[operator] + " " + placeholders[0], nil
+}
+
+var keyConditionOperators = map[string]string{"EQ": "=", "LE": "<=", "LT": "<", "GE": ">=", "GT": ">"}
+
+// filter translates the QueryFilter or ScanFilter parameter of a search, its conditions are
+// joined with the ConditionalOperator, AND by default
+func (tr *legacyTranslator) filter(filter map[string]*types.Condition, operator *string) (string, error) {
+	joiner, err := conditionalOperator(operator, len(filter))
+	if err != nil {
+		return "", err
+	}
+
+	conditions := make([]string, 0, len(filter))
+
+	for _, attr := range slices.Sorted(maps.Keys(filter)) {
+		comparison, values, err := conditionOperands(attr, filter[attr])
+		if err != nil {
+			return "", err
+		}
+
+		condition, err := tr.compare(attr, comparison, values)
+		if err != nil {
+			return "", err
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	return strings.Join(conditions, " "+joiner+" "), nil
+}
+
+func conditionOperands(attr string, condition *types.Condition) (string, []*types.Item, error) {
+	if condition == nil || condition.ComparisonOperator == nil {
+		return "", nil, types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value null at 'comparisonOperator' of the condition on %s failed to satisfy constraint: Member must not be null", attr), nil)
+	}
+
+	return *condition.ComparisonOperator, condition.AttributeValueList, nil
+}
+
+// attributesToGet translates the AttributesToGet parameter of a read into a projection
+// expression
+func (tr *legacyTranslator) attributesToGet(attributes []string) (string, error) {
+	if len(attributes) == 0 {
+		return "", types.NewError("ValidationException", "1 validation error detected: Value '[]' at 'attributesToGet' failed to satisfy constraint: Member must have length greater than or equal to 1", nil)
+	}
+
+	names := make([]string, 0, len(attributes))
+
+	for i, attr := range attributes {
+		if slices.Contains(attributes[:i], attr) {
+			return "", legacyValidationError("Duplicate value in attribute name: %s", attr)
+		}
+
+		names = append(names, tr.name(attr))
+	}
+
+	return strings.Join(names, ", "), nil
+}
+
+// attributeUpdates translates the AttributeUpdates parameter into an update expression,
+// PUT becomes SET, ADD stays ADD and DELETE becomes REMOVE or, with a set, DELETE
+func (tr *legacyTranslator) attributeUpdates(updates map[string]*types.AttributeValueUpdate, ks keySchema) (string, error) {
+	clauses := map[string][]string{}
+
+	for _, attr := range slices.Sorted(maps.Keys(updates)) {
+		clause, action, err := tr.attributeUpdate(attr, updates[attr], ks)
+		if err != nil {
+			return "", err
+		}
+
+		clauses[clause] = append(clauses[clause], action)
+	}
+
+	out := []string{}
+
+	for _, clause := range []string{"SET", "REMOVE", "ADD", "DELETE"} {
+		if len(clauses[clause]) > 0 {
+			out = append(out, clause+" "+strings.Join(clauses[clause], ", "))
+		}
+	}
+
+	return strings.Join(out, " "), nil
+}
+
+func (tr *legacyTranslator) attributeUpdate(attr string, update *types.AttributeValueUpdate, ks keySchema) (string, string, error) {
+	if update == nil {
+		update = &types.AttributeValueUpdate{}
+	}
+
+	action := "PUT"
+	if update.Action != nil {
+		action = *update.Action
+	}
+
+	if action != "PUT" && action != "ADD" && action != "DELETE" {
+		return "", "", types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%s' at 'attributeUpdates.%s.member.action' failed to satisfy constraint: Member must satisfy enum value set: [ADD, PUT, DELETE]", action, attr), nil)
+	}
+
+	if attr == ks.HashKey || attr == ks.RangeKey {
+		return "", "", legacyValidationError("Cannot update attribute %s. This attribute is part of the key", attr)
+	}
+
+	if update.Value == nil {
+		if action != "DELETE" {
+			return "", "", legacyValidationError("Only DELETE action is allowed when no attribute value is specified")
+		}
+
+		return "REMOVE", tr.name(attr), nil
+	}
+
+	if err := types.ValidateItemAttributeValue(update.Value); err != nil {
+		return "", "", types.NewError("ValidationException", err.Error(), nil)
+	}
+
+	valueType := attributeValueType(update.Value)
+
+	switch action {
+	case "ADD":
+		if !slices.Contains([]string{"N", "SS", "NS", "BS"}, valueType) {
+			return "", "", legacyValidationError("ADD action is not supported for the type %s", valueType)
+		}
+
+		return "ADD", tr.name(attr) + " " + tr.value(update.Value), nil
+	case "DELETE":
+		if !slices.Contains([]string{"SS", "NS", "BS"}, valueType) {
+			return "", "", legacyValidationError("DELETE action with value is not supported for the type %s", valueType)
+		}
+
+		return "DELETE", tr.name(attr) + " " + tr.value(update.Value), nil
+	}
+
+	return "SET", tr.name(attr) + " = " + tr.value(update.Value), nil
+}
+
+// comparisonOperators holds how many values each ComparisonOperator takes, where -1 is one
+// or more, and the types the values can have, where nil is any type
+var comparisonOperators = map[string]struct {
+	arguments  int
+	valueTypes []string
+}{
+	"EQ":           {1, nil},
+	"NE":           {1, nil},
+	"LE":           {1, []string{"S", "N", "B"}},
+	"LT":           {1, []string{"S", "N", "B"}},
+	"GE":           {1, []string{"S", "N", "B"}},
+	"GT":           {1, []string{"S", "N", "B"}},
+	"NOT_NULL":     {0, nil},
+	"NULL":         {0, nil},
+	"CONTAINS":     {1, []string{"S", "N", "B"}},
+	"NOT_CONTAINS": {1, []string{"S", "N", "B"}},
+	"BEGINS_WITH":  {1, []string{"S", "B"}},
+	"IN":           {-1, []string{"S", "N", "B"}},
+	"BETWEEN":      {2, []string{"S", "N", "B"}},
+}
+
+func validateComparison(operator string, values []*types.Item) error {
+	spec, ok := comparisonOperators[operator]
+	if !ok {
+		return types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%s' at 'comparisonOperator' failed to satisfy constraint: Member must satisfy enum value set: [IN, NULL, BETWEEN, LT, NOT_CONTAINS, EQ, GT, NOT_NULL, NE, LE, BEGINS_WITH, GE, CONTAINS]", operator), nil)
+	}
+
+	if (spec.arguments >= 0 && len(values) != spec.arguments) || (spec.arguments < 0 && len(values) == 0) {
+		return legacyValidationError("Invalid number of argument(s) for the %s ComparisonOperator", operator)
+	}
+
+	for _, v := range values {
+		if err := types.ValidateItemAttributeValue(v); err != nil {
+			return types.NewError("ValidationException", err.Error(), nil)
+		}
+
+		valueType := attributeValueType(v)
+		if spec.valueTypes != nil && !slices.Contains(spec.valueTypes, valueType) {
+			return legacyValidationError("ComparisonOperator %s is not valid for %s AttributeValue type", operator, valueType)
+		}
+	}
+
+	if operator == "BETWEEN" && attributeValueType(values[0]) != attributeValueType(values[1]) {
+		return legacyValidationError("AttributeValues inside AttributeValueList must be of same type")
+	}
+
+	return nil
+}
+
+// conditionalOperator validates the ConditionalOperator given to join the conditions of a
+// legacy parameter, it returns AND when none was given
+func conditionalOperator(operator *string, conditions int) (string, error) {
+	if operator == nil {
+		return conditionalOperatorAnd, nil
+	}
+
+	if *operator != conditionalOperatorAnd && *operator != conditionalOperatorOr {
+		return "", types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%s' at 'conditionalOperator' failed to satisfy constraint: Member must satisfy enum value set: [AND, OR]", *operator), nil)
+	}
+
+	if conditions < 2 {
+		return "", legacyValidationError("ConditionalOperator can only be used when Filter or Expected has two or more elements")
+	}
+
+	return *operator, nil
+}
+
+// legacyParameter is a request parameter along with whether the request set it
+type legacyParameter struct {
+	name string
+	set  bool
+}
+
+// validateParameterStyle rejects the requests that set both expression parameters and the
+// legacy parameters they replaced, which DynamoDB does not allow to mix
+func validateParameterStyle(legacy, expressions []legacyParameter) error {
+	setNames := func(params []legacyParameter) []string {
+		names := []string{}
+
+		for _, p := range params {
+			if p.set {
+				names = append(names, p.name)
+			}
+		}
+
+		return names
+	}
+
+	legacyNames, expressionNames := setNames(legacy), setNames(expressions)
+	if len(legacyNames) == 0 || len(expressionNames) == 0 {
+		return nil
+	}
+
+	return types.NewError("ValidationException", fmt.Sprintf(
+		"Can not use both expression and non-expression parameters in the same request: Non-expression parameters: {%s} Expression parameters: {%s}",
+		strings.Join(legacyNames, ", "), strings.Join(expressionNames, ", "),
+	), nil)
+}
+
+// validateExpressionMaps rejects ExpressionAttributeNames and ExpressionAttributeValues on
+// requests that do not use any expression
+func validateExpressionMaps(names, values int, expressions string) error {
+	if names > 0 {
+		return types.NewError("ValidationException", "ExpressionAttributeNames can only be specified when using expressions", nil)
+	}
+
+	if values > 0 {
+		return types.NewError("ValidationException", "ExpressionAttributeValues can only be specified when using expressions: "+expressions, nil)
+	}
+
+	return nil
+}
+
+// withExpected sets the condition of a write query from its Expected and ConditionalOperator
+// parameters when it does not have a ConditionExpression, nullExpressions names the
+// expressions DynamoDB reports as missing when expression maps are given without them
+func withExpected(query QueryInput, expected map[string]*types.ExpectedAttributeValue, operator *string, nullExpressions string) (QueryInput, error) {
+	if query.ConditionExpression != nil || (len(expected) == 0 && operator == nil) {
+		return query, nil
+	}
+
+	if len(expected) == 0 {
+		_, err := conditionalOperator(operator, 0)
+
+		return query, err
+	}
+
+	if err := validateExpressionMaps(len(query.Aliases), len(query.ExpressionAttributeValues), nullExpressions); err != nil {
+		return query, err
+	}
+
+	tr := newLegacyTranslator()
+
+	condition, err := tr.expected(expected, operator)
+	if err != nil {
+		return query, err
+	}
+
+	query.ConditionExpression = &condition
+	query.Aliases = tr.names
+	query.ExpressionAttributeValues = tr.values
+
+	return query, nil
+}
+
+// withLegacySearch translates the KeyConditions, QueryFilter, ScanFilter, ConditionalOperator
+// and AttributesToGet parameters of a search on the given key schema into its key condition,
+// filter and projection expressions
+func withLegacySearch(input QueryInput, ks keySchema, attributesDef map[string]string) (QueryInput, error) {
+	filterName, filter := "QueryFilter", input.QueryFilter
+	nullExpressions := "KeyConditionExpression and FilterExpression are null"
+
+	if input.Scan {
+		filterName, filter = "ScanFilter", input.ScanFilter
+		nullExpressions = "FilterExpression is null"
+	}
+
+	if err := validateParameterStyle(
+		[]legacyParameter{{"AttributesToGet", input.AttributesToGet != nil}, {"ConditionalOperator", input.ConditionalOperator != nil}, {"KeyConditions", input.KeyConditions != nil}, {filterName, filter != nil}},
+		[]legacyParameter{{"FilterExpression", input.FilterExpression != ""}, {"KeyConditionExpression", input.KeyConditionExpression != ""}, {"ProjectionExpression", input.ProjectionExpression != ""}},
+	); err != nil {
+		return input, err
+	}
+
+	if input.KeyConditions == nil && filter == nil && input.ConditionalOperator == nil && input.AttributesToGet == nil {
+		return input, nil
+	}
+
+	if err := validateExpressionMaps(len(input.Aliases), len(input.ExpressionAttributeValues), nullExpressions); err != nil {
+		return input, err
+	}
+
+	tr := newLegacyTranslator()
+
+	if !input.Scan && input.KeyConditions != nil {
+		keyCondition, err := tr.keyConditions(input.KeyConditions, ks, attributesDef)
+		if err != nil {
+			return input, err
+		}
+
+		input.KeyConditionExpression = keyCondition
+	}
+
+	if len(filter) == 0 {
+		if _, err := conditionalOperator(input.ConditionalOperator, 0); err != nil {
+			return input, err
+		}
+	} else {
+		for attr := range filter {
+			if !input.Scan && (attr == ks.HashKey || attr == ks.RangeKey) {
+				return input, types.NewError("ValidationException", "QueryFilter can only contain non-primary key attributes: Primary key attribute: "+attr, nil)
+			}
+		}
+
+		filterExpression, err := tr.filter(filter, input.ConditionalOperator)
+		if err != nil {
+			return input, err
+		}
+
+		input.FilterExpression = filterExpression
+	}
+
+	if input.AttributesToGet != nil {
+		projection, err := tr.attributesToGet(input.AttributesToGet)
+		if err != nil {
+			return input, err
+		}
+
+		input.ProjectionExpression = projection
+	}
+
+	input.Aliases, input.ExpressionAttributeValues = tr.names, tr.values
+
+	return input, nil
+}
+
+// ProjectionForAttributesToGet returns the projection expression and the attribute names a
+// read uses for its AttributesToGet parameter, or the given ones when it is not set
+func ProjectionForAttributesToGet(attributesToGet []string, projection string, aliases map[string]string) (string, map[string]string, error) {
+	if err := validateParameterStyle(
+		[]legacyParameter{{"AttributesToGet", attributesToGet != nil}},
+		[]legacyParameter{{"ProjectionExpression", projection != ""}},
+	); err != nil {
+		return "", nil, err
+	}
+
+	if attributesToGet == nil {
+		return projection, aliases, nil
+	}
+
+	if err := validateExpressionMaps(len(aliases), 0, "ProjectionExpression is null"); err != nil {
+		return "", nil, err
+	}
+
+	tr := newLegacyTranslator()
+
+	projection, err := tr.attributesToGet(attributesToGet)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return projection, tr.names, nil
+}
+
+func attributeValueType(v *types.Item) string {
+	switch {
+	case v.S != nil:
+		return "S"
+	case v.N != nil:
+		return "N"
+	case v.B != nil:
+		return "B"
+	case v.SS != nil:
+		return "SS"
+	case v.NS != nil:
+		return "NS"
+	case v.BS != nil:
+		return "BS"
+	case v.BOOL != nil:
+		return "BOOL"
+	case v.NULL != nil:
+		return "NULL"
+	case v.L != nil:
+		return "L"
+	case v.M != nil:
+		return "M"
+	}
+
+	return ""
+}
+
+func legacyValidationError(format string, args ...any) error {
+	return types.NewError("ValidationException", "One or more parameter values were invalid: "+fmt.Sprintf(format, args...), nil)
+}
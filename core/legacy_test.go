@@ -0,0 +1,582 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestExpectedConditions(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	pikachu := map[string]*types.Item{
+		"trainer": {S: new("ash")},
+		"pokemon": {S: new("pikachu")},
+		"type":    {S: new("electric")},
+		"level":   {N: new("25")},
+		"moves":   {SS: []*string{new("thunder"), new("quick attack")}},
+	}
+
+	_, err := table.Put(&types.PutItemInput{Item: pikachu})
+	c.NoError(err)
+
+	cases := []struct {
+		name     string
+		expected map[string]*types.ExpectedAttributeValue
+		operator *string
+		matched  bool
+	}{
+		{"value", map[string]*types.ExpectedAttributeValue{"type": {Value: &types.Item{S: new("electric")}}}, nil, true},
+		{"exists", map[string]*types.ExpectedAttributeValue{"type": {Exists: new(true), Value: &types.Item{S: new("fire")}}}, nil, false},
+		{"not exists", map[string]*types.ExpectedAttributeValue{"evolution": {Exists: new(false)}}, nil, true},
+		{"EQ", map[string]*types.ExpectedAttributeValue{"level": {ComparisonOperator: new("EQ"), AttributeValueList: []*types.Item{{N: new("25.0")}}}}, nil, true},
+		{"NE", map[string]*types.ExpectedAttributeValue{"level": {ComparisonOperator: new("NE"), AttributeValueList: []*types.Item{{N: new("25")}}}}, nil, false},
+		{"LE", map[string]*types.ExpectedAttributeValue{"level": {ComparisonOperator: new("LE"), AttributeValueList: []*types.Item{{N: new("25")}}}}, nil, true},
+		{"LT", map[string]*types.ExpectedAttributeValue{"level": {ComparisonOperator: new("LT"), AttributeValueList: []*types.Item{{N: new("25")}}}}, nil, false},
+		{"GE", map[string]*types.ExpectedAttributeValue{"level": {ComparisonOperator: new("GE"), AttributeValueList: []*types.Item{{N: new("30")}}}}, nil, false},
+		{"GT", map[string]*types.ExpectedAttributeValue{"level": {ComparisonOperator: new("GT"), AttributeValueList: []*types.Item{{N: new("3")}}}}, nil, true},
+		{"NOT_NULL", map[string]*types.ExpectedAttributeValue{"moves": {ComparisonOperator: new("NOT_NULL")}}, nil, true},
+		{"NULL", map[string]*types.ExpectedAttributeValue{"moves": {ComparisonOperator: new("NULL")}}, nil, false},
+		{"CONTAINS", map[string]*types.ExpectedAttributeValue{"moves": {ComparisonOperator: new("CONTAINS"), AttributeValueList: []*types.Item{{S: new("thunder")}}}}, nil, true},
+		{"NOT_CONTAINS", map[string]*types.ExpectedAttributeValue{"moves": {ComparisonOperator: new("NOT_CONTAINS"), AttributeValueList: []*types.Item{{S: new("thunder")}}}}, nil, false},
+		{"NOT_CONTAINS missing", map[string]*types.ExpectedAttributeValue{"evolution": {ComparisonOperator: new("NOT_CONTAINS"), AttributeValueList: []*types.Item{{S: new("raichu")}}}}, nil, true},
+		{"BEGINS_WITH", map[string]*types.ExpectedAttributeValue{"type": {ComparisonOperator: new("BEGINS_WITH"), AttributeValueList: []*types.Item{{S: new("elec")}}}}, nil, true},
+		{"IN", map[string]*types.ExpectedAttributeValue{"type": {ComparisonOperator: new("IN"), AttributeValueList: []*types.Item{{S: new("fire")}, {S: new("water")}}}}, nil, false},
+		{"BETWEEN", map[string]*types.ExpectedAttributeValue{"level": {ComparisonOperator: new("BETWEEN"), AttributeValueList: []*types.Item{{N: new("20")}, {N: new("30")}}}}, nil, true},
+		{"AND", map[string]*types.ExpectedAttributeValue{
+			"type":  {Value: &types.Item{S: new("electric")}},
+			"level": {ComparisonOperator: new("GT"), AttributeValueList: []*types.Item{{N: new("50")}}},
+		}, new("AND"), false},
+		{"OR", map[string]*types.ExpectedAttributeValue{
+			"type":  {Value: &types.Item{S: new("electric")}},
+			"level": {ComparisonOperator: new("GT"), AttributeValueList: []*types.Item{{N: new("50")}}},
+		}, new("OR"), true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := table.Put(&types.PutItemInput{Item: pikachu, Expected: tc.expected, ConditionalOperator: tc.operator})
+			if tc.matched {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var checkErr *types.ConditionalCheckFailedException
+			require.ErrorAs(t, err, &checkErr)
+		})
+	}
+
+	key := map[string]*types.Item{"trainer": {S: new("ash")}, "pokemon": {S: new("pikachu")}}
+
+	_, err = table.Update(&types.UpdateItemInput{
+		Key:      key,
+		Expected: map[string]*types.ExpectedAttributeValue{"level": {ComparisonOperator: new("LT"), AttributeValueList: []*types.Item{{N: new("10")}}}},
+	})
+	c.ErrorAs(err, new(*types.ConditionalCheckFailedException))
+
+	_, err = table.Delete(&types.DeleteItemInput{
+		Key:      key,
+		Expected: map[string]*types.ExpectedAttributeValue{"type": {Value: &types.Item{S: new("electric")}}},
+	})
+	c.NoError(err)
+
+	stored, _, err := table.SearchData(QueryInput{
+		KeyConditionExpression:    "trainer = :trainer",
+		ExpressionAttributeValues: map[string]*types.Item{":trainer": {S: new("ash")}},
+		ScanIndexForward:          true,
+	})
+	c.NoError(err)
+	c.Len(stored, 3)
+}
+
+func TestExpectedValidation(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+	item := map[string]*types.Item{"trainer": {S: new("ash")}, "pokemon": {S: new("pikachu")}}
+
+	cases := []struct {
+		name  string
+		input *types.PutItemInput
+		err   string
+	}{
+		{
+			"mixed parameters",
+			&types.PutItemInput{Item: item, ConditionExpression: new("attribute_exists(trainer)"), Expected: map[string]*types.ExpectedAttributeValue{"trainer": {Exists: new(false)}}},
+			"Can not use both expression and non-expression parameters in the same request: Non-expression parameters: {Expected} Expression parameters: {ConditionExpression}",
+		},
+		{
+			"expression values without expression",
+			&types.PutItemInput{Item: item, Expected: map[string]*types.ExpectedAttributeValue{"trainer": {Exists: new(false)}}, ExpressionAttributeValues: map[string]*types.Item{":t": {S: new("ash")}}},
+			"ExpressionAttributeValues can only be specified when using expressions: ConditionExpression is null",
+		},
+		{
+			"operator without conditions",
+			&types.PutItemInput{Item: item, ConditionalOperator: new("OR"), Expected: map[string]*types.ExpectedAttributeValue{"trainer": {Exists: new(false)}}},
+			"ConditionalOperator can only be used when Filter or Expected has two or more elements",
+		},
+		{
+			"invalid operator",
+			&types.PutItemInput{Item: item, ConditionalOperator: new("XOR")},
+			"Value 'XOR' at 'conditionalOperator' failed to satisfy constraint",
+		},
+		{
+			"exists without value",
+			&types.PutItemInput{Item: item, Expected: map[string]*types.ExpectedAttributeValue{"trainer": {Exists: new(true)}}},
+			"Value must be provided when Exists is true for Attribute: trainer",
+		},
+		{
+			"value when not exists",
+			&types.PutItemInput{Item: item, Expected: map[string]*types.ExpectedAttributeValue{"trainer": {Exists: new(false), Value: &types.Item{S: new("ash")}}}},
+			"Cannot expect an attribute to have a specified value while expecting it to not exist",
+		},
+		{
+			"argument count",
+			&types.PutItemInput{Item: item, Expected: map[string]*types.ExpectedAttributeValue{"level": {ComparisonOperator: new("BETWEEN"), AttributeValueList: []*types.Item{{N: new("1")}}}}},
+			"Invalid number of argument(s) for the BETWEEN ComparisonOperator",
+		},
+		{
+			"argument type",
+			&types.PutItemInput{Item: item, Expected: map[string]*types.ExpectedAttributeValue{"moves": {ComparisonOperator: new("GT"), AttributeValueList: []*types.Item{{SS: []*string{new("thunder")}}}}}},
+			"ComparisonOperator GT is not valid for SS AttributeValue type",
+		},
+		{
+			"unknown comparison",
+			&types.PutItemInput{Item: item, Expected: map[string]*types.ExpectedAttributeValue{"level": {ComparisonOperator: new("LIKE"), AttributeValueList: []*types.Item{{N: new("1")}}}}},
+			"Value 'LIKE' at 'comparisonOperator' failed to satisfy constraint",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := table.Put(tc.input)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	_, err := table.Update(&types.UpdateItemInput{
+		Key:              item,
+		UpdateExpression: "SET #type = :type",
+		Expected:         map[string]*types.ExpectedAttributeValue{"trainer": {Exists: new(false)}},
+	})
+	c.ErrorContains(err, "Non-expression parameters: {Expected} Expression parameters: {UpdateExpression}")
+}
+
+func TestAttributeUpdates(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+	key := map[string]*types.Item{"trainer": {S: new("gary")}, "pokemon": {S: new("eevee")}}
+
+	// the item is created when it does not exist
+	item, err := table.Update(&types.UpdateItemInput{
+		Key: key,
+		AttributeUpdates: map[string]*types.AttributeValueUpdate{
+			"type":  {Value: &types.Item{S: new("normal")}},
+			"level": {Action: new("ADD"), Value: &types.Item{N: new("5")}},
+			"moves": {Action: new("ADD"), Value: &types.Item{SS: []*string{new("tackle"), new("growl")}}},
+		},
+		ReturnValues: new("ALL_NEW"),
+	})
+	c.NoError(err)
+	c.Equal(map[string]*types.Item{
+		"trainer": {S: new("gary")},
+		"pokemon": {S: new("eevee")},
+		"type":    {S: new("normal")},
+		"level":   {N: new("5")},
+		"moves":   {SS: []*string{new("tackle"), new("growl")}},
+	}, item)
+
+	item, err = table.Update(&types.UpdateItemInput{
+		Key: key,
+		AttributeUpdates: map[string]*types.AttributeValueUpdate{
+			"level": {Action: new("ADD"), Value: &types.Item{N: new("1.5")}},
+			"moves": {Action: new("DELETE"), Value: &types.Item{SS: []*string{new("growl")}}},
+			"type":  {Action: new("DELETE")},
+		},
+		Expected:     map[string]*types.ExpectedAttributeValue{"level": {Value: &types.Item{N: new("5")}}},
+		ReturnValues: new("UPDATED_NEW"),
+	})
+	c.NoError(err)
+	c.Equal(map[string]*types.Item{
+		"level": {N: new("6.5")},
+		"moves": {SS: []*string{new("tackle")}},
+	}, item)
+
+	item, err = table.Update(&types.UpdateItemInput{
+		Key:              key,
+		AttributeUpdates: map[string]*types.AttributeValueUpdate{"moves": {Action: new("PUT"), Value: &types.Item{SS: []*string{new("bite")}}}},
+		ReturnValues:     new("UPDATED_OLD"),
+	})
+	c.NoError(err)
+	c.Equal(map[string]*types.Item{"moves": {SS: []*string{new("tackle")}}}, item)
+
+	// deleting every element of a set removes the attribute
+	item, err = table.Update(&types.UpdateItemInput{
+		Key:              key,
+		AttributeUpdates: map[string]*types.AttributeValueUpdate{"moves": {Action: new("DELETE"), Value: &types.Item{SS: []*string{new("bite")}}}},
+		ReturnValues:     new("ALL_NEW"),
+	})
+	c.NoError(err)
+	c.NotContains(item, "moves")
+
+	_, err = table.Update(&types.UpdateItemInput{
+		Key:              key,
+		AttributeUpdates: map[string]*types.AttributeValueUpdate{"level": {Action: new("ADD"), Value: &types.Item{SS: []*string{new("bite")}}}},
+	})
+	c.Error(err)
+
+	// deleting from a missing set does nothing
+	item, err = table.Update(&types.UpdateItemInput{
+		Key:              key,
+		AttributeUpdates: map[string]*types.AttributeValueUpdate{"moves": {Action: new("DELETE"), Value: &types.Item{SS: []*string{new("bite")}}}},
+		ReturnValues:     new("ALL_NEW"),
+	})
+	c.NoError(err)
+	c.NotContains(item, "moves")
+
+	_, err = table.Update(&types.UpdateItemInput{
+		Key:              key,
+		AttributeUpdates: map[string]*types.AttributeValueUpdate{"level": {Action: new("PUT"), Value: &types.Item{N: new("99")}}},
+		Expected:         map[string]*types.ExpectedAttributeValue{"level": {ComparisonOperator: new("GT"), AttributeValueList: []*types.Item{{N: new("50")}}}},
+	})
+	c.ErrorAs(err, new(*types.ConditionalCheckFailedException))
+}
+
+func TestAttributeUpdatesValidation(t *testing.T) {
+	table := createTrainerTable(require.New(t))
+	key := map[string]*types.Item{"trainer": {S: new("gary")}, "pokemon": {S: new("eevee")}}
+
+	cases := []struct {
+		name  string
+		input *types.UpdateItemInput
+		err   string
+	}{
+		{
+			"mixed parameters",
+			&types.UpdateItemInput{Key: key, UpdateExpression: "SET #type = :type", AttributeUpdates: map[string]*types.AttributeValueUpdate{"level": {Action: new("DELETE")}}},
+			"Can not use both expression and non-expression parameters in the same request: Non-expression parameters: {AttributeUpdates} Expression parameters: {UpdateExpression}",
+		},
+		{
+			"expression names without expression",
+			&types.UpdateItemInput{Key: key, ExpressionAttributeNames: map[string]string{"#type": "type"}, AttributeUpdates: map[string]*types.AttributeValueUpdate{"level": {Action: new("DELETE")}}},
+			"ExpressionAttributeNames can only be specified when using expressions",
+		},
+		{
+			"invalid action",
+			&types.UpdateItemInput{Key: key, AttributeUpdates: map[string]*types.AttributeValueUpdate{"level": {Action: new("REMOVE")}}},
+			"Value 'REMOVE' at 'attributeUpdates.level.member.action' failed to satisfy constraint",
+		},
+		{
+			"key attribute",
+			&types.UpdateItemInput{Key: key, AttributeUpdates: map[string]*types.AttributeValueUpdate{"pokemon": {Value: &types.Item{S: new("vaporeon")}}}},
+			"Cannot update attribute pokemon. This attribute is part of the key",
+		},
+		{
+			"missing value",
+			&types.UpdateItemInput{Key: key, AttributeUpdates: map[string]*types.AttributeValueUpdate{"level": {Action: new("ADD")}}},
+			"Only DELETE action is allowed when no attribute value is specified",
+		},
+		{
+			"add string",
+			&types.UpdateItemInput{Key: key, AttributeUpdates: map[string]*types.AttributeValueUpdate{"type": {Action: new("ADD"), Value: &types.Item{S: new("water")}}}},
+			"ADD action is not supported for the type S",
+		},
+		{
+			"delete number",
+			&types.UpdateItemInput{Key: key, AttributeUpdates: map[string]*types.AttributeValueUpdate{"level": {Action: new("DELETE"), Value: &types.Item{N: new("1")}}}},
+			"DELETE action with value is not supported for the type N",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := table.Update(tc.input)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestLegacySearch(t *testing.T) {
+	table := createTrainerTable(require.New(t))
+
+	eq := func(value string) *types.Condition {
+		return &types.Condition{ComparisonOperator: new("EQ"), AttributeValueList: []*types.Item{{S: new(value)}}}
+	}
+
+	cases := []struct {
+		name     string
+		input    QueryInput
+		expected []string
+	}{
+		{
+			"hash key",
+			QueryInput{KeyConditions: map[string]*types.Condition{"trainer": eq("misty")}},
+			[]string{"psyduck", "starmie", "staryu"},
+		},
+		{
+			"range key BEGINS_WITH",
+			QueryInput{KeyConditions: map[string]*types.Condition{
+				"trainer": eq("misty"),
+				"pokemon": {ComparisonOperator: new("BEGINS_WITH"), AttributeValueList: []*types.Item{{S: new("star")}}},
+			}},
+			[]string{"starmie", "staryu"},
+		},
+		{
+			"range key BETWEEN",
+			QueryInput{KeyConditions: map[string]*types.Condition{
+				"trainer": eq("ash"),
+				"pokemon": {ComparisonOperator: new("BETWEEN"), AttributeValueList: []*types.Item{{S: new("c")}, {S: new("q")}}},
+			}},
+			[]string{"charizard", "pikachu"},
+		},
+		{
+			"range key LT",
+			QueryInput{KeyConditions: map[string]*types.Condition{
+				"trainer": eq("ash"),
+				"pokemon": {ComparisonOperator: new("LT"), AttributeValueList: []*types.Item{{S: new("c")}}},
+			}},
+			[]string{"bulbasaur"},
+		},
+		{
+			"query filter",
+			QueryInput{
+				KeyConditions: map[string]*types.Condition{"trainer": eq("ash")},
+				QueryFilter:   map[string]*types.Condition{"type": {ComparisonOperator: new("IN"), AttributeValueList: []*types.Item{{S: new("fire")}, {S: new("water")}}}},
+			},
+			[]string{"charizard", "squirtle"},
+		},
+		{
+			"index",
+			QueryInput{Index: "by-type", KeyConditions: map[string]*types.Condition{"type": eq("rock")}},
+			[]string{"geodude", "onix"},
+		},
+		{
+			"index query filter on the table key",
+			QueryInput{
+				Index:         "by-type",
+				KeyConditions: map[string]*types.Condition{"type": eq("water")},
+				QueryFilter:   map[string]*types.Condition{"trainer": {ComparisonOperator: new("NE"), AttributeValueList: []*types.Item{{S: new("misty")}}}},
+			},
+			[]string{"squirtle"},
+		},
+		{
+			"scan filter",
+			QueryInput{Scan: true, ScanFilter: map[string]*types.Condition{"type": eq("rock")}},
+			[]string{"geodude", "onix"},
+		},
+		{
+			"scan filter AND",
+			QueryInput{Scan: true, ScanFilter: map[string]*types.Condition{
+				"type":    eq("water"),
+				"trainer": {ComparisonOperator: new("NE"), AttributeValueList: []*types.Item{{S: new("misty")}}},
+			}},
+			[]string{"squirtle"},
+		},
+		{
+			"scan filter OR",
+			QueryInput{
+				Scan: true,
+				ScanFilter: map[string]*types.Condition{
+					"type":    eq("fire"),
+					"pokemon": {ComparisonOperator: new("BEGINS_WITH"), AttributeValueList: []*types.Item{{S: new("p")}}},
+				},
+				ConditionalOperator: new("OR"),
+			},
+			[]string{"charizard", "pikachu", "psyduck"},
+		},
+		{
+			"scan filter NULL",
+			QueryInput{Scan: true, ScanFilter: map[string]*types.Condition{"level": {ComparisonOperator: new("NULL")}}},
+			[]string{"bulbasaur", "charizard", "geodude", "onix", "pikachu", "psyduck", "squirtle", "starmie", "staryu"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.input.ScanIndexForward = true
+
+			items, _, err := table.SearchData(tc.input)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.expected, pokemonNames(items))
+		})
+	}
+}
+
+func TestLegacySearchValidation(t *testing.T) {
+	table := createTrainerTable(require.New(t))
+
+	eq := func(value string) *types.Condition {
+		return &types.Condition{ComparisonOperator: new("EQ"), AttributeValueList: []*types.Item{{S: new(value)}}}
+	}
+
+	cases := []struct {
+		name  string
+		input QueryInput
+		err   string
+	}{
+		{
+			"mixed parameters",
+			QueryInput{KeyConditionExpression: "trainer = :t", QueryFilter: map[string]*types.Condition{"type": eq("fire")}},
+			"Can not use both expression and non-expression parameters in the same request: Non-expression parameters: {QueryFilter} Expression parameters: {KeyConditionExpression}",
+		},
+		{
+			"expression values without expression",
+			QueryInput{KeyConditions: map[string]*types.Condition{"trainer": eq("ash")}, ExpressionAttributeValues: map[string]*types.Item{":t": {S: new("ash")}}},
+			"ExpressionAttributeValues can only be specified when using expressions: KeyConditionExpression and FilterExpression are null",
+		},
+		{
+			"missing hash key",
+			QueryInput{KeyConditions: map[string]*types.Condition{"pokemon": eq("onix")}},
+			"Query condition missed key schema element: trainer",
+		},
+		{
+			"too many conditions",
+			QueryInput{KeyConditions: map[string]*types.Condition{"trainer": eq("ash"), "pokemon": eq("onix"), "type": eq("rock")}},
+			"Conditions can be of length 1 or 2 only",
+		},
+		{
+			"hash key operator",
+			QueryInput{KeyConditions: map[string]*types.Condition{"trainer": {ComparisonOperator: new("BEGINS_WITH"), AttributeValueList: []*types.Item{{S: new("a")}}}}},
+			"Query key condition not supported",
+		},
+		{
+			"range key operator",
+			QueryInput{KeyConditions: map[string]*types.Condition{"trainer": eq("ash"), "pokemon": {ComparisonOperator: new("NE"), AttributeValueList: []*types.Item{{S: new("onix")}}}}},
+			"Query key condition not supported",
+		},
+		{
+			"non key attribute",
+			QueryInput{KeyConditions: map[string]*types.Condition{"trainer": eq("ash"), "type": eq("fire")}},
+			"Query key condition not supported",
+		},
+		{
+			"schema type",
+			QueryInput{KeyConditions: map[string]*types.Condition{"trainer": {ComparisonOperator: new("EQ"), AttributeValueList: []*types.Item{{N: new("1")}}}}},
+			"Condition parameter type does not match schema type",
+		},
+		{
+			"argument count",
+			QueryInput{KeyConditions: map[string]*types.Condition{"trainer": {ComparisonOperator: new("EQ")}}},
+			"Invalid number of argument(s) for the EQ ComparisonOperator",
+		},
+		{
+			"query filter on a key",
+			QueryInput{KeyConditions: map[string]*types.Condition{"trainer": eq("ash")}, QueryFilter: map[string]*types.Condition{"pokemon": eq("onix")}},
+			"QueryFilter can only contain non-primary key attributes: Primary key attribute: pokemon",
+		},
+		{
+			"conditional operator with one condition",
+			QueryInput{Scan: true, ScanFilter: map[string]*types.Condition{"type": eq("fire")}, ConditionalOperator: new("OR")},
+			"ConditionalOperator can only be used when Filter or Expected has two or more elements",
+		},
+		{
+			"invalid conditional operator",
+			QueryInput{Scan: true, ScanFilter: map[string]*types.Condition{"type": eq("fire"), "trainer": eq("ash")}, ConditionalOperator: new("XOR")},
+			"Value 'XOR' at 'conditionalOperator' failed to satisfy constraint",
+		},
+		{
+			"scan filter with projection expression",
+			QueryInput{Scan: true, ScanFilter: map[string]*types.Condition{"type": eq("fire")}, ProjectionExpression: "pokemon"},
+			"Non-expression parameters: {ScanFilter} Expression parameters: {ProjectionExpression}",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := table.SearchData(tc.input)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestAttributesToGet(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+	keysOnly := &types.Projection{ProjectionType: new("KEYS_ONLY")}
+
+	c.NoError(table.AddGlobalIndexes([]*types.GlobalSecondaryIndex{{
+		ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 1, WriteCapacityUnits: 1},
+		IndexName:             new("type-keys"),
+		KeySchema:             []*types.KeySchemaElement{{AttributeName: "type", KeyType: "HASH"}, {AttributeName: "pokemon", KeyType: "RANGE"}},
+		Projection:            keysOnly,
+	}}))
+	c.NoError(table.AddLocalIndexes([]*types.LocalSecondaryIndex{{
+		IndexName:  new("by-trainer-type"),
+		KeySchema:  []*types.KeySchemaElement{{AttributeName: "trainer", KeyType: "HASH"}, {AttributeName: "type", KeyType: "RANGE"}},
+		Projection: keysOnly,
+	}}))
+
+	_, err := table.Put(&types.PutItemInput{Item: map[string]*types.Item{
+		"trainer": {S: new("ash")},
+		"pokemon": {S: new("pikachu")},
+		"type":    {S: new("electric")},
+		"level":   {N: new("25")},
+	}})
+	c.NoError(err)
+
+	items, _, err := table.SearchData(QueryInput{
+		KeyConditions:    map[string]*types.Condition{"trainer": {ComparisonOperator: new("EQ"), AttributeValueList: []*types.Item{{S: new("ash")}}}},
+		AttributesToGet:  []string{"pokemon", "level"},
+		ScanIndexForward: true,
+	})
+	c.NoError(err)
+	c.Len(items, 4)
+	c.Equal(map[string]*types.Item{"pokemon": {S: new("bulbasaur")}}, items[0])
+	c.Equal(map[string]*types.Item{"pokemon": {S: new("pikachu")}, "level": {N: new("25")}}, items[2])
+
+	// a global index only returns the attributes it projects
+	items, _, err = table.SearchData(QueryInput{
+		Index:            "type-keys",
+		Scan:             true,
+		ScanFilter:       map[string]*types.Condition{"type": {ComparisonOperator: new("EQ"), AttributeValueList: []*types.Item{{S: new("electric")}}}},
+		AttributesToGet:  []string{"trainer", "level"},
+		ScanIndexForward: true,
+	})
+	c.NoError(err)
+	c.Equal([]map[string]*types.Item{{"trainer": {S: new("ash")}}}, items)
+
+	// a local index fetches the attributes it does not project from the table
+	items, _, err = table.SearchData(QueryInput{
+		Index:                     "by-trainer-type",
+		KeyConditionExpression:    "trainer = :trainer AND #type = :type",
+		ProjectionExpression:      "pokemon, #level",
+		Aliases:                   map[string]string{"#type": "type", "#level": "level"},
+		ExpressionAttributeValues: map[string]*types.Item{":trainer": {S: new("ash")}, ":type": {S: new("electric")}},
+		ScanIndexForward:          true,
+	})
+	c.NoError(err)
+	c.Equal([]map[string]*types.Item{{"pokemon": {S: new("pikachu")}, "level": {N: new("25")}}}, items)
+
+	items, _, err = table.SearchData(QueryInput{Index: "by-trainer-type", Scan: true, ScanIndexForward: true})
+	c.NoError(err)
+	c.Len(items, 1)
+	c.NotContains(items[0], "level")
+
+	_, _, err = table.SearchData(QueryInput{Scan: true, AttributesToGet: []string{"pokemon"}, ProjectionExpression: "pokemon"})
+	c.ErrorContains(err, "Non-expression parameters: {AttributesToGet} Expression parameters: {ProjectionExpression}")
+
+	_, _, err = table.SearchData(QueryInput{Scan: true, AttributesToGet: []string{"pokemon", "pokemon"}})
+	c.ErrorContains(err, "Duplicate value in attribute name: pokemon")
+
+	_, _, err = table.SearchData(QueryInput{Scan: true, AttributesToGet: []string{}})
+	c.ErrorContains(err, "Member must have length greater than or equal to 1")
+
+	projection, aliases, err := ProjectionForAttributesToGet([]string{"pokemon", "level"}, "", nil)
+	c.NoError(err)
+	c.Equal("#l0, #l1", projection)
+	c.Equal(map[string]string{"#l0": "pokemon", "#l1": "level"}, aliases)
+
+	projection, aliases, err = ProjectionForAttributesToGet(nil, "#p", map[string]string{"#p": "pokemon"})
+	c.NoError(err)
+	c.Equal("#p", projection)
+	c.Equal(map[string]string{"#p": "pokemon"}, aliases)
+
+	_, _, err = ProjectionForAttributesToGet([]string{"pokemon"}, "", map[string]string{"#p": "pokemon"})
+	c.ErrorContains(err, "ExpressionAttributeNames can only be specified when using expressions")
+}
@@ -0,0 +1,415 @@
+package core
+
+import (
+	"iter"
+	"math/rand/v2"
+	"strings"
+)
+
+const (
+	skipListMaxLevel = 24
+	// skipListP is the inverse probability of promoting a node to the next level
+	skipListP = 4
+)
+
+// keyEntry locates an item inside a partition. Entries are ordered by range key and then by
+// the primary key of the item, which keeps items sharing an index range key in a stable order.
+type keyEntry struct {
+	rangeKey string
+	pk       string
+}
+
+func (e keyEntry) compare(o keyEntry) int {
+	if c := strings.Compare(e.rangeKey, o.rangeKey); c != 0 {
+		return c
+	}
+
+	return strings.Compare(e.pk, o.pk)
+}
+
+type skipNode struct {
+	entry keyEntry
+	next  []*skipNode
+	prev  *skipNode
+}
+
+// skipList keeps the entries of one partition ordered by range key
+type skipList struct {
+	head   *skipNode
+	tail   *skipNode
+	level  int
+	length int
+}
+
+func newSkipList() *skipList {
+	return &skipList{
+		head:  &skipNode{next: make([]*skipNode, skipListMaxLevel)},
+		level: 1,
+	}
+}
+
+func randomSkipListLevel() int {
+	level := 1
+	for level < skipListMaxLevel && rand.IntN(skipListP) == 0 {
+		level++
+	}
+
+	return level
+}
+
+// findPredecessors returns, for each level, the last node whose entry is lower than e
+func (sl *skipList) findPredecessors(e keyEntry) []*skipNode {
+	update := make([]*skipNode, skipListMaxLevel)
+	x := sl.head
+
+	for lvl := sl.level - 1; lvl >= 0; lvl-- {
+		for x.next[lvl] != nil && x.next[lvl].entry.compare(e) < 0 {
+			x = x.next[lvl]
+		}
+
+		update[lvl] = x
+	}
+
+	return update
+}
+
+// insert adds the entry, it returns false when the entry was already stored
+func (sl *skipList) insert(e keyEntry) bool {
+	update := sl.findPredecessors(e)
+
+	if n := update[0].next[0]; n != nil && n.entry.compare(e) == 0 {
+		return false
+	}
+
+	level := randomSkipListLevel()
+	if level > sl.level {
+		for lvl := sl.level; lvl < level; lvl++ {
+			update[lvl] = sl.head
+		}
+
+		sl.level = level
+	}
+
+	n := &skipNode{entry: e, next: make([]*skipNode, level)}
+
+	for lvl := range level {
+		n.next[lvl] = update[lvl].next[lvl]
+		update[lvl].next[lvl] = n
+	}
+
+	if update[0] != sl.head {
+		n.prev = update[0]
+	}
+
+	if n.next[0] != nil {
+		n.next[0].prev = n
+	} else {
+		sl.tail = n
+	}
+
+	sl.length++
+
+	return true
+}
+
+// remove deletes the entry, it returns false when the entry was not stored
+func (sl *skipList) remove(e keyEntry) bool {
+	update := sl.findPredecessors(e)
+
+	n := update[0].next[0]
+	if n == nil || n.entry.compare(e) != 0 {
+		return false
+	}
+
+	for lvl := range n.next {
+		update[lvl].next[lvl] = n.next[lvl]
+	}
+
+	if n.next[0] != nil {
+		n.next[0].prev = n.prev
+	} else {
+		sl.tail = n.prev
+	}
+
+	for sl.level > 1 && sl.head.next[sl.level-1] == nil {
+		sl.level--
+	}
+
+	sl.length--
+
+	return true
+}
+
+// seek returns the first node whose entry is greater than or equal to e
+func (sl *skipList) seek(e keyEntry) *skipNode {
+	return sl.findPredecessors(e)[0].next[0]
+}
+
+// seekLast returns the last node whose entry is lower than or equal to e
+func (sl *skipList) seekLast(e keyEntry) *skipNode {
+	n := sl.seek(e)
+	if n == nil {
+		return sl.tail
+	}
+
+	if n.entry.compare(e) == 0 {
+		return n
+	}
+
+	return n.prev
+}
+
+func (sl *skipList) first() *skipNode {
+	return sl.head.next[0]
+}
+
+// clone returns a copy of the list, entries are appended in order so it runs in linear time
+func (sl *skipList) clone() *skipList {
+	out := newSkipList()
+	tails := make([]*skipNode, skipListMaxLevel)
+
+	for lvl := range tails {
+		tails[lvl] = out.head
+	}
+
+	for x := sl.first(); x != nil; x = x.next[0] {
+		level := randomSkipListLevel()
+		out.level = max(out.level, level)
+
+		n := &skipNode{entry: x.entry, next: make([]*skipNode, level)}
+		if out.tail != nil {
+			n.prev = out.tail
+		}
+
+		for lvl := range level {
+			tails[lvl].next[lvl] = n
+			tails[lvl] = n
+		}
+
+		out.tail = n
+		out.length++
+	}
+
+	return out
+}
+
+// partitionMap groups the keys of a table or index by partition (hash key). Each partition
+// keeps its entries ordered by range key so a query can go straight to its partition and
+// sort key range instead of walking every stored key.
+type partitionMap struct {
+	partitions map[string]*skipList
+	// hashKeys keeps the partition keys sorted as the range keys of its entries, it is used
+	// by scans
+	hashKeys *skipList
+	size     int
+}
+
+func newPartitionMap() *partitionMap {
+	return &partitionMap{
+		partitions: map[string]*skipList{},
+		hashKeys:   newSkipList(),
+	}
+}
+
+func (pm *partitionMap) len() int {
+	return pm.size
+}
+
+func (pm *partitionMap) put(hashKey string, e keyEntry) {
+	p, ok := pm.partitions[hashKey]
+	if !ok {
+		p = newSkipList()
+		pm.partitions[hashKey] = p
+
+		pm.hashKeys.insert(keyEntry{rangeKey: hashKey})
+	}
+
+	if p.insert(e) {
+		pm.size++
+	}
+}
+
+func (pm *partitionMap) remove(hashKey string, e keyEntry) {
+	p, ok := pm.partitions[hashKey]
+	if !ok || !p.remove(e) {
+		return
+	}
+
+	pm.size--
+
+	if p.length > 0 {
+		return
+	}
+
+	delete(pm.partitions, hashKey)
+	pm.hashKeys.remove(keyEntry{rangeKey: hashKey})
+}
+
+func (pm *partitionMap) clone() *partitionMap {
+	out := &partitionMap{
+		partitions: make(map[string]*skipList, len(pm.partitions)),
+		hashKeys:   pm.hashKeys.clone(),
+		size:       pm.size,
+	}
+
+	for hashKey, p := range pm.partitions {
+		out.partitions[hashKey] = p.clone()
+	}
+
+	return out
+}
+
+// keyBound limits the range keys visited inside a partition, a nil bound is open
+type keyBound struct {
+	lower  *string
+	upper  *string
+	prefix *string
+}
+
+func (b keyBound) aboveUpper(rangeKey string) bool {
+	if b.upper != nil && rangeKey > *b.upper {
+		return true
+	}
+
+	return b.prefix != nil && rangeKey > *b.prefix && !strings.HasPrefix(rangeKey, *b.prefix)
+}
+
+func (b keyBound) belowLower(rangeKey string) bool {
+	if b.lower != nil && rangeKey < *b.lower {
+		return true
+	}
+
+	return b.prefix != nil && rangeKey < *b.prefix
+}
+
+// partitionCursor is the position of an entry inside the partition map
+type partitionCursor struct {
+	hashKey string
+	entry   keyEntry
+}
+
+// rangeEntries iterates the entries of one partition between the bounds. When after is set the
+// iteration starts right after that entry, in the iteration direction.
+func (pm *partitionMap) rangeEntries(hashKey string, bound keyBound, after *keyEntry, forward bool) iter.Seq[keyEntry] {
+	return func(yield func(keyEntry) bool) {
+		p, ok := pm.partitions[hashKey]
+		if !ok {
+			return
+		}
+
+		if forward {
+			p.walkForward(bound, after, yield)
+
+			return
+		}
+
+		p.walkBackward(bound, after, yield)
+	}
+}
+
+func (sl *skipList) walkForward(bound keyBound, after *keyEntry, yield func(keyEntry) bool) {
+	var n *skipNode
+
+	switch {
+	case after != nil:
+		n = sl.seek(*after)
+		if n != nil && n.entry.compare(*after) == 0 {
+			n = n.next[0]
+		}
+	case bound.lower != nil:
+		n = sl.seek(keyEntry{rangeKey: *bound.lower})
+	case bound.prefix != nil:
+		n = sl.seek(keyEntry{rangeKey: *bound.prefix})
+	default:
+		n = sl.first()
+	}
+
+	for ; n != nil; n = n.next[0] {
+		if bound.aboveUpper(n.entry.rangeKey) {
+			return
+		}
+
+		if !yield(n.entry) {
+			return
+		}
+	}
+}
+
+func (sl *skipList) walkBackward(bound keyBound, after *keyEntry, yield func(keyEntry) bool) {
+	var n *skipNode
+
+	switch {
+	case after != nil:
+		n = sl.seek(*after)
+		if n == nil {
+			n = sl.tail
+		} else {
+			n = n.prev
+		}
+	case bound.upper != nil:
+		// no range key sorts between upper and upper+"\x00", so this lands on the last
+		// entry sharing the upper range key whatever its primary key is
+		n = sl.seekLast(keyEntry{rangeKey: *bound.upper + "\x00"})
+	default:
+		n = sl.tail
+	}
+
+	for ; n != nil; n = n.prev {
+		if bound.aboveUpper(n.entry.rangeKey) {
+			continue
+		}
+
+		if bound.belowLower(n.entry.rangeKey) {
+			return
+		}
+
+		if !yield(n.entry) {
+			return
+		}
+	}
+}
+
+// entries iterates every entry of the map ordered by partition and range key. When after is
+// set the iteration starts right after that position, in the iteration direction.
+func (pm *partitionMap) entries(after *partitionCursor, forward bool) iter.Seq[partitionCursor] {
+	return func(yield func(partitionCursor) bool) {
+		// removed nodes keep their links, so the walk goes on when the items of the
+		// current partition are deleted while iterating
+		for n := pm.firstPartition(after, forward); n != nil; n = nextPartition(n, forward) {
+			hashKey := n.entry.rangeKey
+
+			var from *keyEntry
+			if after != nil && hashKey == after.hashKey {
+				from = &after.entry
+			}
+
+			for e := range pm.rangeEntries(hashKey, keyBound{}, from, forward) {
+				if !yield(partitionCursor{hashKey: hashKey, entry: e}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// firstPartition returns the node of the first partition visited by entries
+func (pm *partitionMap) firstPartition(after *partitionCursor, forward bool) *skipNode {
+	switch {
+	case after == nil && forward:
+		return pm.hashKeys.first()
+	case after == nil:
+		return pm.hashKeys.tail
+	case forward:
+		return pm.hashKeys.seek(keyEntry{rangeKey: after.hashKey})
+	default:
+		return pm.hashKeys.seekLast(keyEntry{rangeKey: after.hashKey})
+	}
+}
+
+func nextPartition(n *skipNode, forward bool) *skipNode {
+	if forward {
+		return n.next[0]
+	}
+
+	return n.prev
+}
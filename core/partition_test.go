@@ -0,0 +1,217 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collectRangeKeys(pm *partitionMap, hashKey string, bound keyBound, after *keyEntry, forward bool) []string {
+	keys := []string{}
+
+	for e := range pm.rangeEntries(hashKey, bound, after, forward) {
+		keys = append(keys, e.rangeKey)
+	}
+
+	return keys
+}
+
+func partitionKeys(pm *partitionMap) []string {
+	keys := []string{}
+
+	for n := pm.hashKeys.first(); n != nil; n = n.next[0] {
+		keys = append(keys, n.entry.rangeKey)
+	}
+
+	return keys
+}
+
+func TestSkipList(t *testing.T) {
+	c := require.New(t)
+
+	sl := newSkipList()
+	c.Nil(sl.first())
+
+	for _, i := range []int{5, 3, 9, 1, 7, 3} {
+		sl.insert(keyEntry{rangeKey: fmt.Sprintf("%02d", i)})
+	}
+
+	c.Equal(5, sl.length)
+	c.False(sl.insert(keyEntry{rangeKey: "05"}))
+
+	keys := []string{}
+	for n := sl.first(); n != nil; n = n.next[0] {
+		keys = append(keys, n.entry.rangeKey)
+	}
+
+	c.Equal([]string{"01", "03", "05", "07", "09"}, keys)
+
+	keys = []string{}
+	for n := sl.tail; n != nil; n = n.prev {
+		keys = append(keys, n.entry.rangeKey)
+	}
+
+	c.Equal([]string{"09", "07", "05", "03", "01"}, keys)
+
+	c.Equal("05", sl.seek(keyEntry{rangeKey: "04"}).entry.rangeKey)
+	c.Nil(sl.seek(keyEntry{rangeKey: "10"}))
+	c.Equal("03", sl.seekLast(keyEntry{rangeKey: "04"}).entry.rangeKey)
+	c.Equal("09", sl.seekLast(keyEntry{rangeKey: "10"}).entry.rangeKey)
+	c.Nil(sl.seekLast(keyEntry{rangeKey: "00"}))
+
+	cloned := sl.clone()
+
+	c.True(sl.remove(keyEntry{rangeKey: "09"}))
+	c.False(sl.remove(keyEntry{rangeKey: "09"}))
+	c.True(sl.remove(keyEntry{rangeKey: "01"}))
+	c.Equal("07", sl.tail.entry.rangeKey)
+	c.Equal("03", sl.first().entry.rangeKey)
+	c.Nil(sl.first().prev)
+	c.Equal(3, sl.length)
+
+	c.Equal(5, cloned.length)
+	c.Equal("09", cloned.tail.entry.rangeKey)
+	c.Equal("01", cloned.first().entry.rangeKey)
+}
+
+func TestSkipListLargeOrdering(t *testing.T) {
+	c := require.New(t)
+
+	sl := newSkipList()
+	want := []string{}
+
+	for i := range 1000 {
+		k := fmt.Sprintf("%04d", (i*7919)%1000)
+		sl.insert(keyEntry{rangeKey: k})
+		want = append(want, k)
+	}
+
+	slices.Sort(want)
+
+	got := []string{}
+	for n := sl.first(); n != nil; n = n.next[0] {
+		got = append(got, n.entry.rangeKey)
+	}
+
+	c.Equal(want, got)
+
+	for i := 0; i < 1000; i += 2 {
+		c.True(sl.remove(keyEntry{rangeKey: fmt.Sprintf("%04d", i)}))
+	}
+
+	c.Equal(500, sl.length)
+	c.Equal("0001", sl.first().entry.rangeKey)
+	c.Equal("0999", sl.tail.entry.rangeKey)
+}
+
+func TestPartitionMap(t *testing.T) {
+	c := require.New(t)
+
+	pm := newPartitionMap()
+
+	for _, hashKey := range []string{"b", "a", "c"} {
+		for _, rangeKey := range []string{"3", "1", "2"} {
+			pm.put(hashKey, keyEntry{rangeKey: rangeKey, pk: hashKey + "." + rangeKey})
+		}
+	}
+
+	pm.put("a", keyEntry{rangeKey: "1", pk: "a.1"})
+
+	c.Equal(9, pm.len())
+	c.Equal([]string{"a", "b", "c"}, partitionKeys(pm))
+
+	c.Equal([]string{"1", "2", "3"}, collectRangeKeys(pm, "b", keyBound{}, nil, true))
+	c.Equal([]string{"3", "2", "1"}, collectRangeKeys(pm, "b", keyBound{}, nil, false))
+	c.Empty(collectRangeKeys(pm, "z", keyBound{}, nil, true))
+
+	pks := []string{}
+	for cur := range pm.entries(&partitionCursor{hashKey: "b", entry: keyEntry{rangeKey: "2", pk: "b.2"}}, true) {
+		pks = append(pks, cur.entry.pk)
+	}
+
+	c.Equal([]string{"b.3", "c.1", "c.2", "c.3"}, pks)
+
+	pks = []string{}
+	for cur := range pm.entries(&partitionCursor{hashKey: "b", entry: keyEntry{rangeKey: "2", pk: "b.2"}}, false) {
+		pks = append(pks, cur.entry.pk)
+	}
+
+	c.Equal([]string{"b.1", "a.3", "a.2", "a.1"}, pks)
+
+	// the cursor of a partition that no longer exists resumes at its neighbours
+	pks = []string{}
+	for cur := range pm.entries(&partitionCursor{hashKey: "bb", entry: keyEntry{rangeKey: "1", pk: "bb.1"}}, false) {
+		pks = append(pks, cur.entry.pk)
+	}
+
+	c.Equal([]string{"b.3", "b.2", "b.1", "a.3", "a.2", "a.1"}, pks)
+
+	cloned := pm.clone()
+
+	for _, rangeKey := range []string{"1", "2", "3"} {
+		pm.remove("b", keyEntry{rangeKey: rangeKey, pk: "b." + rangeKey})
+	}
+
+	pm.remove("b", keyEntry{rangeKey: "1", pk: "b.1"})
+
+	c.Equal(6, pm.len())
+	c.Equal([]string{"a", "c"}, partitionKeys(pm))
+	c.NotContains(pm.partitions, "b")
+
+	c.Equal(9, cloned.len())
+	c.Equal([]string{"1", "2", "3"}, collectRangeKeys(cloned, "b", keyBound{}, nil, true))
+}
+
+func TestPartitionMapRangeBounds(t *testing.T) {
+	c := require.New(t)
+
+	pm := newPartitionMap()
+
+	for _, rangeKey := range []string{"apple", "apricot", "banana", "blueberry", "cherry"} {
+		pm.put("fruit", keyEntry{rangeKey: rangeKey, pk: "fruit." + rangeKey})
+	}
+
+	// items sharing a range key (e.g. in a secondary index) are ordered by primary key
+	pm.put("fruit", keyEntry{rangeKey: "banana", pk: "another.banana"})
+
+	lower, upper, prefix := "apricot", "blueberry", "b"
+
+	c.Equal([]string{"apricot", "banana", "banana", "blueberry"}, collectRangeKeys(pm, "fruit", keyBound{lower: &lower, upper: &upper}, nil, true))
+	c.Equal([]string{"blueberry", "banana", "banana", "apricot"}, collectRangeKeys(pm, "fruit", keyBound{lower: &lower, upper: &upper}, nil, false))
+	c.Equal([]string{"banana", "banana", "blueberry"}, collectRangeKeys(pm, "fruit", keyBound{prefix: &prefix}, nil, true))
+	c.Equal([]string{"blueberry", "banana", "banana"}, collectRangeKeys(pm, "fruit", keyBound{prefix: &prefix}, nil, false))
+	c.Equal([]string{"apple", "apricot", "banana", "banana", "blueberry"}, collectRangeKeys(pm, "fruit", keyBound{upper: &upper}, nil, true))
+	c.Equal([]string{"cherry", "blueberry", "banana", "banana", "apricot"}, collectRangeKeys(pm, "fruit", keyBound{lower: &lower}, nil, false))
+
+	after := keyEntry{rangeKey: "banana", pk: "another.banana"}
+	c.Equal([]string{"banana", "blueberry"}, collectRangeKeys(pm, "fruit", keyBound{prefix: &prefix}, &after, true))
+	c.Equal([]string{"apricot", "apple"}, collectRangeKeys(pm, "fruit", keyBound{}, &after, false))
+
+	c.Equal([]string{"apple"}, collectRangeKeys(pm, "fruit", keyBound{}, &keyEntry{rangeKey: "apricot"}, false))
+	c.Empty(collectRangeKeys(pm, "fruit", keyBound{}, &keyEntry{rangeKey: "cherry", pk: "fruit.cherry"}, true))
+}
+
+func TestPartitionMapRemoveWhileIterating(t *testing.T) {
+	c := require.New(t)
+
+	pm := newPartitionMap()
+
+	for i := range 100 {
+		hashKey := fmt.Sprintf("%03d", i)
+		pm.put(hashKey, keyEntry{rangeKey: "1", pk: hashKey})
+	}
+
+	visited := []string{}
+
+	for cur := range pm.entries(nil, true) {
+		visited = append(visited, cur.hashKey)
+		pm.remove(cur.hashKey, cur.entry)
+	}
+
+	c.Len(visited, 100)
+	c.True(slices.IsSorted(visited))
+	c.Zero(pm.len())
+	c.Empty(partitionKeys(pm))
+}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// DefaultRecoveryPeriodInDays is how far back a table can be restored when point in
+	// time recovery is enabled without a recovery period
+	DefaultRecoveryPeriodInDays = 35
+
+	minRecoveryPeriodInDays = 1
+)
+
+// PointInTimeRecovery describes the point in time recovery settings of a table
+type PointInTimeRecovery struct {
+	Enabled                    bool
+	RecoveryPeriodInDays       int
+	EarliestRestorableDateTime time.Time
+	LatestRestorableDateTime   time.Time
+}
+
+// pointInTimeHistory keeps the items the table had when the history starts and the changes
+// made since then, so the table can be rebuilt as of any time in between
+type pointInTimeHistory struct {
+	recoveryPeriod time.Duration
+	start          time.Time
+	base           map[string]map[string]*types.Item
+	changes        []itemChange
+}
+
+// itemChange is an item written at a time, a nil item means it was removed and cleared
+// means every item was removed
+type itemChange struct {
+	key     string
+	item    map[string]*types.Item
+	cleared bool
+	at      time.Time
+}
+
+// ValidateRecoveryPeriodInDays checks the range of a point in time recovery period
+func ValidateRecoveryPeriodInDays(days int) error {
+	if days >= minRecoveryPeriodInDays && days <= DefaultRecoveryPeriodInDays {
+		return nil
+	}
+
+	return types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%d' at 'pointInTimeRecoverySpecification.recoveryPeriodInDays' failed to satisfy constraint: Member must have value between %d and %d", days, minRecoveryPeriodInDays, DefaultRecoveryPeriodInDays), nil)
+}
+
+// EnablePointInTimeRecovery starts recording the item changes of the table, it only updates
+// the recovery period when it is already enabled
+func (t *Table) EnablePointInTimeRecovery(recoveryPeriodInDays int, now time.Time) error {
+	if err := ValidateRecoveryPeriodInDays(recoveryPeriodInDays); err != nil {
+		return err
+	}
+
+	period := time.Duration(recoveryPeriodInDays) * 24 * time.Hour
+
+	if t.history != nil {
+		t.history.recoveryPeriod = period
+
+		return nil
+	}
+
+	t.history = &pointInTimeHistory{
+		recoveryPeriod: period,
+		start:          now,
+		base:           t.Snapshot().data,
+	}
+
+	return nil
+}
+
+// DisablePointInTimeRecovery stops recording the item changes and drops the recorded history
+func (t *Table) DisablePointInTimeRecovery() {
+	t.history = nil
+}
+
+// PointInTimeRecovery describes the point in time recovery of the table at now
+func (t *Table) PointInTimeRecovery(now time.Time) PointInTimeRecovery {
+	if t.history == nil {
+		return PointInTimeRecovery{}
+	}
+
+	t.history.compact(now)
+
+	return PointInTimeRecovery{
+		Enabled:                    true,
+		RecoveryPeriodInDays:       int(t.history.recoveryPeriod / (24 * time.Hour)),
+		EarliestRestorableDateTime: t.history.start,
+		LatestRestorableDateTime:   now,
+	}
+}
+
+// BackupAt copies the table as it was at the given time into a Backup, using the current
+// schema and index definitions
+func (t *Table) BackupAt(at, now time.Time) (*Backup, error) {
+	if t.history == nil {
+		return nil, types.NewError("PointInTimeRecoveryUnavailableException", fmt.Sprintf("Point in time recovery is not enabled for table '%s'", t.Name), nil)
+	}
+
+	t.history.compact(now)
+
+	if at.Before(t.history.start) || at.After(now) {
+		return nil, types.NewError("InvalidRestoreTimeException", fmt.Sprintf("Restore time must be between %s and %s", t.history.start.UTC().Format(time.RFC3339), now.UTC().Format(time.RFC3339)), nil)
+	}
+
+	return t.newBackup(t.Name, at, t.history.itemsAt(at)), nil
+}
+
+// recordHistory appends the change of an item to the point in time history
+func (t *Table) recordHistory(oldItem, newItem map[string]*types.Item) {
+	if t.history == nil {
+		return
+	}
+
+	source := newItem
+	if source == nil {
+		source = oldItem
+	}
+
+	key, err := t.KeySchema.GetKey(t.AttributesDef, source)
+	if err != nil {
+		return
+	}
+
+	t.history.changes = append(t.history.changes, itemChange{key: key, item: deepCopyItemMap(newItem), at: t.now()})
+}
+
+// recordHistoryClear records that every item of the table was removed
+func (t *Table) recordHistoryClear() {
+	if t.history == nil {
+		return
+	}
+
+	t.history.changes = append(t.history.changes, itemChange{cleared: true, at: t.now()})
+}
+
+// itemsAt rebuilds the items as of the given time
+func (h *pointInTimeHistory) itemsAt(at time.Time) map[string]map[string]*types.Item {
+	items := maps.Clone(h.base)
+
+	for _, change := range h.changes {
+		if change.at.After(at) {
+			break
+		}
+
+		change.apply(items)
+	}
+
+	return items
+}
+
+// compact folds the changes that fell out of the recovery period into the base items
+func (h *pointInTimeHistory) compact(now time.Time) {
+	earliest := now.Add(-h.recoveryPeriod)
+	if !h.start.Before(earliest) {
+		return
+	}
+
+	n := 0
+	for n < len(h.changes) && !h.changes[n].at.After(earliest) {
+		h.changes[n].apply(h.base)
+		n++
+	}
+
+	h.changes = h.changes[n:]
+	h.start = earliest
+}
+
+func (c itemChange) apply(items map[string]map[string]*types.Item) {
+	switch {
+	case c.cleared:
+		clear(items)
+	case c.item == nil:
+		delete(items, c.key)
+	default:
+		items[c.key] = c.item
+	}
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestPointInTimeRecovery(t *testing.T) {
+	c := require.New(t)
+
+	clock := NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	table := createTrainerTable(c)
+	table.Clock = clock
+
+	_, err := table.BackupAt(clock.Now(), clock.Now())
+	c.ErrorContains(err, "PointInTimeRecoveryUnavailableException")
+
+	enabledAt := clock.Now()
+	c.NoError(table.EnablePointInTimeRecovery(DefaultRecoveryPeriodInDays, enabledAt))
+	c.Error(table.EnablePointInTimeRecovery(36, enabledAt))
+
+	clock.Advance(time.Minute)
+
+	_, err = table.Delete(&types.DeleteItemInput{Key: map[string]*types.Item{"trainer": {S: new("ash")}, "pokemon": {S: new("pikachu")}}})
+	c.NoError(err)
+
+	beforeCorruption := clock.Now()
+	clock.Advance(time.Minute)
+
+	_, err = table.Put(&types.PutItemInput{Item: map[string]*types.Item{"trainer": {S: new("misty")}, "pokemon": {S: new("staryu")}, "type": {S: new("corrupted")}}})
+	c.NoError(err)
+
+	clock.Advance(time.Minute)
+
+	pitr := table.PointInTimeRecovery(clock.Now())
+	c.True(pitr.Enabled)
+	c.Equal(DefaultRecoveryPeriodInDays, pitr.RecoveryPeriodInDays)
+	c.Equal(enabledAt, pitr.EarliestRestorableDateTime)
+	c.Equal(clock.Now(), pitr.LatestRestorableDateTime)
+
+	backup, err := table.BackupAt(enabledAt, clock.Now())
+	c.NoError(err)
+	c.EqualValues(9, backup.ItemCount)
+
+	backup, err = table.BackupAt(beforeCorruption, clock.Now())
+	c.NoError(err)
+	c.EqualValues(8, backup.ItemCount)
+
+	restored := NewTable("trainers-restored")
+	c.NoError(backup.Restore(restored, RestoreInput{}))
+
+	for _, item := range restored.Data {
+		c.NotEqual("corrupted", types.StringValue(item["type"].S))
+	}
+
+	_, err = table.BackupAt(enabledAt.Add(-time.Second), clock.Now())
+	c.ErrorContains(err, "InvalidRestoreTimeException")
+
+	// changes older than the recovery period are folded into the restorable base
+	c.NoError(table.EnablePointInTimeRecovery(1, clock.Now()))
+	clock.Advance(24*time.Hour - 90*time.Second)
+
+	pitr = table.PointInTimeRecovery(clock.Now())
+	c.Equal(clock.Now().Add(-24*time.Hour), pitr.EarliestRestorableDateTime)
+	c.Len(table.history.changes, 1)
+
+	backup, err = table.BackupAt(pitr.EarliestRestorableDateTime, clock.Now())
+	c.NoError(err)
+	c.EqualValues(8, backup.ItemCount)
+
+	table.DisablePointInTimeRecovery()
+	c.False(table.PointInTimeRecovery(clock.Now()).Enabled)
+}
+
+func TestPointInTimeRecoveryRollback(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+	c.NoError(table.EnablePointInTimeRecovery(DefaultRecoveryPeriodInDays, time.Now()))
+
+	snapshot := table.Snapshot()
+
+	_, err := table.Delete(&types.DeleteItemInput{Key: map[string]*types.Item{"trainer": {S: new("ash")}, "pokemon": {S: new("pikachu")}}})
+	c.NoError(err)
+	c.Len(table.history.changes, 1)
+
+	table.Restore(snapshot)
+	c.Empty(table.history.changes)
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/truora/minidyn/interpreter/language"
+	"github.com/truora/minidyn/types"
+)
+
+// searchPlan narrows a search to the partition and range key bounds pinned by a key condition
+type searchPlan struct {
+	hashKey string
+	bound   keyBound
+}
+
+// planSearch extracts the partition and range key bounds from the key condition of the input.
+// It reports false when the partition key is not pinned to a single value, the search then
+// walks every partition and relies on the interpreter to filter the items.
+func (t *Table) planSearch(input QueryInput, ks keySchema) (searchPlan, bool) {
+	if input.KeyConditionExpression == "" {
+		return searchPlan{}, false
+	}
+
+	conditions, ok := language.ExtractKeyConditions(input.KeyConditionExpression, input.Aliases)
+	if !ok {
+		return searchPlan{}, false
+	}
+
+	plan := searchPlan{}
+	pinned := false
+
+	for _, cond := range conditions {
+		switch cond.Attribute {
+		case ks.HashKey:
+			if cond.Operator != language.KeyConditionEQ {
+				return searchPlan{}, false
+			}
+
+			hashKey, ok := t.encodeKeyCondition(input, ks.HashKey, cond.Values[0])
+			if !ok {
+				return searchPlan{}, false
+			}
+
+			plan.hashKey = hashKey
+			pinned = true
+		case ks.RangeKey:
+			plan.bound = t.rangeKeyBound(input, ks.RangeKey, cond)
+		}
+	}
+
+	return plan, pinned
+}
+
+func (t *Table) encodeKeyCondition(input QueryInput, attr, placeholder string) (string, bool) {
+	val, ok := input.ExpressionAttributeValues[placeholder]
+	if !ok {
+		return "", false
+	}
+
+	encoded, err := encodeKeyAttribute(map[string]*types.Item{attr: val}, attr, t.AttributesDef[attr])
+	if err != nil {
+		return "", false
+	}
+
+	return encoded, true
+}
+
+// rangeKeyBound translates a range key condition into partition bounds. The bounds only
+// narrow the walk, every visited item is still matched against the key condition.
+func (t *Table) rangeKeyBound(input QueryInput, attr string, cond language.KeyCondition) keyBound {
+	values := make([]*string, 0, len(cond.Values))
+
+	for _, placeholder := range cond.Values {
+		encoded, ok := t.encodeKeyCondition(input, attr, placeholder)
+		if !ok {
+			return keyBound{}
+		}
+
+		values = append(values, &encoded)
+	}
+
+	switch cond.Operator {
+	case language.KeyConditionEQ:
+		return keyBound{lower: values[0], upper: values[0]}
+	case language.KeyConditionLT, language.KeyConditionLTE:
+		return keyBound{upper: values[0]}
+	case language.KeyConditionGT, language.KeyConditionGTE:
+		return keyBound{lower: values[0]}
+	case language.KeyConditionBetween:
+		return keyBound{lower: values[0], upper: values[1]}
+	case language.KeyConditionBeginsWith:
+		// begins_with is only valid on S and B keys, whose encodings keep prefixes
+		if t.AttributesDef[attr] == "N" {
+			return keyBound{}
+		}
+
+		return keyBound{prefix: values[0]}
+	}
+
+	return keyBound{}
+}
+
+// startCursor returns the position of the exclusive start key in the searched partition map
+func (t *Table) startCursor(exclusiveStartKey map[string]*types.Item, idx *index) (*partitionCursor, error) {
+	if len(exclusiveStartKey) == 0 {
+		return nil, nil
+	}
+
+	hashKey, rangeKey, err := t.KeySchema.keyParts(t.AttributesDef, exclusiveStartKey)
+	if err != nil {
+		return nil, types.NewError("ValidationException", fmt.Sprintf("The provided starting key is invalid: %s", err.Error()), nil)
+	}
+
+	pk := hashKey
+	if t.KeySchema.RangeKey != "" {
+		pk = joinKeyParts(hashKey, rangeKey)
+	}
+
+	if idx == nil {
+		return &partitionCursor{hashKey: hashKey, entry: keyEntry{rangeKey: rangeKey, pk: pk}}, nil
+	}
+
+	c, ok, err := idx.cursor(pk, exclusiveStartKey)
+	if err != nil || !ok {
+		return nil, types.NewError("ValidationException", "The provided starting key is invalid: The provided key element does not match the schema", nil)
+	}
+
+	return &c, nil
+}
+
+// searchKeys iterates the primary keys of the items visited by a search in result order,
+// skipping the partitions outside of the segment of a parallel scan
+func (t *Table) searchKeys(input QueryInput, idx *index, after *partitionCursor, segment *scanSegment) iter.Seq[string] {
+	pm, ks := t.partitions, t.KeySchema
+	if idx != nil {
+		pm, ks = idx.partitions, idx.keySchema
+	}
+
+	forward := input.ScanIndexForward
+
+	plan, pinned := t.planSearch(input, ks)
+	if !pinned {
+		return func(yield func(string) bool) {
+			for c := range pm.entries(after, forward) {
+				if !segment.contains(c.hashKey) {
+					continue
+				}
+
+				if !yield(c.entry.pk) {
+					return
+				}
+			}
+		}
+	}
+
+	return func(yield func(string) bool) {
+		var from *keyEntry
+
+		if after != nil {
+			if after.hashKey != plan.hashKey {
+				return
+			}
+
+			from = &after.entry
+		}
+
+		for e := range pm.rangeEntries(plan.hashKey, plan.bound, from, forward) {
+			if !yield(e.pk) {
+				return
+			}
+		}
+	}
+}
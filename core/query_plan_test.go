@@ -0,0 +1,240 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func createTrainerTable(c *require.Assertions) *Table {
+	table := NewTable("trainers")
+	table.AttributesDef = map[string]string{"trainer": "S", "pokemon": "S", "type": "S"}
+	table.KeySchema = keySchema{"trainer", "pokemon", false}
+
+	typeIndex := "by-type"
+	allStr := "ALL"
+
+	err := table.AddGlobalIndexes([]*types.GlobalSecondaryIndex{
+		{
+			ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 1, WriteCapacityUnits: 1},
+			IndexName:             &typeIndex,
+			KeySchema: []*types.KeySchemaElement{
+				{AttributeName: "type", KeyType: "HASH"},
+				{AttributeName: "pokemon", KeyType: "RANGE"},
+			},
+			Projection: &types.Projection{ProjectionType: &allStr},
+		},
+	})
+	c.NoError(err)
+
+	team := map[string][][2]string{
+		"ash":   {{"pikachu", "electric"}, {"charizard", "fire"}, {"bulbasaur", "grass"}, {"squirtle", "water"}},
+		"misty": {{"staryu", "water"}, {"starmie", "water"}, {"psyduck", "water"}},
+		"brock": {{"onix", "rock"}, {"geodude", "rock"}},
+	}
+
+	for trainer, pokemons := range team {
+		for _, p := range pokemons {
+			_, err := table.Put(&types.PutItemInput{
+				Item: map[string]*types.Item{
+					"trainer": {S: new(trainer)},
+					"pokemon": {S: new(p[0])},
+					"type":    {S: new(p[1])},
+				},
+			})
+			c.NoError(err)
+		}
+	}
+
+	return table
+}
+
+func pokemonNames(items []map[string]*types.Item) []string {
+	names := make([]string, 0, len(items))
+
+	for _, item := range items {
+		names = append(names, *item["pokemon"].S)
+	}
+
+	return names
+}
+
+func TestPlanSearch(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	input := QueryInput{
+		KeyConditionExpression: "#t = :t AND begins_with(pokemon, :p)",
+		Aliases:                map[string]string{"#t": "trainer"},
+		ExpressionAttributeValues: map[string]*types.Item{
+			":t": {S: new("misty")},
+			":p": {S: new("star")},
+		},
+	}
+
+	plan, pinned := table.planSearch(input, table.KeySchema)
+	c.True(pinned)
+	c.Equal("misty", plan.hashKey)
+	c.Equal("star", *plan.bound.prefix)
+
+	input.KeyConditionExpression = "pokemon = :p"
+	_, pinned = table.planSearch(input, table.KeySchema)
+	c.False(pinned)
+
+	input.KeyConditionExpression = "trainer = :missing"
+	_, pinned = table.planSearch(input, table.KeySchema)
+	c.False(pinned)
+
+	input.KeyConditionExpression = "trainer > :t"
+	_, pinned = table.planSearch(input, table.KeySchema)
+	c.False(pinned)
+
+	_, pinned = table.planSearch(QueryInput{Scan: true}, table.KeySchema)
+	c.False(pinned)
+}
+
+func TestSearchDataSeeksPartition(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	query := func(expr string, forward bool, values map[string]*types.Item) []string {
+		items, _, err := table.SearchData(QueryInput{
+			KeyConditionExpression:    expr,
+			ExpressionAttributeValues: values,
+			ScanIndexForward:          forward,
+		})
+		c.NoError(err)
+
+		return pokemonNames(items)
+	}
+
+	ash := map[string]*types.Item{":t": {S: new("ash")}, ":a": {S: new("c")}, ":b": {S: new("q")}}
+
+	c.Equal([]string{"bulbasaur", "charizard", "pikachu", "squirtle"}, query("trainer = :t", true, ash))
+	c.Equal([]string{"squirtle", "pikachu", "charizard", "bulbasaur"}, query("trainer = :t", false, ash))
+	c.Equal([]string{"charizard", "pikachu"}, query("trainer = :t AND pokemon BETWEEN :a AND :b", true, ash))
+	c.Equal([]string{"pikachu", "charizard"}, query("trainer = :t AND pokemon BETWEEN :a AND :b", false, ash))
+	c.Equal([]string{"squirtle", "pikachu", "charizard"}, query("trainer = :t AND pokemon > :a", false, ash))
+	c.Equal([]string{"bulbasaur"}, query("trainer = :t AND pokemon < :a", true, ash))
+	c.Equal([]string{"charizard"}, query("trainer = :t AND begins_with(pokemon, :a)", true, ash))
+
+	misty := map[string]*types.Item{":t": {S: new("misty")}, ":p": {S: new("starmie")}}
+	c.Equal([]string{"starmie"}, query("trainer = :t AND pokemon = :p", true, misty))
+	c.Equal([]string{"psyduck", "starmie"}, query("trainer = :t AND pokemon <= :p", true, misty))
+	c.Equal([]string{"staryu", "starmie"}, query("trainer = :t AND pokemon >= :p", false, misty))
+
+	c.Empty(query("trainer = :t", true, map[string]*types.Item{":t": {S: new("gary")}}))
+}
+
+func TestSearchDataPartitionPaging(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	for _, forward := range []bool{true, false} {
+		input := QueryInput{
+			KeyConditionExpression:    "trainer = :t",
+			ExpressionAttributeValues: map[string]*types.Item{":t": {S: new("ash")}},
+			ScanIndexForward:          forward,
+			Limit:                     3,
+		}
+
+		first, last, err := table.SearchData(input)
+		c.NoError(err)
+		c.Len(first, 3)
+		c.Len(last, 2)
+
+		input.ExclusiveStartKey = last
+
+		second, last, err := table.SearchData(input)
+		c.NoError(err)
+		c.Len(second, 1)
+		c.Empty(last)
+
+		all, _, err := table.SearchData(QueryInput{
+			KeyConditionExpression:    "trainer = :t",
+			ExpressionAttributeValues: map[string]*types.Item{":t": {S: new("ash")}},
+			ScanIndexForward:          forward,
+		})
+		c.NoError(err)
+		c.Equal(pokemonNames(all), append(pokemonNames(first), pokemonNames(second)...))
+	}
+
+	// a start key from another partition never matches the pinned partition
+	items, _, err := table.SearchData(QueryInput{
+		KeyConditionExpression:    "trainer = :t",
+		ExpressionAttributeValues: map[string]*types.Item{":t": {S: new("ash")}},
+		ExclusiveStartKey:         map[string]*types.Item{"trainer": {S: new("misty")}, "pokemon": {S: new("psyduck")}},
+		ScanIndexForward:          true,
+	})
+	c.NoError(err)
+	c.Empty(items)
+}
+
+func TestSearchDataIndexPartition(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	input := QueryInput{
+		Index:                     "by-type",
+		KeyConditionExpression:    "#type = :type",
+		Aliases:                   map[string]string{"#type": "type"},
+		ExpressionAttributeValues: map[string]*types.Item{":type": {S: new("water")}},
+		ScanIndexForward:          true,
+		Limit:                     2,
+	}
+
+	items, last, err := table.SearchData(input)
+	c.NoError(err)
+	c.Equal([]string{"psyduck", "squirtle"}, pokemonNames(items))
+	c.Equal("squirtle", *last["pokemon"].S)
+	c.Equal("water", *last["type"].S)
+	c.Equal("ash", *last["trainer"].S)
+
+	input.ExclusiveStartKey = last
+
+	items, last, err = table.SearchData(input)
+	c.NoError(err)
+	c.Equal([]string{"starmie", "staryu"}, pokemonNames(items))
+	c.NotEmpty(last)
+
+	input.ExclusiveStartKey = last
+
+	items, last, err = table.SearchData(input)
+	c.NoError(err)
+	c.Empty(items)
+	c.Empty(last)
+
+	_, _, err = table.SearchData(QueryInput{Index: "missing", Scan: true})
+	c.EqualError(err, "ValidationException: The table does not have the specified index: missing")
+}
+
+func TestSearchDataScanWalksEveryPartition(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	items, _, err := table.SearchData(QueryInput{Scan: true, ScanIndexForward: true})
+	c.NoError(err)
+	c.Equal([]string{
+		"bulbasaur", "charizard", "pikachu", "squirtle",
+		"geodude", "onix",
+		"psyduck", "starmie", "staryu",
+	}, pokemonNames(items))
+
+	input := QueryInput{Scan: true, ScanIndexForward: true, Limit: 4}
+
+	items, last, err := table.SearchData(input)
+	c.NoError(err)
+	c.Len(items, 4)
+
+	input.ExclusiveStartKey = last
+
+	items, _, err = table.SearchData(input)
+	c.NoError(err)
+	c.Equal([]string{"geodude", "onix", "psyduck", "starmie"}, pokemonNames(items))
+}
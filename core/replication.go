@@ -0,0 +1,230 @@
+package core
+
+import (
+	"maps"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+// GlobalTableVersion is the version of global tables replicas follow
+const GlobalTableVersion = "2019.11.21"
+
+// ReplicaMetadata is the replication state of an item of a global table, the one the
+// 2017.11.29 version of global tables kept in the aws:rep:updateregion, aws:rep:updatetime
+// and aws:rep:deleting attributes
+type ReplicaMetadata struct {
+	UpdateRegion string
+	UpdateTime   time.Time
+	Deleting     bool
+}
+
+// ReplicaChange is a write made to a replica that the other replicas of the global table
+// have to apply, a nil Item means the item was deleted
+type ReplicaChange struct {
+	Keys     map[string]*types.Item
+	Item     map[string]*types.Item
+	Metadata ReplicaMetadata
+}
+
+// replication keeps the replication state of the items of a replica and the writes made
+// in its region that were not taken yet
+type replication struct {
+	region   string
+	items    map[string]ReplicaMetadata
+	pending  []ReplicaChange
+	applying bool
+}
+
+// replicationSnapshot is the replication state of a table at snapshot time
+type replicationSnapshot struct {
+	items   map[string]ReplicaMetadata
+	pending int
+}
+
+// newerThan resolves the conflicts between replicas, the last writer wins and the
+// writes made at the same time are ordered by region
+func (m ReplicaMetadata) newerThan(other ReplicaMetadata) bool {
+	if !m.UpdateTime.Equal(other.UpdateTime) {
+		return m.UpdateTime.After(other.UpdateTime)
+	}
+
+	return m.UpdateRegion > other.UpdateRegion
+}
+
+// EnableReplication makes the table the replica of a global table in the given region,
+// the writes made from then on are kept until TakeReplicaChanges
+func (t *Table) EnableReplication(region string) {
+	if t.replication != nil {
+		return
+	}
+
+	t.replication = &replication{region: region, items: map[string]ReplicaMetadata{}}
+}
+
+// ReplicaRegion returns the region of the replica, it is empty when the table is not
+// part of a global table
+func (t *Table) ReplicaRegion() string {
+	if t.replication == nil {
+		return ""
+	}
+
+	return t.replication.region
+}
+
+// CreateReplica fills replica, which must be a new table, with the schema, indexes,
+// settings, stream and items of the table and makes it the replica in the given region.
+// The table must already be a replica
+func (t *Table) CreateReplica(replica *Table, region string) error {
+	if err := t.Backup(t.Name, t.now()).Restore(replica, RestoreInput{}); err != nil {
+		return err
+	}
+
+	replica.settings = tableSettings{
+		provisionedThroughput: t.settings.provisionedThroughput,
+		onDemandThroughput:    t.settings.onDemandThroughput,
+		warmThroughput:        t.settings.warmThroughput,
+		tableClass:            t.settings.tableClass,
+		sse:                   t.settings.sse,
+	}
+	replica.setTimeToLiveAttribute(t.ttlAttribute)
+
+	if stream := t.LatestStream(); stream != nil && stream.Enabled {
+		if _, err := replica.EnableStream(stream.ViewType, t.now()); err != nil {
+			return err
+		}
+	}
+
+	replica.replication = &replication{region: region, items: maps.Clone(t.replication.items)}
+
+	return nil
+}
+
+// TakeReplicaChanges returns the writes made in the region of the replica since the last
+// call, in the order they were made
+func (t *Table) TakeReplicaChanges() []ReplicaChange {
+	if t.replication == nil {
+		return nil
+	}
+
+	changes := t.replication.pending
+	t.replication.pending = nil
+
+	return changes
+}
+
+// ApplyReplicaChange applies a write made in another replica unless the item was written
+// later, it reports whether the change was applied. Applied changes are recorded in the
+// streams of the table but they are not replicated again
+func (t *Table) ApplyReplicaChange(change ReplicaChange) bool {
+	if t.replication == nil {
+		return false
+	}
+
+	key, err := t.KeySchema.GetKey(t.AttributesDef, change.Keys)
+	if err != nil {
+		return false
+	}
+
+	if current, ok := t.replication.items[key]; ok && !change.Metadata.newerThan(current) {
+		return false
+	}
+
+	oldItem, existed := t.Data[key]
+	if !existed {
+		oldItem = nil
+	}
+
+	newItem := deepCopyItemMap(change.Item)
+
+	if newItem == nil && existed {
+		t.removeItem(key, oldItem)
+
+		for _, index := range t.Indexes {
+			// the item was indexed with the same key so it can always be removed
+			_ = index.delete(key, oldItem)
+		}
+	}
+
+	if newItem != nil {
+		t.setItem(key, newItem)
+
+		for _, index := range t.Indexes {
+			// replicas share the attribute definitions so the item fits their indexes
+			_ = index.putData(key, newItem)
+		}
+	}
+
+	t.replication.applying = true
+	t.recordChange(oldItem, newItem)
+	t.replication.applying = false
+
+	t.replication.items[key] = change.Metadata
+
+	return true
+}
+
+// ReplicaMetadata returns the replication state of the item with the given key, deleted
+// items keep it so older writes of other replicas do not bring them back
+func (t *Table) ReplicaMetadata(key map[string]*types.Item) (ReplicaMetadata, bool) {
+	if t.replication == nil {
+		return ReplicaMetadata{}, false
+	}
+
+	pk, err := t.KeySchema.GetKey(t.AttributesDef, key)
+	if err != nil {
+		return ReplicaMetadata{}, false
+	}
+
+	metadata, ok := t.replication.items[pk]
+
+	return metadata, ok
+}
+
+// recordReplication stamps a write made in the region of the replica and keeps it for the
+// other replicas
+func (t *Table) recordReplication(oldItem, newItem map[string]*types.Item) {
+	if t.replication == nil || t.replication.applying {
+		return
+	}
+
+	source := newItem
+	if source == nil {
+		source = oldItem
+	}
+
+	key, err := t.KeySchema.GetKey(t.AttributesDef, source)
+	if err != nil {
+		return
+	}
+
+	metadata := ReplicaMetadata{UpdateRegion: t.replication.region, UpdateTime: t.now(), Deleting: newItem == nil}
+	t.replication.items[key] = metadata
+
+	t.replication.pending = append(t.replication.pending, ReplicaChange{
+		Keys:     deepCopyItemMap(t.KeySchema.getKeyItem(source)),
+		Item:     deepCopyItemMap(newItem),
+		Metadata: metadata,
+	})
+}
+
+func (t *Table) snapshotReplication() replicationSnapshot {
+	if t.replication == nil {
+		return replicationSnapshot{}
+	}
+
+	return replicationSnapshot{items: maps.Clone(t.replication.items), pending: len(t.replication.pending)}
+}
+
+// restoreReplication discards the writes made after the snapshot was taken
+func (t *Table) restoreReplication(snap replicationSnapshot) {
+	if t.replication == nil || snap.items == nil {
+		return
+	}
+
+	t.replication.items = snap.items
+
+	if snap.pending < len(t.replication.pending) {
+		t.replication.pending = t.replication.pending[:snap.pending]
+	}
+}
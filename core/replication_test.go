@@ -0,0 +1,94 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestReplication(t *testing.T) {
+	c := require.New(t)
+
+	clock := NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	table := createTrainerTable(c)
+	table.Clock = clock
+
+	c.Empty(table.ReplicaRegion())
+	table.EnableReplication("us-east-1")
+	c.Equal("us-east-1", table.ReplicaRegion())
+
+	replica := NewTable("trainers")
+	replica.Clock = clock
+	c.NoError(table.CreateReplica(replica, "eu-west-1"))
+	c.Equal("eu-west-1", replica.ReplicaRegion())
+	c.Len(replica.Data, len(table.Data))
+	c.Contains(replica.Indexes, "by-type")
+
+	key := map[string]*types.Item{"trainer": {S: new("ash")}, "pokemon": {S: new("pikachu")}}
+
+	_, err := table.Put(&types.PutItemInput{Item: map[string]*types.Item{"trainer": {S: new("ash")}, "pokemon": {S: new("pikachu")}, "type": {S: new("steel")}}})
+	c.NoError(err)
+
+	changes := table.TakeReplicaChanges()
+	c.Len(changes, 1)
+	c.Equal(key, changes[0].Keys)
+	c.Equal(ReplicaMetadata{UpdateRegion: "us-east-1", UpdateTime: clock.Now()}, changes[0].Metadata)
+	c.Empty(table.TakeReplicaChanges())
+
+	// a write made later in the replica wins over the one coming from the table
+	clock.Advance(time.Second)
+
+	_, err = replica.Put(&types.PutItemInput{Item: map[string]*types.Item{"trainer": {S: new("ash")}, "pokemon": {S: new("pikachu")}, "type": {S: new("thunder")}}})
+	c.NoError(err)
+
+	c.False(replica.ApplyReplicaChange(changes[0]))
+	c.Equal("thunder", types.StringValue(replica.Data[mustKey(c, replica, key)]["type"].S))
+
+	c.True(table.ApplyReplicaChange(replica.TakeReplicaChanges()[0]))
+	c.Empty(table.TakeReplicaChanges())
+
+	metadata, ok := table.ReplicaMetadata(key)
+	c.True(ok)
+	c.Equal("eu-west-1", metadata.UpdateRegion)
+
+	items, _, err := table.SearchData(QueryInput{
+		Index:                     "by-type",
+		KeyConditionExpression:    "#t = :t",
+		Aliases:                   map[string]string{"#t": "type"},
+		ExpressionAttributeValues: map[string]*types.Item{":t": {S: new("thunder")}},
+	})
+	c.NoError(err)
+	c.Len(items, 1)
+
+	// deletions keep their metadata so an older write does not bring the item back
+	clock.Advance(time.Second)
+
+	_, err = replica.Delete(&types.DeleteItemInput{Key: key})
+	c.NoError(err)
+
+	deletion := replica.TakeReplicaChanges()[0]
+	c.True(deletion.Metadata.Deleting)
+	c.True(table.ApplyReplicaChange(deletion))
+	c.NotContains(table.Data, mustKey(c, table, key))
+
+	c.False(table.ApplyReplicaChange(changes[0]))
+	c.NotContains(table.Data, mustKey(c, table, key))
+
+	// rolled back writes are not replicated
+	snap := table.Snapshot()
+
+	_, err = table.Put(&types.PutItemInput{Item: map[string]*types.Item{"trainer": {S: new("gary")}, "pokemon": {S: new("eevee")}, "type": {S: new("normal")}}})
+	c.NoError(err)
+
+	table.Restore(snap)
+	c.Empty(table.TakeReplicaChanges())
+}
+
+func mustKey(c *require.Assertions, table *Table, key map[string]*types.Item) string {
+	pk, err := table.KeySchema.GetKey(table.AttributesDef, key)
+	c.NoError(err)
+
+	return pk
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// ClientRequestTokenTTL is how long a ClientRequestToken keeps a request idempotent by default
+	ClientRequestTokenTTL = 10 * time.Minute
+
+	maxClientRequestTokenLength = 36
+)
+
+// RequestTokens remembers the output of the requests made with a ClientRequestToken, so
+// retries of the same request are answered without running it again
+type RequestTokens[T any] struct {
+	entries map[string]requestToken[T]
+	ttl     time.Duration
+}
+
+type requestToken[T any] struct {
+	fingerprint string
+	output      T
+	expires     time.Time
+}
+
+// NewRequestTokens returns an empty RequestTokens
+func NewRequestTokens[T any]() *RequestTokens[T] {
+	return &RequestTokens[T]{entries: map[string]requestToken[T]{}, ttl: ClientRequestTokenTTL}
+}
+
+// SetTTL sets how long the tokens stored from now on keep their requests idempotent
+func (r *RequestTokens[T]) SetTTL(ttl time.Duration) {
+	r.ttl = ttl
+}
+
+// Lookup returns the output stored for token, a token reused with a request whose fingerprint
+// differs from the first one fails with an IdempotentParameterMismatchException
+func (r *RequestTokens[T]) Lookup(token, fingerprint string, now time.Time) (T, bool, error) {
+	var zero T
+
+	entry, ok := r.entries[token]
+	if !ok {
+		return zero, false, nil
+	}
+
+	if !now.Before(entry.expires) {
+		delete(r.entries, token)
+
+		return zero, false, nil
+	}
+
+	if entry.fingerprint != fingerprint {
+		return zero, false, types.NewError("IdempotentParameterMismatchException", "The request uses the same client token as a previous, but non-identical request.", nil)
+	}
+
+	return entry.output, true, nil
+}
+
+// Store remembers the output of the request made with token until the TTL elapses
+func (r *RequestTokens[T]) Store(token, fingerprint string, output T, now time.Time) {
+	for t, entry := range r.entries {
+		if !now.Before(entry.expires) {
+			delete(r.entries, t)
+		}
+	}
+
+	r.entries[token] = requestToken[T]{fingerprint: fingerprint, output: output, expires: now.Add(r.ttl)}
+}
+
+// ValidateClientRequestToken checks the length constraint of a ClientRequestToken
+func ValidateClientRequestToken(token string) error {
+	var constraint string
+
+	switch {
+	case token == "":
+		constraint = "Member must have length greater than or equal to 1"
+	case len(token) > maxClientRequestTokenLength:
+		constraint = fmt.Sprintf("Member must have length less than or equal to %d", maxClientRequestTokenLength)
+	default:
+		return nil
+	}
+
+	return types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%s' at 'clientRequestToken' failed to satisfy constraint: %s", token, constraint), nil)
+}
+
+// RequestFingerprint summarizes a request so retries using the same token can be told apart
+// from different requests reusing it
+func RequestFingerprint(request any) string {
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(raw)
+
+	return hex.EncodeToString(sum[:])
+}
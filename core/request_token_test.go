@@ -0,0 +1,66 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestRequestTokens(t *testing.T) {
+	c := require.New(t)
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tokens := NewRequestTokens[string]()
+
+	_, ok, err := tokens.Lookup("token", "a", now)
+	c.NoError(err)
+	c.False(ok)
+
+	tokens.Store("token", "a", "first", now)
+
+	out, ok, err := tokens.Lookup("token", "a", now.Add(time.Minute))
+	c.NoError(err)
+	c.True(ok)
+	c.Equal("first", out)
+
+	_, _, err = tokens.Lookup("token", "b", now.Add(time.Minute))
+	c.EqualError(err, "IdempotentParameterMismatchException: The request uses the same client token as a previous, but non-identical request.")
+
+	_, ok, err = tokens.Lookup("token", "b", now.Add(ClientRequestTokenTTL))
+	c.NoError(err)
+	c.False(ok)
+
+	tokens.SetTTL(time.Hour)
+	tokens.Store("token", "b", "second", now)
+
+	out, ok, err = tokens.Lookup("token", "b", now.Add(time.Hour-time.Second))
+	c.NoError(err)
+	c.True(ok)
+	c.Equal("second", out)
+}
+
+func TestValidateClientRequestToken(t *testing.T) {
+	c := require.New(t)
+
+	c.NoError(ValidateClientRequestToken("token"))
+
+	err := ValidateClientRequestToken("")
+	c.ErrorContains(err, "Member must have length greater than or equal to 1")
+
+	err = ValidateClientRequestToken(strings.Repeat("a", 37))
+	c.ErrorContains(err, "Member must have length less than or equal to 36")
+
+	_, ok := err.(types.Error)
+	c.True(ok)
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	c := require.New(t)
+
+	a := RequestFingerprint([]*types.Item{{S: new("1")}})
+	c.Equal(a, RequestFingerprint([]*types.Item{{S: new("1")}}))
+	c.NotEqual(a, RequestFingerprint([]*types.Item{{N: new("1")}}))
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"fmt"
+	"hash/crc32"
+
+	"github.com/truora/minidyn/types"
+)
+
+const maxTotalSegments = 1000000
+
+// scanSegment is the part of the key space a parallel scan reads. Like DynamoDB, the range
+// of partition key hashes is split in TotalSegments contiguous slices, so every partition
+// belongs to exactly one segment.
+type scanSegment struct {
+	segment int64
+	total   int64
+}
+
+// newScanSegment validates the Segment and TotalSegments parameters of a scan, it returns nil
+// when the scan is not parallel
+func newScanSegment(segment, total *int64) (*scanSegment, error) {
+	switch {
+	case segment == nil && total == nil:
+		return nil, nil
+	case total == nil:
+		return nil, types.NewError("ValidationException", "The TotalSegments parameter is required but was not present in the request when Segment parameter is present", nil)
+	case segment == nil:
+		return nil, types.NewError("ValidationException", "The Segment parameter is required but was not present in the request when parameter TotalSegments is present", nil)
+	case *segment < 0:
+		return nil, types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%d' at 'segment' failed to satisfy constraint: Member must have value greater than or equal to 0", *segment), nil)
+	case *total < 1:
+		return nil, types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%d' at 'totalSegments' failed to satisfy constraint: Member must have value greater than or equal to 1", *total), nil)
+	case *total > maxTotalSegments:
+		return nil, types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%d' at 'totalSegments' failed to satisfy constraint: Member must have value less than or equal to %d", *total, maxTotalSegments), nil)
+	case *segment >= *total:
+		return nil, types.NewError("ValidationException", fmt.Sprintf("The Segment parameter is zero-based and must be less than parameter TotalSegments: Segment: %d is not less than TotalSegments: %d", *segment, *total), nil)
+	}
+
+	return &scanSegment{segment: *segment, total: *total}, nil
+}
+
+// contains reports whether the partition with the given encoded hash key belongs to the
+// segment, a nil segment holds every partition
+func (s *scanSegment) contains(hashKey string) bool {
+	if s == nil {
+		return true
+	}
+
+	hash := uint64(crc32.ChecksumIEEE([]byte(hashKey)))
+
+	return int64(hash*uint64(s.total)>>32) == s.segment
+}
+
+// validateStart rejects an exclusive start key outside of the segment
+func (s *scanSegment) validateStart(after *partitionCursor) error {
+	if after == nil || s.contains(after.hashKey) {
+		return nil
+	}
+
+	return types.NewError("ValidationException", fmt.Sprintf("The provided Exclusive start key does not map to the provided Segment and TotalSegments values. Segment: %d TotalSegments: %d", s.segment, s.total), nil)
+}
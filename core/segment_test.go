@@ -0,0 +1,118 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func scanSegments(c *require.Assertions, table *Table, input QueryInput, total int64) [][]string {
+	segments := make([][]string, 0, total)
+
+	for segment := range total {
+		input.Segment, input.TotalSegments = new(segment), new(total)
+		input.ExclusiveStartKey = nil
+
+		names := []string{}
+
+		for {
+			items, last, err := table.SearchData(input)
+			c.NoError(err)
+
+			names = append(names, pokemonNames(items)...)
+
+			if len(last) == 0 {
+				break
+			}
+
+			input.ExclusiveStartKey = last
+		}
+
+		segments = append(segments, names)
+	}
+
+	return segments
+}
+
+func TestParallelScan(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	for i := range 40 {
+		_, err := table.Put(&types.PutItemInput{Item: map[string]*types.Item{
+			"trainer": {S: new(fmt.Sprintf("trainer %d", i))},
+			"pokemon": {S: new(fmt.Sprintf("pokemon %d", i))},
+			"type":    {S: new(fmt.Sprintf("type %d", i%7))},
+		}})
+		c.NoError(err)
+	}
+
+	for _, index := range []string{"", "by-type"} {
+		full, _, err := table.SearchData(QueryInput{Index: index, Scan: true, ScanIndexForward: true})
+		c.NoError(err)
+
+		for _, total := range []int64{1, 2, 3, 8} {
+			segments := scanSegments(c, table, QueryInput{Index: index, Scan: true, ScanIndexForward: true, Limit: 2}, total)
+
+			union := []string{}
+			for _, names := range segments {
+				union = append(union, names...)
+			}
+
+			// every item is read by exactly one segment
+			c.ElementsMatch(pokemonNames(full), union, "index %q with %d segments", index, total)
+		}
+	}
+
+	// the items of a partition are read by the same segment
+	segments := scanSegments(c, table, QueryInput{Scan: true, ScanIndexForward: true}, 4)
+	for _, names := range segments {
+		if slices.Contains(names, "pikachu") {
+			c.Subset(names, []string{"bulbasaur", "charizard", "squirtle"})
+		}
+	}
+
+	// the segment of a partition does not change between scans
+	c.Equal(segments, scanSegments(c, table, QueryInput{Scan: true, ScanIndexForward: true}, 4))
+
+	// the start key of a segment must lie inside of it
+	first, last, err := table.SearchData(QueryInput{Scan: true, ScanIndexForward: true, Limit: 1, Segment: new(int64(0)), TotalSegments: new(int64(2))})
+	c.NoError(err)
+	c.Len(first, 1)
+
+	_, _, err = table.SearchData(QueryInput{Scan: true, ScanIndexForward: true, ExclusiveStartKey: last, Segment: new(int64(1)), TotalSegments: new(int64(2))})
+	c.ErrorContains(err, "The provided Exclusive start key does not map to the provided Segment and TotalSegments values")
+}
+
+func TestParallelScanValidation(t *testing.T) {
+	table := createTrainerTable(require.New(t))
+
+	cases := []struct {
+		name    string
+		segment *int64
+		total   *int64
+		err     string
+	}{
+		{"missing total", new(int64(0)), nil, "The TotalSegments parameter is required but was not present in the request when Segment parameter is present"},
+		{"missing segment", nil, new(int64(2)), "The Segment parameter is required but was not present in the request when parameter TotalSegments is present"},
+		{"negative segment", new(int64(-1)), new(int64(2)), "Value '-1' at 'segment' failed to satisfy constraint: Member must have value greater than or equal to 0"},
+		{"no segments", new(int64(0)), new(int64(0)), "Value '0' at 'totalSegments' failed to satisfy constraint: Member must have value greater than or equal to 1"},
+		{"too many segments", new(int64(0)), new(int64(1000001)), "Value '1000001' at 'totalSegments' failed to satisfy constraint: Member must have value less than or equal to 1000000"},
+		{"segment out of range", new(int64(2)), new(int64(2)), "The Segment parameter is zero-based and must be less than parameter TotalSegments: Segment: 2 is not less than TotalSegments: 2"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := table.SearchData(QueryInput{Scan: true, Segment: tc.segment, TotalSegments: tc.total})
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	items, _, err := table.SearchData(QueryInput{Scan: true, Segment: new(int64(999999)), TotalSegments: new(int64(1000000))})
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(items), 1)
+}
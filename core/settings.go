@@ -0,0 +1,357 @@
+package core
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// BillingModeProvisioned bills the read and write capacity provisioned for the table
+	BillingModeProvisioned = "PROVISIONED"
+	// BillingModePayPerRequest bills every request made to the table
+	BillingModePayPerRequest = "PAY_PER_REQUEST"
+	// TableClassStandard is the table class of the tables that do not set one
+	TableClassStandard = "STANDARD"
+	// TableClassStandardInfrequentAccess is the table class for data that is rarely read
+	TableClassStandardInfrequentAccess = "STANDARD_INFREQUENT_ACCESS"
+)
+
+const (
+	maxPayPerRequestUpdates   = 4
+	payPerRequestUpdateWindow = 24 * time.Hour
+	maxTableClassUpdates      = 2
+	tableClassUpdateWindow    = 30 * 24 * time.Hour
+)
+
+// Throughput holds read and write units, a zero value means the units were not given
+type Throughput struct {
+	ReadUnits  int64
+	WriteUnits int64
+}
+
+// SSE is the server-side encryption of a table, tables without it enabled use an AWS owned key
+type SSE struct {
+	Enabled bool
+	// KMSMasterKeyArn is the ARN of the KMS key encrypting the table
+	KMSMasterKeyArn string
+}
+
+// TableSettings holds the settings CreateTable and UpdateTable take besides the key schema,
+// the indexes and the stream, nil fields are left unchanged
+type TableSettings struct {
+	BillingMode               *string
+	ProvisionedThroughput     *types.ProvisionedThroughput
+	OnDemandThroughput        *Throughput
+	WarmThroughput            *Throughput
+	DeletionProtectionEnabled *bool
+	TableClass                *string
+	SSE                       *SSE
+}
+
+// tableSettings keeps the settings of a table along with the updates limited by DynamoDB
+type tableSettings struct {
+	provisionedThroughput types.ProvisionedThroughput
+	onDemandThroughput    *Throughput
+	warmThroughput        *Throughput
+	deletionProtection    bool
+	tableClass            string
+	sse                   *SSE
+	payPerRequestUpdates  []time.Time
+	tableClassUpdates     []time.Time
+}
+
+// SettingsSnapshot is a copy of the settings, the attribute definitions and the indexes of a
+// table, it lets UpdateTable undo the changes it applied when a later part of the request fails
+type SettingsSnapshot struct {
+	billingMode   *string
+	attributesDef map[string]string
+	settings      tableSettings
+	indexes       map[string]*index
+	insights      map[string]*contributorInsights
+}
+
+// SnapshotSettings returns a copy of the settings, the attribute definitions and the
+// indexes of the table. Index changes replace or remove whole indexes, so the indexes
+// themselves are shared with the table
+func (t *Table) SnapshotSettings() SettingsSnapshot {
+	settings := t.settings
+	settings.payPerRequestUpdates = slices.Clone(settings.payPerRequestUpdates)
+	settings.tableClassUpdates = slices.Clone(settings.tableClassUpdates)
+
+	return SettingsSnapshot{
+		billingMode:   t.BillingMode,
+		attributesDef: maps.Clone(t.AttributesDef),
+		settings:      settings,
+		indexes:       maps.Clone(t.Indexes),
+		insights:      maps.Clone(t.insights),
+	}
+}
+
+// RestoreSettings replaces the settings, the attribute definitions and the indexes of the
+// table with a previously taken snapshot
+func (t *Table) RestoreSettings(s SettingsSnapshot) {
+	t.BillingMode = s.billingMode
+	t.AttributesDef = s.attributesDef
+	t.settings = s.settings
+	t.Indexes = s.indexes
+	t.insights = s.insights
+}
+
+// SettingsChanged reports whether the settings of the table differ from the snapshot
+func (t *Table) SettingsChanged(s SettingsSnapshot) bool {
+	current, previous := t.settings, s.settings
+
+	return t.billingMode() != cmp.Or(types.StringValue(s.billingMode), BillingModeProvisioned) ||
+		current.provisionedThroughput != previous.provisionedThroughput ||
+		!equalValues(current.onDemandThroughput, previous.onDemandThroughput) ||
+		!equalValues(current.warmThroughput, previous.warmThroughput) ||
+		current.deletionProtection != previous.deletionProtection ||
+		t.tableClass() != cmp.Or(previous.tableClass, TableClassStandard) ||
+		!equalValues(current.sse, previous.sse)
+}
+
+// SetSettings applies the settings of a new table, the billing mode must be set before
+// creating the primary index so it is usually already set
+func (t *Table) SetSettings(settings TableSettings) error {
+	if err := t.validateSettings(settings); err != nil {
+		return err
+	}
+
+	t.applySettings(settings)
+
+	return nil
+}
+
+// UpdateSettings applies the settings changed by UpdateTable. Like DynamoDB, tables can
+// switch to on-demand 4 times in 24 hours and change their table class twice in 30 days
+func (t *Table) UpdateSettings(settings TableSettings) error {
+	if err := t.validateSettings(settings); err != nil {
+		return err
+	}
+
+	now := t.now()
+
+	toPayPerRequest := types.StringValue(settings.BillingMode) == BillingModePayPerRequest && t.billingMode() != BillingModePayPerRequest
+	payPerRequestUpdates := recentUpdates(t.settings.payPerRequestUpdates, now, payPerRequestUpdateWindow)
+
+	if toPayPerRequest && len(payPerRequestUpdates) >= maxPayPerRequestUpdates {
+		return types.NewError("LimitExceededException", fmt.Sprintf("Subscriber limit exceeded: Update to PayPerRequest mode are limited to %d times in 1 day(s).", maxPayPerRequestUpdates), nil)
+	}
+
+	changesClass := settings.TableClass != nil && *settings.TableClass != t.tableClass()
+	tableClassUpdates := recentUpdates(t.settings.tableClassUpdates, now, tableClassUpdateWindow)
+
+	if changesClass && len(tableClassUpdates) >= maxTableClassUpdates {
+		return types.NewError("LimitExceededException", fmt.Sprintf("Subscriber limit exceeded: Update to TableClass are limited to %d times in 30 day(s).", maxTableClassUpdates), nil)
+	}
+
+	if current, requested := t.settings.provisionedThroughput, settings.ProvisionedThroughput; requested != nil && settings.BillingMode == nil && t.billingMode() == BillingModeProvisioned &&
+		current.ReadCapacityUnits == requested.ReadCapacityUnits && current.WriteCapacityUnits == requested.WriteCapacityUnits {
+		return types.NewError("ValidationException", fmt.Sprintf(
+			"The provisioned throughput for the table will not change. The requested value equals the current value. Current ReadCapacityUnits provisioned for the table: %d. Requested ReadCapacityUnits: %d. Current WriteCapacityUnits provisioned for the table: %d. Requested WriteCapacityUnits: %d. Refer to the Amazon DynamoDB Developer Guide for current limits and how to request higher limits.",
+			current.ReadCapacityUnits, requested.ReadCapacityUnits, current.WriteCapacityUnits, requested.WriteCapacityUnits,
+		), nil)
+	}
+
+	t.applySettings(settings)
+
+	if toPayPerRequest {
+		t.settings.payPerRequestUpdates = append(payPerRequestUpdates, now)
+	}
+
+	if changesClass {
+		t.settings.tableClassUpdates = append(tableClassUpdates, now)
+	}
+
+	return nil
+}
+
+// ValidateDeletion fails while the deletion protection of the table is enabled
+func (t *Table) ValidateDeletion() error {
+	if t.settings.deletionProtection {
+		return types.NewError("ValidationException", "Resource cannot be deleted as it is currently protected against deletion. Disable deletion protection first.", nil)
+	}
+
+	return nil
+}
+
+func (t *Table) validateSettings(settings TableSettings) error {
+	billingMode := t.billingMode()
+
+	if settings.BillingMode != nil {
+		billingMode = *settings.BillingMode
+		if billingMode != BillingModeProvisioned && billingMode != BillingModePayPerRequest {
+			return enumError(billingMode, "billingMode", BillingModeProvisioned, BillingModePayPerRequest)
+		}
+	}
+
+	if throughput := settings.ProvisionedThroughput; throughput != nil {
+		if throughput.ReadCapacityUnits < 1 {
+			return minValueError(throughput.ReadCapacityUnits, "provisionedThroughput.readCapacityUnits")
+		}
+
+		if throughput.WriteCapacityUnits < 1 {
+			return minValueError(throughput.WriteCapacityUnits, "provisionedThroughput.writeCapacityUnits")
+		}
+	} else if billingMode == BillingModeProvisioned && t.billingMode() == BillingModePayPerRequest {
+		return types.NewError("ValidationException", "One or more parameter values were invalid: ProvisionedThroughput must be specified when BillingMode is PROVISIONED", nil)
+	}
+
+	if settings.TableClass != nil && *settings.TableClass != TableClassStandard && *settings.TableClass != TableClassStandardInfrequentAccess {
+		return enumError(*settings.TableClass, "tableClass", TableClassStandardInfrequentAccess, TableClassStandard)
+	}
+
+	return nil
+}
+
+func (t *Table) applySettings(settings TableSettings) {
+	if settings.BillingMode != nil {
+		t.BillingMode = settings.BillingMode
+
+		if *settings.BillingMode == BillingModePayPerRequest {
+			t.settings.provisionedThroughput = types.ProvisionedThroughput{}
+		}
+	}
+
+	// like DynamoDB Local, the throughput given to on-demand tables is ignored
+	if settings.ProvisionedThroughput != nil && t.billingMode() == BillingModeProvisioned {
+		t.settings.provisionedThroughput = *settings.ProvisionedThroughput
+	}
+
+	if settings.OnDemandThroughput != nil {
+		t.settings.onDemandThroughput = mergeThroughput(t.settings.onDemandThroughput, *settings.OnDemandThroughput)
+	}
+
+	if settings.WarmThroughput != nil {
+		t.settings.warmThroughput = mergeThroughput(t.settings.warmThroughput, *settings.WarmThroughput)
+	}
+
+	if settings.DeletionProtectionEnabled != nil {
+		t.settings.deletionProtection = *settings.DeletionProtectionEnabled
+	}
+
+	if settings.TableClass != nil {
+		t.settings.tableClass = *settings.TableClass
+	}
+
+	if settings.SSE != nil {
+		t.settings.sse = settings.SSE
+	}
+}
+
+// describeSettings adds the settings of the table to its description
+func (t *Table) describeSettings(desc *types.TableDescription) {
+	desc.DeletionProtectionEnabled = t.settings.deletionProtection
+	desc.ProvisionedThroughput = &types.ProvisionedThroughputDescription{
+		ReadCapacityUnits:  t.settings.provisionedThroughput.ReadCapacityUnits,
+		WriteCapacityUnits: t.settings.provisionedThroughput.WriteCapacityUnits,
+	}
+
+	if billingMode := types.StringValue(t.BillingMode); billingMode != "" {
+		desc.BillingModeSummary = &types.BillingModeSummary{
+			BillingMode:                       billingMode,
+			LastUpdateToPayPerRequestDateTime: lastUpdate(t.settings.payPerRequestUpdates),
+		}
+	}
+
+	if t.settings.tableClass != "" {
+		desc.TableClassSummary = &types.TableClassSummary{
+			TableClass:         t.settings.tableClass,
+			LastUpdateDateTime: lastUpdate(t.settings.tableClassUpdates),
+		}
+	}
+
+	if throughput := t.settings.onDemandThroughput; throughput != nil {
+		desc.OnDemandThroughput = &types.OnDemandThroughput{
+			MaxReadRequestUnits:  throughput.ReadUnits,
+			MaxWriteRequestUnits: throughput.WriteUnits,
+		}
+	}
+
+	if throughput := t.settings.warmThroughput; throughput != nil {
+		desc.WarmThroughput = &types.WarmThroughputDescription{
+			ReadUnitsPerSecond:  throughput.ReadUnits,
+			WriteUnitsPerSecond: throughput.WriteUnits,
+			Status:              TableStatusActive,
+		}
+	}
+
+	if sse := t.settings.sse; sse != nil && sse.Enabled {
+		desc.SSEDescription = &types.SSEDescription{
+			KMSMasterKeyArn: sse.KMSMasterKeyArn,
+			SSEType:         "KMS",
+			Status:          "ENABLED",
+		}
+	}
+}
+
+// billingMode returns the billing mode of the table, tables that do not set one are
+// provisioned
+func (t *Table) billingMode() string {
+	if billingMode := types.StringValue(t.BillingMode); billingMode != "" {
+		return billingMode
+	}
+
+	return BillingModeProvisioned
+}
+
+func (t *Table) tableClass() string {
+	if t.settings.tableClass != "" {
+		return t.settings.tableClass
+	}
+
+	return TableClassStandard
+}
+
+// mergeThroughput returns current with the units given in update
+func mergeThroughput(current *Throughput, update Throughput) *Throughput {
+	merged := Throughput{}
+	if current != nil {
+		merged = *current
+	}
+
+	if update.ReadUnits != 0 {
+		merged.ReadUnits = update.ReadUnits
+	}
+
+	if update.WriteUnits != 0 {
+		merged.WriteUnits = update.WriteUnits
+	}
+
+	return &merged
+}
+
+// recentUpdates returns the updates made within the window before now
+func recentUpdates(updates []time.Time, now time.Time, window time.Duration) []time.Time {
+	return slices.DeleteFunc(slices.Clone(updates), func(update time.Time) bool {
+		return now.Sub(update) >= window
+	})
+}
+
+func equalValues[T comparable](a, b *T) bool {
+	return a == b || a != nil && b != nil && *a == *b
+}
+
+func lastUpdate(updates []time.Time) *time.Time {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	last := updates[len(updates)-1]
+
+	return &last
+}
+
+func enumError(value, field string, values ...string) error {
+	return types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%s' at '%s' failed to satisfy constraint: Member must satisfy enum value set: [%s]", value, field, strings.Join(values, ", ")), nil)
+}
+
+func minValueError(value int64, field string) error {
+	return types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%d' at '%s' failed to satisfy constraint: Member must have value greater than or equal to 1", value, field), nil)
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestTableSettings(t *testing.T) {
+	c := require.New(t)
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	clock := NewManualClock(now)
+
+	table := NewTable("pokemons")
+	table.Clock = clock
+
+	c.NoError(table.SetSettings(TableSettings{
+		ProvisionedThroughput:     &types.ProvisionedThroughput{ReadCapacityUnits: 5, WriteCapacityUnits: 2},
+		DeletionProtectionEnabled: new(true),
+		SSE:                       &SSE{Enabled: true, KMSMasterKeyArn: "arn:aws:kms:us-east-1:000000000000:key/pokedex"},
+	}))
+
+	desc := table.Description("pokemons")
+	c.True(desc.DeletionProtectionEnabled)
+	c.EqualValues(5, desc.ProvisionedThroughput.ReadCapacityUnits)
+	c.Nil(desc.BillingModeSummary)
+	c.Nil(desc.TableClassSummary)
+	c.Equal(&types.SSEDescription{KMSMasterKeyArn: "arn:aws:kms:us-east-1:000000000000:key/pokedex", SSEType: "KMS", Status: "ENABLED"}, desc.SSEDescription)
+
+	c.ErrorContains(table.ValidateDeletion(), "protected against deletion")
+
+	err := table.UpdateSettings(TableSettings{ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 5, WriteCapacityUnits: 2}})
+	c.ErrorContains(err, "will not change")
+
+	err = table.UpdateSettings(TableSettings{ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 0, WriteCapacityUnits: 2}})
+	c.ErrorContains(err, "at 'provisionedThroughput.readCapacityUnits'")
+
+	c.ErrorContains(table.UpdateSettings(TableSettings{BillingMode: new("FREE")}), "at 'billingMode'")
+	c.ErrorContains(table.UpdateSettings(TableSettings{TableClass: new("GLACIER")}), "at 'tableClass'")
+
+	for range maxPayPerRequestUpdates {
+		c.NoError(table.UpdateSettings(TableSettings{BillingMode: new(BillingModePayPerRequest), OnDemandThroughput: &Throughput{ReadUnits: 100, WriteUnits: -1}}))
+
+		err = table.UpdateSettings(TableSettings{BillingMode: new(BillingModeProvisioned)})
+		c.ErrorContains(err, "ProvisionedThroughput must be specified")
+
+		c.NoError(table.UpdateSettings(TableSettings{BillingMode: new(BillingModeProvisioned), ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 1, WriteCapacityUnits: 1}}))
+	}
+
+	err = table.UpdateSettings(TableSettings{BillingMode: new(BillingModePayPerRequest)})
+
+	var apiErr types.Error
+	c.True(errors.As(err, &apiErr))
+	c.Equal("LimitExceededException", apiErr.Code())
+
+	clock.Advance(24 * time.Hour)
+	c.NoError(table.UpdateSettings(TableSettings{BillingMode: new(BillingModePayPerRequest), DeletionProtectionEnabled: new(false), WarmThroughput: &Throughput{ReadUnits: 12000}}))
+	c.NoError(table.ValidateDeletion())
+
+	desc = table.Description("pokemons")
+	c.Equal(BillingModePayPerRequest, desc.BillingModeSummary.BillingMode)
+	c.Equal(clock.Now(), *desc.BillingModeSummary.LastUpdateToPayPerRequestDateTime)
+	c.Zero(desc.ProvisionedThroughput.ReadCapacityUnits)
+	c.Equal(&types.OnDemandThroughput{MaxReadRequestUnits: 100, MaxWriteRequestUnits: -1}, desc.OnDemandThroughput)
+	c.Equal(&types.WarmThroughputDescription{ReadUnitsPerSecond: 12000, Status: TableStatusActive}, desc.WarmThroughput)
+
+	c.NoError(table.UpdateSettings(TableSettings{TableClass: new(TableClassStandard)}))
+	c.NoError(table.UpdateSettings(TableSettings{TableClass: new(TableClassStandardInfrequentAccess)}))
+	c.NoError(table.UpdateSettings(TableSettings{TableClass: new(TableClassStandard)}))
+	c.ErrorContains(table.UpdateSettings(TableSettings{TableClass: new(TableClassStandardInfrequentAccess)}), "TableClass are limited")
+
+	desc = table.Description("pokemons")
+	c.Equal(TableClassStandard, desc.TableClassSummary.TableClass)
+	c.Equal(clock.Now(), *desc.TableClassSummary.LastUpdateDateTime)
+}
+
+func TestSettingsSnapshot(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+	snapshot := table.SnapshotSettings()
+
+	c.NoError(table.UpdateSettings(TableSettings{BillingMode: new(BillingModePayPerRequest), DeletionProtectionEnabled: new(true), TableClass: new(TableClassStandardInfrequentAccess)}))
+	table.SetAttributeDefinition([]*types.AttributeDefinition{{AttributeName: new("region"), AttributeType: new("S")}})
+
+	c.NoError(table.ApplyIndexChange(&types.GlobalSecondaryIndexUpdate{Create: &types.CreateGlobalSecondaryIndexAction{
+		IndexName:             new("by-region"),
+		KeySchema:             []*types.KeySchemaElement{{AttributeName: "region", KeyType: "HASH"}},
+		Projection:            &types.Projection{ProjectionType: new("ALL")},
+		ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 1, WriteCapacityUnits: 1},
+	}}))
+	c.NoError(table.ApplyIndexChange(&types.GlobalSecondaryIndexUpdate{Delete: &types.DeleteGlobalSecondaryIndexAction{IndexName: new("by-type")}}))
+
+	table.RestoreSettings(snapshot)
+
+	desc := table.Description("trainers")
+	c.False(desc.DeletionProtectionEnabled)
+	c.Nil(desc.BillingModeSummary)
+	c.Nil(desc.TableClassSummary)
+	c.NotContains(table.AttributesDef, "region")
+	c.NoError(table.ValidateDeletion())
+	c.Contains(table.Indexes, "by-type")
+	c.NotContains(table.Indexes, "by-region")
+}
+
+func TestSettingsChanged(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+	snapshot := table.SnapshotSettings()
+
+	c.NoError(table.UpdateSettings(TableSettings{DeletionProtectionEnabled: new(false), TableClass: new(TableClassStandard)}))
+	c.False(table.SettingsChanged(snapshot))
+
+	c.NoError(table.UpdateSettings(TableSettings{DeletionProtectionEnabled: new(true)}))
+	c.True(table.SettingsChanged(snapshot))
+
+	table.RestoreSettings(snapshot)
+	c.False(table.SettingsChanged(snapshot))
+
+	c.NoError(table.UpdateSettings(TableSettings{BillingMode: new(BillingModePayPerRequest)}))
+	c.True(table.SettingsChanged(snapshot))
+}
+
+func TestUpdateSettingsWithoutClock(t *testing.T) {
+	c := require.New(t)
+
+	table := &Table{}
+
+	c.NoError(table.UpdateSettings(TableSettings{BillingMode: new(BillingModePayPerRequest), TableClass: new(TableClassStandardInfrequentAccess)}))
+	c.Equal(BillingModePayPerRequest, table.billingMode())
+	c.Equal(TableClassStandardInfrequentAccess, table.tableClass())
+}
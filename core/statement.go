@@ -0,0 +1,448 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/truora/minidyn/interpreter"
+	"github.com/truora/minidyn/interpreter/partiql"
+	"github.com/truora/minidyn/types"
+)
+
+// StatementInput parameters to execute a PartiQL statement on a table
+type StatementInput struct {
+	Parameters []*types.Item
+	Limit      int64
+	NextToken  string
+}
+
+// StatementOutput result of a PartiQL statement
+type StatementOutput struct {
+	Items            []map[string]*types.Item
+	LastEvaluatedKey map[string]*types.Item
+	NextToken        string
+}
+
+// ExecuteStatement runs a parsed PartiQL statement on the table
+func (t *Table) ExecuteStatement(stmt partiql.Statement, input StatementInput) (*StatementOutput, error) {
+	switch s := stmt.(type) {
+	case *partiql.SelectStatement:
+		return t.executeSelect(s, input)
+	case *partiql.InsertStatement:
+		return t.executeInsert(s, input)
+	case *partiql.UpdateStatement:
+		return t.executeUpdate(s, input)
+	case *partiql.DeleteStatement:
+		return t.executeDelete(s, input)
+	case *partiql.ExistsStatement:
+		return nil, types.NewError("ValidationException", "EXISTS is only supported as a condition in ExecuteTransaction", nil)
+	}
+
+	return nil, types.NewError("ValidationException", fmt.Sprintf("Unsupported statement: %s", stmt), nil)
+}
+
+// StatementItemKey returns the key of the single item a statement works on, it fails when the
+// statement does not pin the whole primary key of the table like batches and transactions require
+func (t *Table) StatementItemKey(stmt partiql.Statement, params []*types.Item) (string, error) {
+	tr := partiql.NewTranslator(params)
+
+	var (
+		key map[string]*types.Item
+		err error
+	)
+
+	switch s := stmt.(type) {
+	case *partiql.InsertStatement:
+		key, err = t.insertItem(tr, s)
+	case *partiql.UpdateStatement:
+		key, _, err = t.statementKey(tr, s.Where)
+	case *partiql.DeleteStatement:
+		key, _, err = t.statementKey(tr, s.Where)
+	case *partiql.SelectStatement:
+		key, err = t.selectKey(tr, s)
+	case *partiql.ExistsStatement:
+		key, err = t.selectKey(tr, s.Select)
+	default:
+		err = types.NewError("ValidationException", fmt.Sprintf("Unsupported statement: %s", stmt), nil)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	itemKey, err := t.KeySchema.GetKey(t.AttributesDef, key)
+	if err != nil {
+		return "", types.NewError("ValidationException", err.Error(), nil)
+	}
+
+	return itemKey, nil
+}
+
+// CheckExists evaluates the EXISTS condition of a transaction, it fails with a
+// ConditionalCheckFailedException when the item selected by the statement does not exist
+func (t *Table) CheckExists(stmt *partiql.ExistsStatement, input StatementInput) error {
+	tr := partiql.NewTranslator(input.Parameters)
+
+	key, rest, err := t.statementKey(tr, stmt.Select.Where)
+	if err != nil {
+		return err
+	}
+
+	itemKey, err := t.KeySchema.GetKey(t.AttributesDef, key)
+	if err != nil {
+		return types.NewError("ValidationException", err.Error(), nil)
+	}
+
+	t.RecordAccess(key)
+
+	item, ok := t.Data[itemKey]
+	if !ok {
+		return &types.ConditionalCheckFailedException{MessageText: ErrConditionalRequestFailed.Error()}
+	}
+
+	if rest == nil {
+		return nil
+	}
+
+	condition, err := tr.Condition(rest)
+	if err != nil {
+		return err
+	}
+
+	matched, err := t.InterpreterMatch(interpreter.MatchInput{
+		TableName:      t.Name,
+		Expression:     condition,
+		ExpressionType: interpreter.ExpressionTypeConditional,
+		Item:           item,
+		Aliases:        tr.Names,
+		Attributes:     tr.Values,
+	})
+	if err != nil {
+		return types.NewError("ValidationException", err.Error(), nil)
+	}
+
+	if !matched {
+		return &types.ConditionalCheckFailedException{MessageText: ErrConditionalRequestFailed.Error()}
+	}
+
+	return nil
+}
+
+func (t *Table) selectKey(tr *partiql.Translator, stmt *partiql.SelectStatement) (map[string]*types.Item, error) {
+	if stmt.Index != "" {
+		return nil, types.NewError("ValidationException", "Only the base table can be read in a batch or a transaction", nil)
+	}
+
+	key, _, err := t.statementKey(tr, stmt.Where)
+
+	return key, err
+}
+
+// selectQuery plans a SELECT statement as a Query when the WHERE clause pins the partition
+// key of the table (or index), otherwise as a Scan filtered by the WHERE clause
+func (t *Table) selectQuery(stmt *partiql.SelectStatement, params []*types.Item) (QueryInput, error) {
+	input := QueryInput{Index: stmt.Index, ScanIndexForward: true}
+
+	ks := t.KeySchema
+
+	if stmt.Index != "" {
+		idx, err := t.searchIndex(input)
+		if err != nil {
+			return input, err
+		}
+
+		ks = idx.keySchema
+	}
+
+	tr := partiql.NewTranslator(params)
+
+	var keyCondition *partiql.KeyCondition
+	if stmt.Where != nil {
+		keyCondition = partiql.SplitKeyCondition(stmt.Where, ks.HashKey, ks.RangeKey)
+	}
+
+	var err error
+
+	switch {
+	case keyCondition != nil:
+		input.KeyConditionExpression, input.FilterExpression, err = translateKeyCondition(tr, keyCondition)
+	case stmt.Where != nil:
+		input.Scan = true
+		input.FilterExpression, err = tr.Condition(stmt.Where)
+	default:
+		input.Scan = true
+	}
+
+	if err != nil {
+		return input, err
+	}
+
+	if stmt.OrderBy != nil {
+		if keyCondition == nil || ks.RangeKey == "" || !stmt.OrderBy.Path.IsAttribute() || stmt.OrderBy.Path.Attribute() != ks.RangeKey {
+			return input, types.NewError("ValidationException", "ORDER BY is only supported on the sort key with a WHERE clause that pins the partition key", nil)
+		}
+
+		input.ScanIndexForward = !stmt.OrderBy.Descending
+	}
+
+	if len(stmt.Projection) > 0 {
+		input.ProjectionExpression = tr.Projection(stmt.Projection)
+	}
+
+	input.Aliases = tr.Names
+	input.ExpressionAttributeValues = tr.Values
+
+	return input, nil
+}
+
+func translateKeyCondition(tr *partiql.Translator, kc *partiql.KeyCondition) (string, string, error) {
+	key, err := tr.Condition(kc.Hash)
+	if err != nil {
+		return "", "", err
+	}
+
+	if kc.Range != nil {
+		rangeCondition, err := tr.Condition(kc.Range)
+		if err != nil {
+			return "", "", err
+		}
+
+		key += " AND " + rangeCondition
+	}
+
+	if kc.Filter == nil {
+		return key, "", nil
+	}
+
+	filter, err := tr.Condition(kc.Filter)
+
+	return key, filter, err
+}
+
+func (t *Table) executeSelect(stmt *partiql.SelectStatement, input StatementInput) (*StatementOutput, error) {
+	query, err := t.selectQuery(stmt, input.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	query.Limit = input.Limit
+
+	query.ExclusiveStartKey, err = decodeNextToken(input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+
+	items, last, err := t.SearchData(query)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &StatementOutput{Items: items}
+
+	if len(last) > 0 {
+		out.LastEvaluatedKey = last
+		out.NextToken = encodeNextToken(last)
+	}
+
+	return out, nil
+}
+
+func (t *Table) executeInsert(stmt *partiql.InsertStatement, input StatementInput) (*StatementOutput, error) {
+	tr := partiql.NewTranslator(input.Parameters)
+
+	item, err := t.insertItem(tr, stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	condition, err := tr.Condition(&partiql.IsExpression{Left: attributePath(t.KeySchema.HashKey), Missing: true})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = t.Put(&types.PutItemInput{
+		TableName:                 &t.Name,
+		Item:                      item,
+		ConditionExpression:       &condition,
+		ExpressionAttributeNames:  tr.Names,
+		ExpressionAttributeValues: tr.Values,
+	})
+	if _, ok := errors.AsType[*types.ConditionalCheckFailedException](err); ok {
+		return nil, types.NewError("DuplicateItemException", "Duplicate primary key exists in table", nil)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatementOutput{Items: []map[string]*types.Item{}}, nil
+}
+
+func (t *Table) insertItem(tr *partiql.Translator, stmt *partiql.InsertStatement) (map[string]*types.Item, error) {
+	value, err := tr.Value(stmt.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if value.M == nil {
+		return nil, types.NewError("ValidationException", "Unsupported type passed as VALUE, the item must be a map", nil)
+	}
+
+	return value.M, nil
+}
+
+func (t *Table) executeUpdate(stmt *partiql.UpdateStatement, input StatementInput) (*StatementOutput, error) {
+	tr := partiql.NewTranslator(input.Parameters)
+
+	key, rest, err := t.statementKey(tr, stmt.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	update, err := tr.UpdateExpression(stmt.Actions)
+	if err != nil {
+		return nil, err
+	}
+
+	// UPDATE never creates items
+	exists := &partiql.IsExpression{Left: attributePath(t.KeySchema.HashKey), Not: true, Missing: true}
+
+	condition, err := tr.Condition(partiql.JoinConditions(exists, rest))
+	if err != nil {
+		return nil, err
+	}
+
+	returnValues := "NONE"
+	if stmt.Returning != nil {
+		returnValues = stmt.Returning.ReturnValues()
+	}
+
+	attrs, err := t.Update(&types.UpdateItemInput{
+		TableName:                 &t.Name,
+		Key:                       key,
+		UpdateExpression:          update,
+		ConditionExpression:       &condition,
+		ExpressionAttributeNames:  tr.Names,
+		ExpressionAttributeValues: tr.Values,
+		ReturnValues:              &returnValues,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return returningOutput(stmt.Returning, attrs), nil
+}
+
+func (t *Table) executeDelete(stmt *partiql.DeleteStatement, input StatementInput) (*StatementOutput, error) {
+	if stmt.Returning != nil && stmt.Returning.ReturnValues() != "ALL_OLD" {
+		return nil, types.NewError("ValidationException", "DELETE only supports RETURNING ALL OLD *", nil)
+	}
+
+	tr := partiql.NewTranslator(input.Parameters)
+
+	key, rest, err := t.statementKey(tr, stmt.Where)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteInput := &types.DeleteItemInput{
+		TableName: &t.Name,
+		Key:       key,
+	}
+
+	if rest != nil {
+		condition, err := tr.Condition(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		deleteInput.ConditionExpression = &condition
+		deleteInput.ExpressionAttributeNames = map[string]*string{}
+		deleteInput.ExpressionAttributeValues = tr.Values
+
+		for alias, name := range tr.Names {
+			deleteInput.ExpressionAttributeNames[alias] = &name
+		}
+	}
+
+	old, err := t.Delete(deleteInput)
+	if err != nil {
+		return nil, err
+	}
+
+	return returningOutput(stmt.Returning, old), nil
+}
+
+// statementKey resolves the primary key pinned by the WHERE clause of UPDATE and DELETE
+// statements, the remaining conditions are returned to be used as a condition expression
+func (t *Table) statementKey(tr *partiql.Translator, where partiql.Expression) (map[string]*types.Item, partiql.Expression, error) {
+	keys := []string{t.KeySchema.HashKey}
+	if t.KeySchema.RangeKey != "" {
+		keys = append(keys, t.KeySchema.RangeKey)
+	}
+
+	values, rest, ok := partiql.ExtractKey(where, keys...)
+	if !ok {
+		return nil, nil, types.NewError("ValidationException", "Where clause does not contain a mandatory equality on all key attributes", nil)
+	}
+
+	key := make(map[string]*types.Item, len(values))
+
+	for name, exp := range values {
+		value, err := tr.Value(exp)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key[name] = value
+	}
+
+	return key, rest, nil
+}
+
+func returningOutput(returning *partiql.Returning, attrs map[string]*types.Item) *StatementOutput {
+	out := &StatementOutput{Items: []map[string]*types.Item{}}
+
+	if returning != nil && len(attrs) > 0 {
+		out.Items = append(out.Items, attrs)
+	}
+
+	return out
+}
+
+func attributePath(name string) *partiql.Path {
+	return &partiql.Path{Elements: []partiql.PathElement{{Name: name}}}
+}
+
+// encodeNextToken serializes the last evaluated key as an opaque pagination token
+func encodeNextToken(key map[string]*types.Item) string {
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return ""
+	}
+
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func decodeNextToken(token string) (map[string]*types.Item, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	invalid := types.NewError("ValidationException", "Invalid NextToken", nil)
+
+	raw, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+
+	key := map[string]*types.Item{}
+
+	if err := json.Unmarshal(raw, &key); err != nil || len(key) == 0 {
+		return nil, invalid
+	}
+
+	return key, nil
+}
@@ -0,0 +1,311 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/interpreter/partiql"
+	"github.com/truora/minidyn/types"
+)
+
+func executeStatement(c *require.Assertions, table *Table, statement string, input StatementInput) (*StatementOutput, error) {
+	stmt, err := partiql.Parse(statement, len(input.Parameters))
+	c.NoError(err)
+
+	return table.ExecuteStatement(stmt, input)
+}
+
+func TestSelectQueryPlan(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	stmt, err := partiql.Parse(`SELECT pokemon FROM trainers WHERE "type" = 'water' AND trainer = ? AND pokemon > 'p' ORDER BY pokemon DESC`, 1)
+	c.NoError(err)
+
+	input, err := table.selectQuery(stmt.(*partiql.SelectStatement), []*types.Item{{S: new("misty")}})
+	c.NoError(err)
+	c.False(input.Scan)
+	c.False(input.ScanIndexForward)
+	c.Equal("(#n0 = :v0) AND (#n1 > :v1)", input.KeyConditionExpression)
+	c.Equal("(#n2 = :v2)", input.FilterExpression)
+	c.Equal("#n1", input.ProjectionExpression)
+	c.Equal(map[string]string{"#n0": "trainer", "#n1": "pokemon", "#n2": "type"}, input.Aliases)
+
+	stmt, err = partiql.Parse(`SELECT * FROM trainers."by-type" WHERE "type" = 'rock'`, 0)
+	c.NoError(err)
+
+	input, err = table.selectQuery(stmt.(*partiql.SelectStatement), nil)
+	c.NoError(err)
+	c.False(input.Scan)
+	c.Equal("by-type", input.Index)
+	c.Equal("(#n0 = :v0)", input.KeyConditionExpression)
+
+	stmt, err = partiql.Parse(`SELECT * FROM trainers WHERE "type" = 'rock'`, 0)
+	c.NoError(err)
+
+	input, err = table.selectQuery(stmt.(*partiql.SelectStatement), nil)
+	c.NoError(err)
+	c.True(input.Scan)
+	c.Empty(input.KeyConditionExpression)
+	c.Equal("(#n0 = :v0)", input.FilterExpression)
+}
+
+func TestExecuteSelect(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	out, err := executeStatement(c, table, `SELECT * FROM trainers WHERE trainer = 'ash' AND pokemon BETWEEN 'b' AND 'q'`, StatementInput{})
+	c.NoError(err)
+	c.Equal([]string{"bulbasaur", "charizard", "pikachu"}, pokemonNames(out.Items))
+	c.Empty(out.NextToken)
+
+	out, err = executeStatement(c, table, `SELECT * FROM trainers WHERE trainer = ? ORDER BY pokemon DESC`, StatementInput{
+		Parameters: []*types.Item{{S: new("misty")}},
+	})
+	c.NoError(err)
+	c.Equal([]string{"staryu", "starmie", "psyduck"}, pokemonNames(out.Items))
+
+	out, err = executeStatement(c, table, `SELECT * FROM trainers WHERE "type" IN ['rock', 'fire'] AND pokemon IS NOT MISSING`, StatementInput{})
+	c.NoError(err)
+	c.Equal([]string{"charizard", "geodude", "onix"}, pokemonNames(out.Items))
+
+	out, err = executeStatement(c, table, `SELECT * FROM trainers."by-type" WHERE "type" = 'water' AND begins_with(pokemon, 'st')`, StatementInput{})
+	c.NoError(err)
+	c.Equal([]string{"starmie", "staryu"}, pokemonNames(out.Items))
+
+	out, err = executeStatement(c, table, `SELECT pokemon FROM trainers WHERE trainer = 'brock'`, StatementInput{})
+	c.NoError(err)
+	c.Equal([]map[string]*types.Item{{"pokemon": {S: new("geodude")}}, {"pokemon": {S: new("onix")}}}, out.Items)
+
+	out, err = executeStatement(c, table, `SELECT * FROM trainers WHERE nickname IS NULL AND trainer = 'brock'`, StatementInput{})
+	c.NoError(err)
+	c.Len(out.Items, 2)
+}
+
+func TestExecuteSelectPaging(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	var pages [][]string
+
+	input := StatementInput{Limit: 4}
+
+	for {
+		out, err := executeStatement(c, table, `SELECT * FROM trainers`, input)
+		c.NoError(err)
+
+		pages = append(pages, pokemonNames(out.Items))
+
+		if out.NextToken == "" {
+			c.Empty(out.LastEvaluatedKey)
+
+			break
+		}
+
+		c.NotEmpty(out.LastEvaluatedKey)
+
+		input.NextToken = out.NextToken
+	}
+
+	c.Equal([][]string{
+		{"bulbasaur", "charizard", "pikachu", "squirtle"},
+		{"geodude", "onix", "psyduck", "starmie"},
+		{"staryu"},
+	}, pages)
+}
+
+func TestExecuteSelectErrors(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	_, err := executeStatement(c, table, `SELECT * FROM trainers ORDER BY pokemon`, StatementInput{})
+	c.EqualError(err, "ValidationException: ORDER BY is only supported on the sort key with a WHERE clause that pins the partition key")
+
+	_, err = executeStatement(c, table, `SELECT * FROM trainers WHERE trainer = 'ash' ORDER BY "type"`, StatementInput{})
+	c.EqualError(err, "ValidationException: ORDER BY is only supported on the sort key with a WHERE clause that pins the partition key")
+
+	_, err = executeStatement(c, table, `SELECT * FROM trainers."missing"`, StatementInput{})
+	c.EqualError(err, "ValidationException: The table does not have the specified index: missing")
+
+	_, err = executeStatement(c, table, `SELECT * FROM trainers`, StatementInput{NextToken: "not-a-token"})
+	c.EqualError(err, "ValidationException: Invalid NextToken")
+
+	_, err = executeStatement(c, table, `EXISTS(SELECT * FROM trainers WHERE trainer = 'ash')`, StatementInput{})
+	c.EqualError(err, "ValidationException: EXISTS is only supported as a condition in ExecuteTransaction")
+}
+
+func TestExecuteInsert(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	out, err := executeStatement(c, table, `INSERT INTO trainers VALUE {'trainer': 'gary', 'pokemon': ?, 'type': 'water'}`, StatementInput{
+		Parameters: []*types.Item{{S: new("blastoise")}},
+	})
+	c.NoError(err)
+	c.Empty(out.Items)
+
+	out, err = executeStatement(c, table, `SELECT * FROM trainers."by-type" WHERE "type" = 'water' AND pokemon = 'blastoise'`, StatementInput{})
+	c.NoError(err)
+	c.Equal([]map[string]*types.Item{{
+		"trainer": {S: new("gary")},
+		"pokemon": {S: new("blastoise")},
+		"type":    {S: new("water")},
+	}}, out.Items)
+
+	_, err = executeStatement(c, table, `INSERT INTO trainers VALUE ?`, StatementInput{
+		Parameters: []*types.Item{{M: map[string]*types.Item{"trainer": {S: new("ash")}, "pokemon": {S: new("pikachu")}}}},
+	})
+	c.EqualError(err, "DuplicateItemException: Duplicate primary key exists in table")
+
+	_, err = executeStatement(c, table, `INSERT INTO trainers VALUE 'ash'`, StatementInput{})
+	c.EqualError(err, "ValidationException: Unsupported type passed as VALUE, the item must be a map")
+
+	_, err = executeStatement(c, table, `INSERT INTO trainers VALUE {'trainer': 'ash'}`, StatementInput{})
+	c.Error(err)
+}
+
+func TestExecuteUpdate(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	out, err := executeStatement(c, table, `UPDATE trainers SET lvl = 5, moves = list_append(if_not_exists(moves, []), ['thunder']) SET tags = set_add(tags, <<'starter'>>) WHERE trainer = 'ash' AND pokemon = ? RETURNING ALL NEW *`, StatementInput{
+		Parameters: []*types.Item{{S: new("pikachu")}},
+	})
+	c.NoError(err)
+	c.Equal([]map[string]*types.Item{{
+		"trainer": {S: new("ash")},
+		"pokemon": {S: new("pikachu")},
+		"type":    {S: new("electric")},
+		"lvl":     {N: new("5")},
+		"moves":   {L: []*types.Item{{S: new("thunder")}}},
+		"tags":    {SS: []*string{new("starter")}},
+	}}, out.Items)
+
+	out, err = executeStatement(c, table, `UPDATE trainers SET lvl = lvl + 1 WHERE pokemon = 'pikachu' AND trainer = 'ash' AND lvl = 5 RETURNING MODIFIED OLD *`, StatementInput{})
+	c.NoError(err)
+	c.Equal([]map[string]*types.Item{{"lvl": {N: new("5")}}}, out.Items)
+
+	out, err = executeStatement(c, table, `UPDATE trainers SET lvl = 1 REMOVE tags WHERE trainer = 'ash' AND pokemon = 'pikachu'`, StatementInput{})
+	c.NoError(err)
+	c.Empty(out.Items)
+
+	out, err = executeStatement(c, table, `SELECT lvl, tags FROM trainers WHERE trainer = 'ash' AND pokemon = 'pikachu'`, StatementInput{})
+	c.NoError(err)
+	c.Equal([]map[string]*types.Item{{"lvl": {N: new("1")}}}, out.Items)
+
+	_, err = executeStatement(c, table, `UPDATE trainers SET lvl = 1 WHERE trainer = 'ash' AND pokemon = 'pikachu' AND lvl = 100`, StatementInput{})
+	c.EqualError(err, "ConditionalCheckFailedException: The conditional request failed")
+
+	_, err = executeStatement(c, table, `UPDATE trainers SET lvl = 1 WHERE trainer = 'ash' AND pokemon = 'mew'`, StatementInput{})
+	c.EqualError(err, "ConditionalCheckFailedException: The conditional request failed")
+	c.Len(table.Data, 9)
+
+	_, err = executeStatement(c, table, `UPDATE trainers SET lvl = 1 WHERE trainer = 'ash'`, StatementInput{})
+	c.EqualError(err, "ValidationException: Where clause does not contain a mandatory equality on all key attributes")
+
+	_, err = executeStatement(c, table, `UPDATE trainers SET "type" = 'rock', pokemon = 'onix' WHERE trainer = 'ash' AND pokemon = 'pikachu'`, StatementInput{})
+	c.Error(err)
+}
+
+func TestExecuteDelete(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	out, err := executeStatement(c, table, `DELETE FROM trainers WHERE trainer = 'brock' AND pokemon = 'onix' RETURNING ALL OLD *`, StatementInput{})
+	c.NoError(err)
+	c.Equal([]map[string]*types.Item{{
+		"trainer": {S: new("brock")},
+		"pokemon": {S: new("onix")},
+		"type":    {S: new("rock")},
+	}}, out.Items)
+
+	out, err = executeStatement(c, table, `DELETE FROM trainers WHERE trainer = 'brock' AND pokemon = 'onix' RETURNING ALL OLD *`, StatementInput{})
+	c.NoError(err)
+	c.Empty(out.Items)
+
+	_, err = executeStatement(c, table, `DELETE FROM trainers WHERE trainer = 'brock' AND pokemon = 'geodude' AND "type" = 'water'`, StatementInput{})
+	c.EqualError(err, "ConditionalCheckFailedException: The conditional request failed")
+
+	out, err = executeStatement(c, table, `DELETE FROM trainers WHERE trainer = 'brock' AND pokemon = 'geodude' AND "type" = 'rock'`, StatementInput{})
+	c.NoError(err)
+	c.Empty(out.Items)
+
+	out, err = executeStatement(c, table, `SELECT * FROM trainers."by-type" WHERE "type" = 'rock'`, StatementInput{})
+	c.NoError(err)
+	c.Empty(out.Items)
+
+	_, err = executeStatement(c, table, `DELETE FROM trainers WHERE trainer = 'ash' RETURNING MODIFIED OLD *`, StatementInput{})
+	c.EqualError(err, "ValidationException: DELETE only supports RETURNING ALL OLD *")
+
+	_, err = executeStatement(c, table, `DELETE FROM trainers WHERE pokemon = 'pikachu'`, StatementInput{})
+	c.EqualError(err, "ValidationException: Where clause does not contain a mandatory equality on all key attributes")
+}
+
+func TestStatementItemKey(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	want, err := table.KeySchema.GetKey(table.AttributesDef, map[string]*types.Item{
+		"trainer": {S: new("ash")},
+		"pokemon": {S: new("pikachu")},
+	})
+	c.NoError(err)
+
+	for _, statement := range []string{
+		`INSERT INTO trainers VALUE {'trainer': 'ash', 'pokemon': ?}`,
+		`UPDATE trainers SET lvl = 1 WHERE trainer = 'ash' AND pokemon = ?`,
+		`DELETE FROM trainers WHERE pokemon = ? AND trainer = 'ash'`,
+		`SELECT * FROM trainers WHERE trainer = 'ash' AND pokemon = ?`,
+		`EXISTS(SELECT * FROM trainers WHERE trainer = 'ash' AND pokemon = ? AND "type" = 'electric')`,
+	} {
+		stmt, err := partiql.Parse(statement, 1)
+		c.NoError(err)
+
+		key, err := table.StatementItemKey(stmt, []*types.Item{{S: new("pikachu")}})
+		c.NoError(err, statement)
+		c.Equal(want, key, statement)
+	}
+
+	stmt, err := partiql.Parse(`SELECT * FROM trainers WHERE trainer = 'ash'`, 0)
+	c.NoError(err)
+
+	_, err = table.StatementItemKey(stmt, nil)
+	c.EqualError(err, "ValidationException: Where clause does not contain a mandatory equality on all key attributes")
+
+	stmt, err = partiql.Parse(`SELECT * FROM trainers."by-type" WHERE "type" = 'water' AND pokemon = 'squirtle'`, 0)
+	c.NoError(err)
+
+	_, err = table.StatementItemKey(stmt, nil)
+	c.EqualError(err, "ValidationException: Only the base table can be read in a batch or a transaction")
+}
+
+func TestCheckExists(t *testing.T) {
+	c := require.New(t)
+
+	table := createTrainerTable(c)
+
+	check := func(statement string) error {
+		stmt, err := partiql.Parse(statement, 0)
+		c.NoError(err)
+
+		return table.CheckExists(stmt.(*partiql.ExistsStatement), StatementInput{})
+	}
+
+	c.NoError(check(`EXISTS(SELECT * FROM trainers WHERE trainer = 'ash' AND pokemon = 'pikachu')`))
+	c.NoError(check(`EXISTS(SELECT * FROM trainers WHERE trainer = 'ash' AND pokemon = 'pikachu' AND "type" = 'electric')`))
+
+	var ccf *types.ConditionalCheckFailedException
+
+	c.ErrorAs(check(`EXISTS(SELECT * FROM trainers WHERE trainer = 'ash' AND pokemon = 'mew')`), &ccf)
+	c.ErrorAs(check(`EXISTS(SELECT * FROM trainers WHERE trainer = 'ash' AND pokemon = 'pikachu' AND "type" = 'fire')`), &ccf)
+
+	c.EqualError(check(`EXISTS(SELECT * FROM trainers WHERE trainer = 'ash')`), "ValidationException: Where clause does not contain a mandatory equality on all key attributes")
+}
@@ -0,0 +1,37 @@
+package core
+
+const (
+	// TableStatusCreating is the status of a table that is not usable yet
+	TableStatusCreating = "CREATING"
+	// TableStatusUpdating is the status of a table applying an UpdateTable change
+	TableStatusUpdating = "UPDATING"
+	// TableStatusDeleting is the status of a table that is being deleted
+	TableStatusDeleting = "DELETING"
+	// TableStatusActive is the status of a table ready for use
+	TableStatusActive = "ACTIVE"
+)
+
+// BeginStatus moves the table to the CREATING, UPDATING or DELETING status, which lasts
+// StatusDelay. Creations and updates then turn ACTIVE, and deleted tables stay DELETING
+func (t *Table) BeginStatus(status string) {
+	t.status = status
+	t.statusChangedAt = t.now()
+}
+
+// Status returns the status of the table following the clock of the table
+func (t *Table) Status() string {
+	if t.status == TableStatusDeleting || t.inTransition() {
+		return t.status
+	}
+
+	return TableStatusActive
+}
+
+// Deleted reports whether the deletion of the table is over
+func (t *Table) Deleted() bool {
+	return t.status == TableStatusDeleting && !t.inTransition()
+}
+
+func (t *Table) inTransition() bool {
+	return t.status != "" && t.now().Sub(t.statusChangedAt) < t.StatusDelay
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableStatus(t *testing.T) {
+	c := require.New(t)
+
+	clock := NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	table := NewTable("pokemons")
+	table.Clock = clock
+	c.Equal(TableStatusActive, table.Status())
+
+	table.BeginStatus(TableStatusCreating)
+	c.Equal(TableStatusActive, table.Status())
+
+	table.StatusDelay = time.Minute
+	table.BeginStatus(TableStatusUpdating)
+	c.Equal(TableStatusUpdating, table.Status())
+	c.Equal(TableStatusUpdating, table.Description("pokemons").TableStatus)
+
+	clock.Advance(time.Minute)
+	c.Equal(TableStatusActive, table.Status())
+
+	table.BeginStatus(TableStatusDeleting)
+	c.Equal(TableStatusDeleting, table.Status())
+	c.False(table.Deleted())
+
+	clock.Advance(time.Minute)
+	c.Equal(TableStatusDeleting, table.Status())
+	c.True(table.Deleted())
+}
+
+func TestTableStatusWithoutClock(t *testing.T) {
+	c := require.New(t)
+
+	table := &Table{StatusDelay: time.Hour}
+
+	table.BeginStatus(TableStatusUpdating)
+	c.Equal(TableStatusUpdating, table.Status())
+	c.False(table.Deleted())
+}
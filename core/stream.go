@@ -0,0 +1,348 @@
+package core
+
+import (
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// StreamViewTypeKeysOnly records only the key attributes of the modified item
+	StreamViewTypeKeysOnly = "KEYS_ONLY"
+	// StreamViewTypeNewImage records the item as it appears after the change
+	StreamViewTypeNewImage = "NEW_IMAGE"
+	// StreamViewTypeOldImage records the item as it appeared before the change
+	StreamViewTypeOldImage = "OLD_IMAGE"
+	// StreamViewTypeNewAndOldImages records both images of the item
+	StreamViewTypeNewAndOldImages = "NEW_AND_OLD_IMAGES"
+
+	// StreamEventInsert is recorded when a new item is added to the table
+	StreamEventInsert = "INSERT"
+	// StreamEventModify is recorded when an existing item is changed
+	StreamEventModify = "MODIFY"
+	// StreamEventRemove is recorded when an item is deleted from the table
+	StreamEventRemove = "REMOVE"
+
+	// streamLabelLayout matches the timestamp format DynamoDB uses for stream labels
+	streamLabelLayout = "2006-01-02T15:04:05.000"
+)
+
+// StreamRecord is a single change captured in a table stream. The Table ChangeListener
+// receives records with both images and no sequence number, whatever the stream settings.
+type StreamRecord struct {
+	EventID        string
+	EventName      string
+	SequenceNumber string
+	Keys           map[string]*types.Item
+	NewImage       map[string]*types.Item
+	OldImage       map[string]*types.Item
+	SizeBytes      int64
+	CreatedAt      time.Time
+	// ServiceInitiated is set for the removals done by the Time To Live sweeper
+	ServiceInitiated bool
+	sequence         uint64
+}
+
+// Stream is the ordered change log of a table. A table keeps every stream it had,
+// only the latest one can be enabled.
+type Stream struct {
+	Label     string
+	ShardID   string
+	ViewType  string
+	CreatedAt time.Time
+	Enabled   bool
+	records   []StreamRecord
+	// sequence is the last sequence number handed out, it never goes back even when
+	// records are discarded by a transaction rollback
+	sequence uint64
+}
+
+func isValidStreamViewType(viewType string) bool {
+	switch viewType {
+	case StreamViewTypeKeysOnly, StreamViewTypeNewImage, StreamViewTypeOldImage, StreamViewTypeNewAndOldImages:
+		return true
+	}
+
+	return false
+}
+
+// FormatSequenceNumber returns the wire representation of a stream sequence number
+func FormatSequenceNumber(seq uint64) string {
+	return fmt.Sprintf("%021d", seq)
+}
+
+// Records returns the records of the stream in sequence order
+func (s *Stream) Records() []StreamRecord {
+	return s.records
+}
+
+// LastSequence returns the sequence number of the last record written to the stream
+func (s *Stream) LastSequence() uint64 {
+	return s.sequence
+}
+
+// RecordsFrom returns up to limit records whose sequence number is greater or equal to seq
+func (s *Stream) RecordsFrom(seq uint64, limit int) []StreamRecord {
+	start := sort.Search(len(s.records), func(i int) bool {
+		return s.records[i].sequence >= seq
+	})
+
+	end := min(start+limit, len(s.records))
+
+	return s.records[start:end]
+}
+
+// Sequence returns the numeric sequence number of the record
+func (r StreamRecord) Sequence() uint64 {
+	return r.sequence
+}
+
+// EnableStream starts a new stream on the table with the given view type
+func (t *Table) EnableStream(viewType string, now time.Time) (*Stream, error) {
+	if !isValidStreamViewType(viewType) {
+		return nil, types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%s' at 'streamSpecification.streamViewType' failed to satisfy constraint: Member must satisfy enum value set: [NEW_IMAGE, OLD_IMAGE, NEW_AND_OLD_IMAGES, KEYS_ONLY]", viewType), nil)
+	}
+
+	latest := t.LatestStream()
+	if latest != nil && latest.Enabled {
+		return nil, types.NewError("ValidationException", fmt.Sprintf("Table already has an enabled stream: %s", latest.Label), nil)
+	}
+
+	label := now.UTC().Format(streamLabelLayout)
+	// labels identify the streams of a table, keep them unique when enabled in the same millisecond
+	for latest != nil && label <= latest.Label {
+		now = now.Add(time.Millisecond)
+		label = now.UTC().Format(streamLabelLayout)
+	}
+
+	stream := &Stream{
+		Label:     label,
+		ShardID:   fmt.Sprintf("shardId-%020d-%08x", now.UnixMilli(), crc32.ChecksumIEEE([]byte(t.Name+"/"+label))),
+		ViewType:  viewType,
+		CreatedAt: now,
+		Enabled:   true,
+	}
+
+	t.streams = append(t.streams, stream)
+
+	return stream, nil
+}
+
+// DisableStream stops recording changes in the latest stream of the table
+func (t *Table) DisableStream() error {
+	latest := t.LatestStream()
+	if latest == nil || !latest.Enabled {
+		return types.NewError("ValidationException", "Table does not have an enabled stream to disable", nil)
+	}
+
+	latest.Enabled = false
+
+	return nil
+}
+
+// LatestStream returns the most recent stream of the table, or nil if it never had one
+func (t *Table) LatestStream() *Stream {
+	if len(t.streams) == 0 {
+		return nil
+	}
+
+	return t.streams[len(t.streams)-1]
+}
+
+// Streams returns every stream of the table ordered by creation
+func (t *Table) Streams() []*Stream {
+	return t.streams
+}
+
+// StreamByLabel returns the stream of the table with the given label
+func (t *Table) StreamByLabel(label string) *Stream {
+	for _, s := range t.streams {
+		if s.Label == label {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// recordChange captures a write that changed an item, it appends a record to the enabled
+// stream and hands the full change to the ChangeListener. A nil oldItem means the item
+// was inserted and a nil newItem that it was removed.
+func (t *Table) recordChange(oldItem, newItem map[string]*types.Item) {
+	t.recordChangeBy(oldItem, newItem, false)
+}
+
+// recordChangeBy records a change that was made by the service instead of a request
+// when serviceInitiated is set
+func (t *Table) recordChangeBy(oldItem, newItem map[string]*types.Item, serviceInitiated bool) {
+	stream := t.LatestStream()
+	streaming := stream != nil && stream.Enabled
+
+	if !streaming && t.ChangeListener == nil && t.history == nil && t.replication == nil {
+		return
+	}
+
+	eventName := StreamEventModify
+
+	switch {
+	case oldItem == nil && newItem == nil:
+		return
+	case oldItem == nil:
+		eventName = StreamEventInsert
+	case newItem == nil:
+		eventName = StreamEventRemove
+	case itemsEqual(oldItem, newItem):
+		// DynamoDB does not write a record when the item did not change
+		return
+	}
+
+	t.recordHistory(oldItem, newItem)
+	t.recordReplication(oldItem, newItem)
+
+	if !streaming && t.ChangeListener == nil {
+		return
+	}
+
+	source := newItem
+	if source == nil {
+		source = oldItem
+	}
+
+	change := StreamRecord{
+		EventName:        eventName,
+		Keys:             deepCopyItemMap(t.KeySchema.getKeyItem(source)),
+		NewImage:         deepCopyItemMap(newItem),
+		OldImage:         deepCopyItemMap(oldItem),
+		CreatedAt:        t.now(),
+		ServiceInitiated: serviceInitiated,
+	}
+	change.SizeBytes = itemSize(change.Keys) + itemSize(change.NewImage) + itemSize(change.OldImage)
+
+	if t.ChangeListener != nil {
+		t.ChangeListener(change)
+	}
+
+	if streaming {
+		stream.append(change)
+	}
+}
+
+// append stores the change projected by the stream view type with the next sequence number
+func (s *Stream) append(change StreamRecord) {
+	s.sequence++
+
+	record := StreamRecord{
+		EventID:          fmt.Sprintf("%032x", s.sequence),
+		EventName:        change.EventName,
+		SequenceNumber:   FormatSequenceNumber(s.sequence),
+		Keys:             deepCopyItemMap(change.Keys),
+		CreatedAt:        change.CreatedAt,
+		ServiceInitiated: change.ServiceInitiated,
+		sequence:         s.sequence,
+	}
+
+	switch s.ViewType {
+	case StreamViewTypeNewImage:
+		record.NewImage = deepCopyItemMap(change.NewImage)
+	case StreamViewTypeOldImage:
+		record.OldImage = deepCopyItemMap(change.OldImage)
+	case StreamViewTypeNewAndOldImages:
+		record.NewImage = deepCopyItemMap(change.NewImage)
+		record.OldImage = deepCopyItemMap(change.OldImage)
+	}
+
+	record.SizeBytes = itemSize(record.Keys) + itemSize(record.NewImage) + itemSize(record.OldImage)
+
+	s.records = append(s.records, record)
+}
+
+func itemsEqual(a, b map[string]*types.Item) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		other, ok := b[k]
+		if !ok || !dynamoItemEqual(v, other) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// streamSnapshot is the length of every stream of a table at snapshot time
+type streamSnapshot map[*Stream]int
+
+func (t *Table) snapshotStreams() streamSnapshot {
+	snap := make(streamSnapshot, len(t.streams))
+	for _, s := range t.streams {
+		snap[s] = len(s.records)
+	}
+
+	return snap
+}
+
+// restoreStreams discards the records written after the snapshot was taken
+func (t *Table) restoreStreams(snap streamSnapshot) {
+	for _, s := range t.streams {
+		if n, ok := snap[s]; ok && n < len(s.records) {
+			s.records = s.records[:n]
+		}
+	}
+}
+
+// itemSize approximates the DynamoDB size of an item: attribute names plus values
+func itemSize(item map[string]*types.Item) int64 {
+	var size int64
+
+	for name, val := range item {
+		size += int64(len(name)) + attributeSize(val)
+	}
+
+	return size
+}
+
+func attributeSize(val *types.Item) int64 {
+	if val == nil {
+		return 0
+	}
+
+	var size int64
+
+	switch {
+	case val.S != nil:
+		size = int64(len(*val.S))
+	case val.N != nil:
+		size = int64(len(*val.N))
+	case val.B != nil:
+		size = int64(len(val.B))
+	case val.BOOL != nil, val.NULL != nil:
+		size = 1
+	case val.M != nil:
+		size = 3 + itemSize(val.M)
+	case val.L != nil:
+		size = 3
+
+		for _, elem := range val.L {
+			size += 1 + attributeSize(elem)
+		}
+	}
+
+	for _, s := range val.SS {
+		size += int64(len(types.StringValue(s)))
+	}
+
+	for _, n := range val.NS {
+		size += int64(len(types.StringValue(n)))
+	}
+
+	for _, b := range val.BS {
+		size += int64(len(b))
+	}
+
+	return size
+}
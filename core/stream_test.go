@@ -0,0 +1,171 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func createStreamTable(t *testing.T, viewType string) (*Table, *Stream) {
+	t.Helper()
+
+	table := NewTable("orders")
+	table.AttributesDef = map[string]string{"id": "S"}
+	table.KeySchema = keySchema{"id", "", false}
+
+	stream, err := table.EnableStream(viewType, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	return table, stream
+}
+
+func TestStreamRecordsWrites(t *testing.T) {
+	c := require.New(t)
+
+	table, stream := createStreamTable(t, StreamViewTypeNewAndOldImages)
+	c.Equal("2024-05-01T10:00:00.000", stream.Label)
+
+	_, err := table.Put(&types.PutItemInput{Item: map[string]*types.Item{
+		"id":     {S: new("o1")},
+		"status": {S: new("new")},
+	}})
+	c.NoError(err)
+
+	_, err = table.Update(&types.UpdateItemInput{
+		Key:                       map[string]*types.Item{"id": {S: new("o1")}},
+		UpdateExpression:          "SET #s = :s",
+		ExpressionAttributeNames:  map[string]string{"#s": "status"},
+		ExpressionAttributeValues: map[string]*types.Item{":s": {S: new("paid")}},
+	})
+	c.NoError(err)
+
+	// an update that leaves the item untouched is not recorded
+	_, err = table.Update(&types.UpdateItemInput{
+		Key:                       map[string]*types.Item{"id": {S: new("o1")}},
+		UpdateExpression:          "SET #s = :s",
+		ExpressionAttributeNames:  map[string]string{"#s": "status"},
+		ExpressionAttributeValues: map[string]*types.Item{":s": {S: new("paid")}},
+	})
+	c.NoError(err)
+
+	_, err = table.Delete(&types.DeleteItemInput{Key: map[string]*types.Item{"id": {S: new("o1")}}})
+	c.NoError(err)
+
+	// deleting a missing item is not recorded
+	_, err = table.Delete(&types.DeleteItemInput{Key: map[string]*types.Item{"id": {S: new("o1")}}})
+	c.NoError(err)
+
+	records := stream.Records()
+	c.Len(records, 3)
+
+	c.Equal(StreamEventInsert, records[0].EventName)
+	c.Nil(records[0].OldImage)
+	c.Equal("new", *records[0].NewImage["status"].S)
+
+	c.Equal(StreamEventModify, records[1].EventName)
+	c.Equal("new", *records[1].OldImage["status"].S)
+	c.Equal("paid", *records[1].NewImage["status"].S)
+
+	c.Equal(StreamEventRemove, records[2].EventName)
+	c.Equal("paid", *records[2].OldImage["status"].S)
+	c.Nil(records[2].NewImage)
+
+	for i, r := range records {
+		c.Equal(map[string]*types.Item{"id": {S: new("o1")}}, r.Keys)
+		c.Equal(uint64(i+1), r.Sequence())
+		c.Equal(FormatSequenceNumber(uint64(i+1)), r.SequenceNumber)
+		c.Positive(r.SizeBytes)
+	}
+
+	c.Len(stream.RecordsFrom(2, 10), 2)
+	c.Len(stream.RecordsFrom(1, 1), 1)
+	c.Empty(stream.RecordsFrom(4, 10))
+}
+
+func TestStreamViewTypes(t *testing.T) {
+	tests := []struct {
+		viewType string
+		hasNew   bool
+		hasOld   bool
+	}{
+		{viewType: StreamViewTypeKeysOnly},
+		{viewType: StreamViewTypeNewImage, hasNew: true},
+		{viewType: StreamViewTypeOldImage, hasOld: true},
+		{viewType: StreamViewTypeNewAndOldImages, hasNew: true, hasOld: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.viewType, func(t *testing.T) {
+			c := require.New(t)
+
+			table, stream := createStreamTable(t, tt.viewType)
+
+			for _, status := range []string{"new", "paid"} {
+				_, err := table.Put(&types.PutItemInput{Item: map[string]*types.Item{
+					"id":     {S: new("o1")},
+					"status": {S: new(status)},
+				}})
+				c.NoError(err)
+			}
+
+			modify := stream.Records()[1]
+			c.Equal(StreamEventModify, modify.EventName)
+			c.Equal(map[string]*types.Item{"id": {S: new("o1")}}, modify.Keys)
+			c.Equal(tt.hasNew, modify.NewImage != nil)
+			c.Equal(tt.hasOld, modify.OldImage != nil)
+		})
+	}
+}
+
+func TestStreamEnableDisable(t *testing.T) {
+	c := require.New(t)
+
+	table, first := createStreamTable(t, StreamViewTypeKeysOnly)
+
+	_, err := table.EnableStream(StreamViewTypeNewImage, first.CreatedAt)
+	c.EqualError(err, "ValidationException: Table already has an enabled stream: 2024-05-01T10:00:00.000")
+
+	_, err = table.EnableStream("ALL", first.CreatedAt)
+	c.ErrorContains(err, "streamSpecification.streamViewType")
+
+	c.NoError(table.DisableStream())
+	c.ErrorContains(table.DisableStream(), "does not have an enabled stream")
+
+	// writes are not recorded while the stream is disabled
+	_, err = table.Put(&types.PutItemInput{Item: map[string]*types.Item{"id": {S: new("o1")}}})
+	c.NoError(err)
+	c.Empty(first.Records())
+
+	second, err := table.EnableStream(StreamViewTypeNewImage, first.CreatedAt)
+	c.NoError(err)
+	c.Equal("2024-05-01T10:00:00.001", second.Label)
+	c.NotEqual(first.ShardID, second.ShardID)
+	c.Same(second, table.LatestStream())
+	c.Same(first, table.StreamByLabel(first.Label))
+	c.Len(table.Streams(), 2)
+}
+
+func TestStreamRestoreDiscardsRecords(t *testing.T) {
+	c := require.New(t)
+
+	table, stream := createStreamTable(t, StreamViewTypeNewImage)
+
+	_, err := table.Put(&types.PutItemInput{Item: map[string]*types.Item{"id": {S: new("o1")}}})
+	c.NoError(err)
+
+	snap := table.Snapshot()
+
+	_, err = table.Put(&types.PutItemInput{Item: map[string]*types.Item{"id": {S: new("o2")}}})
+	c.NoError(err)
+	c.Len(stream.Records(), 2)
+
+	table.Restore(snap)
+	c.Len(stream.Records(), 1)
+
+	// sequence numbers keep growing after a rollback
+	_, err = table.Put(&types.PutItemInput{Item: map[string]*types.Item{"id": {S: new("o3")}}})
+	c.NoError(err)
+	c.Equal(uint64(3), stream.Records()[1].Sequence())
+}
@@ -27,15 +27,21 @@ type QueryInput struct {
 	Aliases                   map[string]string
 	ScanIndexForward          bool
 	Scan                      bool
-	started                   bool
+	KeyConditions             map[string]*types.Condition
+	QueryFilter               map[string]*types.Condition
+	ScanFilter                map[string]*types.Condition
+	ConditionalOperator       *string
+	AttributesToGet           []string
+	Segment                   *int64
+	TotalSegments             *int64
 }
 
 // Table struct to mock a dynamodb table
 type Table struct {
 	Name                 string
+	Arn                  string
 	Indexes              map[string]*index
 	AttributesDef        map[string]string
-	SortedKeys           []string
 	Data                 map[string]map[string]*types.Item
 	KeySchema            keySchema
 	BillingMode          *string
@@ -43,6 +49,21 @@ type Table struct {
 	NativeInterpreter    interpreter.Native
 	LangInterpreter      interpreter.Language
 	IndexActivationDelay time.Duration
+	StatusDelay          time.Duration
+	ChangeListener       func(StreamRecord)
+	RestoreSummary       *RestoreSummary
+	Clock                Clock
+	partitions           *partitionMap
+	streams              []*Stream
+	ttlAttribute         string
+	expirations          expirationHeap
+	history              *pointInTimeHistory
+	tags                 map[string]string
+	replication          *replication
+	insights             map[string]*contributorInsights
+	settings             tableSettings
+	status               string
+	statusChangedAt      time.Time
 }
 
 // NewTable creates a new Table
@@ -51,9 +72,10 @@ func NewTable(name string) *Table {
 		Name:                 name,
 		Indexes:              map[string]*index{},
 		AttributesDef:        map[string]string{},
-		SortedKeys:           []string{},
 		Data:                 map[string]map[string]*types.Item{},
 		IndexActivationDelay: defaultIndexActivationDelay,
+		Clock:                SystemClock,
+		partitions:           newPartitionMap(),
 	}
 }
 
@@ -233,6 +255,7 @@ func (t *Table) deleteIndex(indexName string) error {
 	}
 
 	delete(t.Indexes, indexName)
+	delete(t.insights, indexName)
 
 	return nil
 }
@@ -324,54 +347,20 @@ func validateUpdateItemInputKeysAndValues(input *types.UpdateItemInput) error {
 	return nil
 }
 
-func (t *Table) parseStartKey(schema keySchema, startkeyAttr map[string]*types.Item) string {
-	startKey := ""
-	if len(startkeyAttr) != 0 {
-		startKey, _ = schema.GetKey(t.AttributesDef, startkeyAttr)
-	}
-
-	return startKey
-}
-
-func getPrimaryKey(index *index, k string) (string, bool) {
-	pk, ok := k, true
-
-	if index != nil {
-		pk, ok = index.getPrimaryKey(k)
-	}
-
-	return pk, ok
-}
-
-func (t *Table) fetchQueryData(input QueryInput) (*index, []string) {
-	if input.Index != "" {
-		i := t.Indexes[input.Index]
-		i.startSearch(input.ScanIndexForward)
-
-		return i, i.sortedKeys
+func (t *Table) searchIndex(input QueryInput) (*index, error) {
+	if input.Index == "" {
+		return nil, nil
 	}
 
-	return nil, t.SortedKeys
-}
-
-func prepareSearch(input *QueryInput, index *index, k, startKey string) (string, bool) {
-	pk, ok := getPrimaryKey(index, k)
+	i, ok := t.Indexes[input.Index]
 	if !ok {
-		return pk, ok
-	}
-
-	if input.started {
-		return pk, true
-	}
-
-	if pk == startKey {
-		input.started = true
+		return nil, types.NewError("ValidationException", fmt.Sprintf("The table does not have the specified index: %s", input.Index), nil)
 	}
 
-	return "", false
+	return i, nil
 }
 
-func (t *Table) getMatchedItemAndCount(input *QueryInput, pk, startKey string, idx *index) (map[string]*types.Item, map[string]*types.Item, interpreter.ExpressionType, bool, error) {
+func (t *Table) getMatchedItemAndCount(input *QueryInput, pk string, idx *index) (map[string]*types.Item, map[string]*types.Item, interpreter.ExpressionType, bool, error) {
 	storedItem, ok := t.Data[pk]
 
 	lastMatchExpressionType, matched, err := t.matchKey(*input, storedItem)
@@ -381,12 +370,14 @@ func (t *Table) getMatchedItemAndCount(input *QueryInput, pk, startKey string, i
 
 	fullCopy := copyItem(storedItem)
 
-	if !ok || !input.started || !matched {
+	if !ok || !matched {
 		return fullCopy, fullCopy, lastMatchExpressionType, false, nil
 	}
 
+	// a local index fetches the requested attributes it does not project from the table,
+	// while a global index can only return the attributes it projects
 	baseItem := storedItem
-	if idx != nil {
+	if idx != nil && (input.ProjectionExpression == "" || idx.typ == indexTypeGlobal) {
 		baseItem = idx.ProjectItem(storedItem)
 	}
 
@@ -410,12 +401,12 @@ func (t *Table) getMatchedItemAndCount(input *QueryInput, pk, startKey string, i
 	return fullCopy, fullCopy, lastMatchExpressionType, true, nil
 }
 
-func shouldReturnNextKey(item map[string]*types.Item, count, scanned, limit, keysSize int64) bool {
+func shouldReturnNextKey(item map[string]*types.Item, count, limit int64) bool {
 	if len(item) == 0 || limit == 0 {
 		return false
 	}
 
-	return scanned <= keysSize && limit <= count
+	return limit <= count
 }
 
 func shouldCountItem(expressionType interpreter.ExpressionType, matched bool) bool {
@@ -426,17 +417,8 @@ func shouldBreakPage(count, limit int64) bool {
 	return limit != 0 && limit == count
 }
 
-// GetKeyAt returns the key value in a given position
-func GetKeyAt(sortedKeys []string, size int64, pos int64, forward bool) string {
-	if !forward {
-		return sortedKeys[size-1-int64(pos)]
-	}
-
-	return sortedKeys[pos]
-}
-
 // SearchData queries the table based on the input.
-func (t *Table) SearchData(input QueryInput) ([]map[string]*types.Item, map[string]*types.Item, error) { //nolint:gocognit // query loop with paging, filters, and interpreter errors
+func (t *Table) SearchData(input QueryInput) ([]map[string]*types.Item, map[string]*types.Item, error) {
 	if err := validateSearchInputMaps(input); err != nil {
 		return nil, nil, err
 	}
@@ -445,43 +427,53 @@ func (t *Table) SearchData(input QueryInput) ([]map[string]*types.Item, map[stri
 		return nil, nil, err
 	}
 
-	items := []map[string]*types.Item{}
-	limit := input.Limit
-	exclusiveStartKey := input.ExclusiveStartKey
-	index, sortedKeys := t.fetchQueryData(input)
+	index, err := t.searchIndex(input)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	startKey := t.parseStartKey(t.KeySchema, exclusiveStartKey)
-	input.started = startKey == ""
-	last := map[string]*types.Item{}
-	sortedKeysSize := int64(len(sortedKeys))
+	ks := t.KeySchema
+	if index != nil {
+		ks = index.keySchema
+	}
 
-	forward := input.ScanIndexForward
+	input, err = withLegacySearch(input, ks, t.AttributesDef)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	var (
-		count   int64
-		scanned int64
-	)
+	segment, err := newScanSegment(input.Segment, input.TotalSegments)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	for pos := range sortedKeys {
-		k := GetKeyAt(sortedKeys, sortedKeysSize, int64(pos), forward)
+	after, err := t.startCursor(input.ExclusiveStartKey, index)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		pk, ok := prepareSearch(&input, index, k, startKey)
-		if !ok {
-			scanned++
-			continue
-		}
+	if err := segment.validateStart(after); err != nil {
+		return nil, nil, err
+	}
 
-		item, keyItem, expressionType, matched, gerr := t.getMatchedItemAndCount(&input, pk, startKey, index)
+	items := []map[string]*types.Item{}
+	limit := input.Limit
+	last := map[string]*types.Item{}
+
+	var count int64
+
+	for pk := range t.searchKeys(input, index, after, segment) {
+		item, keyItem, expressionType, matched, gerr := t.getMatchedItemAndCount(&input, pk, index)
 		if gerr != nil {
 			return nil, nil, types.NewError("ValidationException", gerr.Error(), nil)
 		}
 
+		t.recordContribution(input.Index, keyItem, false)
+
 		if matched {
 			items = append(items, item)
 		}
 
-		scanned++
-
 		if shouldCountItem(expressionType, matched) {
 			count++
 		}
@@ -493,11 +485,11 @@ func (t *Table) SearchData(input QueryInput) ([]map[string]*types.Item, map[stri
 		}
 	}
 
-	return items, t.getLastKey(last, limit, count, scanned, sortedKeysSize, index), nil
+	return items, t.getLastKey(last, limit, count, index), nil
 }
 
-func (t *Table) getLastKey(item map[string]*types.Item, limit, count, scanned, keysSize int64, index *index) map[string]*types.Item {
-	if !shouldReturnNextKey(item, count, scanned, limit, keysSize) {
+func (t *Table) getLastKey(item map[string]*types.Item, limit, count int64, index *index) map[string]*types.Item {
+	if !shouldReturnNextKey(item, count, limit) {
 		return map[string]*types.Item{}
 	}
 
@@ -591,10 +583,29 @@ func (t *Table) setItem(key string, item map[string]*types.Item) {
 	_, exists := t.Data[key]
 	t.Data[key] = item
 
-	if !exists {
-		t.SortedKeys = append(t.SortedKeys, key)
-		sort.Strings(t.SortedKeys)
+	t.trackExpiration(key, item)
+
+	if exists {
+		return
 	}
+
+	hashKey, rangeKey, err := t.KeySchema.keyParts(t.AttributesDef, item)
+	if err != nil {
+		return
+	}
+
+	t.partitions.put(hashKey, keyEntry{rangeKey: rangeKey, pk: key})
+}
+
+func (t *Table) removeItem(key string, item map[string]*types.Item) {
+	delete(t.Data, key)
+
+	hashKey, rangeKey, err := t.KeySchema.keyParts(t.AttributesDef, item)
+	if err != nil {
+		return
+	}
+
+	t.partitions.remove(hashKey, keyEntry{rangeKey: rangeKey, pk: key})
 }
 
 func (t *Table) getItem(key string) map[string]*types.Item {
@@ -606,17 +617,22 @@ func (t *Table) getItem(key string) map[string]*types.Item {
 	return item
 }
 
-// Clear removes data and sorted keys from a table
+// Clear removes data and partitions from a table
 func (t *Table) Clear() {
-	t.SortedKeys = []string{}
 	t.Data = map[string]map[string]*types.Item{}
+	t.partitions = newPartitionMap()
+	t.expirations = nil
+	t.recordHistoryClear()
 }
 
 // TableSnapshot captures a point-in-time copy of mutable table state for transactional rollback
 type TableSnapshot struct {
-	data       map[string]map[string]*types.Item
-	sortedKeys []string
-	indexes    map[string]indexSnapshot
+	data        map[string]map[string]*types.Item
+	partitions  *partitionMap
+	indexes     map[string]indexSnapshot
+	streams     streamSnapshot
+	history     int
+	replication replicationSnapshot
 }
 
 // Snapshot returns a deep copy of the table's mutable state
@@ -626,27 +642,37 @@ func (t *Table) Snapshot() TableSnapshot {
 		data[k] = deepCopyItemMap(v)
 	}
 
-	keys := make([]string, len(t.SortedKeys))
-	copy(keys, t.SortedKeys)
-
 	indexes := make(map[string]indexSnapshot, len(t.Indexes))
 	for name, idx := range t.Indexes {
 		indexes[name] = idx.snapshot()
 	}
 
-	return TableSnapshot{data: data, sortedKeys: keys, indexes: indexes}
+	snap := TableSnapshot{data: data, partitions: t.partitions.clone(), indexes: indexes, streams: t.snapshotStreams(), replication: t.snapshotReplication()}
+	if t.history != nil {
+		snap.history = len(t.history.changes)
+	}
+
+	return snap
 }
 
 // Restore replaces the table's mutable state with a previously taken snapshot
 func (t *Table) Restore(s TableSnapshot) {
 	t.Data = s.data
-	t.SortedKeys = s.sortedKeys
+	t.partitions = s.partitions
+	t.rebuildExpirations()
 
 	for name, idx := range t.Indexes {
 		if snap, ok := s.indexes[name]; ok {
 			idx.restore(snap)
 		}
 	}
+
+	t.restoreStreams(s.streams)
+	t.restoreReplication(s.replication)
+
+	if t.history != nil && s.history < len(t.history.changes) {
+		t.history.changes = t.history.changes[:s.history]
+	}
 }
 
 // Put puts items into table
@@ -666,15 +692,29 @@ func (t *Table) Put(input *types.PutItemInput) (map[string]*types.Item, error) {
 		return item, types.NewError("ValidationException", err.Error(), nil)
 	}
 
+	t.RecordAccess(input.Item)
+
+	if err := validateParameterStyle(
+		[]legacyParameter{{"ConditionalOperator", input.ConditionalOperator != nil}, {"Expected", input.Expected != nil}},
+		[]legacyParameter{{"ConditionExpression", input.ConditionExpression != nil}},
+	); err != nil {
+		return item, err
+	}
+
+	condition, err := withExpected(QueryInput{
+		Index:                     PrimaryIndexName,
+		ExpressionAttributeValues: input.ExpressionAttributeValues,
+		Aliases:                   input.ExpressionAttributeNames,
+		Limit:                     1,
+		ConditionExpression:       input.ConditionExpression,
+	}, input.Expected, input.ConditionalOperator, "ConditionExpression is null")
+	if err != nil {
+		return item, err
+	}
+
 	// support conditional writes
-	if input.ConditionExpression != nil {
-		_, matched, merr := t.matchKey(QueryInput{
-			Index:                     PrimaryIndexName,
-			ExpressionAttributeValues: input.ExpressionAttributeValues,
-			Aliases:                   input.ExpressionAttributeNames,
-			Limit:                     1,
-			ConditionExpression:       input.ConditionExpression,
-		}, t.getItem(key))
+	if condition.ConditionExpression != nil {
+		_, matched, merr := t.matchKey(condition, t.getItem(key))
 		if merr != nil {
 			return item, types.NewError("ValidationException", merr.Error(), nil)
 		}
@@ -684,6 +724,8 @@ func (t *Table) Put(input *types.PutItemInput) (map[string]*types.Item, error) {
 		}
 	}
 
+	oldItem, existed := t.Data[key]
+
 	t.setItem(key, item)
 
 	for _, index := range t.Indexes {
@@ -693,6 +735,13 @@ func (t *Table) Put(input *types.PutItemInput) (map[string]*types.Item, error) {
 		}
 	}
 
+	if !existed {
+		oldItem = nil
+	}
+
+	t.recordIndexWrites(item)
+	t.recordChange(oldItem, item)
+
 	return item, nil
 }
 
@@ -821,6 +870,36 @@ func (t *Table) Update(input *types.UpdateItemInput) (map[string]*types.Item, er
 		return nil, types.NewError("ValidationException", err.Error(), nil)
 	}
 
+	if err := validateParameterStyle(
+		[]legacyParameter{{"AttributeUpdates", input.AttributeUpdates != nil}, {"ConditionalOperator", input.ConditionalOperator != nil}, {"Expected", input.Expected != nil}},
+		[]legacyParameter{{"UpdateExpression", input.UpdateExpression != ""}, {"ConditionExpression", input.ConditionExpression != nil}},
+	); err != nil {
+		return nil, err
+	}
+
+	update := interpreter.UpdateInput{
+		TableName:  t.Name,
+		Expression: input.UpdateExpression,
+		Attributes: input.ExpressionAttributeValues,
+		Aliases:    input.ExpressionAttributeNames,
+	}
+
+	legacyUpdate := input.UpdateExpression == "" && (input.AttributeUpdates != nil || input.Expected != nil)
+	if legacyUpdate {
+		if err := validateExpressionMaps(len(input.ExpressionAttributeNames), len(input.ExpressionAttributeValues), "UpdateExpression and ConditionExpression are null"); err != nil {
+			return nil, err
+		}
+
+		tr := newLegacyTranslator()
+
+		expression, err := tr.attributeUpdates(input.AttributeUpdates, t.KeySchema)
+		if err != nil {
+			return nil, err
+		}
+
+		update.Expression, update.Aliases, update.Attributes = expression, tr.names, tr.values
+	}
+
 	if !t.KeySchema.Secondary {
 		if err := language.ValidateUpdateExpressionDoesNotTargetPrimaryKey(
 			input.UpdateExpression,
@@ -838,23 +917,28 @@ func (t *Table) Update(input *types.UpdateItemInput) (map[string]*types.Item, er
 		return nil, types.NewError("ValidationException", err.Error(), nil)
 	}
 
+	t.RecordAccess(input.Key)
+
 	item, ok := t.Data[key]
 	if !ok {
 		// it allow the use of attribute_exists to check if the item exists
 		item = map[string]*types.Item{}
 	}
 
-	// support conditional writes
-	if input.ConditionExpression != nil {
-		query := QueryInput{
-			Index:                     PrimaryIndexName,
-			ExpressionAttributeValues: input.ExpressionAttributeValues,
-			Limit:                     1,
-			ConditionExpression:       input.ConditionExpression,
-			Aliases:                   input.ExpressionAttributeNames,
-		}
+	condition, err := withExpected(QueryInput{
+		Index:                     PrimaryIndexName,
+		ExpressionAttributeValues: input.ExpressionAttributeValues,
+		Limit:                     1,
+		ConditionExpression:       input.ConditionExpression,
+		Aliases:                   input.ExpressionAttributeNames,
+	}, input.Expected, input.ConditionalOperator, "UpdateExpression and ConditionExpression are null")
+	if err != nil {
+		return nil, err
+	}
 
-		_, matched, merr := t.matchKey(query, item)
+	// support conditional writes
+	if condition.ConditionExpression != nil {
+		_, matched, merr := t.matchKey(condition, item)
 		if merr != nil {
 			return nil, types.NewError("ValidationException", merr.Error(), nil)
 		}
@@ -877,13 +961,16 @@ func (t *Table) Update(input *types.UpdateItemInput) (map[string]*types.Item, er
 
 	oldItem := deepCopyItemMap(item)
 
-	err = t.interpreterUpdate(interpreter.UpdateInput{
-		TableName:  t.Name,
-		Expression: input.UpdateExpression,
-		Item:       item,
-		Attributes: input.ExpressionAttributeValues,
-		Aliases:    input.ExpressionAttributeNames,
-	})
+	update.Item = item
+
+	switch {
+	case !legacyUpdate:
+		err = t.interpreterUpdate(update)
+	case update.Expression != "":
+		// the native interpreter does not know the expressions translated from AttributeUpdates
+		err = t.LangInterpreter.Update(update)
+	}
+
 	if err != nil {
 		return nil, types.NewError("ValidationException", err.Error(), nil)
 	}
@@ -900,6 +987,14 @@ func (t *Table) Update(input *types.UpdateItemInput) (map[string]*types.Item, er
 
 	newItem := copyItem(item)
 
+	t.recordIndexWrites(newItem)
+
+	if ok {
+		t.recordChange(oldItem, newItem)
+	} else {
+		t.recordChange(nil, newItem)
+	}
+
 	return buildUpdateItemReturnAttributes(input.ReturnValues, oldItem, newItem), nil
 }
 
@@ -922,6 +1017,15 @@ func (t *Table) Delete(input *types.DeleteItemInput) (map[string]*types.Item, er
 		return nil, types.NewError("ValidationException", err.Error(), nil)
 	}
 
+	t.RecordAccess(input.Key)
+
+	if err := validateParameterStyle(
+		[]legacyParameter{{"ConditionalOperator", input.ConditionalOperator != nil}, {"Expected", input.Expected != nil}},
+		[]legacyParameter{{"ConditionExpression", input.ConditionExpression != nil}},
+	); err != nil {
+		return nil, err
+	}
+
 	item, ok := t.Data[key]
 
 	existingForCond := item
@@ -929,22 +1033,27 @@ func (t *Table) Delete(input *types.DeleteItemInput) (map[string]*types.Item, er
 		existingForCond = map[string]*types.Item{}
 	}
 
-	if input.ConditionExpression != nil && *input.ConditionExpression != "" {
-		aliases := map[string]string{}
+	aliases := map[string]string{}
 
-		for k, v := range input.ExpressionAttributeNames {
-			if v != nil {
-				aliases[k] = *v
-			}
+	for k, v := range input.ExpressionAttributeNames {
+		if v != nil {
+			aliases[k] = *v
 		}
+	}
 
-		_, matched, merr := t.matchKey(QueryInput{
-			Index:                     PrimaryIndexName,
-			ExpressionAttributeValues: input.ExpressionAttributeValues,
-			Aliases:                   aliases,
-			Limit:                     1,
-			ConditionExpression:       input.ConditionExpression,
-		}, existingForCond)
+	condition, err := withExpected(QueryInput{
+		Index:                     PrimaryIndexName,
+		ExpressionAttributeValues: input.ExpressionAttributeValues,
+		Aliases:                   aliases,
+		Limit:                     1,
+		ConditionExpression:       input.ConditionExpression,
+	}, input.Expected, input.ConditionalOperator, "ConditionExpression is null")
+	if err != nil {
+		return nil, err
+	}
+
+	if condition.ConditionExpression != nil && *condition.ConditionExpression != "" {
+		_, matched, merr := t.matchKey(condition, existingForCond)
 		if merr != nil {
 			return nil, types.NewError("ValidationException", merr.Error(), nil)
 		}
@@ -960,16 +1069,7 @@ func (t *Table) Delete(input *types.DeleteItemInput) (map[string]*types.Item, er
 
 	item = copyItem(item)
 
-	delete(t.Data, key)
-
-	pos := sort.SearchStrings(t.SortedKeys, key)
-	if pos == len(t.SortedKeys) {
-		return item, nil
-	}
-
-	copy(t.SortedKeys[pos:], t.SortedKeys[pos+1:])
-	t.SortedKeys[len(t.SortedKeys)-1] = ""
-	t.SortedKeys = t.SortedKeys[:len(t.SortedKeys)-1]
+	t.removeItem(key, item)
 
 	for _, index := range t.Indexes {
 		err := index.delete(key, item)
@@ -978,6 +1078,9 @@ func (t *Table) Delete(input *types.DeleteItemInput) (map[string]*types.Item, er
 		}
 	}
 
+	t.recordIndexWrites(item)
+	t.recordChange(item, nil)
+
 	return item, nil
 }
 
@@ -986,13 +1089,35 @@ func (t *Table) Description(name string) *types.TableDescription {
 	// TODO: implement other fields for TableDescription
 	gsi, lsi := t.IndexesDescription()
 
-	return &types.TableDescription{
+	desc := &types.TableDescription{
 		TableName:              name,
-		ItemCount:              int64(len(t.SortedKeys)),
+		TableArn:               t.Arn,
+		TableStatus:            t.Status(),
+		ItemCount:              int64(t.partitions.len()),
 		KeySchema:              t.KeySchema.describe(),
 		GlobalSecondaryIndexes: gsi,
 		LocalSecondaryIndexes:  lsi,
 	}
+
+	if stream := t.LatestStream(); stream != nil && t.Arn != "" {
+		desc.LatestStreamArn = t.Arn + "/stream/" + stream.Label
+		desc.LatestStreamLabel = stream.Label
+	}
+
+	t.describeSettings(desc)
+
+	return desc
+}
+
+// indexArn returns the ARN of an index of the table, nil when the table has no ARN
+func (t *Table) indexArn(indexName string) *string {
+	if t.Arn == "" {
+		return nil
+	}
+
+	arn := t.Arn + "/index/" + indexName
+
+	return &arn
 }
 
 // IndexesDescription returns the description of the table indexes
@@ -1014,6 +1139,7 @@ func (t *Table) IndexesDescription() ([]types.GlobalSecondaryIndexDescription, [
 
 				gsi = append(gsi, types.GlobalSecondaryIndexDescription{
 					IndexName:   &indexName,
+					IndexArn:    t.indexArn(indexName),
 					ItemCount:   count,
 					KeySchema:   schema,
 					Projection:  index.projection,
@@ -1024,6 +1150,7 @@ func (t *Table) IndexesDescription() ([]types.GlobalSecondaryIndexDescription, [
 			{
 				lsi = append(lsi, types.LocalSecondaryIndexDescription{
 					IndexName:  &indexName,
+					IndexArn:   t.indexArn(indexName),
 					ItemCount:  count,
 					KeySchema:  schema,
 					Projection: index.projection,
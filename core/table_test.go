@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"slices"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -461,7 +462,7 @@ func TestGetKey(t *testing.T) {
 
 	k, err := newTable.KeySchema.GetKey(map[string]string{"HASH": "S", "range": "S"}, map[string]*types.Item{"range": {S: new("range")}, "HASH": {S: new("HASH")}})
 	c.NoError(err)
-	c.Equal("range.HASH", k)
+	c.Equal("range\x00HASH", k)
 
 	_, err = newTable.KeySchema.GetKey(map[string]string{"incorrect": "S", "range": "S"}, map[string]*types.Item{"range": {S: new("range")}, "HASH": {S: new("HASH")}})
 	c.EqualError(err, `Invalid attribute value type; field "HASH"`)
@@ -474,22 +475,6 @@ func TestGetKey(t *testing.T) {
 	c.NoError(err)
 }
 
-func TestGetKeys(t *testing.T) {
-	c := require.New(t)
-
-	val := GetKeyAt([]string{"gk"}, 0, 0, true)
-	c.Equal(val, "gk")
-
-	val = GetKeyAt([]string{"gk"}, 1, 0, false)
-	c.Equal(val, "gk")
-
-	val = GetKeyAt([]string{"gk"}, 0, 0, true)
-	c.Equal(val, "gk")
-
-	val = GetKeyAt([]string{"gk"}, 1, 0, false)
-	c.Equal(val, "gk")
-}
-
 func TestDeleteItem(t *testing.T) {
 	c := require.New(t)
 
@@ -604,11 +589,12 @@ func TestDeleteIndex(t *testing.T) {
 	}
 
 	index := newTable.Indexes["invert"]
-	index.sortedKeys = []string{"key1", "key2"}
-	newTable.Indexes["invert"] = index
+	c.EqualValues(3, index.count())
 
 	_, err = newTable.Delete(inp)
 	c.NoError(err)
+	c.EqualValues(2, index.count())
+	c.NotContains(index.refs, "002\x00Ivysaur")
 }
 
 func TestSearchData(t *testing.T) {
@@ -654,19 +640,15 @@ func TestSearchData(t *testing.T) {
 	queryInput.ConditionExpression = input.ConditionExpression
 	queryInput.ScanIndexForward = true
 
-	newIndex := index{
-		sortedRefs: [][2]string{{"ref1", "ref2"}},
-		sortedKeys: []string{"ref2", "ref1"},
-		refs:       map[string]string{"ref1": "ref2"},
-		Table:      newTable,
-	}
+	idx := newIndex(newTable, indexTypeGlobal, keySchema{HashKey: "id"})
+	idx.refs["ref1"] = partitionCursor{hashKey: "ref2", entry: keyEntry{pk: "ref1"}}
+	idx.partitions.put("ref2", keyEntry{pk: "ref1"})
 
 	newTable.Indexes = map[string]*index{
-		"indice": &newIndex,
+		"indice": idx,
 	}
 
 	queryInput.Index = "indice"
-	queryInput.started = true
 
 	result, lastItem, err = newTable.SearchData(queryInput)
 	c.NoError(err)
@@ -679,7 +661,8 @@ func TestSearchData(t *testing.T) {
 	c.Equal([]map[string]*types.Item{}, result)
 	c.Equal(map[string]*types.Item{}, lastItem)
 
-	newIndex.Clear()
+	idx.Clear()
+	c.Zero(idx.count())
 }
 
 func TestPut_duplicateSetValidationException(t *testing.T) {
@@ -1157,15 +1140,14 @@ func TestGetLastKey(t *testing.T) {
 		Name: "Bulbasaur",
 	})
 
-	newIndex := &index{
-		sortedRefs: [][2]string{{"ref1", "ref2"}},
-		sortedKeys: []string{"ref2", "ref1"},
-		refs:       map[string]string{"ref1": "ref2"},
-		Table:      newTable,
-	}
+	idx := newIndex(newTable, indexTypeGlobal, keySchema{HashKey: "type"})
 
-	result := newTable.getLastKey(item, 1, 1, 1, 2, newIndex)
+	result := newTable.getLastKey(item, 1, 1, idx)
 	c.Equal(item["id"], result["id"])
+	c.Equal(item["type"], result["type"])
+
+	result = newTable.getLastKey(item, 2, 1, nil)
+	c.Empty(result)
 }
 
 func TestInterpreterMatch(t *testing.T) {
@@ -1533,7 +1515,7 @@ func TestSetAttributeDefinition(t *testing.T) {
 	c.Equal(types.StringValue(newAttributesDef[0].AttributeType), newTable.AttributesDef["name"])
 }
 
-func TestFetchQueryData(t *testing.T) {
+func TestSearchKeys(t *testing.T) {
 	c := require.New(t)
 
 	newTable, err := createPokemonTable()
@@ -1597,12 +1579,20 @@ func TestFetchQueryData(t *testing.T) {
 	c.NoError(err)
 	c.Len(newTable.Data, 6)
 
-	index, sortedKeys := newTable.fetchQueryData(QueryInput{
-		Index: "invert",
-	})
+	keys := slices.Collect(newTable.searchKeys(QueryInput{}, newTable.Indexes["invert"], nil, nil))
+	c.Equal([]string{"006\x00Bellsprout", "005\x00Oddish", "004\x00Gloom", "003\x00Venusaur", "002\x00Ivysaur", "001\x00Bulbasaur"}, keys)
+
+	after, err := newTable.startCursor(primaryKeyFromPokemonItem(createPokemon(pokemon{ID: "002", Name: "Ivysaur"})), nil)
+	c.NoError(err)
+
+	keys = slices.Collect(newTable.searchKeys(QueryInput{ScanIndexForward: true}, nil, after, nil))
+	c.Equal([]string{"003\x00Venusaur", "004\x00Gloom", "005\x00Oddish", "006\x00Bellsprout"}, keys)
 
-	c.NotEmpty(sortedKeys)
-	c.Equal(index.sortedRefs[0][0], "006.Bellsprout")
+	_, err = newTable.startCursor(map[string]*types.Item{"id": {S: new("002")}}, nil)
+	c.EqualError(err, `ValidationException: The provided starting key is invalid: One of the required keys was not given a value; field: "name"`)
+
+	_, err = newTable.startCursor(map[string]*types.Item{"name": {S: new("Ivysaur")}}, newTable.Indexes["invert"])
+	c.Error(err)
 }
 
 func TestSearchDataWithProjectionExpression(t *testing.T) {
@@ -1768,6 +1758,6 @@ func TestRestore(t *testing.T) {
 	table.Restore(snap)
 
 	c.Len(table.Data, 1)
-	c.Contains(table.Data, "001.Bulbasaur")
-	c.NotContains(table.Data, "004.Charmander")
+	c.Contains(table.Data, "001\x00Bulbasaur")
+	c.NotContains(table.Data, "004\x00Charmander")
 }
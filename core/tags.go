@@ -0,0 +1,106 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	maxTags           = 50
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+	reservedTagPrefix = "aws:"
+)
+
+// Tag is a key-value pair attached to a table
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// TagResource adds the tags to the table, replacing the values of the keys it already
+// has. Like DynamoDB, nothing is changed when a tag is invalid or the table would end
+// up with more than 50 tags
+func (t *Table) TagResource(tags []Tag) error {
+	if len(tags) == 0 {
+		return types.NewError("ValidationException", "1 validation error detected: Value null at 'tags' failed to satisfy constraint: Member must not be null", nil)
+	}
+
+	merged := maps.Clone(t.tags)
+	if merged == nil {
+		merged = map[string]string{}
+	}
+
+	for i, tag := range tags {
+		if err := validateTagKey(tag.Key, fmt.Sprintf("tags.%d.member.key", i+1)); err != nil {
+			return err
+		}
+
+		if utf8.RuneCountInString(tag.Value) > maxTagValueLength {
+			return tagLengthError(tag.Value, fmt.Sprintf("tags.%d.member.value", i+1), "less than or equal to 256")
+		}
+
+		merged[tag.Key] = tag.Value
+	}
+
+	if len(merged) > maxTags {
+		return types.NewError("ValidationException", fmt.Sprintf("One or more parameter values were invalid: Too many tags, a resource can have at most %d tags", maxTags), nil)
+	}
+
+	t.tags = merged
+
+	return nil
+}
+
+// UntagResource removes the tags with the given keys from the table, keys the table
+// does not have are ignored
+func (t *Table) UntagResource(keys []string) error {
+	if len(keys) == 0 {
+		return types.NewError("ValidationException", "1 validation error detected: Value null at 'tagKeys' failed to satisfy constraint: Member must not be null", nil)
+	}
+
+	for i, key := range keys {
+		if err := validateTagKey(key, fmt.Sprintf("tagKeys.%d.member", i+1)); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range keys {
+		delete(t.tags, key)
+	}
+
+	return nil
+}
+
+// Tags returns the tags of the table sorted by key
+func (t *Table) Tags() []Tag {
+	tags := make([]Tag, 0, len(t.tags))
+
+	for _, key := range slices.Sorted(maps.Keys(t.tags)) {
+		tags = append(tags, Tag{Key: key, Value: t.tags[key]})
+	}
+
+	return tags
+}
+
+func validateTagKey(key, field string) error {
+	switch length := utf8.RuneCountInString(key); {
+	case length < 1:
+		return tagLengthError(key, field, "greater than or equal to 1")
+	case length > maxTagKeyLength:
+		return tagLengthError(key, field, "less than or equal to 128")
+	case strings.HasPrefix(strings.ToLower(key), reservedTagPrefix):
+		return types.NewError("ValidationException", "One or more parameter values were invalid: Tag keys starting with 'aws:' are reserved for system use", nil)
+	}
+
+	return nil
+}
+
+func tagLengthError(value, field, constraint string) error {
+	return types.NewError("ValidationException", fmt.Sprintf("1 validation error detected: Value '%s' at '%s' failed to satisfy constraint: Member must have length %s", value, field, constraint), nil)
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestTags(t *testing.T) {
+	c := require.New(t)
+
+	table := NewTable("pokemons")
+	c.Empty(table.Tags())
+
+	c.NoError(table.TagResource([]Tag{{Key: "team", Value: "rocket"}, {Key: "env", Value: ""}}))
+	c.NoError(table.TagResource([]Tag{{Key: "team", Value: "magma"}}))
+	c.Equal([]Tag{{Key: "env", Value: ""}, {Key: "team", Value: "magma"}}, table.Tags())
+
+	c.ErrorContains(table.TagResource(nil), "'tags'")
+	c.ErrorContains(table.TagResource([]Tag{{Key: ""}}), "tags.1.member.key")
+	c.ErrorContains(table.TagResource([]Tag{{Key: "ok"}, {Key: strings.Repeat("k", 129)}}), "tags.2.member.key")
+	c.ErrorContains(table.TagResource([]Tag{{Key: "ok", Value: strings.Repeat("v", 257)}}), "less than or equal to 256")
+	c.ErrorContains(table.TagResource([]Tag{{Key: "AWS:cost"}}), "reserved")
+	c.Len(table.Tags(), 2)
+
+	tags := []Tag{}
+	for i := range 49 {
+		tags = append(tags, Tag{Key: fmt.Sprintf("key-%d", i)})
+	}
+
+	c.ErrorContains(table.TagResource(tags), "at most 50 tags")
+	c.Len(table.Tags(), 2)
+
+	c.NoError(table.UntagResource([]string{"team", "missing"}))
+	c.Equal([]Tag{{Key: "env", Value: ""}}, table.Tags())
+	c.ErrorContains(table.UntagResource([]string{"aws:cost"}), "reserved")
+	c.ErrorContains(table.UntagResource(nil), "'tagKeys'")
+}
+
+func TestDescriptionArns(t *testing.T) {
+	c := require.New(t)
+
+	table := newImportTestTable()
+	c.Empty(table.Description("pokemons").TableArn)
+
+	table.Arn = "arn:aws:dynamodb:eu-west-1:123456789012:table/pokemons"
+	table.AttributesDef["type"] = "S"
+	table.BillingMode = new("PAY_PER_REQUEST")
+	c.NoError(table.AddGlobalIndexes([]*types.GlobalSecondaryIndex{{
+		IndexName:  new("by-type"),
+		KeySchema:  []*types.KeySchemaElement{{AttributeName: "type", KeyType: "HASH"}},
+		Projection: &types.Projection{ProjectionType: new("ALL")},
+	}}))
+
+	_, err := table.EnableStream("NEW_IMAGE", table.Clock.Now())
+	c.NoError(err)
+
+	desc := table.Description("pokemons")
+	c.Equal(table.Arn, desc.TableArn)
+	c.Equal(table.Arn+"/index/by-type", types.StringValue(desc.GlobalSecondaryIndexes[0].IndexArn))
+	c.True(strings.HasPrefix(desc.LatestStreamArn, table.Arn+"/stream/"))
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	// TimeToLiveStatusEnabled is the status of a table with Time To Live enabled
+	TimeToLiveStatusEnabled = "ENABLED"
+	// TimeToLiveStatusDisabled is the status of a table without Time To Live
+	TimeToLiveStatusDisabled = "DISABLED"
+)
+
+// UpdateTimeToLive enables or disables the expiration of the items of the table using
+// the epoch seconds stored in attributeName
+func (t *Table) UpdateTimeToLive(enabled bool, attributeName string) error {
+	if attributeName == "" {
+		return types.NewError("ValidationException", "1 validation error detected: Value null at 'timeToLiveSpecification.attributeName' failed to satisfy constraint: Member must not be null", nil)
+	}
+
+	if enabled {
+		if t.ttlAttribute != "" {
+			return types.NewError("ValidationException", "TimeToLive is already enabled", nil)
+		}
+
+		t.setTimeToLiveAttribute(attributeName)
+
+		return nil
+	}
+
+	if t.ttlAttribute == "" {
+		return types.NewError("ValidationException", "TimeToLive is already disabled", nil)
+	}
+
+	if t.ttlAttribute != attributeName {
+		return types.NewError("ValidationException", "TimeToLive is active on a different AttributeName: current AttributeName is "+t.ttlAttribute, nil)
+	}
+
+	t.setTimeToLiveAttribute("")
+
+	return nil
+}
+
+func (t *Table) setTimeToLiveAttribute(attributeName string) {
+	t.ttlAttribute = attributeName
+	t.rebuildExpirations()
+}
+
+// TimeToLive returns the Time To Live attribute of the table and its status
+func (t *Table) TimeToLive() (string, string) {
+	if t.ttlAttribute == "" {
+		return "", TimeToLiveStatusDisabled
+	}
+
+	return t.ttlAttribute, TimeToLiveStatusEnabled
+}
+
+// SweepExpired deletes the items whose Time To Live attribute is before now and
+// returns how many were deleted. Removals are recorded as service initiated stream
+// records. Items without the attribute, or with a non numeric one, never expire. Only
+// the items due are visited, so sweeping before every operation stays cheap.
+func (t *Table) SweepExpired(now time.Time) int {
+	if t.ttlAttribute == "" {
+		return 0
+	}
+
+	expired := slices.Sorted(maps.Keys(t.popExpired(types.NewDecimalFromInt(now.Unix()))))
+
+	for _, key := range expired {
+		item := t.Data[key]
+
+		t.removeItem(key, item)
+
+		for _, index := range t.Indexes {
+			// the item was indexed with the same key so it can always be removed
+			_ = index.delete(key, item)
+		}
+
+		t.recordChangeBy(item, nil, true)
+	}
+
+	return len(expired)
+}
+
+// expiresAt returns the Time To Live of the item, it reports false when the item does not
+// have a numeric Time To Live attribute
+func (t *Table) expiresAt(item map[string]*types.Item) (types.Decimal, bool) {
+	if t.ttlAttribute == "" {
+		return types.Decimal{}, false
+	}
+
+	attr, ok := item[t.ttlAttribute]
+	if !ok || attr.N == nil {
+		return types.Decimal{}, false
+	}
+
+	expiresAt, err := types.ParseDecimal(*attr.N)
+	if err != nil {
+		return types.Decimal{}, false
+	}
+
+	return expiresAt, true
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func TestUpdateTimeToLive(t *testing.T) {
+	c := require.New(t)
+
+	table := NewTable("sessions")
+
+	attr, status := table.TimeToLive()
+	c.Empty(attr)
+	c.Equal(TimeToLiveStatusDisabled, status)
+
+	c.Error(table.UpdateTimeToLive(true, ""))
+	c.NoError(table.UpdateTimeToLive(true, "expires_at"))
+
+	attr, status = table.TimeToLive()
+	c.Equal("expires_at", attr)
+	c.Equal(TimeToLiveStatusEnabled, status)
+
+	err := table.UpdateTimeToLive(true, "expires_at")
+	c.EqualError(err, "ValidationException: TimeToLive is already enabled")
+
+	c.Error(table.UpdateTimeToLive(false, "other"))
+	c.NoError(table.UpdateTimeToLive(false, "expires_at"))
+
+	err = table.UpdateTimeToLive(false, "expires_at")
+	c.EqualError(err, "ValidationException: TimeToLive is already disabled")
+}
+
+func TestSweepExpired(t *testing.T) {
+	c := require.New(t)
+
+	table, stream := createStreamTable(t, StreamViewTypeOldImage)
+	table.AttributesDef["status"] = "S"
+
+	c.NoError(table.AddGlobalIndexes([]*types.GlobalSecondaryIndex{{
+		IndexName:             new("by-status"),
+		KeySchema:             []*types.KeySchemaElement{{AttributeName: "status", KeyType: "HASH"}},
+		Projection:            &types.Projection{ProjectionType: new("ALL")},
+		ProvisionedThroughput: &types.ProvisionedThroughput{ReadCapacityUnits: 1, WriteCapacityUnits: 1},
+	}}))
+
+	now := time.Unix(1_700_000_000, 0)
+
+	for id, expiresAt := range map[string]string{"expired": "1699999999", "fresh": "1700000001", "text": "", "decimal": "1699999999.5", "precise": "1699999999.9999999999999999999999999999"} {
+		item := map[string]*types.Item{"id": {S: new(id)}, "status": {S: new("open")}}
+		if id == "text" {
+			item["expires_at"] = &types.Item{S: new("1600000000")}
+		} else {
+			item["expires_at"] = &types.Item{N: new(expiresAt)}
+		}
+
+		_, err := table.Put(&types.PutItemInput{Item: item})
+		c.NoError(err)
+	}
+
+	c.Zero(table.SweepExpired(now), "TTL is disabled")
+	c.NoError(table.UpdateTimeToLive(true, "expires_at"))
+
+	// the expiration keeps the 38 digits of precision of numbers
+	c.Equal(3, table.SweepExpired(now))
+	remaining := []string{}
+	for _, item := range table.Data {
+		remaining = append(remaining, *item["id"].S)
+	}
+
+	c.ElementsMatch([]string{"fresh", "text"}, remaining)
+	c.Equal(int64(2), table.Indexes["by-status"].count())
+
+	records := stream.Records()
+	c.Len(records, 8)
+
+	for _, r := range records[5:] {
+		c.Equal(StreamEventRemove, r.EventName)
+		c.True(r.ServiceInitiated)
+		c.NotNil(r.OldImage)
+	}
+
+	c.Equal("decimal", *records[5].Keys["id"].S)
+	c.Equal("expired", *records[6].Keys["id"].S)
+	c.Equal("precise", *records[7].Keys["id"].S)
+	c.False(records[0].ServiceInitiated)
+
+	c.Zero(table.SweepExpired(now))
+	c.Equal(1, table.SweepExpired(now.Add(2*time.Second)))
+}
@@ -2,6 +2,7 @@ package e2e
 
 import (
 	"context"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -269,6 +270,105 @@ func TestE2E_QueryBatch(t *testing.T) {
 				return normalizeSDKErrorString(err.Error())
 			},
 		},
+		{
+			name: "QueryNumericAndBinaryRangeKeys",
+			fn: func(t *testing.T, client *dynamodb.Client) any {
+				t.Helper()
+				ctx := context.Background()
+
+				table := "e2e_readings"
+
+				_, err := client.CreateTable(ctx, &dynamodb.CreateTableInput{
+					AttributeDefinitions: []dynamodbtypes.AttributeDefinition{
+						{AttributeName: aws.String("sensor"), AttributeType: dynamodbtypes.ScalarAttributeTypeS},
+						{AttributeName: aws.String("seq"), AttributeType: dynamodbtypes.ScalarAttributeTypeN},
+						{AttributeName: aws.String("payload"), AttributeType: dynamodbtypes.ScalarAttributeTypeB},
+					},
+					BillingMode: dynamodbtypes.BillingModePayPerRequest,
+					KeySchema: []dynamodbtypes.KeySchemaElement{
+						{AttributeName: aws.String("sensor"), KeyType: dynamodbtypes.KeyTypeHash},
+						{AttributeName: aws.String("seq"), KeyType: dynamodbtypes.KeyTypeRange},
+					},
+					LocalSecondaryIndexes: []dynamodbtypes.LocalSecondaryIndex{
+						{
+							IndexName: aws.String("by-payload"),
+							KeySchema: []dynamodbtypes.KeySchemaElement{
+								{AttributeName: aws.String("sensor"), KeyType: dynamodbtypes.KeyTypeHash},
+								{AttributeName: aws.String("payload"), KeyType: dynamodbtypes.KeyTypeRange},
+							},
+							Projection: &dynamodbtypes.Projection{ProjectionType: dynamodbtypes.ProjectionTypeAll},
+						},
+					},
+					TableName: aws.String(table),
+				})
+				require.NoError(t, err)
+
+				readings := []struct {
+					seq     string
+					payload []byte
+				}{
+					{"10", []byte{0x80}},
+					{"9", []byte{0x7f}},
+					{"-3.5", []byte{0xff}},
+					{"100", []byte{0x00, 0x01}},
+					{"0", []byte{0x01}},
+					{"0.001", []byte{0x00}},
+				}
+
+				for _, r := range readings {
+					_, err = client.PutItem(ctx, &dynamodb.PutItemInput{
+						TableName: aws.String(table),
+						Item: map[string]dynamodbtypes.AttributeValue{
+							"sensor":  &dynamodbtypes.AttributeValueMemberS{Value: "s1"},
+							"seq":     &dynamodbtypes.AttributeValueMemberN{Value: r.seq},
+							"payload": &dynamodbtypes.AttributeValueMemberB{Value: r.payload},
+						},
+					})
+					require.NoError(t, err)
+				}
+
+				query := func(index, expr string, forward bool, values map[string]dynamodbtypes.AttributeValue) []string {
+					values[":s"] = &dynamodbtypes.AttributeValueMemberS{Value: "s1"}
+
+					input := &dynamodb.QueryInput{
+						TableName:                 aws.String(table),
+						KeyConditionExpression:    aws.String(expr),
+						ExpressionAttributeValues: values,
+						ScanIndexForward:          aws.Bool(forward),
+					}
+					if index != "" {
+						input.IndexName = aws.String(index)
+					}
+
+					out, err := client.Query(ctx, input)
+					require.NoError(t, err)
+
+					seqs := make([]string, 0, len(out.Items))
+					for _, item := range out.Items {
+						seqs = append(seqs, item["seq"].(*dynamodbtypes.AttributeValueMemberN).Value)
+					}
+
+					return seqs
+				}
+
+				return [][]string{
+					query("", "sensor = :s", true, map[string]dynamodbtypes.AttributeValue{}),
+					query("", "sensor = :s", false, map[string]dynamodbtypes.AttributeValue{}),
+					query("", "sensor = :s AND seq BETWEEN :a AND :b", true, map[string]dynamodbtypes.AttributeValue{
+						":a": &dynamodbtypes.AttributeValueMemberN{Value: "2"},
+						":b": &dynamodbtypes.AttributeValueMemberN{Value: "10.0"},
+					}),
+					query("", "sensor = :s AND seq = :a", true, map[string]dynamodbtypes.AttributeValue{
+						":a": &dynamodbtypes.AttributeValueMemberN{Value: "1E1"},
+					}),
+					query("by-payload", "sensor = :s", true, map[string]dynamodbtypes.AttributeValue{}),
+					query("by-payload", "sensor = :s", false, map[string]dynamodbtypes.AttributeValue{}),
+					query("by-payload", "sensor = :s AND begins_with(payload, :p)", true, map[string]dynamodbtypes.AttributeValue{
+						":p": &dynamodbtypes.AttributeValueMemberB{Value: []byte{0x00}},
+					}),
+				}
+			},
+		},
 		{
 			name: "Scan",
 			fn: func(t *testing.T, client *dynamodb.Client) any {
@@ -454,6 +554,140 @@ func TestE2E_QueryBatch(t *testing.T) {
 				return lines
 			},
 		},
+		{
+			name: "ExecuteStatementSelect",
+			fn: func(t *testing.T, client *dynamodb.Client) any {
+				t.Helper()
+				ctx := context.Background()
+
+				parityCreatePokemonTable(ctx, t, client)
+				parityCreatePokemon(ctx, t, client, parityPokemon{ID: "001", Type: "grass", Name: "Bulbasaur", Level: 5})
+				parityCreatePokemon(ctx, t, client, parityPokemon{ID: "002", Type: "grass", Name: "Ivysaur", Level: 16})
+				parityCreatePokemon(ctx, t, client, parityPokemon{ID: "004", Type: "fire", Name: "Charmander", Level: 5})
+
+				out, err := client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+					Statement:  aws.String(`SELECT name FROM "pokemons" WHERE id = ?`),
+					Parameters: []dynamodbtypes.AttributeValue{&dynamodbtypes.AttributeValueMemberS{Value: "004"}},
+				})
+				require.NoError(t, err)
+
+				results := []any{out.Items}
+
+				var ids []string
+
+				input := &dynamodb.ExecuteStatementInput{
+					Statement: aws.String(`SELECT id FROM "pokemons" WHERE "type" = 'grass' AND lvl > 1`),
+					Limit:     aws.Int32(1),
+				}
+
+				for {
+					page, err := client.ExecuteStatement(ctx, input)
+					require.NoError(t, err)
+
+					for _, item := range page.Items {
+						ids = append(ids, parityAttrValueString(item["id"]))
+					}
+
+					if page.NextToken == nil {
+						break
+					}
+
+					input.NextToken = page.NextToken
+				}
+
+				sort.Strings(ids)
+
+				return append(results, ids)
+			},
+		},
+		{
+			name: "ExecuteStatementDML",
+			fn: func(t *testing.T, client *dynamodb.Client) any {
+				t.Helper()
+				ctx := context.Background()
+
+				parityCreatePokemonTable(ctx, t, client)
+
+				_, err := client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+					Statement: aws.String(`INSERT INTO "pokemons" VALUE {'id': '025', 'type': 'electric', 'name': 'Pikachu', 'lvl': 5}`),
+				})
+				require.NoError(t, err)
+
+				_, err = client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+					Statement: aws.String(`INSERT INTO "pokemons" VALUE {'id': '025', 'type': 'electric'}`),
+				})
+
+				var duplicate *dynamodbtypes.DuplicateItemException
+				require.ErrorAs(t, err, &duplicate)
+
+				updated, err := client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+					Statement: aws.String(`UPDATE "pokemons" SET lvl = lvl + 1 REMOVE name WHERE id = '025' RETURNING ALL NEW *`),
+				})
+				require.NoError(t, err)
+
+				deleted, err := client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+					Statement: aws.String(`DELETE FROM "pokemons" WHERE id = '025' RETURNING ALL OLD *`),
+				})
+				require.NoError(t, err)
+
+				return []any{updated.Items, deleted.Items}
+			},
+		},
+		{
+			name: "BatchExecuteStatementAndExecuteTransaction",
+			fn: func(t *testing.T, client *dynamodb.Client) any {
+				t.Helper()
+				ctx := context.Background()
+
+				parityCreatePokemonTable(ctx, t, client)
+				parityCreatePokemon(ctx, t, client, parityPokemon{ID: "001", Type: "grass", Name: "Bulbasaur", Level: 5})
+
+				batch, err := client.BatchExecuteStatement(ctx, &dynamodb.BatchExecuteStatementInput{
+					Statements: []dynamodbtypes.BatchStatementRequest{
+						{Statement: aws.String(`INSERT INTO "pokemons" VALUE {'id': '004', 'type': 'fire', 'name': 'Charmander'}`)},
+						{Statement: aws.String(`INSERT INTO "pokemons" VALUE {'id': '001', 'type': 'grass', 'name': 'Bulbasaur'}`)},
+					},
+				})
+				require.NoError(t, err)
+
+				codes := make([]string, len(batch.Responses))
+				for i, resp := range batch.Responses {
+					if resp.Error != nil {
+						codes[i] = string(resp.Error.Code)
+					}
+				}
+
+				_, err = client.ExecuteTransaction(ctx, &dynamodb.ExecuteTransactionInput{
+					TransactStatements: []dynamodbtypes.ParameterizedStatement{
+						{Statement: aws.String(`UPDATE "pokemons" SET lvl = 6 WHERE id = '001'`)},
+						{Statement: aws.String(`EXISTS(SELECT * FROM "pokemons" WHERE id = '007')`)},
+					},
+				})
+
+				var canceled *dynamodbtypes.TransactionCanceledException
+				require.ErrorAs(t, err, &canceled)
+
+				reasons := make([]string, len(canceled.CancellationReasons))
+				for i, reason := range canceled.CancellationReasons {
+					reasons[i] = aws.ToString(reason.Code)
+				}
+
+				read, err := client.ExecuteTransaction(ctx, &dynamodb.ExecuteTransactionInput{
+					TransactStatements: []dynamodbtypes.ParameterizedStatement{
+						{Statement: aws.String(`SELECT lvl FROM "pokemons" WHERE id = '001'`)},
+						{Statement: aws.String(`SELECT name FROM "pokemons" WHERE id = '004'`)},
+					},
+				})
+				require.NoError(t, err)
+
+				items := make([]map[string]dynamodbtypes.AttributeValue, len(read.Responses))
+				for i, resp := range read.Responses {
+					items[i] = resp.Item
+				}
+
+				return []any{codes, reasons, items}
+			},
+		},
 	}
 
 	for _, tt := range tests {
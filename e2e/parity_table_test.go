@@ -257,6 +257,42 @@ func TestE2E_Table(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "ListTables",
+			fn: func(t *testing.T, client *dynamodb.Client) any {
+				t.Helper()
+				ctx := context.Background()
+
+				for _, name := range []string{"list-trainers", "list-badges", "list-pokemons", "list-gyms", "list-moves"} {
+					_, err := client.CreateTable(ctx, &dynamodb.CreateTableInput{
+						AttributeDefinitions: []dynamodbtypes.AttributeDefinition{
+							{AttributeName: aws.String("id"), AttributeType: dynamodbtypes.ScalarAttributeTypeS},
+						},
+						BillingMode: dynamodbtypes.BillingModePayPerRequest,
+						KeySchema: []dynamodbtypes.KeySchemaElement{
+							{AttributeName: aws.String("id"), KeyType: dynamodbtypes.KeyTypeHash},
+						},
+						TableName: aws.String(name),
+					})
+					require.NoError(t, err)
+				}
+
+				var pages []any
+
+				paginator := dynamodb.NewListTablesPaginator(client, &dynamodb.ListTablesInput{Limit: aws.Int32(2)})
+				for paginator.HasMorePages() {
+					page, err := paginator.NextPage(ctx)
+					require.NoError(t, err)
+
+					pages = append(pages, page.TableNames, aws.ToString(page.LastEvaluatedTableName))
+				}
+
+				out, err := client.ListTables(ctx, &dynamodb.ListTablesInput{ExclusiveStartTableName: aws.String("list-h")})
+				require.NoError(t, err)
+
+				return append(pages, out.TableNames)
+			},
+		},
 		{
 			name: "DescribeTableNotFound",
 			fn: func(t *testing.T, client *dynamodb.Client) any {
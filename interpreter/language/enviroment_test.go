@@ -10,7 +10,7 @@ func TestString(t *testing.T) {
 	env := NewEnvironment()
 
 	env.Set("foo", &String{Value: "blee"})
-	env.Set("bar", &Number{Value: 10})
+	env.Set("bar", &Number{Value: decimal("10")})
 
 	if env.String() != "{bar => 10,foo => blee}" {
 		t.Errorf("unexpected value. got=%v, want=%v", env.String(), "{bar => 10,foo => blee}")
@@ -26,7 +26,7 @@ func TestApply(t *testing.T) {
 
 	env := NewEnvironment()
 	env.Set(":fu", &String{Value: "blee"})
-	env.Set("bar", &Number{Value: 10})
+	env.Set("bar", &Number{Value: decimal("10")})
 
 	env.Apply(item, map[string]string{":fu": "foo"}, map[string]bool{"bar": true})
 
@@ -10,13 +10,14 @@ import (
 // Environment represents the execution enviroment
 type Environment struct {
 	store     map[string]Object
+	removed   map[string]bool
 	Aliases   map[string]string
 	toCompact []Object
 }
 
 // NewEnvironment creates a new enviroment
 func NewEnvironment() *Environment {
-	return &Environment{store: map[string]Object{}, Aliases: map[string]string{}, toCompact: []Object{}}
+	return &Environment{store: map[string]Object{}, removed: map[string]bool{}, Aliases: map[string]string{}, toCompact: []Object{}}
 }
 
 // AddAttributes adds the types attributes to the environment
@@ -115,6 +116,7 @@ func (e *Environment) Set(name string, val Object) Object {
 	}
 
 	e.store[n] = val
+	delete(e.removed, n)
 
 	return val
 }
@@ -130,6 +132,7 @@ func (e *Environment) Remove(name string) {
 	_, ok := e.store[n]
 	if ok {
 		delete(e.store, n)
+		e.removed[n] = true
 
 		return
 	}
@@ -166,6 +169,10 @@ func (e *Environment) Apply(item map[string]*types.Item, aliases map[string]stri
 		vItem := v.ToDynamoDB()
 		item[k] = &vItem
 	}
+
+	for k := range e.removed {
+		delete(item, k)
+	}
 }
 
 // String returns a string representation of the environment
@@ -278,22 +278,21 @@ func evalNumberInfixExpression(operator string, left, right Object) Object {
 		return newError("right operand is not a number: %s", right.Type())
 	}
 
-	leftVal := leftNum.Value
-	rightVal := rightNum.Value
+	cmp := leftNum.Value.Cmp(rightNum.Value)
 
 	switch operator {
 	case "<":
-		return nativeBoolToBooleanObject(leftVal < rightVal)
+		return nativeBoolToBooleanObject(cmp < 0)
 	case "<=":
-		return nativeBoolToBooleanObject(leftVal <= rightVal)
+		return nativeBoolToBooleanObject(cmp <= 0)
 	case ">":
-		return nativeBoolToBooleanObject(leftVal > rightVal)
+		return nativeBoolToBooleanObject(cmp > 0)
 	case ">=":
-		return nativeBoolToBooleanObject(leftVal >= rightVal)
+		return nativeBoolToBooleanObject(cmp >= 0)
 	case "=":
-		return nativeBoolToBooleanObject(leftVal == rightVal)
+		return nativeBoolToBooleanObject(cmp == 0)
 	case "<>":
-		return nativeBoolToBooleanObject(leftVal != rightVal)
+		return nativeBoolToBooleanObject(cmp != 0)
 	default:
 		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
@@ -673,7 +672,12 @@ func evalListIndexValue(node *Identifier, env *Environment) (int64, Object) {
 		return 0, newError("access index with [] only support N as index : got %q", obj.Type())
 	}
 
-	return int64(number.Value), nil
+	idx, ok := number.Value.Int64()
+	if !ok {
+		return 0, newError("access index with [] is out of range: %s", number.Inspect())
+	}
+
+	return idx, nil
 }
 
 func evalMapIndexValue(node *Identifier, env *Environment) (string, Object) {
@@ -973,8 +977,8 @@ func evalActionDelete(node *ActionExpression, env *Environment) Object {
 			return obj
 		}
 
+		// there is nothing to delete from a missing attribute
 		if obj == UNDEFINED {
-			env.Set(id.Value, val)
 			return obj
 		}
 
@@ -983,7 +987,17 @@ func evalActionDelete(node *ActionExpression, env *Environment) Object {
 			return newError("An operand in the update expression has an incorrect data type")
 		}
 
-		return addObj.Delete(val)
+		result := addObj.Delete(val)
+		if isError(result) {
+			return result
+		}
+
+		// sets cannot be empty, deleting every element removes the attribute
+		if sizable, ok := obj.(SizableObject); ok && sizable.Size() == 0 {
+			env.Remove(id.Value)
+		}
+
+		return result
 	}
 
 	return UNDEFINED
@@ -1056,14 +1070,24 @@ func evalInfixUpdate(node *InfixExpression, env *Environment) Object {
 			return errObj
 		}
 
-		return &Number{Value: augend.Value + addend.Value}
+		sum, err := augend.Value.Add(addend.Value)
+		if err != nil {
+			return newError("%s", err.Error())
+		}
+
+		return &Number{Value: sum}
 	case "-":
 		minuend, subtrahend, errObj := evalArithmeticTerms(node, env)
 		if isError(errObj) {
 			return errObj
 		}
 
-		return &Number{Value: minuend.Value - subtrahend.Value}
+		difference, err := minuend.Value.Sub(subtrahend.Value)
+		if err != nil {
+			return newError("%s", err.Error())
+		}
+
+		return &Number{Value: difference}
 	}
 
 	return newError("unknown operator: %s", node.Operator)
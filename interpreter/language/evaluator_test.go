@@ -396,65 +396,65 @@ func TestEvalSetUpdate(t *testing.T) {
 	}{
 		{"SET :x = :val", ":x", &String{Value: "text"}, true},
 		{"SET :w = :val", ":w", &String{Value: "text"}, true},
-		{"SET :two = :one + :one", ":two", &Number{Value: 2}, true},
-		{"SET :zero = :one - :one", ":zero", &Number{Value: 0}, true},
-		{"SET :zero = :one - :one", ":zero", &Number{Value: 0}, true},
-		{"SET :newTwo = if_not_exists(not_found, :one) + :one", ":newTwo", &Number{Value: 2}, true},
-		{"SET :three = if_not_exists(:two, :one) + :one", ":three", &Number{Value: 3}, true},
-		{"SET :list[1] = :one", ":list", &List{Value: []Object{&Number{Value: 0}, &Number{Value: 1}}}, true},
-		{"SET :list[0] = :one", ":list", &List{Value: []Object{&Number{Value: 1}, &Number{Value: 1}}}, true},
+		{"SET :two = :one + :one", ":two", &Number{Value: decimal("2")}, true},
+		{"SET :zero = :one - :one", ":zero", &Number{Value: decimal("0")}, true},
+		{"SET :zero = :one - :one", ":zero", &Number{Value: decimal("0")}, true},
+		{"SET :newTwo = if_not_exists(not_found, :one) + :one", ":newTwo", &Number{Value: decimal("2")}, true},
+		{"SET :three = if_not_exists(:two, :one) + :one", ":three", &Number{Value: decimal("3")}, true},
+		{"SET :list[1] = :one", ":list", &List{Value: []Object{&Number{Value: decimal("0")}, &Number{Value: decimal("1")}}}, true},
+		{"SET :list[0] = :one", ":list", &List{Value: []Object{&Number{Value: decimal("1")}, &Number{Value: decimal("1")}}}, true},
 		{
 			"SET :matrix[0][0] = :one",
 			":matrix",
-			&List{Value: []Object{&List{Value: []Object{&Number{Value: 1}}}}},
+			&List{Value: []Object{&List{Value: []Object{&Number{Value: decimal("1")}}}}},
 			false,
 		},
 		{
 			"SET :hash.a = :one",
 			":hash",
-			&Map{Value: map[string]Object{"a": &Number{Value: 1}}},
+			&Map{Value: map[string]Object{"a": &Number{Value: decimal("1")}}},
 			false,
 		},
 		{
 			"SET :two = if_not_exists(:hash.not_found, :one) + :one",
 			":two",
-			&Number{Value: 2},
+			&Number{Value: decimal("2")},
 			false,
 		},
 		{
 			"SET :all = list_append(if_not_exists(:all, :list), :tools)",
 			":all",
-			&List{Value: []Object{&Number{Value: 0}, &String{Value: "Chisel"}, &String{Value: "Hammer"}, &String{Value: "Nails"}, &String{Value: "Screwdriver"}, &String{Value: "Hacksaw"}}},
+			&List{Value: []Object{&Number{Value: decimal("0")}, &String{Value: "Chisel"}, &String{Value: "Hammer"}, &String{Value: "Nails"}, &String{Value: "Screwdriver"}, &String{Value: "Hacksaw"}}},
 			false,
 		},
 		{
 			"SET :nestedMap.lvl1.lvl2 = :nestedMap.lvl1.lvl2 + :one",
 			":nestedMap",
-			&Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"lvl2": &Number{Value: 1}}}}},
+			&Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"lvl2": &Number{Value: decimal("1")}}}}},
 			false,
 		},
 		{
 			"SET :nestedMap.#pos = #pos + :one",
 			":nestedMap",
-			&Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"lvl2": &Number{Value: 0}}}, ":nestedMap.lvl1.lvl2": &Number{Value: 1}}},
+			&Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"lvl2": &Number{Value: decimal("0")}}}, ":nestedMap.lvl1.lvl2": &Number{Value: decimal("1")}}},
 			false,
 		},
 		{
 			"SET :nestedMap.#secondLevel = #pos + :one",
 			":nestedMap",
-			&Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"lvl2": &Number{Value: 0}}}, "lvl1.lvl2": &Number{Value: 1}}},
+			&Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"lvl2": &Number{Value: decimal("0")}}}, "lvl1.lvl2": &Number{Value: decimal("1")}}},
 			false,
 		},
 		{
 			"SET :nestedMap.lvl1.#nested_map_attr = :one",
 			":nestedMap",
-			&Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"random_field_name": &Number{Value: 1}, "lvl2": &Number{Value: 0}}}}},
+			&Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"random_field_name": &Number{Value: decimal("1")}, "lvl2": &Number{Value: decimal("0")}}}}},
 			false,
 		},
 		{
 			"SET :nestedMap.lvl1.random_field_name = :one",
 			":nestedMap",
-			&Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"random_field_name": &Number{Value: 1}, "lvl2": &Number{Value: 0}}}}},
+			&Map{Value: map[string]Object{"lvl1": &Map{Value: map[string]Object{"random_field_name": &Number{Value: decimal("1")}, "lvl2": &Number{Value: decimal("0")}}}}},
 			false,
 		},
 		{"SET :x = :val REMOVE :val", ":x", &String{Value: "text"}, true},
@@ -488,8 +488,8 @@ func TestEvalAddUpdate(t *testing.T) {
 		expected Object
 		keepEnv  bool
 	}{
-		{"ADD :one :one", ":one", &Number{Value: 2}, boolFalse},
-		{"ADD :numSet :one", ":numSet", &NumberSet{Value: map[float64]bool{1: boolTrue, 2: boolTrue, 4: boolTrue}}, boolFalse},
+		{"ADD :one :one", ":one", &Number{Value: decimal("2")}, boolFalse},
+		{"ADD :numSet :one", ":numSet", &NumberSet{Value: map[types.Decimal]bool{decimal("1"): boolTrue, decimal("2"): boolTrue, decimal("4"): boolTrue}}, boolFalse},
 		{"ADD :binSet :bin", ":binSet", &BinarySet{Value: [][]byte{[]byte("a"), []byte("b"), []byte("c")}}, boolFalse},
 		{"ADD :strSet :val", ":strSet", &StringSet{Value: map[string]bool{"a": boolTrue, "b": boolTrue, "text": boolTrue}}, boolFalse},
 		{"ADD newVal :val", ":val", &String{Value: "text"}, boolFalse},
@@ -557,7 +557,9 @@ func TestEvalDELETEUpdate(t *testing.T) {
 	}{
 		{"DELETE :binSet :binA", ":binSet", &BinarySet{Value: [][]byte{[]byte("b")}}, boolFalse},
 		{"DELETE :strSet :a", ":strSet", &StringSet{Value: map[string]bool{"b": boolTrue}}, boolFalse},
-		{"DELETE :numSet :two", ":numSet", &NumberSet{Value: map[float64]bool{4: boolTrue}}, boolFalse},
+		{"DELETE :numSet :two", ":numSet", &NumberSet{Value: map[types.Decimal]bool{decimal("4"): boolTrue}}, boolFalse},
+		{"DELETE :strSet :strSet", ":strSet", UNDEFINED, boolFalse},
+		{"DELETE :missing :strSet", ":missing", UNDEFINED, boolFalse},
 	}
 
 	env := startEvalUpdateEnv(t)
@@ -830,7 +832,7 @@ func TestIsNumber(t *testing.T) {
 		t.Fatal("expected to be boolFalse")
 	}
 
-	num := Number{Value: 10}
+	num := Number{Value: decimal("10")}
 	if !isNumber(&num) {
 		t.Fatal("expected to be boolTrue")
 	}
@@ -1102,7 +1104,7 @@ func TestIsUndefinedObject(t *testing.T) {
 }
 
 func TestEvalBooleanInfixExpressionLeftNotBoolean(t *testing.T) {
-	got := evalBooleanInfixExpression("AND", &Number{Value: 1}, TRUE)
+	got := evalBooleanInfixExpression("AND", &Number{Value: decimal("1")}, TRUE)
 	want := newError("left operand is not boolean: N")
 	if !isError(got) || got.Inspect() != want.Inspect() {
 		t.Fatalf("got %v, want %v", got, want)
@@ -1169,3 +1171,83 @@ func testEvalUpdate(t *testing.T, input string, env *Environment) Object {
 
 	return EvalUpdate(update, env)
 }
+
+func TestEvalUpdateArithmeticPrecision(t *testing.T) {
+	tests := []struct {
+		input    string
+		envField string
+		expected string
+		err      string
+	}{
+		{"SET :counter = :counter + :one", ":counter", "9007199254740994", ""},
+		{"ADD :counter :one", ":counter", "9007199254740994", ""},
+		{"SET :amount = :amount - :cents", ":amount", "123456789012345678901234567890123456.77", ""},
+		{"SET :amount = :amount + :tiny", "", "", types.ErrNumberPrecision.Error()},
+		{"ADD :huge :huge", "", "", types.ErrNumberOverflow.Error()},
+		{"SET :huge = :huge + :huge", "", "", types.ErrNumberOverflow.Error()},
+	}
+
+	for _, tt := range tests {
+		env := startEvalUpdateEnv(t)
+
+		err := env.AddAttributes(map[string]*types.Item{
+			":counter": {N: new("9007199254740993")},
+			":amount":  {N: new("123456789012345678901234567890123456.78")},
+			":cents":   {N: new("0.01")},
+			":tiny":    {N: new("1E-10")},
+			":huge":    {N: new("9E+125")},
+		})
+		if err != nil {
+			t.Fatalf("error adding attributes %v", err)
+		}
+
+		result := testEvalUpdate(t, tt.input, env)
+		if tt.err != "" {
+			errObj, ok := result.(*Error)
+			if !ok || errObj.Message != tt.err {
+				t.Errorf("expected error %q for %q, got=%v", tt.err, tt.input, result.Inspect())
+			}
+
+			continue
+		}
+
+		if isError(result) {
+			t.Fatalf("error evaluating update %q, env=%s, %s", tt.input, env.String(), result.Inspect())
+		}
+
+		if got := env.Get(tt.envField).Inspect(); got != tt.expected {
+			t.Errorf("result has wrong value for %q in %q. got=%v, want=%v", tt.envField, tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestEvalNumberComparisonPrecision(t *testing.T) {
+	env := NewEnvironment()
+
+	err := env.AddAttributes(map[string]*types.Item{
+		":a": {N: new("1234567890123456789012")},
+		":b": {N: new("1234567890123456789013")},
+		":c": {N: new("1.2345678901234567890120E+21")},
+	})
+	if err != nil {
+		t.Fatalf("error adding attributes %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected Object
+	}{
+		{":a < :b", TRUE},
+		{":a = :b", FALSE},
+		{":a = :c", TRUE},
+		{":b > :c", TRUE},
+		{":a BETWEEN :c AND :b", TRUE},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input, env)
+		if evaluated != tt.expected {
+			t.Errorf("result has wrong value for %q. got=%v, want=%v", tt.input, evaluated, tt.expected)
+		}
+	}
+}
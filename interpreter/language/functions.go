@@ -128,6 +128,11 @@ func contains(args ...Object) Object {
 	path := args[0]
 	operand := args[1]
 
+	// a missing attribute does not contain anything
+	if isUndefined(path) {
+		return FALSE
+	}
+
 	container, ok := path.(ContainerObject)
 	if !ok {
 		return newError("contains is not supported for path=%s", path.Type())
@@ -144,7 +149,7 @@ func objectSize(args ...Object) Object {
 	path := args[0]
 
 	if sizable, ok := path.(SizableObject); ok {
-		return &Number{Value: float64(sizable.Size())}
+		return &Number{Value: types.NewDecimalFromInt(sizable.Size())}
 	}
 
 	return newError("type not supported: size %s", path.Type())
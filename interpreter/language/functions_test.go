@@ -133,7 +133,7 @@ func TestBeginsWithError(t *testing.T) {
 		t.Fatalf("expect invalid type error, got=%s %s", begins.Type(), begins.Inspect())
 	}
 
-	num := &Number{Value: 5}
+	num := &Number{Value: decimal("5")}
 	begins = beginsWith(num, expectedBinary)
 
 	if begins.Type() != ObjectTypeError || begins.Inspect() != "ERROR: invalid type N" {
@@ -175,6 +175,11 @@ func TestContainsWithFailure(t *testing.T) {
 	if contained.Type() == ObjectTypeBoolean && contained.Inspect() != "false" {
 		t.Fatal("value should be true")
 	}
+
+	contained = contains(&Null{IsUndefined: true}, expectedContains)
+	if contained != FALSE {
+		t.Fatalf("missing attribute should not contain anything, got=%s", contained.Inspect())
+	}
 }
 
 func TestContainsWithError(t *testing.T) {
@@ -186,7 +191,7 @@ func TestContainsWithError(t *testing.T) {
 		t.Fatalf("expect invalid type error, got=%s %q", contained.Type(), contained.Inspect())
 	}
 
-	num := &Number{Value: 5}
+	num := &Number{Value: decimal("5")}
 	contained = contains(num, expectedBinary)
 
 	if contained.Type() != ObjectTypeError || contained.Inspect() != "ERROR: contains is not supported for path=N" {
@@ -228,7 +233,7 @@ func TestObjectSize(t *testing.T) {
 		t.Fatalf("string set size expected=2, actual=%s", size.Inspect())
 	}
 
-	ns := &NumberSet{Value: map[float64]bool{1: true, 2: true}}
+	ns := &NumberSet{Value: map[types.Decimal]bool{decimal("1"): true, decimal("2"): true}}
 	size = objectSize(ns)
 	if size.Inspect() != "2" {
 		t.Fatalf("number set size expected=2, actual=%s", size.Inspect())
@@ -0,0 +1,114 @@
+package language
+
+import "strings"
+
+// KeyConditionOperator is the comparison applied by a single KeyConditionExpression term.
+type KeyConditionOperator string
+
+const (
+	// KeyConditionEQ matches key values equal to the operand
+	KeyConditionEQ KeyConditionOperator = "="
+	// KeyConditionLT matches key values lower than the operand
+	KeyConditionLT KeyConditionOperator = "<"
+	// KeyConditionLTE matches key values lower than or equal to the operand
+	KeyConditionLTE KeyConditionOperator = "<="
+	// KeyConditionGT matches key values greater than the operand
+	KeyConditionGT KeyConditionOperator = ">"
+	// KeyConditionGTE matches key values greater than or equal to the operand
+	KeyConditionGTE KeyConditionOperator = ">="
+	// KeyConditionBetween matches key values inside the inclusive range of both operands
+	KeyConditionBetween KeyConditionOperator = "BETWEEN"
+	// KeyConditionBeginsWith matches key values starting with the operand
+	KeyConditionBeginsWith KeyConditionOperator = "begins_with"
+)
+
+// KeyCondition is one term of a KeyConditionExpression: an operator applied to a key
+// attribute with expression attribute value placeholders (:value) as operands.
+type KeyCondition struct {
+	Attribute string
+	Operator  KeyConditionOperator
+	Values    []string
+}
+
+// ExtractKeyConditions parses a KeyConditionExpression into its terms, resolving attribute
+// name placeholders with aliases. It reports false when the expression does not parse or is
+// not a conjunction of simple key comparisons; callers should then evaluate the expression
+// item by item instead of using the terms to narrow the search.
+func ExtractKeyConditions(expression string, aliases map[string]string) ([]KeyCondition, bool) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, false
+	}
+
+	l := NewLexer(expression)
+	p := NewParser(l)
+	conditional := p.ParseConditionalExpression()
+
+	if len(p.Errors()) != 0 || conditional.Expression == nil {
+		return nil, false
+	}
+
+	conditions := []KeyCondition{}
+
+	if !collectKeyConditions(conditional.Expression, aliases, &conditions) {
+		return nil, false
+	}
+
+	return conditions, true
+}
+
+func collectKeyConditions(expr Expression, aliases map[string]string, out *[]KeyCondition) bool {
+	switch n := expr.(type) {
+	case *InfixExpression:
+		if n.Operator == AND {
+			return collectKeyConditions(n.Left, aliases, out) && collectKeyConditions(n.Right, aliases, out)
+		}
+
+		return appendComparisonKeyCondition(n, aliases, out)
+	case *BetweenExpression:
+		return appendKeyCondition(KeyConditionBetween, n.Left, n.Range[:], aliases, out)
+	case *CallExpression:
+		fn, ok := n.Function.(*Identifier)
+		if !ok || fn.Value != string(KeyConditionBeginsWith) || len(n.Arguments) != 2 {
+			return false
+		}
+
+		return appendKeyCondition(KeyConditionBeginsWith, n.Arguments[0], n.Arguments[1:], aliases, out)
+	default:
+		return false
+	}
+}
+
+func appendComparisonKeyCondition(n *InfixExpression, aliases map[string]string, out *[]KeyCondition) bool {
+	switch KeyConditionOperator(n.Operator) {
+	case KeyConditionEQ, KeyConditionLT, KeyConditionLTE, KeyConditionGT, KeyConditionGTE:
+		return appendKeyCondition(KeyConditionOperator(n.Operator), n.Left, []Expression{n.Right}, aliases, out)
+	default:
+		return false
+	}
+}
+
+func appendKeyCondition(op KeyConditionOperator, attr Expression, operands []Expression, aliases map[string]string, out *[]KeyCondition) bool {
+	id, ok := attr.(*Identifier)
+	if !ok || strings.HasPrefix(id.Value, ":") {
+		return false
+	}
+
+	values := make([]string, 0, len(operands))
+
+	for _, operand := range operands {
+		v, ok := operand.(*Identifier)
+		if !ok || !strings.HasPrefix(v.Value, ":") {
+			return false
+		}
+
+		values = append(values, v.Value)
+	}
+
+	*out = append(*out, KeyCondition{
+		Attribute: resolveExpressionAttributeName(id.Value, aliases),
+		Operator:  op,
+		Values:    values,
+	})
+
+	return true
+}
@@ -0,0 +1,94 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractKeyConditions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		expr    string
+		aliases map[string]string
+		want    []KeyCondition
+		wantOK  bool
+	}{
+		{
+			name:   "empty expression",
+			expr:   "  ",
+			wantOK: false,
+		},
+		{
+			name:   "hash key only",
+			expr:   "id = :id",
+			want:   []KeyCondition{{Attribute: "id", Operator: KeyConditionEQ, Values: []string{":id"}}},
+			wantOK: true,
+		},
+		{
+			name:    "hash and range comparison with aliases",
+			expr:    "#id = :id AND #n >= :n",
+			aliases: map[string]string{"#id": "id", "#n": "name"},
+			want: []KeyCondition{
+				{Attribute: "id", Operator: KeyConditionEQ, Values: []string{":id"}},
+				{Attribute: "name", Operator: KeyConditionGTE, Values: []string{":n"}},
+			},
+			wantOK: true,
+		},
+		{
+			name: "between",
+			expr: "(id = :id) AND lvl BETWEEN :a AND :b",
+			want: []KeyCondition{
+				{Attribute: "id", Operator: KeyConditionEQ, Values: []string{":id"}},
+				{Attribute: "lvl", Operator: KeyConditionBetween, Values: []string{":a", ":b"}},
+			},
+			wantOK: true,
+		},
+		{
+			name: "begins_with",
+			expr: "id = :id AND begins_with(name, :prefix)",
+			want: []KeyCondition{
+				{Attribute: "id", Operator: KeyConditionEQ, Values: []string{":id"}},
+				{Attribute: "name", Operator: KeyConditionBeginsWith, Values: []string{":prefix"}},
+			},
+			wantOK: true,
+		},
+		{
+			name:   "disjunction is not a key condition",
+			expr:   "id = :id OR id = :other",
+			wantOK: false,
+		},
+		{
+			name:   "operand on the left side",
+			expr:   ":id = id",
+			wantOK: false,
+		},
+		{
+			name:   "unsupported function",
+			expr:   "id = :id AND contains(name, :n)",
+			wantOK: false,
+		},
+		{
+			name:   "syntax error",
+			expr:   "id = ",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := require.New(t)
+
+			got, ok := ExtractKeyConditions(tt.expr, tt.aliases)
+			c.Equal(tt.wantOK, ok)
+
+			if tt.wantOK {
+				c.Equal(tt.want, got)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package language
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/truora/minidyn/types"
 )
@@ -18,9 +17,7 @@ func MapToObject(val *types.Item) (Object, error) {
 
 		return FALSE, nil
 	case val.N != nil:
-		n, err := strconv.ParseFloat(types.StringValue(val.N), 64)
-
-		return &Number{Value: n}, err
+		return ParseNumber(types.StringValue(val.N))
 	case val.S != nil:
 		return &String{Value: types.StringValue(val.S)}, nil
 	case val.NULL != nil && *val.NULL:
@@ -117,10 +114,10 @@ func mapAttributeToBinarySet(val *types.Item) (Object, error) {
 }
 
 func mapAttributeToNumberSet(val *types.Item) (Object, error) {
-	ns := map[float64]bool{}
+	ns := map[types.Decimal]bool{}
 
 	for _, val := range val.NS {
-		n, err := strconv.ParseFloat(types.StringValue(val), 64)
+		n, err := types.ParseDecimal(types.StringValue(val))
 		if err != nil {
 			return nil, err
 		}
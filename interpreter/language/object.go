@@ -3,8 +3,9 @@ package language
 import (
 	"bytes"
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
 
 	"github.com/truora/minidyn/types"
@@ -101,14 +102,25 @@ type DetachableObject interface {
 	Delete(obj Object) Object
 }
 
-// Number is the representation of numbers
+// Number is the representation of numbers, backed by an exact decimal with the
+// DynamoDB precision and range
 type Number struct {
-	Value float64
+	Value types.Decimal
+}
+
+// ParseNumber parses a DynamoDB number into its object representation
+func ParseNumber(s string) (*Number, error) {
+	d, err := types.ParseDecimal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Number{Value: d}, nil
 }
 
 // Inspect returns the readable value of the object
 func (i *Number) Inspect() string {
-	return numToString(i.Value)
+	return i.Value.String()
 }
 
 // Type returns the object type
@@ -118,15 +130,11 @@ func (i *Number) Type() ObjectType {
 
 // ToDynamoDB returns the types attribute value
 func (i *Number) ToDynamoDB() types.Item {
-	str := numToString(i.Value)
+	str := i.Value.String()
 
 	return types.Item{N: new(str)}
 }
 
-func numToString(v float64) string {
-	return strconv.FormatFloat(v, 'f', -1, 64)
-}
-
 // Add if the obj is an number it adds the value to the number
 func (i *Number) Add(obj Object) Object {
 	n, ok := obj.(*Number)
@@ -134,7 +142,12 @@ func (i *Number) Add(obj Object) Object {
 		return newError("Incorrect operand type for operator or function; operator: ADD, operand type: %s", obj.Type())
 	}
 
-	i.Value += n.Value
+	sum, err := i.Value.Add(n.Value)
+	if err != nil {
+		return newError("%s", err.Error())
+	}
+
+	i.Value = sum
 
 	return UNDEFINED
 }
@@ -758,23 +771,19 @@ func (bs *BinarySet) Add(obj Object) Object {
 
 // NumberSet is the representation of a number set
 type NumberSet struct {
-	Value map[float64]bool
+	Value map[types.Decimal]bool
 }
 
 // Inspect returns the readable value of the object
 func (ns *NumberSet) Inspect() string {
 	var out bytes.Buffer
 
-	vals := make([]float64, 0, len(ns.Value))
-	for v := range ns.Value {
-		vals = append(vals, v)
-	}
+	vals := slices.SortedFunc(maps.Keys(ns.Value), types.Decimal.Cmp)
 
-	sort.Float64s(vals)
 	out.WriteString("[ ")
 
 	for _, k := range vals {
-		fmt.Fprintf(&out, "%v", k)
+		out.WriteString(k.String())
 		out.WriteString(" ")
 	}
 
@@ -793,7 +802,7 @@ func (ns *NumberSet) ToDynamoDB() types.Item {
 	attr := types.Item{NS: make([]*string, 0, len(ns.Value))}
 
 	for v := range ns.Value {
-		str := numToString(v)
+		str := v.String()
 
 		attr.NS = append(attr.NS, new(str))
 	}
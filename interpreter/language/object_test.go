@@ -8,22 +8,31 @@ import (
 	"github.com/truora/minidyn/types"
 )
 
+func decimal(s string) types.Decimal {
+	d, err := types.ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
 func TestNumberInspect(t *testing.T) {
-	n := Number{Value: 1.0}
+	n := Number{Value: decimal("1.0")}
 	if n.Inspect() != "1" {
 		t.Fatalf("not equal actual=%s expected=%s", n.Inspect(), "1")
 	}
 }
 
 func TestNumberAdd(t *testing.T) {
-	n := Number{Value: 1.0}
+	n := Number{Value: decimal("1.0")}
 
-	obj := n.Add(&Number{Value: 1.0})
+	obj := n.Add(&Number{Value: decimal("1.0")})
 	if obj != UNDEFINED {
 		t.Fatalf("return object should be NULL, got=%q", obj.Inspect())
 	}
 
-	if n.Value != 2 {
+	if n.Value != decimal("2") {
 		t.Fatalf("result object should be 2, got=%q", n.Inspect())
 	}
 }
@@ -81,7 +90,7 @@ func TestStringContains(t *testing.T) {
 		t.Fatalf("should be false")
 	}
 
-	if str.Contains(&Number{Value: 10}) {
+	if str.Contains(&Number{Value: decimal("10")}) {
 		t.Fatalf("should be false")
 	}
 }
@@ -107,7 +116,7 @@ func TestMapInspect(t *testing.T) {
 func TestListInspect(t *testing.T) {
 	str := List{
 		Value: []Object{
-			&String{Value: "Cookies"}, &String{Value: "Coffee"}, &Number{Value: 3.14159},
+			&String{Value: "Cookies"}, &String{Value: "Coffee"}, &Number{Value: decimal("3.14159")},
 		},
 	}
 
@@ -120,7 +129,7 @@ func TestListInspect(t *testing.T) {
 func TestListContains(t *testing.T) {
 	list := List{
 		Value: []Object{
-			&String{Value: "Cookies"}, &String{Value: "Coffee"}, &Number{Value: 3.14159},
+			&String{Value: "Cookies"}, &String{Value: "Coffee"}, &Number{Value: decimal("3.14159")},
 		},
 	}
 
@@ -128,7 +137,7 @@ func TestListContains(t *testing.T) {
 		t.Fatalf("should be true")
 	}
 
-	if !list.Contains(&Number{Value: 3.14159}) {
+	if !list.Contains(&Number{Value: decimal("3.14159")}) {
 		t.Fatalf("should be true")
 	}
 
@@ -229,7 +238,7 @@ func TestStringSetContains(t *testing.T) {
 		t.Fatalf("should be false")
 	}
 
-	if strSet.Contains(&Number{Value: 10}) {
+	if strSet.Contains(&Number{Value: decimal("10")}) {
 		t.Fatalf("should be false")
 	}
 }
@@ -316,7 +325,7 @@ func TestBinarySetContains(t *testing.T) {
 		t.Fatalf("should be false")
 	}
 
-	if strSet.Contains(&Number{Value: 10}) {
+	if strSet.Contains(&Number{Value: decimal("10")}) {
 		t.Fatalf("should be false")
 	}
 }
@@ -391,9 +400,9 @@ func TestBinarySetRemove(t *testing.T) {
 
 func TestNumberSetInspect(t *testing.T) {
 	strSet := NumberSet{
-		Value: map[float64]bool{
-			1: true,
-			2: true,
+		Value: map[types.Decimal]bool{
+			decimal("1"): true,
+			decimal("2"): true,
 		},
 	}
 
@@ -405,25 +414,25 @@ func TestNumberSetInspect(t *testing.T) {
 
 func TestNumberSetContains(t *testing.T) {
 	strSet := NumberSet{
-		Value: map[float64]bool{
-			1: true,
-			2: true,
+		Value: map[types.Decimal]bool{
+			decimal("1"): true,
+			decimal("2"): true,
 		},
 	}
 
-	if !strSet.Contains(&Number{Value: 1}) {
+	if !strSet.Contains(&Number{Value: decimal("1")}) {
 		t.Fatalf("should be true")
 	}
 
-	if strSet.Contains(&Number{Value: 3}) {
+	if strSet.Contains(&Number{Value: decimal("3")}) {
 		t.Fatalf("should be false")
 	}
 
-	if !strSet.Contains(&NumberSet{Value: map[float64]bool{1: true}}) {
+	if !strSet.Contains(&NumberSet{Value: map[types.Decimal]bool{decimal("1"): true}}) {
 		t.Fatalf("should be true")
 	}
 
-	if strSet.Contains(&NumberSet{Value: map[float64]bool{3: true}}) {
+	if strSet.Contains(&NumberSet{Value: map[types.Decimal]bool{decimal("3"): true}}) {
 		t.Fatalf("should be false")
 	}
 
@@ -433,9 +442,9 @@ func TestNumberSetContains(t *testing.T) {
 }
 
 func TestNumberSetAdd(t *testing.T) {
-	ns := NumberSet{Value: map[float64]bool{1: true}}
+	ns := NumberSet{Value: map[types.Decimal]bool{decimal("1"): true}}
 
-	obj := ns.Add(&Number{Value: 2})
+	obj := ns.Add(&Number{Value: decimal("2")})
 	if obj != UNDEFINED {
 		t.Fatalf("return object should be NULL, got=%q", obj.Inspect())
 	}
@@ -444,7 +453,7 @@ func TestNumberSetAdd(t *testing.T) {
 		t.Fatalf("result object should be 2 elements, got=%q", ns.Inspect())
 	}
 
-	obj = ns.Add(&NumberSet{Value: map[float64]bool{3: true}})
+	obj = ns.Add(&NumberSet{Value: map[types.Decimal]bool{decimal("3"): true}})
 	if obj != UNDEFINED {
 		t.Fatalf("return object should be NULL, got=%q", obj.Inspect())
 	}
@@ -455,9 +464,9 @@ func TestNumberSetAdd(t *testing.T) {
 }
 
 func TestNumberSetDelete(t *testing.T) {
-	ns := NumberSet{Value: map[float64]bool{1: true, 2: true, 3: true}}
+	ns := NumberSet{Value: map[types.Decimal]bool{decimal("1"): true, decimal("2"): true, decimal("3"): true}}
 
-	obj := ns.Delete(&Number{Value: 2})
+	obj := ns.Delete(&Number{Value: decimal("2")})
 	if obj != UNDEFINED {
 		t.Fatalf("return object should be NULL, got=%q", obj.Inspect())
 	}
@@ -466,7 +475,7 @@ func TestNumberSetDelete(t *testing.T) {
 		t.Fatalf("result object should have 2 elements, got=%q", ns.Inspect())
 	}
 
-	obj = ns.Delete(&NumberSet{Value: map[float64]bool{1: true, 3: true}})
+	obj = ns.Delete(&NumberSet{Value: map[types.Decimal]bool{decimal("1"): true, decimal("3"): true}})
 	if obj != UNDEFINED {
 		t.Fatalf("return object should be NULL, got=%q", obj.Inspect())
 	}
@@ -488,7 +497,7 @@ func BenchmarkStringInspect(b *testing.B) {
 
 //nolint:gocyclo // comprehensive conversions covered in one test
 func TestToDynamo(t *testing.T) {
-	num := Number{Value: 3}
+	num := Number{Value: decimal("3")}
 	dNum := num.ToDynamoDB()
 
 	if num.Inspect() != types.StringValue(dNum.N) {
@@ -555,7 +564,7 @@ func TestToDynamo(t *testing.T) {
 		t.Errorf("binary set item to be %s got=%s", bs.Value[0], dBs.BS[0])
 	}
 
-	nm := NumberSet{Value: map[float64]bool{1: true}}
+	nm := NumberSet{Value: map[types.Decimal]bool{decimal("1"): true}}
 	dNm := nm.ToDynamoDB()
 
 	if types.StringValue(dNm.NS[0]) != "1" {
@@ -109,7 +109,12 @@ func extractPathListIndex(idxExpr Expression, env *Environment) (int64, error) {
 		return 0, fmt.Errorf("projection path: list index must be a number")
 	}
 
-	return int64(number.Value), nil
+	idx, ok := number.Value.Int64()
+	if !ok {
+		return 0, fmt.Errorf("projection path: list index is out of range: %s", number.Inspect())
+	}
+
+	return idx, nil
 }
 
 // SetProjectedPath writes val into target following path, creating nested M and L containers as needed.
@@ -202,6 +202,34 @@ func TestLanguageUpdate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "remove",
+			input: UpdateInput{
+				TableName:  "test",
+				Expression: "SET a = :a REMOVE #t",
+				Item: map[string]*types.Item{
+					"a": {
+						S: new("a"),
+					},
+					"two": {
+						N: new("2"),
+					},
+				},
+				Attributes: map[string]*types.Item{
+					":a": {
+						N: new("1"),
+					},
+				},
+				Aliases: map[string]string{
+					"#t": "two",
+				},
+			},
+			output: map[string]*types.Item{
+				"a": {
+					N: new("1"),
+				},
+			},
+		},
 		{
 			name: "runtime validation error",
 			input: UpdateInput{
@@ -0,0 +1,467 @@
+package partiql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Statement is a parsed PartiQL statement
+type Statement interface {
+	statementNode()
+	// TableName returns the table targeted by the statement
+	TableName() string
+	String() string
+}
+
+// Expression is a PartiQL expression
+type Expression interface {
+	expressionNode()
+	String() string
+}
+
+// SelectStatement represents SELECT <projection> FROM "table"."index" WHERE ... ORDER BY ...
+type SelectStatement struct {
+	Table string
+	Index string
+	// Projection holds the projected paths, it is empty when selecting *
+	Projection []*Path
+	Where      Expression
+	OrderBy    *OrderBy
+}
+
+func (ss *SelectStatement) statementNode() {
+	// this is used to identify the statement type
+}
+
+// TableName returns the table targeted by the statement
+func (ss *SelectStatement) TableName() string { return ss.Table }
+
+func (ss *SelectStatement) String() string {
+	var out strings.Builder
+
+	out.WriteString("SELECT ")
+
+	if len(ss.Projection) == 0 {
+		out.WriteString("*")
+	}
+
+	for i, p := range ss.Projection {
+		if i > 0 {
+			out.WriteString(", ")
+		}
+
+		out.WriteString(p.String())
+	}
+
+	out.WriteString(" FROM " + quoteIdentifier(ss.Table))
+
+	if ss.Index != "" {
+		out.WriteString("." + quoteIdentifier(ss.Index))
+	}
+
+	if ss.Where != nil {
+		out.WriteString(" WHERE " + ss.Where.String())
+	}
+
+	if ss.OrderBy != nil {
+		out.WriteString(" " + ss.OrderBy.String())
+	}
+
+	return out.String()
+}
+
+// ExistsStatement represents EXISTS(SELECT ...), only valid inside transactions
+type ExistsStatement struct {
+	Select *SelectStatement
+}
+
+func (es *ExistsStatement) statementNode() {
+	// this is used to identify the statement type
+}
+
+// TableName returns the table targeted by the statement
+func (es *ExistsStatement) TableName() string { return es.Select.Table }
+
+func (es *ExistsStatement) String() string {
+	return "EXISTS(" + es.Select.String() + ")"
+}
+
+// InsertStatement represents INSERT INTO "table" VALUE {...}
+type InsertStatement struct {
+	Table string
+	Value Expression
+}
+
+func (is *InsertStatement) statementNode() {
+	// this is used to identify the statement type
+}
+
+// TableName returns the table targeted by the statement
+func (is *InsertStatement) TableName() string { return is.Table }
+
+func (is *InsertStatement) String() string {
+	return "INSERT INTO " + quoteIdentifier(is.Table) + " VALUE " + is.Value.String()
+}
+
+// UpdateAction is a SET or REMOVE clause of an UPDATE statement, Value is nil for REMOVE
+type UpdateAction struct {
+	Path  *Path
+	Value Expression
+}
+
+func (ua *UpdateAction) String() string {
+	if ua.Value == nil {
+		return "REMOVE " + ua.Path.String()
+	}
+
+	return "SET " + ua.Path.String() + " = " + ua.Value.String()
+}
+
+// UpdateStatement represents UPDATE "table" SET ... REMOVE ... WHERE ... RETURNING ...
+type UpdateStatement struct {
+	Table     string
+	Actions   []*UpdateAction
+	Where     Expression
+	Returning *Returning
+}
+
+func (us *UpdateStatement) statementNode() {
+	// this is used to identify the statement type
+}
+
+// TableName returns the table targeted by the statement
+func (us *UpdateStatement) TableName() string { return us.Table }
+
+func (us *UpdateStatement) String() string {
+	var out strings.Builder
+
+	out.WriteString("UPDATE " + quoteIdentifier(us.Table))
+
+	for _, action := range us.Actions {
+		out.WriteString(" " + action.String())
+	}
+
+	out.WriteString(" WHERE " + us.Where.String())
+
+	if us.Returning != nil {
+		out.WriteString(" " + us.Returning.String())
+	}
+
+	return out.String()
+}
+
+// DeleteStatement represents DELETE FROM "table" WHERE ... RETURNING ...
+type DeleteStatement struct {
+	Table     string
+	Where     Expression
+	Returning *Returning
+}
+
+func (ds *DeleteStatement) statementNode() {
+	// this is used to identify the statement type
+}
+
+// TableName returns the table targeted by the statement
+func (ds *DeleteStatement) TableName() string { return ds.Table }
+
+func (ds *DeleteStatement) String() string {
+	out := "DELETE FROM " + quoteIdentifier(ds.Table) + " WHERE " + ds.Where.String()
+
+	if ds.Returning != nil {
+		out += " " + ds.Returning.String()
+	}
+
+	return out
+}
+
+// Returning is the RETURNING clause of UPDATE and DELETE statements
+type Returning struct {
+	Modified bool
+	New      bool
+}
+
+// ReturnValues returns the ReturnValues option matching the clause
+func (r *Returning) ReturnValues() string {
+	switch {
+	case r.Modified && r.New:
+		return "UPDATED_NEW"
+	case r.Modified:
+		return "UPDATED_OLD"
+	case r.New:
+		return "ALL_NEW"
+	}
+
+	return "ALL_OLD"
+}
+
+func (r *Returning) String() string {
+	out := "RETURNING ALL"
+	if r.Modified {
+		out = "RETURNING MODIFIED"
+	}
+
+	if r.New {
+		return out + " NEW *"
+	}
+
+	return out + " OLD *"
+}
+
+// OrderBy is the ORDER BY clause of a select
+type OrderBy struct {
+	Path       *Path
+	Descending bool
+}
+
+func (ob *OrderBy) String() string {
+	direction := "ASC"
+	if ob.Descending {
+		direction = "DESC"
+	}
+
+	return "ORDER BY " + ob.Path.String() + " " + direction
+}
+
+// PathElement is a step of a document path, either an attribute name or a list index
+type PathElement struct {
+	Name    string
+	Index   int
+	IsIndex bool
+}
+
+// Path is a document path like a.b[1].c
+type Path struct {
+	Elements []PathElement
+}
+
+func (p *Path) expressionNode() {
+	// this is used to identify the expression type
+}
+
+// Attribute returns the top level attribute of the path
+func (p *Path) Attribute() string {
+	return p.Elements[0].Name
+}
+
+// IsAttribute reports if the path is a top level attribute without nested elements
+func (p *Path) IsAttribute() bool {
+	return len(p.Elements) == 1
+}
+
+func (p *Path) String() string {
+	var out strings.Builder
+
+	for i, e := range p.Elements {
+		switch {
+		case e.IsIndex:
+			fmt.Fprintf(&out, "[%d]", e.Index)
+		case i == 0:
+			out.WriteString(quoteIdentifier(e.Name))
+		default:
+			out.WriteString("." + quoteIdentifier(e.Name))
+		}
+	}
+
+	return out.String()
+}
+
+// LiteralKind the kind of scalar literal
+type LiteralKind int
+
+const (
+	// LiteralString a single quoted string
+	LiteralString LiteralKind = iota
+	// LiteralNumber a number
+	LiteralNumber
+	// LiteralBoolean TRUE or FALSE
+	LiteralBoolean
+	// LiteralNull NULL
+	LiteralNull
+)
+
+// Literal is a scalar value written in the statement
+type Literal struct {
+	Kind  LiteralKind
+	Value string
+}
+
+func (l *Literal) expressionNode() {
+	// this is used to identify the expression type
+}
+
+func (l *Literal) String() string {
+	if l.Kind == LiteralString {
+		return "'" + strings.ReplaceAll(l.Value, "'", "''") + "'"
+	}
+
+	return l.Value
+}
+
+// Parameter is a ? placeholder, Position is zero based
+type Parameter struct {
+	Position int
+}
+
+func (p *Parameter) expressionNode() {
+	// this is used to identify the expression type
+}
+
+func (p *Parameter) String() string { return "?" }
+
+// ListLiteral is a list value like [1, 'a']
+type ListLiteral struct {
+	Elements []Expression
+}
+
+func (ll *ListLiteral) expressionNode() {
+	// this is used to identify the expression type
+}
+
+func (ll *ListLiteral) String() string {
+	return "[" + joinExpressions(ll.Elements) + "]"
+}
+
+// SetLiteral is a set value like <<'a', 'b'>>
+type SetLiteral struct {
+	Elements []Expression
+}
+
+func (sl *SetLiteral) expressionNode() {
+	// this is used to identify the expression type
+}
+
+func (sl *SetLiteral) String() string {
+	return "<<" + joinExpressions(sl.Elements) + ">>"
+}
+
+// MapEntry is a key and value of a map literal
+type MapEntry struct {
+	Key   string
+	Value Expression
+}
+
+// MapLiteral is a map value like {'a': 1}
+type MapLiteral struct {
+	Entries []MapEntry
+}
+
+func (ml *MapLiteral) expressionNode() {
+	// this is used to identify the expression type
+}
+
+func (ml *MapLiteral) String() string {
+	entries := make([]string, 0, len(ml.Entries))
+	for _, e := range ml.Entries {
+		entries = append(entries, "'"+e.Key+"': "+e.Value.String())
+	}
+
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// InfixExpression is a comparison or a logical AND/OR
+type InfixExpression struct {
+	Operator string
+	Left     Expression
+	Right    Expression
+}
+
+func (ie *InfixExpression) expressionNode() {
+	// this is used to identify the expression type
+}
+
+func (ie *InfixExpression) String() string {
+	return "(" + ie.Left.String() + " " + ie.Operator + " " + ie.Right.String() + ")"
+}
+
+// NotExpression negates a condition
+type NotExpression struct {
+	Right Expression
+}
+
+func (ne *NotExpression) expressionNode() {
+	// this is used to identify the expression type
+}
+
+func (ne *NotExpression) String() string {
+	return "(NOT " + ne.Right.String() + ")"
+}
+
+// BetweenExpression represents left BETWEEN low AND high
+type BetweenExpression struct {
+	Left Expression
+	Low  Expression
+	High Expression
+}
+
+func (be *BetweenExpression) expressionNode() {
+	// this is used to identify the expression type
+}
+
+func (be *BetweenExpression) String() string {
+	return "(" + be.Left.String() + " BETWEEN " + be.Low.String() + " AND " + be.High.String() + ")"
+}
+
+// InExpression represents left IN [values]
+type InExpression struct {
+	Left   Expression
+	Values []Expression
+}
+
+func (ie *InExpression) expressionNode() {
+	// this is used to identify the expression type
+}
+
+func (ie *InExpression) String() string {
+	return "(" + ie.Left.String() + " IN [" + joinExpressions(ie.Values) + "])"
+}
+
+// CallExpression is a function call like begins_with(a, 'x')
+type CallExpression struct {
+	Function  string
+	Arguments []Expression
+}
+
+func (ce *CallExpression) expressionNode() {
+	// this is used to identify the expression type
+}
+
+func (ce *CallExpression) String() string {
+	return ce.Function + "(" + joinExpressions(ce.Arguments) + ")"
+}
+
+// IsExpression represents left IS [NOT] NULL|MISSING
+type IsExpression struct {
+	Left    Expression
+	Not     bool
+	Missing bool
+}
+
+func (ie *IsExpression) expressionNode() {
+	// this is used to identify the expression type
+}
+
+func (ie *IsExpression) String() string {
+	out := ie.Left.String() + " IS "
+	if ie.Not {
+		out += "NOT "
+	}
+
+	if ie.Missing {
+		return "(" + out + "MISSING)"
+	}
+
+	return "(" + out + "NULL)"
+}
+
+func joinExpressions(exps []Expression) string {
+	parts := make([]string, 0, len(exps))
+	for _, e := range exps {
+		parts = append(parts, e.String())
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
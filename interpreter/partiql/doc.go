@@ -0,0 +1,6 @@
+/*
+Package partiql parses the PartiQL statements accepted by ExecuteStatement and translates
+their conditions into DynamoDB expressions, so they are evaluated by the expression
+interpreter like the rest of the API
+*/
+package partiql
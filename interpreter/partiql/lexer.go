@@ -0,0 +1,194 @@
+package partiql
+
+import (
+	"strings"
+
+	"github.com/truora/minidyn/interpreter/language"
+)
+
+// Lexer splits a PartiQL statement into tokens
+type Lexer struct {
+	input        string
+	position     int
+	readPosition int
+	ch           byte
+}
+
+var singleChar = map[byte]language.TokenType{
+	'=': language.EQ,
+	'(': language.LPAREN,
+	')': language.RPAREN,
+	',': language.COMMA,
+	'[': language.LBRACKET,
+	']': language.RBRACKET,
+	'.': language.DOT,
+	'?': PARAM,
+	'*': STAR,
+	'{': LBRACE,
+	'}': RBRACE,
+	':': COLON,
+	'+': language.PLUS,
+	'-': language.MINUS,
+}
+
+// NewLexer creates a new lexer
+func NewLexer(input string) *Lexer {
+	l := &Lexer{input: input}
+	l.readChar()
+
+	return l
+}
+
+func (l *Lexer) readChar() {
+	if l.readPosition >= len(l.input) {
+		l.ch = 0
+	} else {
+		l.ch = l.input[l.readPosition]
+	}
+
+	l.position = l.readPosition
+	l.readPosition++
+}
+
+func (l *Lexer) peekChar() byte {
+	if l.readPosition >= len(l.input) {
+		return 0
+	}
+
+	return l.input[l.readPosition]
+}
+
+// NextToken look up for the next token
+func (l *Lexer) NextToken() language.Token {
+	l.skipWhitespace()
+
+	if typ, ok := singleChar[l.ch]; ok {
+		tok := language.Token{Type: typ, Literal: string(l.ch)}
+		l.readChar()
+
+		return tok
+	}
+
+	switch {
+	case l.ch == 0:
+		return language.Token{Type: language.EOF}
+	case l.ch == '<' || l.ch == '>' || l.ch == '!':
+		return l.readOperator()
+	case l.ch == '\'':
+		return l.readQuoted('\'', STRING)
+	case l.ch == '"':
+		return l.readQuoted('"', QUOTED)
+	case isDigit(l.ch):
+		return language.Token{Type: NUMBER, Literal: l.readNumber()}
+	case isIdentifierStart(l.ch):
+		ident := l.readIdentifier()
+
+		return language.Token{Type: lookupIdent(ident), Literal: ident}
+	}
+
+	tok := language.Token{Type: language.ILLEGAL, Literal: string(l.ch)}
+	l.readChar()
+
+	return tok
+}
+
+func (l *Lexer) readOperator() language.Token {
+	two := string(l.ch) + string(l.peekChar())
+
+	operators := map[string]language.TokenType{
+		"<=": language.LTE,
+		">=": language.GTE,
+		"<>": language.NotEQ,
+		"!=": language.NotEQ,
+		"<<": LDANGLE,
+		">>": RDANGLE,
+	}
+
+	if typ, ok := operators[two]; ok {
+		l.readChar()
+		l.readChar()
+
+		return language.Token{Type: typ, Literal: two}
+	}
+
+	ch := l.ch
+	l.readChar()
+
+	switch ch {
+	case '<':
+		return language.Token{Type: language.LT, Literal: "<"}
+	case '>':
+		return language.Token{Type: language.GT, Literal: ">"}
+	}
+
+	return language.Token{Type: language.ILLEGAL, Literal: string(ch)}
+}
+
+// readQuoted reads a string delimited by quote, a doubled quote escapes the delimiter
+func (l *Lexer) readQuoted(quote byte, typ language.TokenType) language.Token {
+	var out strings.Builder
+
+	for {
+		l.readChar()
+
+		switch {
+		case l.ch == 0:
+			return language.Token{Type: language.ILLEGAL, Literal: out.String()}
+		case l.ch == quote && l.peekChar() == quote:
+			out.WriteByte(quote)
+			l.readChar()
+		case l.ch == quote:
+			l.readChar()
+
+			return language.Token{Type: typ, Literal: out.String()}
+		default:
+			out.WriteByte(l.ch)
+		}
+	}
+}
+
+func (l *Lexer) readNumber() string {
+	position := l.position
+
+	for isDigit(l.ch) || l.ch == '.' {
+		l.readChar()
+	}
+
+	if l.ch == 'e' || l.ch == 'E' {
+		l.readChar()
+
+		if l.ch == '+' || l.ch == '-' {
+			l.readChar()
+		}
+
+		for isDigit(l.ch) {
+			l.readChar()
+		}
+	}
+
+	return l.input[position:l.position]
+}
+
+func (l *Lexer) readIdentifier() string {
+	position := l.position
+
+	for isIdentifierStart(l.ch) || isDigit(l.ch) {
+		l.readChar()
+	}
+
+	return l.input[position:l.position]
+}
+
+func (l *Lexer) skipWhitespace() {
+	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+		l.readChar()
+	}
+}
+
+func isDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+func isIdentifierStart(ch byte) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+}
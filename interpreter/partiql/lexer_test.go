@@ -0,0 +1,73 @@
+package partiql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/interpreter/language"
+)
+
+func TestNextToken(t *testing.T) {
+	c := require.New(t)
+
+	input := `SELECT "name", a.b[2] FROM "pokemons"."by-type" WHERE id = 'it''s' AND lvl >= -1.5e3 ` +
+		`AND x <> ? AND y != {'k': <<1>>} ORDER BY id desc`
+
+	expected := []language.Token{
+		{Type: SELECT, Literal: "SELECT"},
+		{Type: QUOTED, Literal: "name"},
+		{Type: language.COMMA, Literal: ","},
+		{Type: language.IDENT, Literal: "a"},
+		{Type: language.DOT, Literal: "."},
+		{Type: language.IDENT, Literal: "b"},
+		{Type: language.LBRACKET, Literal: "["},
+		{Type: NUMBER, Literal: "2"},
+		{Type: language.RBRACKET, Literal: "]"},
+		{Type: FROM, Literal: "FROM"},
+		{Type: QUOTED, Literal: "pokemons"},
+		{Type: language.DOT, Literal: "."},
+		{Type: QUOTED, Literal: "by-type"},
+		{Type: WHERE, Literal: "WHERE"},
+		{Type: language.IDENT, Literal: "id"},
+		{Type: language.EQ, Literal: "="},
+		{Type: STRING, Literal: "it's"},
+		{Type: language.AND, Literal: "AND"},
+		{Type: language.IDENT, Literal: "lvl"},
+		{Type: language.GTE, Literal: ">="},
+		{Type: language.MINUS, Literal: "-"},
+		{Type: NUMBER, Literal: "1.5e3"},
+		{Type: language.AND, Literal: "AND"},
+		{Type: language.IDENT, Literal: "x"},
+		{Type: language.NotEQ, Literal: "<>"},
+		{Type: PARAM, Literal: "?"},
+		{Type: language.AND, Literal: "AND"},
+		{Type: language.IDENT, Literal: "y"},
+		{Type: language.NotEQ, Literal: "!="},
+		{Type: LBRACE, Literal: "{"},
+		{Type: STRING, Literal: "k"},
+		{Type: COLON, Literal: ":"},
+		{Type: LDANGLE, Literal: "<<"},
+		{Type: NUMBER, Literal: "1"},
+		{Type: RDANGLE, Literal: ">>"},
+		{Type: RBRACE, Literal: "}"},
+		{Type: ORDER, Literal: "ORDER"},
+		{Type: BY, Literal: "BY"},
+		{Type: language.IDENT, Literal: "id"},
+		{Type: DESC, Literal: "desc"},
+		{Type: language.EOF},
+	}
+
+	l := NewLexer(input)
+
+	for _, want := range expected {
+		c.Equal(want, l.NextToken())
+	}
+}
+
+func TestNextTokenIllegal(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal(language.Token{Type: language.ILLEGAL, Literal: "abc"}, NewLexer(`'abc`).NextToken())
+	c.Equal(language.Token{Type: language.ILLEGAL, Literal: ";"}, NewLexer(`;`).NextToken())
+	c.Equal(language.Token{Type: language.ILLEGAL, Literal: "!"}, NewLexer(`!a`).NextToken())
+}
@@ -0,0 +1,890 @@
+package partiql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/truora/minidyn/interpreter/language"
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	_ int = iota
+	precedenceValueLowest
+	precedenceValueOR          // OR
+	precedenceValueAND         // AND
+	precedenceValueNOT         // NOT
+	precedenceValueComparators // = <> < <= > >= IS IN BETWEEN
+)
+
+var precedences = map[language.TokenType]int{
+	language.OR:      precedenceValueOR,
+	language.AND:     precedenceValueAND,
+	language.EQ:      precedenceValueComparators,
+	language.NotEQ:   precedenceValueComparators,
+	language.LT:      precedenceValueComparators,
+	language.LTE:     precedenceValueComparators,
+	language.GT:      precedenceValueComparators,
+	language.GTE:     precedenceValueComparators,
+	language.BETWEEN: precedenceValueComparators,
+	language.IN:      precedenceValueComparators,
+	IS:               precedenceValueComparators,
+}
+
+// conditionFunctions are the functions allowed in WHERE clauses with their arity
+var conditionFunctions = map[string]int{
+	"begins_with":    2,
+	"contains":       2,
+	"attribute_type": 2,
+	"size":           1,
+}
+
+// updateFunctions are the functions allowed in SET clauses with their arity
+var updateFunctions = map[string]int{
+	"list_append":   2,
+	"if_not_exists": 2,
+	"set_add":       2,
+	"set_delete":    2,
+}
+
+// Parser builds statements from the tokens of a Lexer
+type Parser struct {
+	l         *Lexer
+	curToken  language.Token
+	peekToken language.Token
+	errors    []string
+	params    int
+}
+
+// NewParser creates a new parser
+func NewParser(l *Lexer) *Parser {
+	p := &Parser{l: l, errors: []string{}}
+
+	p.nextToken()
+	p.nextToken()
+
+	return p
+}
+
+// ErrParameterCount when the request parameters don't match the statement placeholders
+var ErrParameterCount = types.NewError("ValidationException", "Number of parameters in request and statement don't match.", nil)
+
+// Parse parses a PartiQL statement expecting the given number of ? parameters,
+// syntax errors are reported as a ValidationException
+func Parse(statement string, parameters int) (Statement, error) {
+	p := NewParser(NewLexer(statement))
+
+	stmt := p.ParseStatement()
+	if len(p.errors) > 0 {
+		return nil, validationError(p.errors[0])
+	}
+
+	if p.params != parameters {
+		return nil, ErrParameterCount
+	}
+
+	return stmt, nil
+}
+
+// Errors returns the parsing errors
+func (p *Parser) Errors() []string {
+	return p.errors
+}
+
+// Parameters returns the number of ? placeholders found in the statement
+func (p *Parser) Parameters() int {
+	return p.params
+}
+
+func (p *Parser) nextToken() {
+	p.curToken = p.peekToken
+	p.peekToken = p.l.NextToken()
+}
+
+func (p *Parser) curTokenIs(t language.TokenType) bool {
+	return p.curToken.Type == t
+}
+
+func (p *Parser) peekTokenIs(t language.TokenType) bool {
+	return p.peekToken.Type == t
+}
+
+func (p *Parser) expectPeek(t language.TokenType) bool {
+	if p.peekTokenIs(t) {
+		p.nextToken()
+
+		return true
+	}
+
+	p.peekError(t)
+
+	return false
+}
+
+func (p *Parser) peekError(t language.TokenType) {
+	p.errors = append(p.errors, fmt.Sprintf("expected %s, found %s", t, describe(p.peekToken)))
+}
+
+func (p *Parser) unexpected(tok language.Token) {
+	p.errors = append(p.errors, "unexpected "+describe(tok))
+}
+
+func describe(tok language.Token) string {
+	switch tok.Type {
+	case language.EOF:
+		return "end of statement"
+	case STRING:
+		return "string '" + tok.Literal + "'"
+	case language.ILLEGAL:
+		return "illegal character " + tok.Literal
+	}
+
+	return "token " + tok.Literal
+}
+
+func (p *Parser) peekPrecedence() int {
+	if prec, ok := precedences[p.peekToken.Type]; ok {
+		return prec
+	}
+
+	return precedenceValueLowest
+}
+
+// ParseStatement parses a full statement
+func (p *Parser) ParseStatement() Statement {
+	var stmt Statement
+
+	switch p.curToken.Type {
+	case SELECT:
+		if sel := p.parseSelectStatement(); sel != nil {
+			stmt = sel
+		}
+	case EXISTS:
+		if ex := p.parseExistsStatement(); ex != nil {
+			stmt = ex
+		}
+	case INSERT:
+		if ins := p.parseInsertStatement(); ins != nil {
+			stmt = ins
+		}
+	case UPDATE:
+		if upd := p.parseUpdateStatement(); upd != nil {
+			stmt = upd
+		}
+	case language.DELETE:
+		if del := p.parseDeleteStatement(); del != nil {
+			stmt = del
+		}
+	default:
+		p.unexpected(p.curToken)
+
+		return nil
+	}
+
+	if len(p.errors) == 0 && !p.peekTokenIs(language.EOF) {
+		p.unexpected(p.peekToken)
+	}
+
+	if len(p.errors) > 0 {
+		return nil
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseExistsStatement() *ExistsStatement {
+	if !p.expectPeek(language.LPAREN) || !p.expectPeek(SELECT) {
+		return nil
+	}
+
+	sel := p.parseSelectStatement()
+	if sel == nil || !p.expectPeek(language.RPAREN) {
+		return nil
+	}
+
+	return &ExistsStatement{Select: sel}
+}
+
+func (p *Parser) parseSelectStatement() *SelectStatement {
+	stmt := &SelectStatement{}
+
+	p.nextToken()
+
+	if p.curTokenIs(STAR) {
+		p.nextToken()
+	} else {
+		for {
+			path := p.parsePath()
+			if path == nil {
+				return nil
+			}
+
+			stmt.Projection = append(stmt.Projection, path)
+
+			p.nextToken()
+
+			if !p.curTokenIs(language.COMMA) {
+				break
+			}
+
+			p.nextToken()
+		}
+	}
+
+	if !p.curTokenIs(FROM) {
+		p.errors = append(p.errors, "expected FROM, found "+describe(p.curToken))
+
+		return nil
+	}
+
+	if !p.parseTarget(&stmt.Table, &stmt.Index) {
+		return nil
+	}
+
+	if p.peekTokenIs(WHERE) {
+		p.nextToken()
+		p.nextToken()
+
+		stmt.Where = p.parseExpression(precedenceValueLowest)
+		if stmt.Where == nil {
+			return nil
+		}
+	}
+
+	if p.peekTokenIs(ORDER) {
+		stmt.OrderBy = p.parseOrderBy()
+		if stmt.OrderBy == nil {
+			return nil
+		}
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseInsertStatement() *InsertStatement {
+	if !p.expectPeek(INTO) {
+		return nil
+	}
+
+	p.nextToken()
+
+	table, ok := p.parseName()
+	if !ok || !p.expectPeek(VALUE) {
+		return nil
+	}
+
+	p.nextToken()
+
+	value := p.parseValue()
+	if value == nil {
+		return nil
+	}
+
+	return &InsertStatement{Table: table, Value: value}
+}
+
+func (p *Parser) parseUpdateStatement() *UpdateStatement {
+	p.nextToken()
+
+	table, ok := p.parseName()
+	if !ok {
+		return nil
+	}
+
+	stmt := &UpdateStatement{Table: table}
+	remove := false
+
+	for {
+		comma := len(stmt.Actions) > 0 && p.peekTokenIs(language.COMMA)
+		if comma {
+			p.nextToken()
+		}
+
+		if p.peekTokenIs(language.SET) || p.peekTokenIs(language.REMOVE) {
+			p.nextToken()
+
+			remove = p.curTokenIs(language.REMOVE)
+		} else if !comma {
+			break
+		}
+
+		p.nextToken()
+
+		action := p.parseUpdateAction(remove)
+		if action == nil {
+			return nil
+		}
+
+		stmt.Actions = append(stmt.Actions, action)
+	}
+
+	if len(stmt.Actions) == 0 {
+		p.errors = append(p.errors, "expected SET or REMOVE, found "+describe(p.peekToken))
+
+		return nil
+	}
+
+	stmt.Where = p.parseWhere()
+	if stmt.Where == nil {
+		return nil
+	}
+
+	stmt.Returning, ok = p.parseReturning()
+	if !ok {
+		return nil
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseUpdateAction(remove bool) *UpdateAction {
+	path := p.parsePath()
+	if path == nil {
+		return nil
+	}
+
+	if remove {
+		return &UpdateAction{Path: path}
+	}
+
+	if !p.expectPeek(language.EQ) {
+		return nil
+	}
+
+	p.nextToken()
+
+	left := p.parseSetOperand()
+	if left == nil {
+		return nil
+	}
+
+	if !p.peekTokenIs(language.PLUS) && !p.peekTokenIs(language.MINUS) {
+		return &UpdateAction{Path: path, Value: left}
+	}
+
+	p.nextToken()
+
+	operator := p.curToken.Literal
+
+	p.nextToken()
+
+	right := p.parseSetOperand()
+	if right == nil {
+		return nil
+	}
+
+	return &UpdateAction{Path: path, Value: &InfixExpression{Operator: operator, Left: left, Right: right}}
+}
+
+// parseSetOperand parses a path, a value or a function call of a SET clause
+func (p *Parser) parseSetOperand() Expression {
+	switch {
+	case p.curTokenIs(language.IDENT) && p.peekTokenIs(language.LPAREN):
+		return p.parseCallExpression(updateFunctions, p.parseSetOperand)
+	case p.curTokenIs(language.IDENT) || p.curTokenIs(QUOTED):
+		return p.parsePathExpression()
+	}
+
+	return p.parseValue()
+}
+
+func (p *Parser) parseDeleteStatement() *DeleteStatement {
+	if !p.expectPeek(FROM) {
+		return nil
+	}
+
+	p.nextToken()
+
+	table, ok := p.parseName()
+	if !ok {
+		return nil
+	}
+
+	stmt := &DeleteStatement{Table: table}
+
+	stmt.Where = p.parseWhere()
+	if stmt.Where == nil {
+		return nil
+	}
+
+	stmt.Returning, ok = p.parseReturning()
+	if !ok {
+		return nil
+	}
+
+	return stmt
+}
+
+// parseWhere parses the mandatory WHERE clause of UPDATE and DELETE statements
+func (p *Parser) parseWhere() Expression {
+	if !p.expectPeek(WHERE) {
+		return nil
+	}
+
+	p.nextToken()
+
+	return p.parseExpression(precedenceValueLowest)
+}
+
+// parseReturning parses an optional RETURNING (ALL|MODIFIED) (OLD|NEW) * clause
+func (p *Parser) parseReturning() (*Returning, bool) {
+	if !p.peekTokenIs(RETURNING) {
+		return nil, true
+	}
+
+	p.nextToken()
+	p.nextToken()
+
+	r := &Returning{}
+
+	switch p.curToken.Type {
+	case ALL:
+	case MODIFIED:
+		r.Modified = true
+	default:
+		p.errors = append(p.errors, "expected ALL or MODIFIED, found "+describe(p.curToken))
+
+		return nil, false
+	}
+
+	p.nextToken()
+
+	switch p.curToken.Type {
+	case OLD:
+	case NEW:
+		r.New = true
+	default:
+		p.errors = append(p.errors, "expected OLD or NEW, found "+describe(p.curToken))
+
+		return nil, false
+	}
+
+	if !p.expectPeek(STAR) {
+		return nil, false
+	}
+
+	return r, true
+}
+
+// parseTarget reads "table" or "table"."index" following the current token
+func (p *Parser) parseTarget(table, index *string) bool {
+	p.nextToken()
+
+	name, ok := p.parseName()
+	if !ok {
+		return false
+	}
+
+	*table = name
+
+	if !p.peekTokenIs(language.DOT) {
+		return true
+	}
+
+	p.nextToken()
+	p.nextToken()
+
+	*index, ok = p.parseName()
+
+	return ok
+}
+
+func (p *Parser) parseName() (string, bool) {
+	if p.curTokenIs(QUOTED) || p.curTokenIs(language.IDENT) {
+		return p.curToken.Literal, true
+	}
+
+	p.errors = append(p.errors, "expected identifier, found "+describe(p.curToken))
+
+	return "", false
+}
+
+func (p *Parser) parseOrderBy() *OrderBy {
+	p.nextToken()
+
+	if !p.expectPeek(BY) {
+		return nil
+	}
+
+	p.nextToken()
+
+	path := p.parsePath()
+	if path == nil {
+		return nil
+	}
+
+	ob := &OrderBy{Path: path}
+
+	switch {
+	case p.peekTokenIs(DESC):
+		ob.Descending = true
+
+		p.nextToken()
+	case p.peekTokenIs(ASC):
+		p.nextToken()
+	}
+
+	return ob
+}
+
+// parsePath parses a document path starting at the current token
+func (p *Parser) parsePath() *Path {
+	name, ok := p.parseName()
+	if !ok {
+		return nil
+	}
+
+	path := &Path{Elements: []PathElement{{Name: name}}}
+
+	for p.peekTokenIs(language.DOT) || p.peekTokenIs(language.LBRACKET) {
+		p.nextToken()
+
+		if p.curTokenIs(language.DOT) {
+			p.nextToken()
+
+			name, ok := p.parseName()
+			if !ok {
+				return nil
+			}
+
+			path.Elements = append(path.Elements, PathElement{Name: name})
+
+			continue
+		}
+
+		if !p.expectPeek(NUMBER) {
+			return nil
+		}
+
+		idx, err := strconv.Atoi(p.curToken.Literal)
+		if err != nil || idx < 0 {
+			p.errors = append(p.errors, "invalid list index "+p.curToken.Literal)
+
+			return nil
+		}
+
+		if !p.expectPeek(language.RBRACKET) {
+			return nil
+		}
+
+		path.Elements = append(path.Elements, PathElement{Index: idx, IsIndex: true})
+	}
+
+	return path
+}
+
+func (p *Parser) parseExpression(precedence int) Expression {
+	left := p.parsePrefix()
+
+	for left != nil && !p.peekTokenIs(language.EOF) && precedence < p.peekPrecedence() {
+		p.nextToken()
+
+		left = p.parseInfix(left)
+	}
+
+	return left
+}
+
+func (p *Parser) parsePrefix() Expression {
+	switch p.curToken.Type {
+	case language.NOT:
+		p.nextToken()
+
+		right := p.parseExpression(precedenceValueNOT)
+		if right == nil {
+			return nil
+		}
+
+		return &NotExpression{Right: right}
+	case language.LPAREN:
+		p.nextToken()
+
+		exp := p.parseExpression(precedenceValueLowest)
+		if exp == nil || !p.expectPeek(language.RPAREN) {
+			return nil
+		}
+
+		return exp
+	case language.IDENT:
+		if p.peekTokenIs(language.LPAREN) {
+			return p.parseCallExpression(conditionFunctions, func() Expression {
+				return p.parseExpression(precedenceValueLowest)
+			})
+		}
+
+		return p.parsePathExpression()
+	case QUOTED:
+		return p.parsePathExpression()
+	}
+
+	return p.parseValue()
+}
+
+// parsePathExpression avoids returning a typed nil inside the Expression interface
+func (p *Parser) parsePathExpression() Expression {
+	if path := p.parsePath(); path != nil {
+		return path
+	}
+
+	return nil
+}
+
+// parseValue parses literals and parameters
+func (p *Parser) parseValue() Expression {
+	switch p.curToken.Type {
+	case STRING:
+		return &Literal{Kind: LiteralString, Value: p.curToken.Literal}
+	case NUMBER:
+		return &Literal{Kind: LiteralNumber, Value: p.curToken.Literal}
+	case language.MINUS:
+		if !p.expectPeek(NUMBER) {
+			return nil
+		}
+
+		return &Literal{Kind: LiteralNumber, Value: "-" + p.curToken.Literal}
+	case TRUE, FALSE:
+		return &Literal{Kind: LiteralBoolean, Value: strings.ToLower(p.curToken.Literal)}
+	case NULL:
+		return &Literal{Kind: LiteralNull, Value: "NULL"}
+	case PARAM:
+		p.params++
+
+		return &Parameter{Position: p.params - 1}
+	case language.LBRACKET:
+		elements, ok := p.parseValueList(language.RBRACKET)
+		if !ok {
+			return nil
+		}
+
+		return &ListLiteral{Elements: elements}
+	case LDANGLE:
+		elements, ok := p.parseValueList(RDANGLE)
+		if !ok {
+			return nil
+		}
+
+		return &SetLiteral{Elements: elements}
+	case LBRACE:
+		return p.parseMapLiteral()
+	}
+
+	p.unexpected(p.curToken)
+
+	return nil
+}
+
+// parseValueList parses comma separated values until the end token
+func (p *Parser) parseValueList(end language.TokenType) ([]Expression, bool) {
+	elements := []Expression{}
+
+	if p.peekTokenIs(end) {
+		p.nextToken()
+
+		return elements, true
+	}
+
+	for {
+		p.nextToken()
+
+		value := p.parseValue()
+		if value == nil {
+			return nil, false
+		}
+
+		elements = append(elements, value)
+
+		if !p.peekTokenIs(language.COMMA) {
+			break
+		}
+
+		p.nextToken()
+	}
+
+	return elements, p.expectPeek(end)
+}
+
+func (p *Parser) parseMapLiteral() Expression {
+	ml := &MapLiteral{}
+
+	if p.peekTokenIs(RBRACE) {
+		p.nextToken()
+
+		return ml
+	}
+
+	for {
+		if !p.expectPeek(STRING) {
+			return nil
+		}
+
+		key := p.curToken.Literal
+
+		if !p.expectPeek(COLON) {
+			return nil
+		}
+
+		p.nextToken()
+
+		value := p.parseValue()
+		if value == nil {
+			return nil
+		}
+
+		ml.Entries = append(ml.Entries, MapEntry{Key: key, Value: value})
+
+		if !p.peekTokenIs(language.COMMA) {
+			break
+		}
+
+		p.nextToken()
+	}
+
+	if !p.expectPeek(RBRACE) {
+		return nil
+	}
+
+	return ml
+}
+
+func (p *Parser) parseCallExpression(functions map[string]int, parseArgument func() Expression) Expression {
+	name := strings.ToLower(p.curToken.Literal)
+
+	arity, ok := functions[name]
+	if !ok {
+		p.errors = append(p.errors, "unsupported function "+p.curToken.Literal)
+
+		return nil
+	}
+
+	p.nextToken()
+
+	call := &CallExpression{Function: name}
+
+	for !p.peekTokenIs(language.RPAREN) {
+		p.nextToken()
+
+		arg := parseArgument()
+		if arg == nil {
+			return nil
+		}
+
+		call.Arguments = append(call.Arguments, arg)
+
+		if !p.peekTokenIs(language.COMMA) {
+			break
+		}
+
+		p.nextToken()
+	}
+
+	if !p.expectPeek(language.RPAREN) {
+		return nil
+	}
+
+	if len(call.Arguments) != arity {
+		p.errors = append(p.errors, fmt.Sprintf("function %s expects %d arguments, found %d", name, arity, len(call.Arguments)))
+
+		return nil
+	}
+
+	return call
+}
+
+func (p *Parser) parseInfix(left Expression) Expression {
+	switch p.curToken.Type {
+	case language.BETWEEN:
+		return p.parseBetweenExpression(left)
+	case language.IN:
+		return p.parseInExpression(left)
+	case IS:
+		return p.parseIsExpression(left)
+	}
+
+	operator := strings.ToUpper(p.curToken.Literal)
+	if p.curTokenIs(language.NotEQ) {
+		operator = "<>"
+	}
+
+	precedence := precedences[p.curToken.Type]
+
+	p.nextToken()
+
+	right := p.parseExpression(precedence)
+	if right == nil {
+		return nil
+	}
+
+	return &InfixExpression{Operator: operator, Left: left, Right: right}
+}
+
+func (p *Parser) parseBetweenExpression(left Expression) Expression {
+	p.nextToken()
+
+	low := p.parseExpression(precedenceValueComparators)
+	if low == nil || !p.expectPeek(language.AND) {
+		return nil
+	}
+
+	p.nextToken()
+
+	high := p.parseExpression(precedenceValueComparators)
+	if high == nil {
+		return nil
+	}
+
+	return &BetweenExpression{Left: left, Low: low, High: high}
+}
+
+func (p *Parser) parseInExpression(left Expression) Expression {
+	end := language.RBRACKET
+
+	switch {
+	case p.peekTokenIs(language.LBRACKET):
+	case p.peekTokenIs(language.LPAREN):
+		end = language.RPAREN
+	default:
+		p.peekError(language.LBRACKET)
+
+		return nil
+	}
+
+	p.nextToken()
+
+	values, ok := p.parseValueList(end)
+	if !ok {
+		return nil
+	}
+
+	if len(values) == 0 {
+		p.errors = append(p.errors, "IN requires at least one value")
+
+		return nil
+	}
+
+	return &InExpression{Left: left, Values: values}
+}
+
+func (p *Parser) parseIsExpression(left Expression) Expression {
+	is := &IsExpression{Left: left}
+
+	if p.peekTokenIs(language.NOT) {
+		is.Not = true
+
+		p.nextToken()
+	}
+
+	p.nextToken()
+
+	switch p.curToken.Type {
+	case NULL:
+	case MISSING:
+		is.Missing = true
+	default:
+		p.errors = append(p.errors, "expected NULL or MISSING, found "+describe(p.curToken))
+
+		return nil
+	}
+
+	return is
+}
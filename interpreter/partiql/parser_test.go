@@ -0,0 +1,132 @@
+package partiql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSelect(t *testing.T) {
+	tests := map[string]string{
+		`SELECT * FROM pokemons`: `SELECT * FROM "pokemons"`,
+		`select "name", stats.hp, moves[0] from "pokemons"."by-type"`:    `SELECT "name", "stats"."hp", "moves"[0] FROM "pokemons"."by-type"`,
+		`SELECT * FROM t WHERE id = ? AND lvl BETWEEN 1 AND 10`:          `SELECT * FROM "t" WHERE (("id" = ?) AND ("lvl" BETWEEN 1 AND 10))`,
+		`SELECT * FROM t WHERE a = 1 OR b = 2 AND c = 3`:                 `SELECT * FROM "t" WHERE (("a" = 1) OR (("b" = 2) AND ("c" = 3)))`,
+		`SELECT * FROM t WHERE NOT a = 1 AND b = 2`:                      `SELECT * FROM "t" WHERE ((NOT ("a" = 1)) AND ("b" = 2))`,
+		`SELECT * FROM t WHERE (a = 1 OR b = 2) AND c = 3`:               `SELECT * FROM "t" WHERE ((("a" = 1) OR ("b" = 2)) AND ("c" = 3))`,
+		`SELECT * FROM t WHERE type IN ['fire', ?]`:                      `SELECT * FROM "t" WHERE ("type" IN ['fire', ?])`,
+		`SELECT * FROM t WHERE type IN ('fire')`:                         `SELECT * FROM "t" WHERE ("type" IN ['fire'])`,
+		`SELECT * FROM t WHERE BEGINS_WITH(name, 'Pi')`:                  `SELECT * FROM "t" WHERE begins_with("name", 'Pi')`,
+		`SELECT * FROM t WHERE size(moves) > 2`:                          `SELECT * FROM "t" WHERE (size("moves") > 2)`,
+		`SELECT * FROM t WHERE a IS MISSING AND b IS NOT NULL`:           `SELECT * FROM "t" WHERE (("a" IS MISSING) AND ("b" IS NOT NULL))`,
+		`SELECT * FROM t WHERE a = {'k': [1, TRUE, NULL], 's': <<'x'>>}`: `SELECT * FROM "t" WHERE ("a" = {'k': [1, true, NULL], 's': <<'x'>>})`,
+		`SELECT * FROM t WHERE id = 'x' ORDER BY sk DESC`:                `SELECT * FROM "t" WHERE ("id" = 'x') ORDER BY "sk" DESC`,
+		`SELECT * FROM t WHERE id = 'x' ORDER BY sk`:                     `SELECT * FROM "t" WHERE ("id" = 'x') ORDER BY "sk" ASC`,
+		`EXISTS(SELECT * FROM t WHERE id = 'x')`:                         `EXISTS(SELECT * FROM "t" WHERE ("id" = 'x'))`,
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			c := require.New(t)
+
+			p := NewParser(NewLexer(input))
+			stmt := p.ParseStatement()
+			c.Empty(p.Errors())
+			c.Equal(expected, stmt.String())
+		})
+	}
+}
+
+func TestParseDML(t *testing.T) {
+	tests := map[string]string{
+		`INSERT INTO pokemons VALUE {'id': ?, 'lvl': -5}`:                                                 `INSERT INTO "pokemons" VALUE {'id': ?, 'lvl': -5}`,
+		`insert into "pokemons" value ?`:                                                                  `INSERT INTO "pokemons" VALUE ?`,
+		`UPDATE t SET a = ?, REMOVE b WHERE id = ?`:                                                       `UPDATE "t" SET "a" = ? REMOVE "b" WHERE ("id" = ?)`,
+		`UPDATE t SET a = 1 SET b.c[0] = d + 2, e = e - 1 REMOVE f, g WHERE id = 'x'`:                     `UPDATE "t" SET "a" = 1 SET "b"."c"[0] = ("d" + 2) SET "e" = ("e" - 1) REMOVE "f" REMOVE "g" WHERE ("id" = 'x')`,
+		`UPDATE t SET l = list_append(if_not_exists(l, []), [?]) WHERE id = 'x' RETURNING MODIFIED NEW *`: `UPDATE "t" SET "l" = list_append(if_not_exists("l", []), [?]) WHERE ("id" = 'x') RETURNING MODIFIED NEW *`,
+		`UPDATE t SET s = set_add(s, <<'a'>>) WHERE id = 'x' RETURNING ALL OLD *`:                         `UPDATE "t" SET "s" = set_add("s", <<'a'>>) WHERE ("id" = 'x') RETURNING ALL OLD *`,
+		`DELETE FROM t WHERE id = 'x' AND lvl > 1`:                                                        `DELETE FROM "t" WHERE (("id" = 'x') AND ("lvl" > 1))`,
+		`DELETE FROM t WHERE id = 'x' RETURNING ALL OLD *`:                                                `DELETE FROM "t" WHERE ("id" = 'x') RETURNING ALL OLD *`,
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			c := require.New(t)
+
+			p := NewParser(NewLexer(input))
+			stmt := p.ParseStatement()
+			c.Empty(p.Errors())
+			c.Equal(expected, stmt.String())
+		})
+	}
+}
+
+func TestReturningReturnValues(t *testing.T) {
+	c := require.New(t)
+
+	c.Equal("ALL_OLD", (&Returning{}).ReturnValues())
+	c.Equal("ALL_NEW", (&Returning{New: true}).ReturnValues())
+	c.Equal("UPDATED_OLD", (&Returning{Modified: true}).ReturnValues())
+	c.Equal("UPDATED_NEW", (&Returning{Modified: true, New: true}).ReturnValues())
+}
+
+func TestParseSelectTarget(t *testing.T) {
+	c := require.New(t)
+
+	stmt, err := Parse(`SELECT id FROM "pokemons"."by-type" WHERE type = ?`, 1)
+	c.NoError(err)
+
+	sel, ok := stmt.(*SelectStatement)
+	c.True(ok)
+	c.Equal("pokemons", sel.Table)
+	c.Equal("pokemons", sel.TableName())
+	c.Equal("by-type", sel.Index)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		`SELEC * FROM t`:                                  "unexpected token SELEC",
+		`SELECT * t`:                                      "expected FROM, found token t",
+		`SELECT * FROM t WHERE`:                           "unexpected end of statement",
+		`SELECT * FROM t WHERE a = 1 extra`:               "unexpected token extra",
+		`SELECT * FROM t WHERE a IS EMPTY`:                "expected NULL or MISSING, found token EMPTY",
+		`SELECT * FROM t WHERE a IN []`:                   "IN requires at least one value",
+		`SELECT * FROM t WHERE unknown(a)`:                "unsupported function unknown",
+		`SELECT * FROM t WHERE begins_with(a)`:            "function begins_with expects 2 arguments, found 1",
+		`SELECT * FROM t WHERE a = 'open`:                 "unexpected illegal character open",
+		`SELECT * FROM t ORDER id`:                        "expected BY, found token id",
+		`INSERT t VALUE {}`:                               "expected INTO, found token t",
+		`INSERT INTO t {'a': 1}`:                          "expected VALUE, found token {",
+		`UPDATE t WHERE id = 1`:                           "expected SET or REMOVE, found token WHERE",
+		`UPDATE t SET a = 1`:                              "expected WHERE, found end of statement",
+		`UPDATE t SET a = contains(a, 1) WHERE id = 1`:    "unsupported function contains",
+		`UPDATE t SET a = 1 WHERE id = 1 RETURNING NEW *`: "expected ALL or MODIFIED, found token NEW",
+		`UPDATE t SET a = 1 WHERE id = 1 RETURNING ALL *`: "expected OLD or NEW, found token *",
+		`DELETE t WHERE id = 1`:                           "expected FROM, found token t",
+		`DELETE FROM t`:                                   "expected WHERE, found end of statement",
+		`DELETE FROM t WHERE id = 1 RETURNING ALL OLD`:    "expected *, found end of statement",
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			c := require.New(t)
+
+			p := NewParser(NewLexer(input))
+			c.Nil(p.ParseStatement())
+			c.Equal(expected, p.Errors()[0])
+		})
+	}
+}
+
+func TestParseParameters(t *testing.T) {
+	c := require.New(t)
+
+	_, err := Parse(`SELECT * FROM t WHERE a = ? AND b IN [?, ?]`, 3)
+	c.NoError(err)
+
+	_, err = Parse(`SELECT * FROM t WHERE a = ?`, 2)
+	c.Equal(ErrParameterCount, err)
+
+	_, err = Parse(`SELECT * FROM`, 0)
+	c.EqualError(err, "ValidationException: Statement wasn't well formed, can't be processed: expected identifier, found end of statement")
+}
@@ -0,0 +1,180 @@
+package partiql
+
+// KeyCondition is the part of a WHERE clause that can be answered with a key lookup
+type KeyCondition struct {
+	// Hash the equality on the partition key
+	Hash Expression
+	// Range the optional condition on the sort key
+	Range Expression
+	// Filter the remaining conditions
+	Filter Expression
+}
+
+var flippedOperators = map[string]string{
+	"=":  "=",
+	"<":  ">",
+	"<=": ">=",
+	">":  "<",
+	">=": "<=",
+}
+
+// SplitKeyCondition looks in the top level AND conditions of where for an equality on
+// hashKey and at most one condition on rangeKey usable as a KeyConditionExpression,
+// it returns nil when the partition key is not pinned and the statement needs a scan
+func SplitKeyCondition(where Expression, hashKey, rangeKey string) *KeyCondition {
+	kc := &KeyCondition{}
+
+	var rest []Expression
+
+	for _, conjunct := range conjuncts(where) {
+		switch {
+		case kc.Hash == nil && isKeyEquality(conjunct, hashKey):
+			kc.Hash = normalizeComparison(conjunct)
+		case kc.Range == nil && rangeKey != "" && isRangeCondition(conjunct, rangeKey):
+			kc.Range = normalizeComparison(conjunct)
+		default:
+			rest = append(rest, conjunct)
+		}
+	}
+
+	if kc.Hash == nil {
+		return nil
+	}
+
+	kc.Filter = JoinConditions(rest...)
+
+	return kc
+}
+
+// JoinConditions combines the non nil conditions with AND, it returns nil when there are none
+func JoinConditions(exps ...Expression) Expression {
+	var out Expression
+
+	for _, exp := range exps {
+		if exp == nil {
+			continue
+		}
+
+		if out == nil {
+			out = exp
+
+			continue
+		}
+
+		out = &InfixExpression{Operator: "AND", Left: out, Right: exp}
+	}
+
+	return out
+}
+
+func conjuncts(exp Expression) []Expression {
+	if exp == nil {
+		return nil
+	}
+
+	if infix, ok := exp.(*InfixExpression); ok && infix.Operator == "AND" {
+		return append(conjuncts(infix.Left), conjuncts(infix.Right)...)
+	}
+
+	return []Expression{exp}
+}
+
+func isKeyPath(exp Expression, key string) bool {
+	path, ok := exp.(*Path)
+
+	return ok && path.IsAttribute() && path.Attribute() == key
+}
+
+func isValue(exp Expression) bool {
+	switch exp.(type) {
+	case *Literal, *Parameter:
+		return true
+	}
+
+	return false
+}
+
+// keyComparison reports if exp compares the key with a value, in any order
+func keyComparison(exp Expression, key string) (*InfixExpression, bool) {
+	infix, ok := exp.(*InfixExpression)
+	if !ok {
+		return nil, false
+	}
+
+	if _, ok := flippedOperators[infix.Operator]; !ok {
+		return nil, false
+	}
+
+	matches := isKeyPath(infix.Left, key) && isValue(infix.Right) ||
+		isKeyPath(infix.Right, key) && isValue(infix.Left)
+
+	return infix, matches
+}
+
+func isKeyEquality(exp Expression, key string) bool {
+	infix, ok := keyComparison(exp, key)
+
+	return ok && infix.Operator == "="
+}
+
+func isRangeCondition(exp Expression, key string) bool {
+	if _, ok := keyComparison(exp, key); ok {
+		return true
+	}
+
+	switch e := exp.(type) {
+	case *BetweenExpression:
+		return isKeyPath(e.Left, key) && isValue(e.Low) && isValue(e.High)
+	case *CallExpression:
+		return e.Function == "begins_with" && isKeyPath(e.Arguments[0], key) && isValue(e.Arguments[1])
+	}
+
+	return false
+}
+
+// normalizeComparison moves the key path to the left side of a comparison
+func normalizeComparison(exp Expression) Expression {
+	infix, ok := exp.(*InfixExpression)
+	if !ok || isPath(infix.Left) {
+		return exp
+	}
+
+	return &InfixExpression{Operator: flippedOperators[infix.Operator], Left: infix.Right, Right: infix.Left}
+}
+
+func isPath(exp Expression) bool {
+	_, ok := exp.(*Path)
+
+	return ok
+}
+
+// ExtractKey looks in the top level AND conditions of where for an equality between each
+// key attribute and a value, it returns those values and the remaining conditions. It
+// reports false when any key attribute is not pinned
+func ExtractKey(where Expression, keys ...string) (map[string]Expression, Expression, bool) {
+	values := map[string]Expression{}
+
+	var rest []Expression
+
+	for _, conjunct := range conjuncts(where) {
+		matched := false
+
+		for _, key := range keys {
+			if _, found := values[key]; found || !isKeyEquality(conjunct, key) {
+				continue
+			}
+
+			infix := normalizeComparison(conjunct).(*InfixExpression)
+			values[key] = infix.Right
+			matched = true
+
+			break
+		}
+
+		if !matched {
+			rest = append(rest, conjunct)
+		}
+	}
+
+	return values, JoinConditions(rest...), len(values) == len(keys)
+}
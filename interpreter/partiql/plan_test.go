@@ -0,0 +1,96 @@
+package partiql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitKeyCondition(t *testing.T) {
+	tests := []struct {
+		where  string
+		hash   string
+		rng    string
+		filter string
+	}{
+		{`id = 'a'`, `("id" = 'a')`, "", ""},
+		{`'a' = id AND 5 < lvl`, `("id" = 'a')`, `("lvl" > 5)`, ""},
+		{`name = 'x' AND id = 'a' AND lvl BETWEEN 1 AND 2`, `("id" = 'a')`, `("lvl" BETWEEN 1 AND 2)`, `("name" = 'x')`},
+		{`begins_with(lvl, '1') AND id = ? AND lvl < 3`, `("id" = ?)`, `begins_with("lvl", '1')`, `("lvl" < 3)`},
+		{`id = 'a' AND lvl <> 3 AND a = 1`, `("id" = 'a')`, "", `(("lvl" <> 3) AND ("a" = 1))`},
+		{`id = 'a' AND id = 'b'`, `("id" = 'a')`, "", `("id" = 'b')`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.where, func(t *testing.T) {
+			c := require.New(t)
+
+			kc := SplitKeyCondition(parseWhere(c, tt.where), "id", "lvl")
+			c.NotNil(kc)
+			c.Equal(tt.hash, kc.Hash.String())
+
+			if tt.rng == "" {
+				c.Nil(kc.Range)
+			} else {
+				c.Equal(tt.rng, kc.Range.String())
+			}
+
+			if tt.filter == "" {
+				c.Nil(kc.Filter)
+			} else {
+				c.Equal(tt.filter, kc.Filter.String())
+			}
+		})
+	}
+}
+
+func TestSplitKeyConditionScan(t *testing.T) {
+	for _, where := range []string{
+		`lvl = 1`,
+		`id = 'a' OR id = 'b'`,
+		`id > 'a'`,
+		`id IN ['a']`,
+		`id = lvl`,
+		`id.nested = 'a'`,
+	} {
+		t.Run(where, func(t *testing.T) {
+			c := require.New(t)
+
+			c.Nil(SplitKeyCondition(parseWhere(c, where), "id", "lvl"))
+		})
+	}
+}
+
+func TestExtractKey(t *testing.T) {
+	c := require.New(t)
+
+	values, rest, ok := ExtractKey(parseWhere(c, `lvl > 1 AND 'a' = id AND sk = ? AND name = 'x'`), "id", "sk")
+	c.True(ok)
+	c.Equal(`'a'`, values["id"].String())
+	c.Equal(`?`, values["sk"].String())
+	c.Equal(`(("lvl" > 1) AND ("name" = 'x'))`, rest.String())
+
+	values, rest, ok = ExtractKey(parseWhere(c, `id = 'a'`), "id")
+	c.True(ok)
+	c.Len(values, 1)
+	c.Nil(rest)
+
+	_, _, ok = ExtractKey(parseWhere(c, `id = 'a' AND sk > 1`), "id", "sk")
+	c.False(ok)
+
+	_, _, ok = ExtractKey(parseWhere(c, `id = 'a' OR id = 'b'`), "id")
+	c.False(ok)
+}
+
+func TestJoinConditions(t *testing.T) {
+	c := require.New(t)
+
+	c.Nil(JoinConditions())
+	c.Nil(JoinConditions(nil, nil))
+
+	a := parseWhere(c, `a = 1`)
+	b := parseWhere(c, `b = 2`)
+
+	c.Equal(a, JoinConditions(nil, a))
+	c.Equal(`(("a" = 1) AND ("b" = 2))`, JoinConditions(a, nil, b).String())
+}
@@ -0,0 +1,118 @@
+package partiql
+
+import (
+	"strings"
+
+	"github.com/truora/minidyn/interpreter/language"
+)
+
+// Tokens shared with the expression language keep their language.TokenType
+const (
+	// PARAM positional parameter placeholder
+	PARAM language.TokenType = "?"
+	// STRING single quoted string literal
+	STRING language.TokenType = "STRING"
+	// NUMBER numeric literal
+	NUMBER language.TokenType = "NUMBER"
+	// QUOTED double quoted identifier
+	QUOTED language.TokenType = "QUOTED"
+	// STAR projection wildcard
+	STAR language.TokenType = "*"
+	// LBRACE left brace delimiter of map literals
+	LBRACE language.TokenType = "{"
+	// RBRACE right brace delimiter of map literals
+	RBRACE language.TokenType = "}"
+	// COLON separates the keys and values of map literals
+	COLON language.TokenType = ":"
+	// LDANGLE left delimiter of set literals
+	LDANGLE language.TokenType = "<<"
+	// RDANGLE right delimiter of set literals
+	RDANGLE language.TokenType = ">>"
+
+	// SELECT statement keyword
+	SELECT language.TokenType = "SELECT"
+	// FROM statement keyword
+	FROM language.TokenType = "FROM"
+	// WHERE statement keyword
+	WHERE language.TokenType = "WHERE"
+	// IS comparison keyword for NULL and MISSING
+	IS language.TokenType = "IS"
+	// NULL literal keyword
+	NULL language.TokenType = "NULL"
+	// MISSING keyword for absent attributes
+	MISSING language.TokenType = "MISSING"
+	// TRUE literal keyword
+	TRUE language.TokenType = "TRUE"
+	// FALSE literal keyword
+	FALSE language.TokenType = "FALSE"
+	// ORDER statement keyword
+	ORDER language.TokenType = "ORDER"
+	// BY statement keyword
+	BY language.TokenType = "BY"
+	// ASC ascending sort keyword
+	ASC language.TokenType = "ASC"
+	// DESC descending sort keyword
+	DESC language.TokenType = "DESC"
+	// EXISTS transaction condition keyword
+	EXISTS language.TokenType = "EXISTS"
+	// INSERT statement keyword
+	INSERT language.TokenType = "INSERT"
+	// INTO statement keyword
+	INTO language.TokenType = "INTO"
+	// VALUE statement keyword
+	VALUE language.TokenType = "VALUE"
+	// UPDATE statement keyword
+	UPDATE language.TokenType = "UPDATE"
+	// RETURNING statement keyword
+	RETURNING language.TokenType = "RETURNING"
+	// ALL returning keyword
+	ALL language.TokenType = "ALL"
+	// MODIFIED returning keyword
+	MODIFIED language.TokenType = "MODIFIED"
+	// OLD returning keyword
+	OLD language.TokenType = "OLD"
+	// NEW returning keyword
+	NEW language.TokenType = "NEW"
+)
+
+var keywords = map[string]language.TokenType{
+	"SELECT":    SELECT,
+	"FROM":      FROM,
+	"WHERE":     WHERE,
+	"AND":       language.AND,
+	"OR":        language.OR,
+	"NOT":       language.NOT,
+	"BETWEEN":   language.BETWEEN,
+	"IN":        language.IN,
+	"IS":        IS,
+	"NULL":      NULL,
+	"MISSING":   MISSING,
+	"TRUE":      TRUE,
+	"FALSE":     FALSE,
+	"ORDER":     ORDER,
+	"BY":        BY,
+	"ASC":       ASC,
+	"DESC":      DESC,
+	"EXISTS":    EXISTS,
+	"INSERT":    INSERT,
+	"INTO":      INTO,
+	"VALUE":     VALUE,
+	"UPDATE":    UPDATE,
+	"SET":       language.SET,
+	"REMOVE":    language.REMOVE,
+	"DELETE":    language.DELETE,
+	"RETURNING": RETURNING,
+	"ALL":       ALL,
+	"MODIFIED":  MODIFIED,
+	"OLD":       OLD,
+	"NEW":       NEW,
+}
+
+// lookupIdent checks if the ident is a keyword
+func lookupIdent(ident string) language.TokenType {
+	if tok, ok := keywords[strings.ToUpper(ident)]; ok {
+		return tok
+	}
+
+	return language.IDENT
+}
@@ -0,0 +1,416 @@
+package partiql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/truora/minidyn/types"
+)
+
+// Translator converts PartiQL expressions into DynamoDB expressions, every path and value
+// is replaced by a placeholder registered in Names and Values
+type Translator struct {
+	Names  map[string]string
+	Values map[string]*types.Item
+
+	params  []*types.Item
+	aliases map[string]string
+}
+
+// NewTranslator creates a translator resolving ? placeholders with params
+func NewTranslator(params []*types.Item) *Translator {
+	return &Translator{
+		Names:   map[string]string{},
+		Values:  map[string]*types.Item{},
+		params:  params,
+		aliases: map[string]string{},
+	}
+}
+
+// Condition translates a WHERE expression into a condition expression
+func (tr *Translator) Condition(exp Expression) (string, error) {
+	switch e := exp.(type) {
+	case *InfixExpression:
+		return tr.infix(e)
+	case *NotExpression:
+		right, err := tr.Condition(e.Right)
+		if err != nil {
+			return "", err
+		}
+
+		return "(NOT " + right + ")", nil
+	case *BetweenExpression:
+		return tr.between(e)
+	case *InExpression:
+		return tr.in(e)
+	case *CallExpression:
+		return tr.call(e)
+	case *IsExpression:
+		return tr.is(e)
+	}
+
+	return "", validationError("unsupported condition " + exp.String())
+}
+
+func (tr *Translator) infix(e *InfixExpression) (string, error) {
+	translate := tr.Operand
+	if e.Operator == "AND" || e.Operator == "OR" {
+		translate = tr.Condition
+	}
+
+	left, err := translate(e.Left)
+	if err != nil {
+		return "", err
+	}
+
+	right, err := translate(e.Right)
+	if err != nil {
+		return "", err
+	}
+
+	return "(" + left + " " + e.Operator + " " + right + ")", nil
+}
+
+func (tr *Translator) between(e *BetweenExpression) (string, error) {
+	operands, err := tr.operands(e.Left, e.Low, e.High)
+	if err != nil {
+		return "", err
+	}
+
+	return "(" + operands[0] + " BETWEEN " + operands[1] + " AND " + operands[2] + ")", nil
+}
+
+func (tr *Translator) in(e *InExpression) (string, error) {
+	operands, err := tr.operands(append([]Expression{e.Left}, e.Values...)...)
+	if err != nil {
+		return "", err
+	}
+
+	return "(" + operands[0] + " IN (" + strings.Join(operands[1:], ", ") + "))", nil
+}
+
+func (tr *Translator) call(e *CallExpression) (string, error) {
+	operands, err := tr.operands(e.Arguments...)
+	if err != nil {
+		return "", err
+	}
+
+	return e.Function + "(" + strings.Join(operands, ", ") + ")", nil
+}
+
+func (tr *Translator) is(e *IsExpression) (string, error) {
+	path, ok := e.Left.(*Path)
+	if !ok {
+		return "", validationError("IS NULL and IS MISSING require a document path")
+	}
+
+	name := tr.Path(path)
+
+	if e.Missing {
+		if e.Not {
+			return "attribute_exists(" + name + ")", nil
+		}
+
+		return "attribute_not_exists(" + name + ")", nil
+	}
+
+	null := tr.value(&types.Item{S: new("NULL")})
+
+	if e.Not {
+		return "(attribute_exists(" + name + ") AND NOT attribute_type(" + name + ", " + null + "))", nil
+	}
+
+	return "(attribute_not_exists(" + name + ") OR attribute_type(" + name + ", " + null + "))", nil
+}
+
+func (tr *Translator) operands(exps ...Expression) ([]string, error) {
+	out := make([]string, 0, len(exps))
+
+	for _, exp := range exps {
+		operand, err := tr.Operand(exp)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, operand)
+	}
+
+	return out, nil
+}
+
+// Operand translates a path, a size() call or a value used as an operand
+func (tr *Translator) Operand(exp Expression) (string, error) {
+	switch e := exp.(type) {
+	case *Path:
+		return tr.Path(e), nil
+	case *CallExpression:
+		if e.Function != "size" {
+			return "", validationError("function " + e.Function + " can't be used as an operand")
+		}
+
+		return tr.call(e)
+	}
+
+	item, err := tr.Value(exp)
+	if err != nil {
+		return "", err
+	}
+
+	return tr.value(item), nil
+}
+
+// UpdateExpression translates the SET and REMOVE clauses of an UPDATE statement, set_add and
+// set_delete become ADD and DELETE actions
+func (tr *Translator) UpdateExpression(actions []*UpdateAction) (string, error) {
+	clauses := map[string][]string{}
+
+	for _, action := range actions {
+		clause, exp, err := tr.updateAction(action)
+		if err != nil {
+			return "", err
+		}
+
+		clauses[clause] = append(clauses[clause], exp)
+	}
+
+	out := []string{}
+
+	for _, clause := range []string{"SET", "REMOVE", "ADD", "DELETE"} {
+		if len(clauses[clause]) > 0 {
+			out = append(out, clause+" "+strings.Join(clauses[clause], ", "))
+		}
+	}
+
+	return strings.Join(out, " "), nil
+}
+
+func (tr *Translator) updateAction(action *UpdateAction) (string, string, error) {
+	path := tr.Path(action.Path)
+
+	if action.Value == nil {
+		return "REMOVE", path, nil
+	}
+
+	call, ok := action.Value.(*CallExpression)
+	if ok && (call.Function == "set_add" || call.Function == "set_delete") {
+		target, ok := call.Arguments[0].(*Path)
+		if !ok || target.String() != action.Path.String() {
+			return "", "", validationError(call.Function + " must update the attribute given as its first argument")
+		}
+
+		item, err := tr.Value(call.Arguments[1])
+		if err != nil {
+			return "", "", err
+		}
+
+		if call.Function == "set_add" {
+			return "ADD", path + " " + tr.value(item), nil
+		}
+
+		return "DELETE", path + " " + tr.value(item), nil
+	}
+
+	value, err := tr.setOperand(action.Value)
+	if err != nil {
+		return "", "", err
+	}
+
+	return "SET", path + " = " + value, nil
+}
+
+func (tr *Translator) setOperand(exp Expression) (string, error) {
+	switch e := exp.(type) {
+	case *Path:
+		return tr.Path(e), nil
+	case *InfixExpression:
+		left, err := tr.setOperand(e.Left)
+		if err != nil {
+			return "", err
+		}
+
+		right, err := tr.setOperand(e.Right)
+		if err != nil {
+			return "", err
+		}
+
+		return left + " " + e.Operator + " " + right, nil
+	case *CallExpression:
+		if e.Function == "set_add" || e.Function == "set_delete" {
+			return "", validationError(e.Function + " must be the whole value of a SET clause")
+		}
+
+		args := make([]string, 0, len(e.Arguments))
+
+		for _, arg := range e.Arguments {
+			operand, err := tr.setOperand(arg)
+			if err != nil {
+				return "", err
+			}
+
+			args = append(args, operand)
+		}
+
+		return e.Function + "(" + strings.Join(args, ", ") + ")", nil
+	}
+
+	item, err := tr.Value(exp)
+	if err != nil {
+		return "", err
+	}
+
+	return tr.value(item), nil
+}
+
+// Path translates a document path replacing every attribute name by a placeholder
+func (tr *Translator) Path(p *Path) string {
+	var out strings.Builder
+
+	for i, e := range p.Elements {
+		switch {
+		case e.IsIndex:
+			out.WriteString("[" + strconv.Itoa(e.Index) + "]")
+		case i == 0:
+			out.WriteString(tr.name(e.Name))
+		default:
+			out.WriteString("." + tr.name(e.Name))
+		}
+	}
+
+	return out.String()
+}
+
+// Projection translates the projected paths into a projection expression
+func (tr *Translator) Projection(paths []*Path) string {
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		out = append(out, tr.Path(p))
+	}
+
+	return strings.Join(out, ", ")
+}
+
+func (tr *Translator) name(attribute string) string {
+	if alias, ok := tr.aliases[attribute]; ok {
+		return alias
+	}
+
+	alias := fmt.Sprintf("#n%d", len(tr.aliases))
+	tr.aliases[attribute] = alias
+	tr.Names[alias] = attribute
+
+	return alias
+}
+
+func (tr *Translator) value(item *types.Item) string {
+	placeholder := fmt.Sprintf(":v%d", len(tr.Values))
+	tr.Values[placeholder] = item
+
+	return placeholder
+}
+
+// Value evaluates a literal or a parameter into an attribute value
+func (tr *Translator) Value(exp Expression) (*types.Item, error) {
+	switch e := exp.(type) {
+	case *Literal:
+		return literalValue(e)
+	case *Parameter:
+		if e.Position >= len(tr.params) {
+			return nil, ErrParameterCount
+		}
+
+		return tr.params[e.Position], nil
+	case *ListLiteral:
+		list, err := tr.values(e.Elements)
+		if err != nil {
+			return nil, err
+		}
+
+		return &types.Item{L: list}, nil
+	case *MapLiteral:
+		m := make(map[string]*types.Item, len(e.Entries))
+
+		for _, entry := range e.Entries {
+			v, err := tr.Value(entry.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			m[entry.Key] = v
+		}
+
+		return &types.Item{M: m}, nil
+	case *SetLiteral:
+		return tr.set(e)
+	}
+
+	return nil, validationError("expected a value, found " + exp.String())
+}
+
+func (tr *Translator) values(exps []Expression) ([]*types.Item, error) {
+	out := make([]*types.Item, 0, len(exps))
+
+	for _, exp := range exps {
+		v, err := tr.Value(exp)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, v)
+	}
+
+	return out, nil
+}
+
+func (tr *Translator) set(e *SetLiteral) (*types.Item, error) {
+	elements, err := tr.values(e.Elements)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &types.Item{}
+
+	for _, v := range elements {
+		switch {
+		case v.S != nil && set.NS == nil && set.BS == nil:
+			set.SS = append(set.SS, v.S)
+		case v.N != nil && set.SS == nil && set.BS == nil:
+			set.NS = append(set.NS, v.N)
+		case v.B != nil && set.SS == nil && set.NS == nil:
+			set.BS = append(set.BS, v.B)
+		default:
+			return nil, validationError("set elements must be strings, numbers or binaries of a single type")
+		}
+	}
+
+	if len(elements) == 0 {
+		return nil, validationError("an empty set is not allowed")
+	}
+
+	if err := types.ValidateItemAttributeValue(set); err != nil {
+		return nil, types.NewError("ValidationException", err.Error(), nil)
+	}
+
+	return set, nil
+}
+
+func literalValue(l *Literal) (*types.Item, error) {
+	switch l.Kind {
+	case LiteralNumber:
+		if _, err := types.ParseDecimal(l.Value); err != nil {
+			return nil, types.NewError("ValidationException", err.Error(), nil)
+		}
+
+		return &types.Item{N: new(l.Value)}, nil
+	case LiteralBoolean:
+		return &types.Item{BOOL: new(l.Value == "true")}, nil
+	case LiteralNull:
+		return &types.Item{NULL: new(true)}, nil
+	}
+
+	return &types.Item{S: new(l.Value)}, nil
+}
+
+func validationError(msg string) error {
+	return types.NewError("ValidationException", "Statement wasn't well formed, can't be processed: "+msg, nil)
+}
@@ -0,0 +1,135 @@
+package partiql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/types"
+)
+
+func parseWhere(c *require.Assertions, where string) Expression {
+	p := NewParser(NewLexer("SELECT * FROM t WHERE " + where))
+
+	stmt := p.ParseStatement()
+	c.Empty(p.Errors())
+
+	return stmt.(*SelectStatement).Where
+}
+
+func TestTranslatorCondition(t *testing.T) {
+	tests := map[string]string{
+		`a.b[1] = 'x'`:                    `(#n0.#n1[1] = :v0)`,
+		`a = 1 AND (b <> 2 OR NOT a < 3)`: `((#n0 = :v0) AND ((#n1 <> :v1) OR (NOT (#n0 < :v2))))`,
+		`a BETWEEN 1 AND 5`:               `(#n0 BETWEEN :v0 AND :v1)`,
+		`a IN ['x', 'y']`:                 `(#n0 IN (:v0, :v1))`,
+		`begins_with(a, 'x')`:             `begins_with(#n0, :v0)`,
+		`contains(a, 'x')`:                `contains(#n0, :v0)`,
+		`attribute_type(a, 'S')`:          `attribute_type(#n0, :v0)`,
+		`size(a) >= 2`:                    `(size(#n0) >= :v0)`,
+		`a IS MISSING`:                    `attribute_not_exists(#n0)`,
+		`a IS NOT MISSING`:                `attribute_exists(#n0)`,
+		`a IS NULL`:                       `(attribute_not_exists(#n0) OR attribute_type(#n0, :v0))`,
+		`a IS NOT NULL`:                   `(attribute_exists(#n0) AND NOT attribute_type(#n0, :v0))`,
+		`a = b`:                           `(#n0 = #n1)`,
+	}
+
+	for where, expected := range tests {
+		t.Run(where, func(t *testing.T) {
+			c := require.New(t)
+
+			tr := NewTranslator(nil)
+
+			out, err := tr.Condition(parseWhere(c, where))
+			c.NoError(err)
+			c.Equal(expected, out)
+		})
+	}
+}
+
+func TestTranslatorNamesAndValues(t *testing.T) {
+	c := require.New(t)
+
+	tr := NewTranslator([]*types.Item{{S: new("fire")}})
+
+	out, err := tr.Condition(parseWhere(c, `"type" = ? AND stats.hp > 10 AND tags = <<'a', 'b'>> AND m = {'k': [TRUE, NULL]}`))
+	c.NoError(err)
+	c.Equal(`((((#n0 = :v0) AND (#n1.#n2 > :v1)) AND (#n3 = :v2)) AND (#n4 = :v3))`, out)
+
+	c.Equal(map[string]string{"#n0": "type", "#n1": "stats", "#n2": "hp", "#n3": "tags", "#n4": "m"}, tr.Names)
+	c.Equal(map[string]*types.Item{
+		":v0": {S: new("fire")},
+		":v1": {N: new("10")},
+		":v2": {SS: []*string{new("a"), new("b")}},
+		":v3": {M: map[string]*types.Item{"k": {L: []*types.Item{{BOOL: new(true)}, {NULL: new(true)}}}}},
+	}, tr.Values)
+
+	c.Equal("#n1.#n2, #n5[0]", tr.Projection([]*Path{
+		{Elements: []PathElement{{Name: "stats"}, {Name: "hp"}}},
+		{Elements: []PathElement{{Name: "moves"}, {Index: 0, IsIndex: true}}},
+	}))
+}
+
+func TestTranslatorErrors(t *testing.T) {
+	tests := map[string]string{
+		`a = 1e999999`:            "ValidationException: Number overflow. Attempting to store a number with magnitude larger than supported range",
+		`a = <<'x', 1>>`:          "ValidationException: Statement wasn't well formed, can't be processed: set elements must be strings, numbers or binaries of a single type",
+		`a = <<'x', 'x'>>`:        "ValidationException: One or more parameter values were invalid: Input collection [x, x] contains duplicates.",
+		`a = <<>>`:                "ValidationException: Statement wasn't well formed, can't be processed: an empty set is not allowed",
+		`'x' IS NULL`:             "ValidationException: Statement wasn't well formed, can't be processed: IS NULL and IS MISSING require a document path",
+		`a = begins_with(a, 'x')`: "ValidationException: Statement wasn't well formed, can't be processed: function begins_with can't be used as an operand",
+		`a = 1 AND 'x'`:           "ValidationException: Statement wasn't well formed, can't be processed: unsupported condition 'x'",
+	}
+
+	for where, expected := range tests {
+		t.Run(where, func(t *testing.T) {
+			c := require.New(t)
+
+			_, err := NewTranslator(nil).Condition(parseWhere(c, where))
+			c.EqualError(err, expected)
+		})
+	}
+}
+
+func TestTranslatorUpdateExpression(t *testing.T) {
+	tests := map[string]string{
+		`SET a = 1, REMOVE b`:                                                 `SET #n0 = :v0 REMOVE #n1`,
+		`SET a = a + 1 SET b = if_not_exists(b, 0) - 2`:                       `SET #n0 = #n0 + :v0, #n1 = if_not_exists(#n1, :v1) - :v2`,
+		`SET l = list_append(l, [1])`:                                         `SET #n0 = list_append(#n0, :v0)`,
+		`SET s = set_add(s, <<1>>), d = set_delete(d, <<'x'>>) REMOVE m.n[1]`: `REMOVE #n2.#n3[1] ADD #n0 :v0 DELETE #n1 :v1`,
+	}
+
+	for actions, expected := range tests {
+		t.Run(actions, func(t *testing.T) {
+			c := require.New(t)
+
+			p := NewParser(NewLexer("UPDATE t " + actions + " WHERE id = 1"))
+			stmt := p.ParseStatement()
+			c.Empty(p.Errors())
+
+			out, err := NewTranslator(nil).UpdateExpression(stmt.(*UpdateStatement).Actions)
+			c.NoError(err)
+			c.Equal(expected, out)
+		})
+	}
+}
+
+func TestTranslatorUpdateExpressionErrors(t *testing.T) {
+	tests := map[string]string{
+		`SET a = set_add(b, <<1>>)`:                   "ValidationException: Statement wasn't well formed, can't be processed: set_add must update the attribute given as its first argument",
+		`SET a = list_append(set_add(a, <<1>>), [1])`: "ValidationException: Statement wasn't well formed, can't be processed: set_add must be the whole value of a SET clause",
+		`SET a = ?`: "ValidationException: Number of parameters in request and statement don't match.",
+	}
+
+	for actions, expected := range tests {
+		t.Run(actions, func(t *testing.T) {
+			c := require.New(t)
+
+			p := NewParser(NewLexer("UPDATE t " + actions + " WHERE id = 1"))
+			stmt := p.ParseStatement()
+			c.Empty(p.Errors())
+
+			_, err := NewTranslator(nil).UpdateExpression(stmt.(*UpdateStatement).Actions)
+			c.EqualError(err, expected)
+		})
+	}
+}
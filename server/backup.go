@@ -0,0 +1,313 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+	"github.com/truora/minidyn/types"
+)
+
+const maxListBackupsLimit = 100
+
+// epochSeconds encodes t the way the DynamoDB JSON protocol sends timestamps
+func epochSeconds(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
+}
+
+func epochSecondsPtr(t *time.Time) *float64 {
+	if t == nil {
+		return nil
+	}
+
+	return aws.Float64(epochSeconds(*t))
+}
+
+// backupArn builds an ARN following the format DynamoDB uses for on-demand backups
+func (c *Client) backupArn(tableName string, createdAt time.Time) string {
+	c.backupSeq++
+
+	return fmt.Sprintf("%s/backup/%014d-%08x", c.tableArn(tableName), createdAt.UnixMilli(), c.backupSeq)
+}
+
+// getBackup resolves a backup ARN, callers must hold c.mu
+func (c *Client) getBackup(arn string) (*core.Backup, error) {
+	backup, ok := c.backups[arn]
+	if !ok {
+		return nil, &ddbtypes.BackupNotFoundException{Message: aws.String("Backup not found: " + arn)}
+	}
+
+	return backup, nil
+}
+
+func backupDetails(arn, status string, backup *core.Backup) BackupDetails {
+	return BackupDetails{
+		BackupArn:              arn,
+		BackupName:             backup.Name,
+		BackupCreationDateTime: epochSeconds(backup.CreatedAt),
+		BackupSizeBytes:        backup.SizeBytes,
+		BackupStatus:           status,
+		BackupType:             string(ddbtypes.BackupTypeUser),
+	}
+}
+
+func (c *Client) backupDescription(arn, status string, backup *core.Backup) BackupDescription {
+	source := mapTableDescriptionToDDB(backup.Source)
+
+	desc := BackupDescription{
+		BackupDetails: backupDetails(arn, status, backup),
+		SourceTableDetails: SourceTableDetails{
+			TableName:      backup.TableName,
+			TableArn:       c.tableArn(backup.TableName),
+			KeySchema:      source.KeySchema,
+			ItemCount:      backup.ItemCount,
+			TableSizeBytes: backup.SizeBytes,
+			BillingMode:    aws.ToString(backup.BillingMode),
+		},
+	}
+
+	for _, gsi := range source.GlobalSecondaryIndexes {
+		desc.SourceTableFeatureDetails.GlobalSecondaryIndexes = append(desc.SourceTableFeatureDetails.GlobalSecondaryIndexes, ddbtypes.GlobalSecondaryIndexInfo{
+			IndexName:  gsi.IndexName,
+			KeySchema:  gsi.KeySchema,
+			Projection: gsi.Projection,
+		})
+	}
+
+	for _, lsi := range source.LocalSecondaryIndexes {
+		desc.SourceTableFeatureDetails.LocalSecondaryIndexes = append(desc.SourceTableFeatureDetails.LocalSecondaryIndexes, ddbtypes.LocalSecondaryIndexInfo{
+			IndexName:  lsi.IndexName,
+			KeySchema:  lsi.KeySchema,
+			Projection: lsi.Projection,
+		})
+	}
+
+	return desc
+}
+
+// CreateBackup copies the schema, indexes and items of a table into a new on-demand backup.
+func (c *Client) CreateBackup(ctx context.Context, input *CreateBackupInput) (*CreateBackupOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableName := aws.ToString(input.TableName)
+
+	if err := c.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	if err := core.ValidateBackupName(aws.ToString(input.BackupName)); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	table, ok := c.tables[tableName]
+	if !ok {
+		return nil, &ddbtypes.TableNotFoundException{Message: aws.String("Table not found: " + tableName)}
+	}
+
+	c.sweepOnAccess(table)
+
+	now := c.clock.Now()
+	backup := table.Backup(aws.ToString(input.BackupName), now)
+	arn := c.backupArn(tableName, now)
+	c.backups[arn] = backup
+
+	return &CreateBackupOutput{BackupDetails: backupDetails(arn, string(ddbtypes.BackupStatusAvailable), backup)}, nil
+}
+
+// DescribeBackup returns the details of a backup and of the table it was taken from.
+func (c *Client) DescribeBackup(ctx context.Context, input *DescribeBackupInput) (*DescribeBackupOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.forceFailureErr != nil {
+		return nil, c.forceFailureErr
+	}
+
+	arn := aws.ToString(input.BackupArn)
+
+	backup, err := c.getBackup(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DescribeBackupOutput{BackupDescription: c.backupDescription(arn, string(ddbtypes.BackupStatusAvailable), backup)}, nil
+}
+
+// DeleteBackup removes a backup, the response describes it with the DELETED status.
+func (c *Client) DeleteBackup(ctx context.Context, input *DeleteBackupInput) (*DeleteBackupOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.forceFailureErr != nil {
+		return nil, c.forceFailureErr
+	}
+
+	arn := aws.ToString(input.BackupArn)
+
+	backup, err := c.getBackup(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(c.backups, arn)
+
+	return &DeleteBackupOutput{BackupDescription: c.backupDescription(arn, string(ddbtypes.BackupStatusDeleted), backup)}, nil
+}
+
+// ListBackups returns the backups ordered by creation time, filtered by table, creation time
+// range and backup type. Every backup made by CreateBackup has the USER type.
+func (c *Client) ListBackups(ctx context.Context, input *ListBackupsInput) (*ListBackupsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableName := aws.ToString(input.TableName)
+
+	if err := c.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	limit := maxListBackupsLimit
+	if input.Limit != nil {
+		if *input.Limit < 1 || *input.Limit > maxListBackupsLimit {
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value between 1 and %d", *input.Limit, maxListBackupsLimit)}
+		}
+
+		limit = int(*input.Limit)
+	}
+
+	switch input.BackupType {
+	case "", ddbtypes.BackupTypeFilterUser, ddbtypes.BackupTypeFilterAll:
+	case ddbtypes.BackupTypeFilterSystem, ddbtypes.BackupTypeFilterAwsBackup:
+		return &ListBackupsOutput{BackupSummaries: []BackupSummary{}}, nil
+	default:
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%s' at 'backupType' failed to satisfy constraint: Member must satisfy enum value set: [USER, SYSTEM, AWS_BACKUP, ALL]", input.BackupType)}
+	}
+
+	summaries := []BackupSummary{}
+
+	for arn, backup := range c.backups {
+		if tableName != "" && backup.TableName != tableName {
+			continue
+		}
+
+		if input.TimeRangeLowerBound != nil && backup.CreatedAt.Before(input.TimeRangeLowerBound.Time()) {
+			continue
+		}
+
+		if input.TimeRangeUpperBound != nil && !backup.CreatedAt.Before(input.TimeRangeUpperBound.Time()) {
+			continue
+		}
+
+		details := backupDetails(arn, string(ddbtypes.BackupStatusAvailable), backup)
+		summaries = append(summaries, BackupSummary{
+			BackupArn:              details.BackupArn,
+			BackupName:             details.BackupName,
+			BackupCreationDateTime: details.BackupCreationDateTime,
+			BackupSizeBytes:        details.BackupSizeBytes,
+			BackupStatus:           details.BackupStatus,
+			BackupType:             details.BackupType,
+			TableArn:               c.tableArn(backup.TableName),
+			TableName:              backup.TableName,
+		})
+	}
+
+	// the ARN embeds the creation time, so ordering by it lists the oldest backups first
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].BackupCreationDateTime != summaries[j].BackupCreationDateTime {
+			return summaries[i].BackupCreationDateTime < summaries[j].BackupCreationDateTime
+		}
+
+		return summaries[i].BackupArn < summaries[j].BackupArn
+	})
+
+	start := 0
+
+	if exclusiveStart := aws.ToString(input.ExclusiveStartBackupArn); exclusiveStart != "" {
+		for i, s := range summaries {
+			if s.BackupArn == exclusiveStart {
+				start = i + 1
+
+				break
+			}
+		}
+	}
+
+	end := min(start+limit, len(summaries))
+	output := &ListBackupsOutput{BackupSummaries: summaries[start:end]}
+
+	if end < len(summaries) {
+		output.LastEvaluatedBackupArn = aws.String(summaries[end-1].BackupArn)
+	}
+
+	return output, nil
+}
+
+// RestoreTableFromBackup creates a new table with the schema, indexes and items of a backup.
+func (c *Client) RestoreTableFromBackup(ctx context.Context, input *RestoreTableFromBackupInput) (*RestoreTableFromBackupOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	targetName := aws.ToString(input.TargetTableName)
+
+	if err := c.failureErrFor(targetName, ""); err != nil {
+		return nil, err
+	}
+
+	arn := aws.ToString(input.BackupArn)
+
+	backup, err := c.getBackup(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := c.restoreTable(targetName, backup, restoreOverrides(input.BillingModeOverride, input.GlobalSecondaryIndexOverride, input.LocalSecondaryIndexOverride), &core.RestoreSummary{
+		SourceBackupArn: arn,
+		SourceTableArn:  c.tableArn(backup.TableName),
+		RestoreDateTime: backup.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RestoreTableFromBackupOutput{TableDescription: c.tableDescription(targetName, table)}, nil
+}
+
+// restoreOverrides maps the overrides of a restore request, an empty index override drops the
+// indexes of that kind so it must not collapse into nil
+func restoreOverrides(billingMode ddbtypes.BillingMode, gsi []ddbtypes.GlobalSecondaryIndex, lsi []ddbtypes.LocalSecondaryIndex) core.RestoreInput {
+	input := core.RestoreInput{BillingMode: toStringPtr(string(billingMode))}
+
+	if gsi != nil {
+		input.GlobalSecondaryIndexes = append([]*types.GlobalSecondaryIndex{}, mapGSI(gsi)...)
+	}
+
+	if lsi != nil {
+		input.LocalSecondaryIndexes = append([]*types.LocalSecondaryIndex{}, mapLSI(lsi)...)
+	}
+
+	return input
+}
+
+// restoreTable creates the target table of a restore from the backup, callers must hold c.mu
+func (c *Client) restoreTable(targetName string, backup *core.Backup, input core.RestoreInput, summary *core.RestoreSummary) (*core.Table, error) {
+	if _, ok := c.tables[targetName]; ok {
+		return nil, &ddbtypes.TableAlreadyExistsException{Message: aws.String("Table already exists: " + targetName)}
+	}
+
+	table := c.newTable(targetName)
+
+	if err := backup.Restore(table, input); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	table.RestoreSummary = summary
+	c.tables[targetName] = table
+
+	return table, nil
+}
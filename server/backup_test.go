@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func createBackupTestTable(t *testing.T, ddb *dynamodb.Client) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	_, err := ddb.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String("pokemons"),
+		KeySchema: []ddbtypes.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: ddbtypes.KeyTypeHash},
+		},
+		AttributeDefinitions: []ddbtypes.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: ddbtypes.ScalarAttributeTypeS},
+			{AttributeName: aws.String("type"), AttributeType: ddbtypes.ScalarAttributeTypeS},
+		},
+		BillingMode: ddbtypes.BillingModePayPerRequest,
+		GlobalSecondaryIndexes: []ddbtypes.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String("by-type"),
+				KeySchema: []ddbtypes.KeySchemaElement{
+					{AttributeName: aws.String("type"), KeyType: ddbtypes.KeyTypeHash},
+					{AttributeName: aws.String("id"), KeyType: ddbtypes.KeyTypeRange},
+				},
+				Projection: &ddbtypes.Projection{ProjectionType: ddbtypes.ProjectionTypeAll},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	for id, typ := range map[string]string{"25": "electric", "4": "fire", "7": "water"} {
+		_, err = ddb.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String("pokemons"),
+			Item: map[string]ddbtypes.AttributeValue{
+				"id":   &ddbtypes.AttributeValueMemberS{Value: id},
+				"type": &ddbtypes.AttributeValueMemberS{Value: typ},
+			},
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestServerBackups(t *testing.T) {
+	c := require.New(t)
+
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	srv.SetClock(clock)
+
+	createBackupTestTable(t, ddb)
+
+	created, err := ddb.CreateBackup(ctx, &dynamodb.CreateBackupInput{TableName: aws.String("pokemons"), BackupName: aws.String("daily")})
+	c.NoError(err)
+
+	details := created.BackupDetails
+	backupArn := aws.ToString(details.BackupArn)
+	c.Contains(backupArn, "arn:aws:dynamodb:us-east-1:000000000000:table/pokemons/backup/")
+	c.Equal("daily", aws.ToString(details.BackupName))
+	c.Equal(ddbtypes.BackupStatusAvailable, details.BackupStatus)
+	c.Equal(ddbtypes.BackupTypeUser, details.BackupType)
+	c.Equal(clock.Now(), aws.ToTime(details.BackupCreationDateTime).UTC())
+	c.Positive(aws.ToInt64(details.BackupSizeBytes))
+
+	// changes made after the backup are not restored
+	_, err = ddb.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String("pokemons"),
+		Key:       map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "25"}},
+	})
+	c.NoError(err)
+
+	described, err := ddb.DescribeBackup(ctx, &dynamodb.DescribeBackupInput{BackupArn: aws.String(backupArn)})
+	c.NoError(err)
+	c.Equal("pokemons", aws.ToString(described.BackupDescription.SourceTableDetails.TableName))
+	c.EqualValues(3, aws.ToInt64(described.BackupDescription.SourceTableDetails.ItemCount))
+	c.Equal(ddbtypes.BillingModePayPerRequest, described.BackupDescription.SourceTableDetails.BillingMode)
+	c.Len(described.BackupDescription.SourceTableFeatureDetails.GlobalSecondaryIndexes, 1)
+
+	restored, err := ddb.RestoreTableFromBackup(ctx, &dynamodb.RestoreTableFromBackupInput{
+		BackupArn:       aws.String(backupArn),
+		TargetTableName: aws.String("pokemons-restored"),
+	})
+	c.NoError(err)
+	c.EqualValues(3, aws.ToInt64(restored.TableDescription.ItemCount))
+	c.Len(restored.TableDescription.GlobalSecondaryIndexes, 1)
+	c.Equal(backupArn, aws.ToString(restored.TableDescription.RestoreSummary.SourceBackupArn))
+	c.Equal(clock.Now(), aws.ToTime(restored.TableDescription.RestoreSummary.RestoreDateTime).UTC())
+	c.False(aws.ToBool(restored.TableDescription.RestoreSummary.RestoreInProgress))
+
+	query, err := ddb.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String("pokemons-restored"),
+		IndexName:                 aws.String("by-type"),
+		KeyConditionExpression:    aws.String("#type = :type"),
+		ExpressionAttributeNames:  map[string]string{"#type": "type"},
+		ExpressionAttributeValues: map[string]ddbtypes.AttributeValue{":type": &ddbtypes.AttributeValueMemberS{Value: "electric"}},
+	})
+	c.NoError(err)
+	c.Len(query.Items, 1)
+
+	withoutIndexes, err := ddb.RestoreTableFromBackup(ctx, &dynamodb.RestoreTableFromBackupInput{
+		BackupArn:                    aws.String(backupArn),
+		TargetTableName:              aws.String("pokemons-lean"),
+		GlobalSecondaryIndexOverride: []ddbtypes.GlobalSecondaryIndex{},
+		BillingModeOverride:          ddbtypes.BillingModeProvisioned,
+	})
+	c.NoError(err)
+	c.Empty(withoutIndexes.TableDescription.GlobalSecondaryIndexes)
+
+	_, err = ddb.RestoreTableFromBackup(ctx, &dynamodb.RestoreTableFromBackupInput{
+		BackupArn:       aws.String(backupArn),
+		TargetTableName: aws.String("pokemons"),
+	})
+
+	var existsErr *ddbtypes.TableAlreadyExistsException
+	c.True(errors.As(err, &existsErr))
+
+	clock.Advance(time.Hour)
+
+	_, err = ddb.CreateBackup(ctx, &dynamodb.CreateBackupInput{TableName: aws.String("pokemons-restored"), BackupName: aws.String("restored")})
+	c.NoError(err)
+
+	listed, err := ddb.ListBackups(ctx, &dynamodb.ListBackupsInput{Limit: aws.Int32(1)})
+	c.NoError(err)
+	c.Len(listed.BackupSummaries, 1)
+	c.Equal(backupArn, aws.ToString(listed.BackupSummaries[0].BackupArn))
+	c.Equal(backupArn, aws.ToString(listed.LastEvaluatedBackupArn))
+
+	listed, err = ddb.ListBackups(ctx, &dynamodb.ListBackupsInput{ExclusiveStartBackupArn: listed.LastEvaluatedBackupArn})
+	c.NoError(err)
+	c.Len(listed.BackupSummaries, 1)
+	c.Equal("pokemons-restored", aws.ToString(listed.BackupSummaries[0].TableName))
+	c.Nil(listed.LastEvaluatedBackupArn)
+
+	listed, err = ddb.ListBackups(ctx, &dynamodb.ListBackupsInput{TimeRangeUpperBound: aws.Time(clock.Now())})
+	c.NoError(err)
+	c.Len(listed.BackupSummaries, 1)
+	c.Equal("daily", aws.ToString(listed.BackupSummaries[0].BackupName))
+
+	listed, err = ddb.ListBackups(ctx, &dynamodb.ListBackupsInput{TableName: aws.String("pokemons"), BackupType: ddbtypes.BackupTypeFilterSystem})
+	c.NoError(err)
+	c.Empty(listed.BackupSummaries)
+
+	deleted, err := ddb.DeleteBackup(ctx, &dynamodb.DeleteBackupInput{BackupArn: aws.String(backupArn)})
+	c.NoError(err)
+	c.Equal(ddbtypes.BackupStatusDeleted, deleted.BackupDescription.BackupDetails.BackupStatus)
+
+	_, err = ddb.DescribeBackup(ctx, &dynamodb.DescribeBackupInput{BackupArn: aws.String(backupArn)})
+
+	var notFoundErr *ddbtypes.BackupNotFoundException
+	c.True(errors.As(err, &notFoundErr))
+}
+
+func TestServerCreateBackupErrors(t *testing.T) {
+	c := require.New(t)
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+
+	_, err := ddb.CreateBackup(ctx, &dynamodb.CreateBackupInput{TableName: aws.String("missing"), BackupName: aws.String("daily")})
+
+	var tableErr *ddbtypes.TableNotFoundException
+	c.True(errors.As(err, &tableErr))
+
+	createBackupTestTable(t, ddb)
+
+	_, err = ddb.CreateBackup(ctx, &dynamodb.CreateBackupInput{TableName: aws.String("pokemons"), BackupName: aws.String("daily backup")})
+
+	var apiErr smithy.APIError
+	c.True(errors.As(err, &apiErr))
+	c.Equal("ValidationException", apiErr.ErrorCode())
+}
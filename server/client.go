@@ -3,6 +3,9 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
 	"sync"
 	"time"
 
@@ -25,17 +28,59 @@ type Client struct {
 	tableFailureErrs     map[string]error
 	unprocessedMatchers  map[string]func(int, map[string]*AttributeValue) bool
 	indexActivationDelay time.Duration
+	tableStatusDelay     time.Duration
+	deletingTables       map[string]*core.Table
+	region               string
+	accountID            string
+	clock                core.Clock
+	sweepExpiredOnAccess bool
+	transactionTokens    *core.RequestTokens[*ExecuteTransactionOutput]
+	transactWriteTokens  *core.RequestTokens[*TransactWriteItemsOutput]
+	backups              map[string]*core.Backup
+	backupSeq            int
+	exportSink           core.ExportSink
+	exports              map[string]*core.Export
+	exportSeq            int
+	importSource         fs.FS
+	imports              map[string]*tableImport
+	importSeq            int
+	regions              *regions
 }
 
 // NewClient creates a new in-memory DynamoDB-compatible client used by the HTTP server.
 func NewClient() *Client {
-	return &Client{
+	c := &Client{
 		tables:              map[string]*core.Table{},
 		mu:                  sync.Mutex{},
 		nativeInterpreter:   interpreter.NewNativeInterpreter(),
 		langInterpreter:     &interpreter.Language{},
 		tableFailureErrs:    map[string]error{},
 		unprocessedMatchers: map[string]func(int, map[string]*AttributeValue) bool{},
+		deletingTables:      map[string]*core.Table{},
+		region:              defaultRegion,
+		accountID:           defaultAccountID,
+		clock:               core.SystemClock,
+		transactionTokens:   core.NewRequestTokens[*ExecuteTransactionOutput](),
+		transactWriteTokens: core.NewRequestTokens[*TransactWriteItemsOutput](),
+		backups:             map[string]*core.Backup{},
+		exports:             map[string]*core.Export{},
+		imports:             map[string]*tableImport{},
+	}
+	c.regions = newRegions(c)
+
+	return c
+}
+
+// setAccount changes the region and the account ID used to build ARNs, including the
+// ones of the existing tables
+func (c *Client) setAccount(region, accountID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.region, c.accountID = region, accountID
+
+	for name, table := range c.tables {
+		table.Arn = c.tableArn(name)
 	}
 }
 
@@ -171,16 +216,87 @@ func (c *Client) setIndexActivationDelay(delay time.Duration) {
 	}
 }
 
+func (c *Client) setTableStatusDelay(delay time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tableStatusDelay = delay
+
+	for _, table := range c.tables {
+		table.StatusDelay = delay
+	}
+}
+
+func (c *Client) setClientRequestTokenTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.transactionTokens.SetTTL(ttl)
+	c.transactWriteTokens.SetTTL(ttl)
+}
+
 // Table helpers
 func (c *Client) getTable(tableName string) (*core.Table, error) {
+	table, err := c.lookupTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.sweepOnAccess(table)
+
+	return table, nil
+}
+
+// lookupTable returns a table without sweeping its expired items, write transactions use it
+// so no sweep happens between their snapshot and their rollback
+func (c *Client) lookupTable(tableName string) (*core.Table, error) {
 	table, ok := c.tables[tableName]
-	if !ok {
+	if !ok || table.Status() == core.TableStatusCreating {
 		return nil, &ddbtypes.ResourceNotFoundException{Message: aws.String("Cannot do operations on a non-existent table")}
 	}
 
 	return table, nil
 }
 
+// deletingTable returns a table whose deletion is still in progress, callers must hold c.mu
+func (c *Client) deletingTable(tableName string) (*core.Table, bool) {
+	table, ok := c.deletingTables[tableName]
+	if ok && table.Deleted() {
+		delete(c.deletingTables, tableName)
+
+		return nil, false
+	}
+
+	return table, ok
+}
+
+// changingTableError is returned by the operations that change tables while the table is
+// still being created or updated
+func changingTableError(tableName, status string) error {
+	action := "updated"
+	if status == core.TableStatusCreating {
+		action = "created"
+	}
+
+	return &ddbtypes.ResourceInUseException{Message: aws.String(fmt.Sprintf("Attempt to change a resource which is still in use: Table is being %s: %s", action, tableName))}
+}
+
+// newTable creates an empty table using the interpreters configured in the client, the
+// table stays CREATING for the table status delay
+func (c *Client) newTable(tableName string) *core.Table {
+	table := core.NewTable(tableName)
+	table.Arn = c.tableArn(tableName)
+	table.NativeInterpreter = *c.nativeInterpreter
+	table.UseNativeInterpreter = c.useNativeInterpreter
+	table.LangInterpreter = *c.langInterpreter
+	table.IndexActivationDelay = c.indexActivationDelay
+	table.StatusDelay = c.tableStatusDelay
+	table.Clock = c.clock
+	table.BeginStatus(core.TableStatusCreating)
+
+	return table
+}
+
 // CreateTable creates a new table in the in-memory engine.
 func (c *Client) CreateTable(ctx context.Context, input *CreateTableInput) (*CreateTableOutput, error) {
 	tableName := aws.ToString(input.TableName)
@@ -188,13 +304,13 @@ func (c *Client) CreateTable(ctx context.Context, input *CreateTableInput) (*Cre
 		return nil, &ddbtypes.ResourceInUseException{Message: aws.String("Cannot create preexisting table")}
 	}
 
-	table := core.NewTable(tableName)
+	if _, ok := c.deletingTable(tableName); ok {
+		return nil, &ddbtypes.ResourceInUseException{Message: aws.String("Cannot create preexisting table")}
+	}
+
+	table := c.newTable(tableName)
 	table.SetAttributeDefinition(mapAttributeDefinitions(input.AttributeDefinitions))
 	table.BillingMode = toStringPtr(string(input.BillingMode))
-	table.NativeInterpreter = *c.nativeInterpreter
-	table.UseNativeInterpreter = c.useNativeInterpreter
-	table.LangInterpreter = *c.langInterpreter
-	table.IndexActivationDelay = c.indexActivationDelay
 
 	if err := table.CreatePrimaryIndex(&types.CreateTableInput{
 		KeySchema:             mapKeySchema(input.KeySchema),
@@ -211,9 +327,30 @@ func (c *Client) CreateTable(ctx context.Context, input *CreateTableInput) (*Cre
 		return nil, mapKnownError(err)
 	}
 
+	if err := c.applyStreamSpecification(table, input.StreamSpecification); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if err := table.SetSettings(core.TableSettings{
+		ProvisionedThroughput:     mapProvisionedThroughput(input.ProvisionedThroughput),
+		OnDemandThroughput:        mapOnDemandThroughput(input.OnDemandThroughput),
+		WarmThroughput:            mapWarmThroughput(input.WarmThroughput),
+		DeletionProtectionEnabled: input.DeletionProtectionEnabled,
+		TableClass:                toStringPtr(string(input.TableClass)),
+		SSE:                       c.mapSSESpecification(input.SSESpecification),
+	}); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if len(input.Tags) > 0 {
+		if err := table.TagResource(mapTags(input.Tags)); err != nil {
+			return nil, mapKnownError(err)
+		}
+	}
+
 	c.tables[tableName] = table
 
-	return &CreateTableOutput{TableDescription: mapTableDescriptionToDDB(table.Description(tableName))}, nil
+	return &CreateTableOutput{TableDescription: c.tableDescription(tableName, table)}, nil
 }
 
 // UpdateTable applies metadata changes, including GSI updates.
@@ -225,44 +362,159 @@ func (c *Client) UpdateTable(ctx context.Context, input *UpdateTableInput) (*Upd
 		return nil, &ddbtypes.ResourceNotFoundException{Message: aws.String("Cannot do operations on a non-existent table")}
 	}
 
+	if status := table.Status(); status != core.TableStatusActive {
+		return nil, changingTableError(tableName, status)
+	}
+
+	update := core.TableSettings{
+		BillingMode:               toStringPtr(string(input.BillingMode)),
+		ProvisionedThroughput:     mapProvisionedThroughput(input.ProvisionedThroughput),
+		OnDemandThroughput:        mapOnDemandThroughput(input.OnDemandThroughput),
+		WarmThroughput:            mapWarmThroughput(input.WarmThroughput),
+		DeletionProtectionEnabled: input.DeletionProtectionEnabled,
+		TableClass:                toStringPtr(string(input.TableClass)),
+		SSE:                       c.mapSSESpecification(input.SSESpecification),
+	}
+
+	if update == (core.TableSettings{}) && len(input.GlobalSecondaryIndexUpdates) == 0 && input.StreamSpecification == nil &&
+		len(input.ReplicaUpdates) == 0 && input.MultiRegionConsistency == "" {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "At least one of ProvisionedThroughput, BillingMode, UpdateStreamEnabled, GlobalSecondaryIndexUpdates or SSESpecification or ReplicaUpdates is required"}
+	}
+
+	// a failure in any part of the request restores the settings, attribute definitions and indexes
+	settings := table.SnapshotSettings()
+
+	if err := table.UpdateSettings(update); err != nil {
+		return nil, mapKnownError(err)
+	}
+
 	if input.AttributeDefinitions != nil {
 		table.SetAttributeDefinition(mapAttributeDefinitions(input.AttributeDefinitions))
 	}
 
 	for _, change := range mapGSIUpdate(input.GlobalSecondaryIndexUpdates) {
 		if err := table.ApplyIndexChange(change); err != nil {
-			return &UpdateTableOutput{TableDescription: mapTableDescriptionToDDB(table.Description(tableName))}, mapKnownError(err)
+			table.RestoreSettings(settings)
+
+			return &UpdateTableOutput{TableDescription: c.tableDescription(tableName, table)}, mapKnownError(err)
+		}
+	}
+
+	if err := c.applyStreamSpecification(table, input.StreamSpecification); err != nil {
+		table.RestoreSettings(settings)
+
+		return nil, mapKnownError(err)
+	}
+
+	if len(input.ReplicaUpdates) > 0 {
+		if err := c.updateReplicas(ctx, tableName, table, input.ReplicaUpdates); err != nil {
+			table.RestoreSettings(settings)
+
+			return nil, err
 		}
 	}
 
-	return &UpdateTableOutput{TableDescription: mapTableDescriptionToDDB(table.Description(tableName))}, nil
+	if table.SettingsChanged(settings) || len(input.GlobalSecondaryIndexUpdates) > 0 || input.StreamSpecification != nil || len(input.ReplicaUpdates) > 0 {
+		table.BeginStatus(core.TableStatusUpdating)
+	}
+
+	desc := c.tableDescription(tableName, table)
+	c.describeReplicas(tableName, table, desc)
+
+	return &UpdateTableOutput{TableDescription: desc}, nil
 }
 
-// DeleteTable removes a table and its data.
+// DeleteTable removes a table and its data, the table is described as DELETING until
+// the table status delay passes.
 func (c *Client) DeleteTable(ctx context.Context, input *DeleteTableInput) (*DeleteTableOutput, error) {
 	tableName := aws.ToString(input.TableName)
 
-	table, err := c.getTable(tableName)
-	if err != nil {
-		return nil, err
+	table, ok := c.tables[tableName]
+	if !ok {
+		return nil, &ddbtypes.ResourceNotFoundException{Message: aws.String("Cannot do operations on a non-existent table")}
+	}
+
+	if status := table.Status(); status != core.TableStatusActive {
+		return nil, changingTableError(tableName, status)
 	}
 
-	desc := mapTableDescriptionToDDB(table.Description(tableName))
+	if err := table.ValidateDeletion(); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	table.BeginStatus(core.TableStatusDeleting)
+
+	desc := c.tableDescription(tableName, table)
 	delete(c.tables, tableName)
 
+	if !table.Deleted() {
+		c.deletingTables[tableName] = table
+	}
+
 	return &DeleteTableOutput{TableDescription: desc}, nil
 }
 
-// DescribeTable returns table metadata.
+// DescribeTable returns table metadata, including the tables still being created or deleted.
 func (c *Client) DescribeTable(ctx context.Context, input *DescribeTableInput) (*DescribeTableOutput, error) {
 	tableName := aws.ToString(input.TableName)
 
-	table, err := c.getTable(tableName)
-	if err != nil {
-		return nil, err
+	table, ok := c.tables[tableName]
+	if !ok {
+		table, ok = c.deletingTable(tableName)
+	}
+
+	if !ok {
+		return nil, &ddbtypes.ResourceNotFoundException{Message: aws.String("Cannot do operations on a non-existent table")}
+	}
+
+	c.sweepOnAccess(table)
+
+	desc := c.tableDescription(tableName, table)
+	c.describeReplicas(tableName, table, desc)
+
+	return &DescribeTableOutput{Table: desc}, nil
+}
+
+// ListTables returns the table names in ascending order, at most 100 per page.
+func (c *Client) ListTables(ctx context.Context, input *ListTablesInput) (*ListTablesOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.forceFailureErr != nil {
+		return nil, c.forceFailureErr
 	}
 
-	return &DescribeTableOutput{Table: mapTableDescriptionToDDB(table.Description(tableName))}, nil
+	limit := maxListTablesLimit
+	if input.Limit != nil {
+		switch {
+		case *input.Limit < 1:
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value greater than or equal to 1", *input.Limit)}
+		case *input.Limit > maxListTablesLimit:
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value less than or equal to %d", *input.Limit, maxListTablesLimit)}
+		}
+
+		limit = int(*input.Limit)
+	}
+
+	exclusiveStart := aws.ToString(input.ExclusiveStartTableName)
+
+	names := make([]string, 0, len(c.tables))
+	for name := range c.tables {
+		if name > exclusiveStart {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	output := &ListTablesOutput{TableNames: names}
+
+	if len(names) > limit {
+		output.TableNames = names[:limit]
+		output.LastEvaluatedTableName = aws.String(names[limit-1])
+	}
+
+	return output, nil
 }
 
 // ClearTable removes all data from a specific table, including its indexes.
@@ -298,7 +550,7 @@ func (c *Client) ClearAllTables() {
 	}
 }
 
-// Reset removes all tables and their indexes from the in-memory client.
+// Reset removes all tables, their indexes, their backups, their exports and their imports from the in-memory client.
 func (c *Client) Reset() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -306,6 +558,11 @@ func (c *Client) Reset() {
 	for name := range c.tables {
 		delete(c.tables, name)
 	}
+
+	clear(c.deletingTables)
+	clear(c.backups)
+	clear(c.exports)
+	clear(c.imports)
 }
 
 // PutItem inserts or replaces an item.
@@ -334,6 +591,7 @@ func (c *Client) PutItem(ctx context.Context, input *PutItemInput) (*PutItemOutp
 		TableName:                   input.TableName,
 		ConditionExpression:         input.ConditionExpression,
 		ConditionalOperator:         toStringPtr(string(input.ConditionalOperator)),
+		Expected:                    mapExpectedAttributeValues(input.Expected),
 		ExpressionAttributeNames:    input.ExpressionAttributeNames,
 		ExpressionAttributeValues:   mapAttributeValueMapToTypes(input.ExpressionAttributeValues),
 		Item:                        mapAttributeValueMapToTypes(input.Item),
@@ -374,6 +632,7 @@ func (c *Client) DeleteItem(ctx context.Context, input *DeleteItemInput) (*Delet
 		TableName:                 input.TableName,
 		ConditionExpression:       input.ConditionExpression,
 		ConditionalOperator:       toStringPtr(string(input.ConditionalOperator)),
+		Expected:                  mapExpectedAttributeValues(input.Expected),
 		ExpressionAttributeNames:  toStringPtrMap(input.ExpressionAttributeNames),
 		ExpressionAttributeValues: mapAttributeValueMapToTypes(input.ExpressionAttributeValues),
 		Key:                       mapAttributeValueMapToTypes(input.Key),
@@ -414,8 +673,10 @@ func (c *Client) UpdateItem(ctx context.Context, input *UpdateItemInput) (*Updat
 
 	item, err := table.Update(&types.UpdateItemInput{
 		TableName:                           input.TableName,
+		AttributeUpdates:                    mapAttributeValueUpdates(input.AttributeUpdates),
 		ConditionExpression:                 input.ConditionExpression,
 		ConditionalOperator:                 toStringPtr(string(input.ConditionalOperator)),
+		Expected:                            mapExpectedAttributeValues(input.Expected),
 		ExpressionAttributeNames:            input.ExpressionAttributeNames,
 		ExpressionAttributeValues:           mapAttributeValueMapToTypes(input.ExpressionAttributeValues),
 		Key:                                 mapAttributeValueMapToTypes(input.Key),
@@ -443,6 +704,11 @@ func (c *Client) GetItem(ctx context.Context, input *GetItemInput) (*GetItemOutp
 		return nil, err
 	}
 
+	projection, aliases, err := core.ProjectionForAttributesToGet(input.AttributesToGet, aws.ToString(input.ProjectionExpression), input.ExpressionAttributeNames)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
 	table, err := c.getTable(aws.ToString(input.TableName))
 	if err != nil {
 		return nil, err
@@ -462,9 +728,11 @@ func (c *Client) GetItem(ctx context.Context, input *GetItemInput) (*GetItemOutp
 		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: err.Error()}
 	}
 
+	table.RecordAccess(keyMap)
+
 	stored := table.Data[key]
 
-	item, err := getItemAttributesForOutput(table, stored, aws.ToString(input.ProjectionExpression), input.ExpressionAttributeNames)
+	item, err := getItemAttributesForOutput(table, stored, projection, aliases)
 	if err != nil {
 		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: err.Error()}
 	}
@@ -510,6 +778,10 @@ func (c *Client) Query(ctx context.Context, input *QueryInput) (*QueryOutput, er
 		ProjectionExpression:      aws.ToString(input.ProjectionExpression),
 		Limit:                     int64(aws.ToInt32(input.Limit)),
 		ScanIndexForward:          aws.ToBool(input.ScanIndexForward),
+		KeyConditions:             mapConditions(input.KeyConditions),
+		QueryFilter:               mapConditions(input.QueryFilter),
+		ConditionalOperator:       toStringPtr(string(input.ConditionalOperator)),
+		AttributesToGet:           input.AttributesToGet,
 	})
 	if err != nil {
 		return nil, mapKnownError(err)
@@ -557,6 +829,11 @@ func (c *Client) Scan(ctx context.Context, input *ScanInput) (*ScanOutput, error
 		Limit:                     int64(aws.ToInt32(input.Limit)),
 		Scan:                      true,
 		ScanIndexForward:          true,
+		ScanFilter:                mapConditions(input.ScanFilter),
+		ConditionalOperator:       toStringPtr(string(input.ConditionalOperator)),
+		AttributesToGet:           input.AttributesToGet,
+		Segment:                   toInt64Ptr(input.Segment),
+		TotalSegments:             toInt64Ptr(input.TotalSegments),
 	})
 	if err != nil {
 		return nil, mapKnownError(err)
@@ -574,6 +851,7 @@ func (c *Client) Scan(ctx context.Context, input *ScanInput) (*ScanOutput, error
 const (
 	batchWriteItemRequestsLimit = 25
 	batchGetItemRequestsLimit   = 100
+	maxListTablesLimit          = 100
 )
 
 // DynamoDB returns this message when a WriteRequest has both Put and Delete, or neither.
@@ -652,8 +930,9 @@ func (c *Client) BatchWriteItem(ctx context.Context, input *BatchWriteItemInput)
 
 	for tableName, reqs := range input.RequestItems {
 		for i, req := range reqs {
-			if emulation.unprocessed(tableName, i, batchWriteRequestKey(req)) {
+			if raw := batchWriteRequestKey(req); emulation.unprocessed(tableName, i, raw) {
 				unprocessed[tableName] = append(unprocessed[tableName], req)
+				c.recordThrottle(tableName, raw)
 
 				continue
 			}
@@ -767,6 +1046,7 @@ func (c *Client) batchGetItemForTable(ctx context.Context, tableName string, req
 	for i, key := range reqs.Keys {
 		if emulation.unprocessed(tableName, i, key) {
 			unprocessedKeys = append(unprocessedKeys, key)
+			c.recordThrottle(tableName, key)
 
 			continue
 		}
@@ -775,6 +1055,7 @@ func (c *Client) batchGetItemForTable(ctx context.Context, tableName string, req
 			TableName:                aws.String(tableName),
 			Key:                      key,
 			ConsistentRead:           reqs.ConsistentRead,
+			AttributesToGet:          reqs.AttributesToGet,
 			ExpressionAttributeNames: reqs.ExpressionAttributeNames,
 			ProjectionExpression:     reqs.ProjectionExpression,
 		})
@@ -813,37 +1094,61 @@ func (c *Client) prepareTransact(items []TransactWriteItem) (map[string]core.Tab
 			continue
 		}
 
-		if ferr := c.failureErrFor(tableName, ""); ferr != nil {
-			return nil, ferr
+		table, err := c.snapshotTransactTable(snapshots, tableName)
+		if err != nil {
+			return nil, err
 		}
 
-		if _, alreadySnapped := snapshots[tableName]; !alreadySnapped {
-			table, err := c.getTable(tableName)
-			if err != nil {
-				return nil, mapKnownError(err)
-			}
+		internalKeyMap := mapAttributeValueMapToTypes(rawKeyMap)
 
-			snapshots[tableName] = table.Snapshot()
+		if key, err := table.KeySchema.GetKey(table.AttributesDef, internalKeyMap); err == nil {
+			if err := markTransactKey(seenKeys, tableName, key); err != nil {
+				return nil, err
+			}
 		}
+	}
 
-		table := c.tables[tableName]
-		internalKeyMap := mapAttributeValueMapToTypes(rawKeyMap)
+	return snapshots, nil
+}
 
-		if key, err := table.KeySchema.GetKey(table.AttributesDef, internalKeyMap); err == nil {
-			id := tableName + "|" + key
+// snapshotTransactTable snapshots the table the first time a transaction touches it, so the
+// transaction can be rolled back
+func (c *Client) snapshotTransactTable(snapshots map[string]core.TableSnapshot, tableName string) (*core.Table, error) {
+	if ferr := c.failureErrFor(tableName, ""); ferr != nil {
+		return nil, ferr
+	}
 
-			if _, exists := seenKeys[id]; exists {
-				return nil, &smithy.GenericAPIError{
-					Code:    "ValidationException",
-					Message: "Transaction request cannot include multiple operations on one item",
-				}
-			}
+	table, err := c.lookupTable(tableName)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if _, alreadySnapped := snapshots[tableName]; !alreadySnapped {
+		snapshots[tableName] = table.Snapshot()
+	}
+
+	return table, nil
+}
 
-			seenKeys[id] = struct{}{}
+func (c *Client) restoreSnapshots(snapshots map[string]core.TableSnapshot) {
+	for name, snap := range snapshots {
+		c.tables[name].Restore(snap)
+	}
+}
+
+func markTransactKey(seenKeys map[string]struct{}, tableName, key string) error {
+	id := tableName + "|" + key
+
+	if _, exists := seenKeys[id]; exists {
+		return &smithy.GenericAPIError{
+			Code:    "ValidationException",
+			Message: "Transaction request cannot include multiple operations on one item",
 		}
 	}
 
-	return snapshots, nil
+	seenKeys[id] = struct{}{}
+
+	return nil
 }
 
 // TransactWriteItems executes a set of Put, Update, Delete, and ConditionCheck operations atomically.
@@ -856,6 +1161,27 @@ func (c *Client) TransactWriteItems(ctx context.Context, input *TransactWriteIte
 		return nil, c.forceFailureErr
 	}
 
+	token := aws.ToString(input.ClientRequestToken)
+
+	var fingerprint string
+
+	if input.ClientRequestToken != nil {
+		fingerprint = core.RequestFingerprint(input.TransactItems)
+
+		if err := core.ValidateClientRequestToken(token); err != nil {
+			return nil, mapKnownError(err)
+		}
+
+		out, ok, err := c.transactWriteTokens.Lookup(token, fingerprint, c.clock.Now())
+		if err != nil {
+			return nil, mapKnownError(err)
+		}
+
+		if ok {
+			return out, nil
+		}
+	}
+
 	snapshots, err := c.prepareTransact(input.TransactItems)
 	if err != nil {
 		return nil, err
@@ -865,9 +1191,7 @@ func (c *Client) TransactWriteItems(ctx context.Context, input *TransactWriteIte
 
 	defer func() {
 		if execErr != nil {
-			for name, snap := range snapshots {
-				c.tables[name].Restore(snap)
-			}
+			c.restoreSnapshots(snapshots)
 		}
 	}()
 
@@ -880,7 +1204,13 @@ func (c *Client) TransactWriteItems(ctx context.Context, input *TransactWriteIte
 		}
 	}
 
-	return &TransactWriteItemsOutput{}, nil
+	out := &TransactWriteItemsOutput{}
+
+	if input.ClientRequestToken != nil {
+		c.transactWriteTokens.Store(token, fingerprint, out, c.clock.Now())
+	}
+
+	return out, nil
 }
 
 func validateTransactGetItemsInput(c *Client, input *TransactGetItemsInput) error {
@@ -923,7 +1253,7 @@ func validateTransactGetItemsInput(c *Client, input *TransactGetItemsInput) erro
 			}
 		}
 
-		table, err := c.getTable(tableName)
+		table, err := c.lookupTable(tableName)
 		if err != nil {
 			return mapKnownError(err)
 		}
@@ -1004,7 +1334,7 @@ func (c *Client) runTransactPut(i, n int, put *Put) error {
 		return vErr
 	}
 
-	table, tErr := c.getTable(aws.ToString(put.TableName))
+	table, tErr := c.lookupTable(aws.ToString(put.TableName))
 	if tErr != nil {
 		return mapKnownError(tErr)
 	}
@@ -1027,7 +1357,7 @@ func (c *Client) runTransactUpdate(i, n int, update *Update) error {
 		return vErr
 	}
 
-	table, tErr := c.getTable(aws.ToString(update.TableName))
+	table, tErr := c.lookupTable(aws.ToString(update.TableName))
 	if tErr != nil {
 		return mapKnownError(tErr)
 	}
@@ -1057,7 +1387,7 @@ func (c *Client) runTransactDelete(i, n int, del *Delete) error {
 		return vErr
 	}
 
-	table, tErr := c.getTable(aws.ToString(del.TableName))
+	table, tErr := c.lookupTable(aws.ToString(del.TableName))
 	if tErr != nil {
 		return mapKnownError(tErr)
 	}
@@ -1080,7 +1410,7 @@ func (c *Client) runTransactConditionCheck(i, n int, check *ConditionCheck) erro
 		return vErr
 	}
 
-	table, tErr := c.getTable(aws.ToString(check.TableName))
+	table, tErr := c.lookupTable(aws.ToString(check.TableName))
 	if tErr != nil {
 		return mapKnownError(tErr)
 	}
@@ -1099,6 +1429,8 @@ func (c *Client) runTransactConditionCheck(i, n int, check *ConditionCheck) erro
 		return &smithy.GenericAPIError{Code: "ValidationException", Message: kErr.Error()}
 	}
 
+	table.RecordAccess(keyMap)
+
 	stored := table.Data[key]
 	if stored == nil {
 		stored = map[string]*types.Item{}
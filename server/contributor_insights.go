@@ -0,0 +1,211 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+const maxListContributorInsightsResults = 100
+
+// Contributor is a key ranked by Contributor Insights along with the accesses it made.
+type Contributor struct {
+	Key   map[string]*AttributeValue
+	Count int64
+}
+
+// ContributorRanking ranks the keys that made the most accesses in a window. Total
+// counts the accesses of every key, so the share of the top keys can be checked.
+type ContributorRanking struct {
+	Contributors []Contributor
+	Total        int64
+}
+
+// UpdateContributorInsights enables or disables Contributor Insights for a table or a
+// global secondary index.
+func (c *Client) UpdateContributorInsights(ctx context.Context, input *UpdateContributorInsightsInput) (*UpdateContributorInsightsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableName, indexName := aws.ToString(input.TableName), aws.ToString(input.IndexName)
+
+	if err := c.failureErrFor(tableName, indexName); err != nil {
+		return nil, err
+	}
+
+	var enable bool
+
+	switch input.ContributorInsightsAction {
+	case ddbtypes.ContributorInsightsActionEnable:
+		enable = true
+	case ddbtypes.ContributorInsightsActionDisable:
+	case "":
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "1 validation error detected: Value null at 'contributorInsightsAction' failed to satisfy constraint: Member must not be null"}
+	default:
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%s' at 'contributorInsightsAction' failed to satisfy constraint: Member must satisfy enum value set: [ENABLE, DISABLE]", input.ContributorInsightsAction)}
+	}
+
+	table, err := c.getTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := table.UpdateContributorInsights(indexName, enable)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	return &UpdateContributorInsightsOutput{TableName: tableName, IndexName: input.IndexName, ContributorInsightsStatus: status}, nil
+}
+
+// DescribeContributorInsights describes Contributor Insights for a table or a global
+// secondary index.
+func (c *Client) DescribeContributorInsights(ctx context.Context, input *DescribeContributorInsightsInput) (*DescribeContributorInsightsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableName, indexName := aws.ToString(input.TableName), aws.ToString(input.IndexName)
+
+	if err := c.failureErrFor(tableName, indexName); err != nil {
+		return nil, err
+	}
+
+	table, err := c.getTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := table.DescribeContributorInsights(indexName)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	output := &DescribeContributorInsightsOutput{
+		TableName:                   tableName,
+		IndexName:                   input.IndexName,
+		ContributorInsightsRuleList: desc.Rules,
+		ContributorInsightsStatus:   desc.Status,
+	}
+
+	if !desc.LastUpdateTime.IsZero() {
+		output.LastUpdateDateTime = epochSecondsPtr(&desc.LastUpdateTime)
+	}
+
+	return output, nil
+}
+
+// ListContributorInsights lists the tables and global secondary indexes Contributor
+// Insights was enabled for, ordered by table and index.
+func (c *Client) ListContributorInsights(ctx context.Context, input *ListContributorInsightsInput) (*ListContributorInsightsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableName := aws.ToString(input.TableName)
+
+	if err := c.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	if input.MaxResults < 0 || input.MaxResults > maxListContributorInsightsResults {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'maxResults' failed to satisfy constraint: Member must have value between 0 and %d", input.MaxResults, maxListContributorInsightsResults)}
+	}
+
+	limit := maxListContributorInsightsResults
+	if input.MaxResults > 0 {
+		limit = int(input.MaxResults)
+	}
+
+	tableNames := slices.Sorted(maps.Keys(c.tables))
+
+	if tableName != "" {
+		if _, err := c.getTable(tableName); err != nil {
+			return nil, err
+		}
+
+		tableNames = []string{tableName}
+	}
+
+	summaries := []ContributorInsightsSummary{}
+
+	for _, name := range tableNames {
+		table := c.tables[name]
+		if table.Status() == core.TableStatusCreating {
+			continue
+		}
+
+		for _, desc := range table.ListContributorInsights() {
+			summary := ContributorInsightsSummary{TableName: name, ContributorInsightsStatus: desc.Status}
+			if desc.IndexName != "" {
+				summary.IndexName = aws.String(desc.IndexName)
+			}
+
+			summaries = append(summaries, summary)
+		}
+	}
+
+	start := 0
+
+	if token := aws.ToString(input.NextToken); token != "" {
+		for i, summary := range summaries {
+			if contributorInsightsToken(summary) == token {
+				start = i + 1
+
+				break
+			}
+		}
+	}
+
+	end := min(start+limit, len(summaries))
+	output := &ListContributorInsightsOutput{ContributorInsightsSummaries: summaries[start:end]}
+
+	if end < len(summaries) {
+		output.NextToken = aws.String(contributorInsightsToken(summaries[end-1]))
+	}
+
+	return output, nil
+}
+
+func contributorInsightsToken(summary ContributorInsightsSummary) string {
+	return summary.TableName + "/" + aws.ToString(summary.IndexName)
+}
+
+func (c *Client) topContributors(tableName, indexName string, rule core.ContributorInsightsRule, window time.Duration, limit int) (ContributorRanking, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	table, err := c.getTable(tableName)
+	if err != nil {
+		return ContributorRanking{}, err
+	}
+
+	ranking, err := table.TopContributors(indexName, rule, window, limit)
+	if err != nil {
+		return ContributorRanking{}, mapKnownError(err)
+	}
+
+	output := ContributorRanking{Contributors: make([]Contributor, 0, len(ranking.Contributors)), Total: ranking.Total}
+
+	for _, contributor := range ranking.Contributors {
+		output.Contributors = append(output.Contributors, Contributor{Key: mapTypesMapToAttributeValue(contributor.Key), Count: contributor.Count})
+	}
+
+	return output, nil
+}
+
+// recordThrottle counts a batch request left unprocessed as a throttled request of the
+// item it was for
+func (c *Client) recordThrottle(tableName string, raw map[string]*AttributeValue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if table, ok := c.tables[tableName]; ok {
+		table.RecordThrottle(mapAttributeValueMapToTypes(raw))
+	}
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func TestServerContributorInsights(t *testing.T) {
+	c := require.New(t)
+
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	srv := NewServer()
+	srv.SetClock(clock)
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+
+	createBackupTestTable(t, ddb)
+
+	described, err := ddb.DescribeContributorInsights(ctx, &dynamodb.DescribeContributorInsightsInput{TableName: aws.String("pokemons")})
+	c.NoError(err)
+	c.Equal(ddbtypes.ContributorInsightsStatusDisabled, described.ContributorInsightsStatus)
+	c.Nil(described.LastUpdateDateTime)
+
+	_, err = ddb.UpdateContributorInsights(ctx, &dynamodb.UpdateContributorInsightsInput{TableName: aws.String("pokemons"), ContributorInsightsAction: "PAUSE"})
+	c.ErrorContains(err, "Member must satisfy enum value set: [ENABLE, DISABLE]")
+
+	_, err = ddb.UpdateContributorInsights(ctx, &dynamodb.UpdateContributorInsightsInput{
+		TableName:                 aws.String("pokemons"),
+		IndexName:                 aws.String("by-name"),
+		ContributorInsightsAction: ddbtypes.ContributorInsightsActionEnable,
+	})
+	c.ErrorContains(err, "Requested resource not found")
+
+	for _, indexName := range []*string{nil, aws.String("by-type")} {
+		updated, err := ddb.UpdateContributorInsights(ctx, &dynamodb.UpdateContributorInsightsInput{
+			TableName:                 aws.String("pokemons"),
+			IndexName:                 indexName,
+			ContributorInsightsAction: ddbtypes.ContributorInsightsActionEnable,
+		})
+		c.NoError(err)
+		c.Equal(ddbtypes.ContributorInsightsStatusEnabling, updated.ContributorInsightsStatus)
+		c.Equal(aws.ToString(indexName), aws.ToString(updated.IndexName))
+	}
+
+	described, err = ddb.DescribeContributorInsights(ctx, &dynamodb.DescribeContributorInsightsInput{TableName: aws.String("pokemons"), IndexName: aws.String("by-type")})
+	c.NoError(err)
+	c.Equal(ddbtypes.ContributorInsightsStatusEnabled, described.ContributorInsightsStatus)
+	c.Equal(clock.Now(), described.LastUpdateDateTime.UTC())
+	c.Len(described.ContributorInsightsRuleList, 4)
+	c.Contains(described.ContributorInsightsRuleList[0], "DynamoDBContributorInsights-PKC-pokemons-by-type-")
+
+	listed, err := ddb.ListContributorInsights(ctx, &dynamodb.ListContributorInsightsInput{MaxResults: 1})
+	c.NoError(err)
+	c.Len(listed.ContributorInsightsSummaries, 1)
+	c.Nil(listed.ContributorInsightsSummaries[0].IndexName)
+
+	listed, err = ddb.ListContributorInsights(ctx, &dynamodb.ListContributorInsightsInput{NextToken: listed.NextToken})
+	c.NoError(err)
+	c.Equal("by-type", aws.ToString(listed.ContributorInsightsSummaries[0].IndexName))
+	c.Nil(listed.NextToken)
+
+	// the new access pattern reads the same pokemon over and over
+	for range 8 {
+		_, err = ddb.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: aws.String("pokemons"),
+			Key:       map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "25"}},
+		})
+		c.NoError(err)
+	}
+
+	_, err = ddb.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String("pokemons"),
+		IndexName:                 aws.String("by-type"),
+		KeyConditionExpression:    aws.String("#type = :type"),
+		ExpressionAttributeNames:  map[string]string{"#type": "type"},
+		ExpressionAttributeValues: map[string]ddbtypes.AttributeValue{":type": &ddbtypes.AttributeValueMemberS{Value: "fire"}},
+	})
+	c.NoError(err)
+
+	srv.EmulateUnprocessedItems("pokemons", func(_ int, raw map[string]*AttributeValue) bool {
+		return aws.ToString(raw["id"].S) == "7"
+	})
+
+	_, err = ddb.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]ddbtypes.KeysAndAttributes{"pokemons": {Keys: []map[string]ddbtypes.AttributeValue{
+			{"id": &ddbtypes.AttributeValueMemberS{Value: "4"}},
+			{"id": &ddbtypes.AttributeValueMemberS{Value: "7"}},
+		}}},
+	})
+	c.NoError(err)
+
+	ranking, err := srv.TopContributors("pokemons", "", core.MostAccessedPartitionKeys, 5*time.Minute, 1)
+	c.NoError(err)
+	c.Equal(int64(9), ranking.Total)
+	c.Equal([]Contributor{{Key: map[string]*AttributeValue{"id": {S: aws.String("25")}}, Count: 8}}, ranking.Contributors)
+
+	// a check that fails once a single partition takes most of the traffic
+	hot := float64(ranking.Contributors[0].Count) / float64(ranking.Total)
+	c.Greater(hot, 0.5)
+
+	ranking, err = srv.TopContributors("pokemons", "", core.MostThrottledPartitionKeys, 5*time.Minute, 0)
+	c.NoError(err)
+	c.Equal([]Contributor{{Key: map[string]*AttributeValue{"id": {S: aws.String("7")}}, Count: 1}}, ranking.Contributors)
+
+	ranking, err = srv.TopContributors("pokemons", "by-type", core.MostAccessedKeys, 5*time.Minute, 0)
+	c.NoError(err)
+	c.Equal([]Contributor{{Key: map[string]*AttributeValue{"type": {S: aws.String("fire")}, "id": {S: aws.String("4")}}, Count: 1}}, ranking.Contributors)
+
+	_, err = srv.TopContributors("pokemons", "", core.MostAccessedKeys, 5*time.Minute, 0)
+	c.ErrorContains(err, "has no rule SKC")
+
+	// accesses leave the window as the clock moves forward
+	clock.Advance(10 * time.Minute)
+
+	ranking, err = srv.TopContributors("pokemons", "", core.MostAccessedPartitionKeys, 5*time.Minute, 0)
+	c.NoError(err)
+	c.Empty(ranking.Contributors)
+
+	updated, err := ddb.UpdateContributorInsights(ctx, &dynamodb.UpdateContributorInsightsInput{TableName: aws.String("pokemons"), ContributorInsightsAction: ddbtypes.ContributorInsightsActionDisable})
+	c.NoError(err)
+	c.Equal(ddbtypes.ContributorInsightsStatusDisabling, updated.ContributorInsightsStatus)
+
+	described, err = ddb.DescribeContributorInsights(ctx, &dynamodb.DescribeContributorInsightsInput{TableName: aws.String("pokemons")})
+	c.NoError(err)
+	c.Equal(ddbtypes.ContributorInsightsStatusDisabled, described.ContributorInsightsStatus)
+	c.Empty(described.ContributorInsightsRuleList)
+
+	_, err = srv.TopContributors("pokemons", "", core.MostAccessedPartitionKeys, 5*time.Minute, 0)
+	c.ErrorContains(err, "not enabled")
+}
@@ -5,7 +5,41 @@ SDK clients so you can exercise application code without swapping clients.
 
 Key features:
   - DynamoDB JSON API: Supports CreateTable/DescribeTable/UpdateTable/DeleteTable,
-    PutItem/GetItem/UpdateItem/DeleteItem, Query, Scan, and BatchWriteItem.
+    ListTables, PutItem/GetItem/UpdateItem/DeleteItem, Query, Scan, BatchWriteItem,
+    and PartiQL SELECT/INSERT/UPDATE/DELETE statements through ExecuteStatement,
+    BatchExecuteStatement and ExecuteTransaction.
+  - DynamoDB Streams JSON API: Tables with a StreamSpecification record their
+    changes, which are served by ListStreams/DescribeStream/GetShardIterator/
+    GetRecords. Point a dynamodbstreams.Client at the same httptest server.
+  - On-demand backups: CreateBackup/DescribeBackup/ListBackups/DeleteBackup
+    snapshot a table, and RestoreTableFromBackup copies a backup into a new table.
+  - Point-in-time recovery: UpdateContinuousBackups/DescribeContinuousBackups
+    record the item history of a table, RestoreTableToPointInTime rebuilds it as
+    of a time given by the clock passed to SetClock.
+  - Exports: ExportTableToPointInTime/DescribeExport/ListExports write full and
+    incremental exports in the S3 layout of DynamoDB to the sink given to
+    SetExportSink, like a core.DirectoryExportSink writing to a local directory.
+  - Imports: ImportTable/DescribeImport/ListImports create a table from the CSV,
+    DynamoDB JSON or Ion files read from the fs.FS given to SetImportSource, and
+    ImportErrors returns the items that could not be imported.
+  - Table status: SetTableStatusDelay keeps tables CREATING, UPDATING or DELETING
+    for a while, so the TableExists and TableNotExists waiters of the SDK wait.
+  - Table settings: CreateTable/UpdateTable keep the billing mode, throughput,
+    deletion protection, table class and encryption settings of a table, and
+    DeleteTable fails while deletion protection is on.
+  - Tagging: TagResource/UntagResource/ListTagsOfResource manage the tags of a
+    table, and CreateTable honors Tags. SetAccount sets the region and the
+    account ID of every ARN.
+  - Global tables: one server hosts several regions, picked by the SigV4
+    credential scope of a request or by the handler returned by Region.
+    UpdateTable ReplicaUpdates creates replicas in other regions, writes reach
+    them after the lag set with SetReplicationLag and the last writer wins.
+  - Contributor Insights: UpdateContributorInsights/DescribeContributorInsights/
+    ListContributorInsights track the most accessed and most throttled keys of a
+    table or a global secondary index, and TopContributors ranks them over a
+    sliding window.
+  - Time To Live: UpdateTimeToLive/DescribeTimeToLive expire items following the
+    clock given to SetClock, use a core.ManualClock to move time in tests.
   - AWS SDK v2 friendly: Use the standard dynamodb.Client with a custom endpoint
     resolver pointing at the httptest server.
   - Generated request shapes: Input structs in requests.go are generated by
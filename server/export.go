@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+const maxListExportsResults = 25
+
+func (c *Client) setExportSink(sink core.ExportSink) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.exportSink = sink
+}
+
+// exportArn builds an ARN following the format DynamoDB uses for table exports
+func (c *Client) exportArn(tableName string, startTime time.Time) string {
+	c.exportSeq++
+
+	return fmt.Sprintf("%s/export/%014d-%08x", c.tableArn(tableName), startTime.UnixMilli(), c.exportSeq)
+}
+
+func exportDescription(export *core.Export) ExportDescription {
+	desc := ExportDescription{
+		ExportArn:       export.ExportArn,
+		ExportStatus:    export.Status,
+		ExportType:      export.Type,
+		ExportFormat:    export.Format,
+		ExportManifest:  toStringPtr(export.Manifest),
+		StartTime:       epochSeconds(export.StartTime),
+		EndTime:         epochSeconds(export.EndTime),
+		TableArn:        export.TableArn,
+		ClientToken:     toStringPtr(export.ClientToken),
+		S3Bucket:        export.S3Bucket,
+		S3Prefix:        toStringPtr(export.S3Prefix),
+		S3SseAlgorithm:  string(ddbtypes.S3SseAlgorithmAes256),
+		ItemCount:       export.ItemCount,
+		BilledSizeBytes: export.BilledSizeBytes,
+		FailureCode:     toStringPtr(export.FailureCode),
+		FailureMessage:  toStringPtr(export.FailureMessage),
+	}
+
+	if export.Type == core.ExportTypeIncremental {
+		desc.IncrementalExportSpecification = &IncrementalExportSpecificationDescription{
+			ExportFromTime: epochSeconds(export.ExportFromTime),
+			ExportToTime:   epochSeconds(export.ExportToTime),
+			ExportViewType: export.ViewType,
+		}
+	} else {
+		desc.ExportTime = aws.Float64(epochSeconds(export.ExportTime))
+	}
+
+	return desc
+}
+
+// ExportTableToPointInTime writes the items of a table to the export sink.
+func (c *Client) ExportTableToPointInTime(ctx context.Context, input *ExportTableToPointInTimeInput) (*ExportTableToPointInTimeOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableArn := aws.ToString(input.TableArn)
+	_, tableName, _ := strings.Cut(tableArn, ":table/")
+
+	if err := c.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	if tableArn == "" || aws.ToString(input.S3Bucket) == "" {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "TableArn and S3Bucket must be specified"}
+	}
+
+	table, err := c.getBackupTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.clock.Now()
+	exportInput := core.ExportInput{
+		ClientToken: aws.ToString(input.ClientToken),
+		TableArn:    tableArn,
+		S3Bucket:    aws.ToString(input.S3Bucket),
+		S3Prefix:    aws.ToString(input.S3Prefix),
+		Format:      string(input.ExportFormat),
+		Type:        string(input.ExportType),
+		ExportTime:  now,
+	}
+
+	if input.ExportTime != nil {
+		exportInput.ExportTime = input.ExportTime.Time()
+	}
+
+	if spec := input.IncrementalExportSpecification; spec != nil {
+		exportInput.ViewType = string(spec.ExportViewType)
+
+		if spec.ExportFromTime != nil {
+			exportInput.ExportFromTime = spec.ExportFromTime.Time()
+		}
+
+		if spec.ExportToTime != nil {
+			exportInput.ExportToTime = spec.ExportToTime.Time()
+		}
+	}
+
+	if err := core.ValidateExportInput(&exportInput); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if exportInput.Type == core.ExportTypeIncremental && (exportInput.ExportFromTime.IsZero() || exportInput.ExportToTime.IsZero()) {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "IncrementalExportSpecification must specify ExportFromTime and ExportToTime for an incremental export"}
+	}
+
+	exportInput.ExportArn = c.exportArn(tableName, now)
+
+	export, err := table.Export(c.exportSink, exportInput, now)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	c.exports[export.ExportArn] = export
+
+	return &ExportTableToPointInTimeOutput{ExportDescription: exportDescription(export)}, nil
+}
+
+// DescribeExport returns the description of a table export.
+func (c *Client) DescribeExport(ctx context.Context, input *DescribeExportInput) (*DescribeExportOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.forceFailureErr != nil {
+		return nil, c.forceFailureErr
+	}
+
+	arn := aws.ToString(input.ExportArn)
+
+	export, ok := c.exports[arn]
+	if !ok {
+		return nil, &ddbtypes.ExportNotFoundException{Message: aws.String("Export not found: " + arn)}
+	}
+
+	return &DescribeExportOutput{ExportDescription: exportDescription(export)}, nil
+}
+
+// ListExports lists the table exports, oldest first.
+func (c *Client) ListExports(ctx context.Context, input *ListExportsInput) (*ListExportsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableArn := aws.ToString(input.TableArn)
+	_, tableName, _ := strings.Cut(tableArn, ":table/")
+
+	if err := c.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	limit := maxListExportsResults
+	if input.MaxResults != nil {
+		if *input.MaxResults < 1 || *input.MaxResults > maxListExportsResults {
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'maxResults' failed to satisfy constraint: Member must have value between 1 and %d", *input.MaxResults, maxListExportsResults)}
+		}
+
+		limit = int(*input.MaxResults)
+	}
+
+	exports := []*core.Export{}
+
+	for _, export := range c.exports {
+		if tableArn != "" && export.TableArn != tableArn {
+			continue
+		}
+
+		exports = append(exports, export)
+	}
+
+	sort.Slice(exports, func(i, j int) bool {
+		if !exports[i].StartTime.Equal(exports[j].StartTime) {
+			return exports[i].StartTime.Before(exports[j].StartTime)
+		}
+
+		return exports[i].ExportArn < exports[j].ExportArn
+	})
+
+	start := 0
+
+	if token := aws.ToString(input.NextToken); token != "" {
+		for i, export := range exports {
+			if export.ExportArn == token {
+				start = i + 1
+
+				break
+			}
+		}
+	}
+
+	end := min(start+limit, len(exports))
+	output := &ListExportsOutput{ExportSummaries: make([]ExportSummary, 0, end-start)}
+
+	for _, export := range exports[start:end] {
+		output.ExportSummaries = append(output.ExportSummaries, ExportSummary{
+			ExportArn:    export.ExportArn,
+			ExportStatus: export.Status,
+			ExportType:   export.Type,
+		})
+	}
+
+	if end < len(exports) {
+		output.NextToken = aws.String(exports[end-1].ExportArn)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func TestServerExports(t *testing.T) {
+	c := require.New(t)
+
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	srv.SetClock(clock)
+
+	createBackupTestTable(t, ddb)
+
+	tableArn := aws.String("arn:aws:dynamodb:us-east-1:000000000000:table/pokemons")
+
+	_, err := ddb.ExportTableToPointInTime(ctx, &dynamodb.ExportTableToPointInTimeInput{TableArn: tableArn, S3Bucket: aws.String("exports")})
+
+	var unavailableErr *ddbtypes.PointInTimeRecoveryUnavailableException
+	c.True(errors.As(err, &unavailableErr))
+
+	_, err = ddb.UpdateContinuousBackups(ctx, &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String("pokemons"),
+		PointInTimeRecoverySpecification: &ddbtypes.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+
+	enabledAt := clock.Now()
+	clock.Advance(time.Hour)
+
+	failed, err := ddb.ExportTableToPointInTime(ctx, &dynamodb.ExportTableToPointInTimeInput{TableArn: tableArn, S3Bucket: aws.String("exports")})
+	c.NoError(err)
+	c.Equal(ddbtypes.ExportStatusFailed, failed.ExportDescription.ExportStatus)
+	c.Equal("S3NoSuchBucket", aws.ToString(failed.ExportDescription.FailureCode))
+
+	dir := t.TempDir()
+	srv.SetExportSink(core.DirectoryExportSink(dir))
+
+	full, err := ddb.ExportTableToPointInTime(ctx, &dynamodb.ExportTableToPointInTimeInput{
+		TableArn:     tableArn,
+		S3Bucket:     aws.String("exports"),
+		S3Prefix:     aws.String("pokedex"),
+		ExportFormat: ddbtypes.ExportFormatDynamodbJson,
+		ClientToken:  aws.String("token"),
+	})
+	c.NoError(err)
+
+	desc := full.ExportDescription
+	c.Equal(ddbtypes.ExportStatusCompleted, desc.ExportStatus)
+	c.Equal(ddbtypes.ExportTypeFullExport, desc.ExportType)
+	c.Equal(clock.Now(), aws.ToTime(desc.ExportTime).UTC())
+	c.EqualValues(3, aws.ToInt64(desc.ItemCount))
+	c.Equal("token", aws.ToString(desc.ClientToken))
+	c.True(strings.HasPrefix(aws.ToString(desc.ExportArn), aws.ToString(tableArn)+"/export/"))
+
+	summary, err := os.ReadFile(filepath.Join(dir, "exports", filepath.FromSlash(aws.ToString(desc.ExportManifest))))
+	c.NoError(err)
+	c.Contains(string(summary), `"exportArn":"`+aws.ToString(desc.ExportArn)+`"`)
+
+	dataFiles, err := filepath.Glob(filepath.Join(filepath.Dir(filepath.Join(dir, "exports", filepath.FromSlash(aws.ToString(desc.ExportManifest)))), "data", "*.json.gz"))
+	c.NoError(err)
+	c.Len(dataFiles, 1)
+
+	file, err := os.Open(dataFiles[0])
+	c.NoError(err)
+
+	defer func() { c.NoError(file.Close()) }()
+
+	zr, err := gzip.NewReader(file)
+	c.NoError(err)
+
+	raw, err := io.ReadAll(zr)
+	c.NoError(err)
+
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	c.Len(lines, 3)
+
+	var record map[string]map[string]map[string]string
+	c.NoError(json.Unmarshal([]byte(lines[0]), &record))
+	c.Equal("electric", record["Item"]["type"]["S"])
+
+	_, err = ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String("pokemons"),
+		Item: map[string]ddbtypes.AttributeValue{
+			"id":   &ddbtypes.AttributeValueMemberS{Value: "1"},
+			"type": &ddbtypes.AttributeValueMemberS{Value: "grass"},
+		},
+	})
+	c.NoError(err)
+
+	clock.Advance(time.Minute)
+
+	incremental, err := ddb.ExportTableToPointInTime(ctx, &dynamodb.ExportTableToPointInTimeInput{
+		TableArn:     tableArn,
+		S3Bucket:     aws.String("exports"),
+		ExportFormat: ddbtypes.ExportFormatIon,
+		ExportType:   ddbtypes.ExportTypeIncrementalExport,
+		IncrementalExportSpecification: &ddbtypes.IncrementalExportSpecification{
+			ExportFromTime: aws.Time(enabledAt),
+			ExportToTime:   aws.Time(clock.Now()),
+			ExportViewType: ddbtypes.ExportViewTypeNewImage,
+		},
+	})
+	c.NoError(err)
+	c.EqualValues(1, aws.ToInt64(incremental.ExportDescription.ItemCount))
+	c.Equal(ddbtypes.ExportViewTypeNewImage, incremental.ExportDescription.IncrementalExportSpecification.ExportViewType)
+	c.Equal(enabledAt, aws.ToTime(incremental.ExportDescription.IncrementalExportSpecification.ExportFromTime).UTC())
+
+	_, err = ddb.ExportTableToPointInTime(ctx, &dynamodb.ExportTableToPointInTimeInput{
+		TableArn:   tableArn,
+		S3Bucket:   aws.String("exports"),
+		ExportTime: aws.Time(enabledAt.Add(-time.Hour)),
+	})
+
+	var timeErr *ddbtypes.InvalidExportTimeException
+	c.True(errors.As(err, &timeErr))
+
+	described, err := ddb.DescribeExport(ctx, &dynamodb.DescribeExportInput{ExportArn: desc.ExportArn})
+	c.NoError(err)
+	c.Equal(desc.ExportManifest, described.ExportDescription.ExportManifest)
+
+	_, err = ddb.DescribeExport(ctx, &dynamodb.DescribeExportInput{ExportArn: aws.String(aws.ToString(tableArn) + "/export/unknown")})
+
+	var notFoundErr *ddbtypes.ExportNotFoundException
+	c.True(errors.As(err, &notFoundErr))
+
+	listed, err := ddb.ListExports(ctx, &dynamodb.ListExportsInput{TableArn: tableArn, MaxResults: aws.Int32(2)})
+	c.NoError(err)
+	c.Len(listed.ExportSummaries, 2)
+	c.Equal(failed.ExportDescription.ExportArn, listed.ExportSummaries[0].ExportArn)
+	c.NotNil(listed.NextToken)
+
+	listed, err = ddb.ListExports(ctx, &dynamodb.ListExportsInput{TableArn: tableArn, NextToken: listed.NextToken})
+	c.NoError(err)
+	c.Len(listed.ExportSummaries, 1)
+	c.Equal(ddbtypes.ExportTypeIncrementalExport, listed.ExportSummaries[0].ExportType)
+	c.Nil(listed.NextToken)
+
+	_, err = ddb.ListExports(ctx, &dynamodb.ListExportsInput{MaxResults: aws.Int32(26)})
+	c.ErrorContains(err, "ValidationException")
+}
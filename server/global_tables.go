@@ -0,0 +1,392 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+	"github.com/truora/minidyn/types"
+)
+
+// regions are the regions hosted together with a client, the writes made to a replica of
+// a global table reach the replicas of the other regions once the replication lag passes
+type regions struct {
+	mu      sync.Mutex
+	clients []*Client
+	lag     time.Duration
+	queue   []replicaWrite
+}
+
+// replicaWrite is a change of a replica waiting to be applied to the replica of a region
+type replicaWrite struct {
+	client    *Client
+	tableName string
+	change    core.ReplicaChange
+}
+
+func newRegions(home *Client) *regions {
+	return &regions{clients: []*Client{home}}
+}
+
+// signingRegion returns the region of the SigV4 credential scope of a request
+func signingRegion(r *http.Request) string {
+	_, credential, ok := strings.Cut(r.Header.Get("Authorization"), "Credential=")
+	if !ok {
+		return ""
+	}
+
+	credential, _, _ = strings.Cut(credential, ",")
+
+	// the scope is access key/date/region/service/aws4_request
+	scope := strings.Split(credential, "/")
+	if len(scope) < 5 {
+		return ""
+	}
+
+	return scope[2]
+}
+
+func (r *regions) setLag(lag time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lag = lag
+}
+
+// client returns the client of a hosted region, nil when the region is not hosted
+func (r *regions) client(region string) *Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.lookup(region)
+}
+
+// lookup finds the client of a region, callers must hold r.mu
+func (r *regions) lookup(region string) *Client {
+	for _, client := range r.clients {
+		client.mu.Lock()
+		hosted := client.region == region
+		client.mu.Unlock()
+
+		if hosted {
+			return client
+		}
+	}
+
+	return nil
+}
+
+// host returns the client of a region, a new region starts with the clock, the account,
+// the delays and the import and export locations of from. Callers must hold r.mu
+func (r *regions) host(region string, from *Client) *Client {
+	if client := r.lookup(region); client != nil {
+		return client
+	}
+
+	from.mu.Lock()
+	defer from.mu.Unlock()
+
+	client := NewClient()
+	client.regions = r
+	client.region, client.accountID = region, from.accountID
+	client.useNativeInterpreter = from.useNativeInterpreter
+	client.indexActivationDelay = from.indexActivationDelay
+	client.tableStatusDelay = from.tableStatusDelay
+	client.clock = from.clock
+	client.sweepExpiredOnAccess = from.sweepExpiredOnAccess
+	client.exportSink = from.exportSink
+	client.importSource = from.importSource
+
+	r.clients = append(r.clients, client)
+
+	return client
+}
+
+// reset removes the tables of every region along with the writes waiting to be replicated
+func (r *regions) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, client := range r.clients {
+		client.Reset()
+	}
+
+	clear(r.queue)
+	r.queue = nil
+}
+
+// replicate takes the writes made to the replicas of every region and applies the ones
+// made at least the replication lag ago to the replicas of the other regions
+func (r *regions) replicate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, source := range r.clients {
+		source.mu.Lock()
+
+		for tableName, table := range source.tables {
+			for _, change := range table.TakeReplicaChanges() {
+				for _, client := range r.clients {
+					if client != source {
+						r.queue = append(r.queue, replicaWrite{client: client, tableName: tableName, change: change})
+					}
+				}
+			}
+		}
+
+		source.mu.Unlock()
+	}
+
+	waiting := r.queue[:0]
+
+	for _, write := range r.queue {
+		if !write.apply(r.lag) {
+			waiting = append(waiting, write)
+		}
+	}
+
+	clear(r.queue[len(waiting):])
+	r.queue = waiting
+}
+
+// apply applies the write to the replica of its region once the replication lag passed,
+// it reports false while the write has to wait
+func (w replicaWrite) apply(lag time.Duration) bool {
+	w.client.mu.Lock()
+	defer w.client.mu.Unlock()
+
+	if w.change.Metadata.UpdateTime.Add(lag).After(w.client.clock.Now()) {
+		return false
+	}
+
+	// the replica may have been deleted while the write was waiting
+	if table, ok := w.client.tables[w.tableName]; ok && table.ReplicaRegion() != "" {
+		table.ApplyReplicaChange(w.change)
+	}
+
+	return true
+}
+
+// replicas describes the replicas of a global table in the regions other than the one of
+// from, sorted by region
+func (r *regions) replicas(from *Client, tableName string) []ddbtypes.ReplicaDescription {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	replicas := []ddbtypes.ReplicaDescription{}
+
+	for _, client := range r.clients {
+		if client == from {
+			continue
+		}
+
+		client.mu.Lock()
+
+		if table, ok := client.tables[tableName]; ok && table.ReplicaRegion() != "" {
+			replica := ddbtypes.ReplicaDescription{
+				RegionName:    aws.String(client.region),
+				ReplicaStatus: ddbtypes.ReplicaStatus(table.Status()),
+			}
+
+			if sse := table.Description(tableName).SSEDescription; sse != nil {
+				replica.KMSMasterKeyId = aws.String(sse.KMSMasterKeyArn)
+			}
+
+			replicas = append(replicas, replica)
+		}
+
+		client.mu.Unlock()
+	}
+
+	sort.Slice(replicas, func(i, j int) bool {
+		return aws.ToString(replicas[i].RegionName) < aws.ToString(replicas[j].RegionName)
+	})
+
+	return replicas
+}
+
+// describeReplicas adds the version and the replicas of a global table to its description
+func (c *Client) describeReplicas(tableName string, table *core.Table, desc *TableDescription) {
+	if table.ReplicaRegion() == "" {
+		return
+	}
+
+	replicas := c.regions.replicas(c, tableName)
+	if len(replicas) == 0 {
+		return
+	}
+
+	desc.GlobalTableVersion = aws.String(core.GlobalTableVersion)
+	desc.Replicas = replicas
+}
+
+// updateReplicas creates, updates and deletes the replicas of a table in other regions,
+// the regions the server does not host yet are hosted when a replica is created there
+func (c *Client) updateReplicas(ctx context.Context, tableName string, table *core.Table, updates []ddbtypes.ReplicationGroupUpdate) error {
+	c.regions.mu.Lock()
+	defer c.regions.mu.Unlock()
+
+	for i, update := range updates {
+		var err error
+
+		switch {
+		case update.Create != nil:
+			err = c.createReplica(tableName, table, i, update.Create)
+		case update.Update != nil:
+			err = c.updateReplica(tableName, i, update.Update)
+		case update.Delete != nil:
+			err = c.deleteReplica(ctx, tableName, i, update.Delete)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createReplica copies the table into a new replica, callers must hold c.regions.mu
+func (c *Client) createReplica(tableName string, table *core.Table, i int, action *ddbtypes.CreateReplicationGroupMemberAction) error {
+	if action.RegionName == nil {
+		return replicaRegionNullError(i, "create")
+	}
+
+	region := aws.ToString(action.RegionName)
+	dest := c.regions.host(region, c)
+
+	if dest == c {
+		return &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("One or more parameter values were invalid: Cannot add replica in the region of the table: %s", region)}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if stream := table.LatestStream(); stream == nil || !stream.Enabled || stream.ViewType != core.StreamViewTypeNewAndOldImages {
+		return &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("One or more parameter values were invalid: Table %s must have DynamoDB Streams enabled with NEW_AND_OLD_IMAGES to be replicated", tableName)}
+	}
+
+	dest.mu.Lock()
+	defer dest.mu.Unlock()
+
+	_, exists := dest.tables[tableName]
+	if _, deleting := dest.deletingTable(tableName); exists || deleting {
+		return &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("Failed to create a the new replica of table with name: '%s' because one or more replicas already existed as tables.", tableName)}
+	}
+
+	table.EnableReplication(c.region)
+
+	replica := dest.newTable(tableName)
+	if err := table.CreateReplica(replica, region); err != nil {
+		return mapKnownError(err)
+	}
+
+	if err := replica.SetSettings(dest.replicaSettings(replica, action.ProvisionedThroughputOverride, action.OnDemandThroughputOverride, action.TableClassOverride, action.KMSMasterKeyId)); err != nil {
+		return mapKnownError(err)
+	}
+
+	dest.tables[tableName] = replica
+
+	return nil
+}
+
+// updateReplica changes the settings a replica overrides, callers must hold c.regions.mu
+func (c *Client) updateReplica(tableName string, i int, action *ddbtypes.UpdateReplicationGroupMemberAction) error {
+	if action.RegionName == nil {
+		return replicaRegionNullError(i, "update")
+	}
+
+	dest, replica, err := c.replica(tableName, aws.ToString(action.RegionName))
+	if err != nil {
+		return err
+	}
+
+	dest.mu.Lock()
+	defer dest.mu.Unlock()
+
+	if err := replica.UpdateSettings(dest.replicaSettings(replica, action.ProvisionedThroughputOverride, action.OnDemandThroughputOverride, action.TableClassOverride, action.KMSMasterKeyId)); err != nil {
+		return mapKnownError(err)
+	}
+
+	return nil
+}
+
+// deleteReplica deletes the replica of a region, callers must hold c.regions.mu
+func (c *Client) deleteReplica(ctx context.Context, tableName string, i int, action *ddbtypes.DeleteReplicationGroupMemberAction) error {
+	if action.RegionName == nil {
+		return replicaRegionNullError(i, "delete")
+	}
+
+	dest, _, err := c.replica(tableName, aws.ToString(action.RegionName))
+	if err != nil {
+		return err
+	}
+
+	dest.mu.Lock()
+	defer dest.mu.Unlock()
+
+	_, err = dest.DeleteTable(ctx, &DeleteTableInput{TableName: aws.String(tableName)})
+
+	return err
+}
+
+// replica returns the replica of a table in another region, callers must hold c.regions.mu
+func (c *Client) replica(tableName, region string) (*Client, *core.Table, error) {
+	dest := c.regions.lookup(region)
+	if dest != nil && dest != c {
+		dest.mu.Lock()
+		table, ok := dest.tables[tableName]
+		dest.mu.Unlock()
+
+		if ok && table.ReplicaRegion() != "" {
+			return dest, table, nil
+		}
+	}
+
+	return nil, nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("Replica specified in the Replica Update or Replica Delete action of the request was not found: %s", region)}
+}
+
+// replicaSettings maps the settings a replica overrides, the KMS key is resolved in the
+// region of the replica
+func (c *Client) replicaSettings(replica *core.Table, throughput *ddbtypes.ProvisionedThroughputOverride, onDemand *ddbtypes.OnDemandThroughputOverride, tableClass ddbtypes.TableClass, kmsKeyID *string) core.TableSettings {
+	settings := core.TableSettings{TableClass: toStringPtr(string(tableClass))}
+
+	if throughput != nil && throughput.ReadCapacityUnits != nil {
+		current := replica.Description(replica.Name).ProvisionedThroughput
+		settings.ProvisionedThroughput = &types.ProvisionedThroughput{ReadCapacityUnits: *throughput.ReadCapacityUnits, WriteCapacityUnits: current.WriteCapacityUnits}
+	}
+
+	if onDemand != nil {
+		settings.OnDemandThroughput = &core.Throughput{ReadUnits: aws.ToInt64(onDemand.MaxReadRequestUnits)}
+	}
+
+	if kmsKeyID != nil {
+		settings.SSE = &core.SSE{Enabled: true, KMSMasterKeyArn: c.kmsKeyArn(*kmsKeyID)}
+	}
+
+	return settings
+}
+
+func replicaRegionNullError(i int, action string) error {
+	return &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value null at 'replicaUpdates.%d.member.%s.regionName' failed to satisfy constraint: Member must not be null", i+1, action)}
+}
+
+func (c *Client) replicationMetadata(tableName string, key map[string]*AttributeValue) (core.ReplicaMetadata, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	table, ok := c.tables[tableName]
+	if !ok {
+		return core.ReplicaMetadata{}, false
+	}
+
+	return table.ReplicaMetadata(mapAttributeValueMapToTypes(key))
+}
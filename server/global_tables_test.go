@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func inRegion(region string) func(*dynamodb.Options) {
+	return func(o *dynamodb.Options) {
+		o.Region = region
+	}
+}
+
+func getPokemonName(t *testing.T, ddb *dynamodb.Client, id string, optFns ...func(*dynamodb.Options)) string {
+	t.Helper()
+
+	out, err := ddb.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String("pokemons"),
+		Key:       map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: id}},
+	}, optFns...)
+	require.NoError(t, err)
+
+	name, ok := out.Item["name"].(*ddbtypes.AttributeValueMemberS)
+	if !ok {
+		return ""
+	}
+
+	return name.Value
+}
+
+func putPokemon(t *testing.T, ddb *dynamodb.Client, id, name string) {
+	t.Helper()
+
+	_, err := ddb.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String("pokemons"),
+		Item: map[string]ddbtypes.AttributeValue{
+			"id":   &ddbtypes.AttributeValueMemberS{Value: id},
+			"name": &ddbtypes.AttributeValueMemberS{Value: name},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestServerGlobalTables(t *testing.T) {
+	c := require.New(t)
+
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	srv := NewServer()
+	srv.SetClock(clock)
+	srv.SetReplicationLag(time.Second)
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	// every request sent to the endpoint of a region is served by it
+	euTS := httptest.NewServer(srv.Region("eu-west-1"))
+	defer euTS.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+	eu := newTestDynamoClient(t, euTS.URL)
+
+	_, err := ddb.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:            aws.String("pokemons"),
+		KeySchema:            []ddbtypes.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: ddbtypes.KeyTypeHash}},
+		AttributeDefinitions: []ddbtypes.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: ddbtypes.ScalarAttributeTypeS}},
+		BillingMode:          ddbtypes.BillingModePayPerRequest,
+	})
+	c.NoError(err)
+
+	putPokemon(t, ddb, "25", "pikachu")
+
+	addReplicas := &dynamodb.UpdateTableInput{
+		TableName: aws.String("pokemons"),
+		ReplicaUpdates: []ddbtypes.ReplicationGroupUpdate{
+			{Create: &ddbtypes.CreateReplicationGroupMemberAction{RegionName: aws.String("eu-west-1")}},
+			{Create: &ddbtypes.CreateReplicationGroupMemberAction{RegionName: aws.String("ap-southeast-1"), KMSMasterKeyId: aws.String("pokedex")}},
+		},
+	}
+
+	_, err = ddb.UpdateTable(ctx, addReplicas)
+	c.ErrorContains(err, "must have DynamoDB Streams enabled with NEW_AND_OLD_IMAGES")
+
+	_, err = ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:           aws.String("pokemons"),
+		StreamSpecification: &ddbtypes.StreamSpecification{StreamEnabled: aws.Bool(true), StreamViewType: ddbtypes.StreamViewTypeNewAndOldImages},
+	})
+	c.NoError(err)
+
+	updated, err := ddb.UpdateTable(ctx, addReplicas)
+	c.NoError(err)
+	c.Equal(core.GlobalTableVersion, aws.ToString(updated.TableDescription.GlobalTableVersion))
+	c.Len(updated.TableDescription.Replicas, 2)
+	c.Equal("ap-southeast-1", aws.ToString(updated.TableDescription.Replicas[0].RegionName))
+	c.Equal("arn:aws:kms:ap-southeast-1:000000000000:key/pokedex", aws.ToString(updated.TableDescription.Replicas[0].KMSMasterKeyId))
+	c.Equal(ddbtypes.ReplicaStatusActive, updated.TableDescription.Replicas[1].ReplicaStatus)
+
+	_, err = ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:      aws.String("pokemons"),
+		ReplicaUpdates: []ddbtypes.ReplicationGroupUpdate{{Create: &ddbtypes.CreateReplicationGroupMemberAction{RegionName: aws.String("eu-west-1")}}},
+	})
+	c.ErrorContains(err, "one or more replicas already existed as tables")
+
+	// replicas start with the items of the table
+	c.Equal("pikachu", getPokemonName(t, eu, "25"))
+
+	described, err := eu.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String("pokemons")})
+	c.NoError(err)
+	c.Equal("arn:aws:dynamodb:eu-west-1:000000000000:table/pokemons", aws.ToString(described.Table.TableArn))
+	c.Equal([]string{"ap-southeast-1", "us-east-1"}, replicaRegions(described.Table.Replicas))
+
+	// writes reach the other regions after the replication lag, the region of a request
+	// is also taken from its signature
+	putPokemon(t, ddb, "1", "bulbasaur")
+	c.Empty(getPokemonName(t, eu, "1"))
+
+	clock.Advance(time.Second)
+	c.Equal("bulbasaur", getPokemonName(t, eu, "1"))
+	c.Equal("bulbasaur", getPokemonName(t, ddb, "1", inRegion("ap-southeast-1")))
+
+	// the last writer wins when the regions change the same item
+	putPokemon(t, ddb, "4", "charmander")
+	clock.Advance(time.Millisecond)
+	putPokemon(t, eu, "4", "charmeleon")
+	clock.Advance(time.Second)
+
+	c.Equal("charmeleon", getPokemonName(t, ddb, "4"))
+	c.Equal("charmeleon", getPokemonName(t, eu, "4"))
+
+	metadata, ok := srv.ReplicationMetadata("pokemons", map[string]*AttributeValue{"id": {S: aws.String("4")}})
+	c.True(ok)
+	c.Equal(core.ReplicaMetadata{UpdateRegion: "eu-west-1", UpdateTime: clock.Now().Add(-time.Second)}, metadata)
+
+	_, err = eu.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String("pokemons"),
+		Key:       map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "25"}},
+	})
+	c.NoError(err)
+
+	clock.Advance(time.Second)
+	c.Empty(getPokemonName(t, ddb, "25"))
+
+	metadata, ok = srv.Region("ap-southeast-1").ReplicationMetadata("pokemons", map[string]*AttributeValue{"id": {S: aws.String("25")}})
+	c.True(ok)
+	c.True(metadata.Deleting)
+
+	updated, err = ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:      aws.String("pokemons"),
+		ReplicaUpdates: []ddbtypes.ReplicationGroupUpdate{{Delete: &ddbtypes.DeleteReplicationGroupMemberAction{RegionName: aws.String("ap-southeast-1")}}},
+	})
+	c.NoError(err)
+	c.Equal([]string{"eu-west-1"}, replicaRegions(updated.TableDescription.Replicas))
+
+	_, err = ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:      aws.String("pokemons"),
+		ReplicaUpdates: []ddbtypes.ReplicationGroupUpdate{{Delete: &ddbtypes.DeleteReplicationGroupMemberAction{RegionName: aws.String("ap-southeast-1")}}},
+	})
+	c.ErrorContains(err, "was not found: ap-southeast-1")
+
+	// requests signed for regions the server does not host are served by its region
+	c.Equal("charmeleon", getPokemonName(t, ddb, "4", inRegion("sa-east-1")))
+
+	c.NoError(srv.Reset())
+
+	_, err = eu.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String("pokemons")})
+	c.Error(err)
+}
+
+func replicaRegions(replicas []ddbtypes.ReplicaDescription) []string {
+	regions := make([]string, 0, len(replicas))
+
+	for _, replica := range replicas {
+		regions = append(regions, aws.ToString(replica.RegionName))
+	}
+
+	return regions
+}
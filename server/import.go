@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+const maxListImportsPageSize = 25
+
+// tableImport keeps an import with the parts of its request echoed by its description
+type tableImport struct {
+	*core.Import
+
+	tableArn           string
+	bucketSource       *ddbtypes.S3BucketSource
+	formatOptions      *ddbtypes.InputFormatOptions
+	creationParameters *ddbtypes.TableCreationParameters
+}
+
+func (c *Client) setImportSource(source fs.FS) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.importSource = source
+}
+
+func (c *Client) importErrors(importArn string) []core.ImportError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	imp, ok := c.imports[importArn]
+	if !ok {
+		return nil
+	}
+
+	return imp.Errors
+}
+
+// importArn builds an ARN following the format DynamoDB uses for table imports
+func (c *Client) importArn(tableName string, startTime time.Time) string {
+	c.importSeq++
+
+	return fmt.Sprintf("%s/import/%014d-%08x", c.tableArn(tableName), startTime.UnixMilli(), c.importSeq)
+}
+
+// importLogGroupArn is the CloudWatch log group where DynamoDB logs the import errors
+func (c *Client) importLogGroupArn() string {
+	return fmt.Sprintf("arn:aws:logs:%s:%s:log-group:/aws-dynamodb/imports:*", c.region, c.accountID)
+}
+
+func (c *Client) importDescription(imp *tableImport) ImportTableDescription {
+	return ImportTableDescription{
+		ImportArn:               imp.ImportArn,
+		ImportStatus:            imp.Status,
+		TableArn:                imp.tableArn,
+		ClientToken:             toStringPtr(imp.ClientToken),
+		S3BucketSource:          imp.bucketSource,
+		CloudWatchLogGroupArn:   c.importLogGroupArn(),
+		InputFormat:             imp.Format,
+		InputFormatOptions:      imp.formatOptions,
+		InputCompressionType:    imp.Compression,
+		TableCreationParameters: imp.creationParameters,
+		StartTime:               epochSeconds(imp.StartTime),
+		EndTime:                 epochSeconds(imp.EndTime),
+		ProcessedSizeBytes:      imp.ProcessedSizeBytes,
+		ProcessedItemCount:      imp.ProcessedItemCount,
+		ImportedItemCount:       imp.ImportedItemCount,
+		ErrorCount:              imp.ErrorCount,
+		FailureCode:             toStringPtr(imp.FailureCode),
+		FailureMessage:          toStringPtr(imp.FailureMessage),
+	}
+}
+
+// ImportTable creates a new table with the items read from the import source.
+func (c *Client) ImportTable(ctx context.Context, input *ImportTableInput) (*ImportTableOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	params := input.TableCreationParameters
+	if params == nil {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "1 validation error detected: Value null at 'tableCreationParameters' failed to satisfy constraint: Member must not be null"}
+	}
+
+	tableName := aws.ToString(params.TableName)
+
+	if err := c.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	importInput := core.ImportInput{
+		ClientToken: aws.ToString(input.ClientToken),
+		Format:      string(input.InputFormat),
+		Compression: string(input.InputCompressionType),
+	}
+
+	if input.S3BucketSource != nil {
+		importInput.S3Bucket = aws.ToString(input.S3BucketSource.S3Bucket)
+		importInput.S3KeyPrefix = aws.ToString(input.S3BucketSource.S3KeyPrefix)
+	}
+
+	if input.InputFormatOptions != nil && input.InputFormatOptions.Csv != nil {
+		importInput.CSVDelimiter = aws.ToString(input.InputFormatOptions.Csv.Delimiter)
+		importInput.CSVHeader = input.InputFormatOptions.Csv.HeaderList
+	}
+
+	if err := core.ValidateImportInput(&importInput); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if _, ok := c.tables[tableName]; ok {
+		return nil, &ddbtypes.ResourceInUseException{Message: aws.String("Table already exists: " + tableName)}
+	}
+
+	if _, err := c.CreateTable(ctx, &CreateTableInput{
+		AttributeDefinitions:   params.AttributeDefinitions,
+		KeySchema:              params.KeySchema,
+		TableName:              params.TableName,
+		BillingMode:            params.BillingMode,
+		GlobalSecondaryIndexes: params.GlobalSecondaryIndexes,
+		ProvisionedThroughput:  params.ProvisionedThroughput,
+		OnDemandThroughput:     params.OnDemandThroughput,
+		SSESpecification:       params.SSESpecification,
+	}); err != nil {
+		return nil, err
+	}
+
+	now := c.clock.Now()
+	importInput.ImportArn = c.importArn(tableName, now)
+
+	imp := &tableImport{
+		Import:             c.tables[tableName].Import(c.importSource, importInput, now),
+		tableArn:           c.tableArn(tableName),
+		bucketSource:       input.S3BucketSource,
+		formatOptions:      input.InputFormatOptions,
+		creationParameters: params,
+	}
+
+	// like DynamoDB, a failed import leaves no table behind
+	if imp.Status == core.ImportStatusFailed {
+		delete(c.tables, tableName)
+	}
+
+	c.imports[imp.ImportArn] = imp
+
+	return &ImportTableOutput{ImportTableDescription: c.importDescription(imp)}, nil
+}
+
+// DescribeImport returns the description of a table import.
+func (c *Client) DescribeImport(ctx context.Context, input *DescribeImportInput) (*DescribeImportOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.forceFailureErr != nil {
+		return nil, c.forceFailureErr
+	}
+
+	arn := aws.ToString(input.ImportArn)
+
+	imp, ok := c.imports[arn]
+	if !ok {
+		return nil, &ddbtypes.ImportNotFoundException{Message: aws.String("Import not found: " + arn)}
+	}
+
+	return &DescribeImportOutput{ImportTableDescription: c.importDescription(imp)}, nil
+}
+
+// ListImports lists the table imports, oldest first.
+func (c *Client) ListImports(ctx context.Context, input *ListImportsInput) (*ListImportsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableArn := aws.ToString(input.TableArn)
+	_, tableName, _ := strings.Cut(tableArn, ":table/")
+
+	if err := c.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	pageSize := maxListImportsPageSize
+	if input.PageSize != nil {
+		if *input.PageSize < 1 || *input.PageSize > maxListImportsPageSize {
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'pageSize' failed to satisfy constraint: Member must have value between 1 and %d", *input.PageSize, maxListImportsPageSize)}
+		}
+
+		pageSize = int(*input.PageSize)
+	}
+
+	imports := []*tableImport{}
+
+	for _, imp := range c.imports {
+		if tableArn != "" && imp.tableArn != tableArn {
+			continue
+		}
+
+		imports = append(imports, imp)
+	}
+
+	sort.Slice(imports, func(i, j int) bool {
+		if !imports[i].StartTime.Equal(imports[j].StartTime) {
+			return imports[i].StartTime.Before(imports[j].StartTime)
+		}
+
+		return imports[i].ImportArn < imports[j].ImportArn
+	})
+
+	start := 0
+
+	if token := aws.ToString(input.NextToken); token != "" {
+		for i, imp := range imports {
+			if imp.ImportArn == token {
+				start = i + 1
+
+				break
+			}
+		}
+	}
+
+	end := min(start+pageSize, len(imports))
+	output := &ListImportsOutput{ImportSummaryList: make([]ImportSummary, 0, end-start)}
+
+	for _, imp := range imports[start:end] {
+		output.ImportSummaryList = append(output.ImportSummaryList, ImportSummary{
+			ImportArn:             imp.ImportArn,
+			ImportStatus:          imp.Status,
+			TableArn:              imp.tableArn,
+			S3BucketSource:        imp.bucketSource,
+			CloudWatchLogGroupArn: c.importLogGroupArn(),
+			InputFormat:           imp.Format,
+			StartTime:             epochSeconds(imp.StartTime),
+			EndTime:               epochSeconds(imp.EndTime),
+		})
+	}
+
+	if end < len(imports) {
+		output.NextToken = aws.String(imports[end-1].ImportArn)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func importTestInput(tableName, bucket string) *dynamodb.ImportTableInput {
+	return &dynamodb.ImportTableInput{
+		S3BucketSource: &ddbtypes.S3BucketSource{S3Bucket: aws.String(bucket), S3KeyPrefix: aws.String("pokedex/")},
+		InputFormat:    ddbtypes.InputFormatCsv,
+		InputFormatOptions: &ddbtypes.InputFormatOptions{
+			Csv: &ddbtypes.CsvOptions{Delimiter: aws.String(";")},
+		},
+		TableCreationParameters: &ddbtypes.TableCreationParameters{
+			TableName:            aws.String(tableName),
+			AttributeDefinitions: []ddbtypes.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: ddbtypes.ScalarAttributeTypeN}},
+			KeySchema:            []ddbtypes.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: ddbtypes.KeyTypeHash}},
+			BillingMode:          ddbtypes.BillingModePayPerRequest,
+		},
+	}
+}
+
+func TestServerImports(t *testing.T) {
+	c := require.New(t)
+
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+	srv.SetClock(core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+
+	failed, err := ddb.ImportTable(ctx, importTestInput("pokemons", "imports"))
+	c.NoError(err)
+	c.Equal(ddbtypes.ImportStatusFailed, failed.ImportTableDescription.ImportStatus)
+	c.Equal("S3NoSuchBucket", aws.ToString(failed.ImportTableDescription.FailureCode))
+
+	_, err = ddb.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String("pokemons")})
+
+	var tableErr *ddbtypes.ResourceNotFoundException
+	c.True(errors.As(err, &tableErr))
+
+	dir := t.TempDir()
+	c.NoError(os.MkdirAll(filepath.Join(dir, "imports", "pokedex"), 0o755))
+	c.NoError(os.WriteFile(filepath.Join(dir, "imports", "pokedex", "pokemons.csv"), []byte("id;name\n1;bulbasaur\n4;charmander\nseven;squirtle\n"), 0o600))
+	srv.SetImportSource(os.DirFS(dir))
+
+	imported, err := ddb.ImportTable(ctx, importTestInput("pokemons", "imports"))
+	c.NoError(err)
+
+	desc := imported.ImportTableDescription
+	c.Equal(ddbtypes.ImportStatusCompleted, desc.ImportStatus)
+	c.Equal("arn:aws:dynamodb:us-east-1:000000000000:table/pokemons", aws.ToString(desc.TableArn))
+	c.Equal(ddbtypes.InputCompressionTypeNone, desc.InputCompressionType)
+	c.Equal(";", aws.ToString(desc.InputFormatOptions.Csv.Delimiter))
+	c.Equal("pokemons", aws.ToString(desc.TableCreationParameters.TableName))
+	c.EqualValues(3, desc.ProcessedItemCount)
+	c.EqualValues(2, desc.ImportedItemCount)
+	c.EqualValues(1, desc.ErrorCount)
+
+	errs := srv.ImportErrors(aws.ToString(desc.ImportArn))
+	c.Len(errs, 1)
+	c.Equal("pokedex/pokemons.csv", errs[0].Key)
+	c.Equal(2, errs[0].ItemIndex)
+
+	item, err := ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String("pokemons"),
+		Key:       map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberN{Value: "4"}},
+	})
+	c.NoError(err)
+	c.Equal(&ddbtypes.AttributeValueMemberS{Value: "charmander"}, item.Item["name"])
+
+	_, err = ddb.ImportTable(ctx, importTestInput("pokemons", "imports"))
+
+	var inUseErr *ddbtypes.ResourceInUseException
+	c.True(errors.As(err, &inUseErr))
+
+	input := importTestInput("moves", "imports")
+	input.InputFormat = ddbtypes.InputFormatIon
+	_, err = ddb.ImportTable(ctx, input)
+	c.ErrorContains(err, "CSV input format")
+
+	described, err := ddb.DescribeImport(ctx, &dynamodb.DescribeImportInput{ImportArn: desc.ImportArn})
+	c.NoError(err)
+	c.EqualValues(2, described.ImportTableDescription.ImportedItemCount)
+
+	_, err = ddb.DescribeImport(ctx, &dynamodb.DescribeImportInput{ImportArn: aws.String(aws.ToString(desc.TableArn) + "/import/unknown")})
+
+	var notFoundErr *ddbtypes.ImportNotFoundException
+	c.True(errors.As(err, &notFoundErr))
+
+	listed, err := ddb.ListImports(ctx, &dynamodb.ListImportsInput{TableArn: desc.TableArn, PageSize: aws.Int32(1)})
+	c.NoError(err)
+	c.Len(listed.ImportSummaryList, 1)
+	c.Equal(failed.ImportTableDescription.ImportArn, listed.ImportSummaryList[0].ImportArn)
+	c.NotNil(listed.NextToken)
+
+	listed, err = ddb.ListImports(ctx, &dynamodb.ListImportsInput{TableArn: desc.TableArn, NextToken: listed.NextToken})
+	c.NoError(err)
+	c.Len(listed.ImportSummaryList, 1)
+	c.Equal(desc.ImportArn, listed.ImportSummaryList[0].ImportArn)
+	c.Nil(listed.NextToken)
+
+	_, err = ddb.ListImports(ctx, &dynamodb.ListImportsInput{PageSize: aws.Int32(26)})
+	c.ErrorContains(err, "ValidationException")
+}
@@ -52,6 +52,56 @@ func mapAttributeValueMapToTypes(m map[string]*AttributeValue) map[string]*types
 	return out
 }
 
+func mapExpectedAttributeValues(m map[string]ExpectedAttributeValue) map[string]*types.ExpectedAttributeValue {
+	if m == nil {
+		return nil
+	}
+
+	out := make(map[string]*types.ExpectedAttributeValue, len(m))
+	for k, v := range m {
+		out[k] = &types.ExpectedAttributeValue{
+			AttributeValueList: mapAttributeValueListToTypes(v.AttributeValueList),
+			ComparisonOperator: toStringPtr(string(v.ComparisonOperator)),
+			Exists:             v.Exists,
+			Value:              mapAttributeValueToTypes(v.Value),
+		}
+	}
+
+	return out
+}
+
+func mapConditions(m map[string]Condition) map[string]*types.Condition {
+	if m == nil {
+		return nil
+	}
+
+	out := make(map[string]*types.Condition, len(m))
+	for k, v := range m {
+		out[k] = &types.Condition{
+			AttributeValueList: mapAttributeValueListToTypes(v.AttributeValueList),
+			ComparisonOperator: toStringPtr(string(v.ComparisonOperator)),
+		}
+	}
+
+	return out
+}
+
+func mapAttributeValueUpdates(m map[string]AttributeValueUpdate) map[string]*types.AttributeValueUpdate {
+	if m == nil {
+		return nil
+	}
+
+	out := make(map[string]*types.AttributeValueUpdate, len(m))
+	for k, v := range m {
+		out[k] = &types.AttributeValueUpdate{
+			Action: toStringPtr(string(v.Action)),
+			Value:  mapAttributeValueToTypes(v.Value),
+		}
+	}
+
+	return out
+}
+
 // types.Item -> AttributeValue (JSON)
 func mapTypesToAttributeValue(it *types.Item) *AttributeValue {
 	if it == nil {
@@ -303,6 +353,14 @@ func toStringPtr(s string) *string {
 	return aws.String(s)
 }
 
+func toInt64Ptr(i *int32) *int64 {
+	if i == nil {
+		return nil
+	}
+
+	return new(int64(*i))
+}
+
 func toStringPtrs(in []string) []*string {
 	if len(in) == 0 {
 		return nil
@@ -322,13 +380,49 @@ func mapTableDescriptionToDDB(td *types.TableDescription) *ddbtypes.TableDescrip
 		return nil
 	}
 
-	return &ddbtypes.TableDescription{
-		TableName:              aws.String(td.TableName),
-		ItemCount:              aws.Int64(td.ItemCount),
-		KeySchema:              mapTypesKeySchema(td.KeySchema),
-		GlobalSecondaryIndexes: mapTypesGSI(td.GlobalSecondaryIndexes),
-		LocalSecondaryIndexes:  mapTypesLSI(td.LocalSecondaryIndexes),
+	desc := &ddbtypes.TableDescription{
+		TableName:                 aws.String(td.TableName),
+		TableArn:                  toStringPtr(td.TableArn),
+		TableStatus:               ddbtypes.TableStatus(td.TableStatus),
+		ItemCount:                 aws.Int64(td.ItemCount),
+		KeySchema:                 mapTypesKeySchema(td.KeySchema),
+		GlobalSecondaryIndexes:    mapTypesGSI(td.GlobalSecondaryIndexes),
+		LocalSecondaryIndexes:     mapTypesLSI(td.LocalSecondaryIndexes),
+		DeletionProtectionEnabled: aws.Bool(td.DeletionProtectionEnabled),
 	}
+
+	if td.ProvisionedThroughput != nil {
+		desc.ProvisionedThroughput = &ddbtypes.ProvisionedThroughputDescription{
+			NumberOfDecreasesToday: aws.Int64(td.ProvisionedThroughput.NumberOfDecreasesToday),
+			ReadCapacityUnits:      aws.Int64(td.ProvisionedThroughput.ReadCapacityUnits),
+			WriteCapacityUnits:     aws.Int64(td.ProvisionedThroughput.WriteCapacityUnits),
+		}
+	}
+
+	if td.OnDemandThroughput != nil {
+		desc.OnDemandThroughput = &ddbtypes.OnDemandThroughput{
+			MaxReadRequestUnits:  aws.Int64(td.OnDemandThroughput.MaxReadRequestUnits),
+			MaxWriteRequestUnits: aws.Int64(td.OnDemandThroughput.MaxWriteRequestUnits),
+		}
+	}
+
+	if td.WarmThroughput != nil {
+		desc.WarmThroughput = &ddbtypes.TableWarmThroughputDescription{
+			ReadUnitsPerSecond:  aws.Int64(td.WarmThroughput.ReadUnitsPerSecond),
+			WriteUnitsPerSecond: aws.Int64(td.WarmThroughput.WriteUnitsPerSecond),
+			Status:              ddbtypes.TableStatus(td.WarmThroughput.Status),
+		}
+	}
+
+	if td.SSEDescription != nil {
+		desc.SSEDescription = &ddbtypes.SSEDescription{
+			KMSMasterKeyArn: toStringPtr(td.SSEDescription.KMSMasterKeyArn),
+			SSEType:         ddbtypes.SSEType(td.SSEDescription.SSEType),
+			Status:          ddbtypes.SSEStatus(td.SSEDescription.Status),
+		}
+	}
+
+	return desc
 }
 
 func mapTypesKeySchema(in []types.KeySchemaElement) []ddbtypes.KeySchemaElement {
@@ -359,6 +453,7 @@ func mapTypesGSI(in []types.GlobalSecondaryIndexDescription) []ddbtypes.GlobalSe
 		gCopy := g
 		out[i] = ddbtypes.GlobalSecondaryIndexDescription{
 			IndexName:   gCopy.IndexName,
+			IndexArn:    gCopy.IndexArn,
 			ItemCount:   aws.Int64(gCopy.ItemCount),
 			KeySchema:   mapTypesKeySchema(gCopy.KeySchema),
 			IndexStatus: ddbtypes.IndexStatus(aws.ToString(gCopy.IndexStatus)),
@@ -383,6 +478,7 @@ func mapTypesLSI(in []types.LocalSecondaryIndexDescription) []ddbtypes.LocalSeco
 		lCopy := l
 		out[i] = ddbtypes.LocalSecondaryIndexDescription{
 			IndexName: lCopy.IndexName,
+			IndexArn:  lCopy.IndexArn,
 			ItemCount: aws.Int64(lCopy.ItemCount),
 			KeySchema: mapTypesKeySchema(lCopy.KeySchema),
 			Projection: &ddbtypes.Projection{
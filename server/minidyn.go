@@ -2,10 +2,12 @@ package server
 
 import (
 	"errors"
+	"io/fs"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/truora/minidyn/core"
 )
 
 // FailureCondition describe the failure condition to emulate.
@@ -102,6 +104,161 @@ func (s *Server) SetIndexActivationDelay(delay time.Duration) {
 	s.client.setIndexActivationDelay(delay)
 }
 
+// SetTableStatusDelay configures how long tables report CREATING, UPDATING or DELETING
+// before the change completes. Data-plane calls fail with ResourceNotFoundException
+// while a table is CREATING, and UpdateTable and DeleteTable fail with
+// ResourceInUseException until the table is ACTIVE again.
+func (s *Server) SetTableStatusDelay(delay time.Duration) {
+	if s == nil || s.client == nil {
+		return
+	}
+
+	s.client.setTableStatusDelay(delay)
+}
+
+// SetClientRequestTokenTTL configures how long the ClientRequestToken of
+// TransactWriteItems and ExecuteTransaction keeps a request idempotent, 10 minutes
+// by default. The window follows the clock given to SetClock.
+func (s *Server) SetClientRequestTokenTTL(ttl time.Duration) {
+	if s == nil || s.client == nil {
+		return
+	}
+
+	s.client.setClientRequestTokenTTL(ttl)
+}
+
+// SetClock replaces the clock used to expire items with Time To Live and to time
+// the point in time recovery history, a core.ManualClock lets tests move time
+// forward without sleeping.
+func (s *Server) SetClock(clock core.Clock) {
+	if s == nil || s.client == nil {
+		return
+	}
+
+	s.client.setClock(clock)
+}
+
+// SetAccount sets the region and the account ID of the ARNs built by the server, which
+// default to us-east-1 and 000000000000. The ARNs of the existing tables change too.
+func (s *Server) SetAccount(region, accountID string) {
+	if s == nil || s.client == nil {
+		return
+	}
+
+	s.client.setAccount(region, accountID)
+}
+
+// Region returns the server of another region hosted by s, which starts with the clock,
+// the account and the delays of s. Requests reach a region when they are signed for it
+// or when they are sent to the handler returned by Region, like
+// httptest.NewServer(srv.Region("eu-west-1")), whatever region they are signed for.
+// The setters of the returned server only configure its region.
+func (s *Server) Region(region string) *Server {
+	if s == nil || s.client == nil {
+		return s
+	}
+
+	regions := s.client.regions
+
+	regions.mu.Lock()
+	defer regions.mu.Unlock()
+
+	return &Server{client: regions.host(region, s.client), pinned: true}
+}
+
+// SetReplicationLag configures how long the writes made to a replica of a global table
+// take to reach the replicas of the other regions, following the clock of each region.
+// Writes are replicated when the server receives a request, without lag by default.
+func (s *Server) SetReplicationLag(lag time.Duration) {
+	if s == nil || s.client == nil {
+		return
+	}
+
+	s.client.regions.setLag(lag)
+}
+
+// ReplicationMetadata returns the region and the time of the last write of an item of a
+// global table replica, which the 2017.11.29 global tables kept in the aws:rep:updateregion,
+// aws:rep:updatetime and aws:rep:deleting attributes. Deleted items keep their metadata so
+// older writes from other regions do not bring them back.
+func (s *Server) ReplicationMetadata(tableName string, key map[string]*AttributeValue) (core.ReplicaMetadata, bool) {
+	if s == nil || s.client == nil {
+		return core.ReplicaMetadata{}, false
+	}
+
+	s.client.regions.replicate()
+
+	return s.client.replicationMetadata(tableName, key)
+}
+
+// TopContributors ranks the keys of a Contributor Insights rule of a table, or of one of
+// its global secondary indexes when indexName is not empty, by the accesses they made in
+// the last window, which is counted in whole minutes following the clock given to
+// SetClock. Every item read or written is an access, and batch requests left unprocessed
+// by EmulateUnprocessedItems count as throttled. Every key is ranked when limit is not
+// positive. Contributor Insights must be enabled with UpdateContributorInsights.
+func (s *Server) TopContributors(tableName, indexName string, rule core.ContributorInsightsRule, window time.Duration, limit int) (ContributorRanking, error) {
+	if s == nil || s.client == nil {
+		return ContributorRanking{}, ErrServerNotInitialized
+	}
+
+	return s.client.topContributors(tableName, indexName, rule, window, limit)
+}
+
+// SetExportSink sets where ExportTableToPointInTime writes the files it would put in
+// S3, use a core.DirectoryExportSink to write them under a local directory. Without
+// a sink exports fail with the S3NoSuchBucket failure code.
+func (s *Server) SetExportSink(sink core.ExportSink) {
+	if s == nil || s.client == nil {
+		return
+	}
+
+	s.client.setExportSink(sink)
+}
+
+// SetImportSource sets where ImportTable reads the objects it would read from S3,
+// every bucket being a directory of source, like os.DirFS(dir). Without a source
+// imports fail with the S3NoSuchBucket failure code.
+func (s *Server) SetImportSource(source fs.FS) {
+	if s == nil || s.client == nil {
+		return
+	}
+
+	s.client.setImportSource(source)
+}
+
+// ImportErrors returns the items an import could not read or write, which DynamoDB
+// logs to CloudWatch.
+func (s *Server) ImportErrors(importArn string) []core.ImportError {
+	if s == nil || s.client == nil {
+		return nil
+	}
+
+	return s.client.importErrors(importArn)
+}
+
+// SweepExpiredOnAccess controls whether expired items are deleted as soon as their table is
+// used, write transactions leave them in place so a rollback never undoes a sweep. By
+// default expired items stay visible to reads until SweepExpiredItems deletes them, like
+// DynamoDB does for up to 48 hours.
+func (s *Server) SweepExpiredOnAccess(sweep bool) {
+	if s == nil || s.client == nil {
+		return
+	}
+
+	s.client.setSweepExpiredOnAccess(sweep)
+}
+
+// SweepExpiredItems deletes the expired items of every table with Time To Live
+// enabled and returns how many were deleted.
+func (s *Server) SweepExpiredItems() int {
+	if s == nil || s.client == nil {
+		return 0
+	}
+
+	return s.client.sweepExpiredItems()
+}
+
 // ClearTable removes all data from a table and its indexes using the in-memory client.
 func (s *Server) ClearTable(tableName string) error {
 	if s == nil || s.client == nil {
@@ -122,13 +279,20 @@ func (s *Server) ClearAllTables() error {
 	return nil
 }
 
-// Reset removes all tables and indexes from the in-memory client.
+// Reset removes all tables and indexes from the in-memory client, and from the other
+// regions it hosts unless s is the server of a single region returned by Region.
 func (s *Server) Reset() error {
 	if s == nil || s.client == nil {
 		return ErrServerNotInitialized
 	}
 
-	s.client.Reset()
+	if s.pinned {
+		s.client.Reset()
+
+		return nil
+	}
+
+	s.client.regions.reset()
 
 	return nil
 }
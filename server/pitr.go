@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+// getBackupTable resolves the table of a continuous backups request, callers must hold c.mu
+func (c *Client) getBackupTable(tableName string) (*core.Table, error) {
+	table, ok := c.tables[tableName]
+	if !ok {
+		return nil, &ddbtypes.TableNotFoundException{Message: aws.String("Table not found: " + tableName)}
+	}
+
+	c.sweepOnAccess(table)
+
+	return table, nil
+}
+
+func (c *Client) continuousBackupsDescription(table *core.Table) ContinuousBackupsDescription {
+	pitr := table.PointInTimeRecovery(c.clock.Now())
+
+	desc := ContinuousBackupsDescription{
+		ContinuousBackupsStatus:        string(ddbtypes.ContinuousBackupsStatusEnabled),
+		PointInTimeRecoveryDescription: PointInTimeRecoveryDescription{PointInTimeRecoveryStatus: string(ddbtypes.PointInTimeRecoveryStatusDisabled)},
+	}
+
+	if pitr.Enabled {
+		desc.PointInTimeRecoveryDescription = PointInTimeRecoveryDescription{
+			PointInTimeRecoveryStatus:  string(ddbtypes.PointInTimeRecoveryStatusEnabled),
+			RecoveryPeriodInDays:       aws.Int32(int32(pitr.RecoveryPeriodInDays)), //nolint:gosec // at most 35 days
+			EarliestRestorableDateTime: aws.Float64(epochSeconds(pitr.EarliestRestorableDateTime)),
+			LatestRestorableDateTime:   aws.Float64(epochSeconds(pitr.LatestRestorableDateTime)),
+		}
+	}
+
+	return desc
+}
+
+// UpdateContinuousBackups enables or disables the point in time recovery of a table.
+func (c *Client) UpdateContinuousBackups(ctx context.Context, input *UpdateContinuousBackupsInput) (*UpdateContinuousBackupsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableName := aws.ToString(input.TableName)
+
+	if err := c.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	spec := input.PointInTimeRecoverySpecification
+	if spec == nil || spec.PointInTimeRecoveryEnabled == nil {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "1 validation error detected: Value null at 'pointInTimeRecoverySpecification.pointInTimeRecoveryEnabled' failed to satisfy constraint: Member must not be null"}
+	}
+
+	table, err := c.getBackupTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !aws.ToBool(spec.PointInTimeRecoveryEnabled) {
+		table.DisablePointInTimeRecovery()
+	} else {
+		days := core.DefaultRecoveryPeriodInDays
+		if spec.RecoveryPeriodInDays != nil {
+			days = int(*spec.RecoveryPeriodInDays)
+		}
+
+		if err := table.EnablePointInTimeRecovery(days, c.clock.Now()); err != nil {
+			return nil, mapKnownError(err)
+		}
+	}
+
+	return &UpdateContinuousBackupsOutput{ContinuousBackupsDescription: c.continuousBackupsDescription(table)}, nil
+}
+
+// DescribeContinuousBackups returns the point in time recovery settings of a table.
+func (c *Client) DescribeContinuousBackups(ctx context.Context, input *DescribeContinuousBackupsInput) (*DescribeContinuousBackupsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableName := aws.ToString(input.TableName)
+
+	if err := c.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	table, err := c.getBackupTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DescribeContinuousBackupsOutput{ContinuousBackupsDescription: c.continuousBackupsDescription(table)}, nil
+}
+
+// RestoreTableToPointInTime creates a new table with the items a table had at a point in time.
+func (c *Client) RestoreTableToPointInTime(ctx context.Context, input *RestoreTableToPointInTimeInput) (*RestoreTableToPointInTimeOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	targetName := aws.ToString(input.TargetTableName)
+
+	if err := c.failureErrFor(targetName, ""); err != nil {
+		return nil, err
+	}
+
+	sourceName := aws.ToString(input.SourceTableName)
+	if arn := aws.ToString(input.SourceTableArn); arn != "" {
+		_, sourceName, _ = strings.Cut(arn, ":table/")
+	}
+
+	if sourceName == "" {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "Either SourceTableName or SourceTableArn must be specified"}
+	}
+
+	source, err := c.getBackupTable(sourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.clock.Now()
+	restoreTime := now
+
+	if !aws.ToBool(input.UseLatestRestorableTime) {
+		if input.RestoreDateTime == nil {
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "RestoreDateTime must be specified when UseLatestRestorableTime is not true"}
+		}
+
+		restoreTime = input.RestoreDateTime.Time()
+	}
+
+	backup, err := source.BackupAt(restoreTime, now)
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	table, err := c.restoreTable(targetName, backup, restoreOverrides(input.BillingModeOverride, input.GlobalSecondaryIndexOverride, input.LocalSecondaryIndexOverride), &core.RestoreSummary{
+		SourceTableArn:  c.tableArn(sourceName),
+		RestoreDateTime: restoreTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RestoreTableToPointInTimeOutput{TableDescription: c.tableDescription(targetName, table)}, nil
+}
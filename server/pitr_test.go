@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func TestServerPointInTimeRecovery(t *testing.T) {
+	c := require.New(t)
+
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	srv.SetClock(clock)
+
+	createBackupTestTable(t, ddb)
+
+	described, err := ddb.DescribeContinuousBackups(ctx, &dynamodb.DescribeContinuousBackupsInput{TableName: aws.String("pokemons")})
+	c.NoError(err)
+	c.Equal(ddbtypes.ContinuousBackupsStatusEnabled, described.ContinuousBackupsDescription.ContinuousBackupsStatus)
+	c.Equal(ddbtypes.PointInTimeRecoveryStatusDisabled, described.ContinuousBackupsDescription.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus)
+
+	_, err = ddb.RestoreTableToPointInTime(ctx, &dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName:         aws.String("pokemons"),
+		TargetTableName:         aws.String("pokemons-restored"),
+		UseLatestRestorableTime: aws.Bool(true),
+	})
+
+	var unavailableErr *ddbtypes.PointInTimeRecoveryUnavailableException
+	c.True(errors.As(err, &unavailableErr))
+
+	updated, err := ddb.UpdateContinuousBackups(ctx, &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String("pokemons"),
+		PointInTimeRecoverySpecification: &ddbtypes.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(true), RecoveryPeriodInDays: aws.Int32(7)},
+	})
+	c.NoError(err)
+
+	pitr := updated.ContinuousBackupsDescription.PointInTimeRecoveryDescription
+	c.Equal(ddbtypes.PointInTimeRecoveryStatusEnabled, pitr.PointInTimeRecoveryStatus)
+	c.EqualValues(7, aws.ToInt32(pitr.RecoveryPeriodInDays))
+	c.Equal(clock.Now(), aws.ToTime(pitr.EarliestRestorableDateTime).UTC())
+
+	clock.Advance(time.Minute)
+	healthy := clock.Now()
+	clock.Advance(time.Minute)
+
+	// corrupt the table after the healthy point
+	_, err = ddb.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String("pokemons"),
+		Key:       map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "25"}},
+	})
+	c.NoError(err)
+
+	_, err = ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String("pokemons"),
+		Item: map[string]ddbtypes.AttributeValue{
+			"id":   &ddbtypes.AttributeValueMemberS{Value: "4"},
+			"type": &ddbtypes.AttributeValueMemberS{Value: "corrupted"},
+		},
+	})
+	c.NoError(err)
+
+	clock.Advance(time.Minute)
+
+	restored, err := ddb.RestoreTableToPointInTime(ctx, &dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableArn:  aws.String("arn:aws:dynamodb:us-east-1:000000000000:table/pokemons"),
+		TargetTableName: aws.String("pokemons-healthy"),
+		RestoreDateTime: aws.Time(healthy),
+	})
+	c.NoError(err)
+	c.EqualValues(3, aws.ToInt64(restored.TableDescription.ItemCount))
+	c.Len(restored.TableDescription.GlobalSecondaryIndexes, 1)
+	c.Equal("arn:aws:dynamodb:us-east-1:000000000000:table/pokemons", aws.ToString(restored.TableDescription.RestoreSummary.SourceTableArn))
+	c.Equal(healthy, aws.ToTime(restored.TableDescription.RestoreSummary.RestoreDateTime).UTC())
+
+	item, err := ddb.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String("pokemons-healthy"),
+		Key:       map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "4"}},
+	})
+	c.NoError(err)
+	c.Equal(&ddbtypes.AttributeValueMemberS{Value: "fire"}, item.Item["type"])
+
+	latest, err := ddb.RestoreTableToPointInTime(ctx, &dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName:         aws.String("pokemons"),
+		TargetTableName:         aws.String("pokemons-latest"),
+		UseLatestRestorableTime: aws.Bool(true),
+	})
+	c.NoError(err)
+	c.EqualValues(2, aws.ToInt64(latest.TableDescription.ItemCount))
+
+	_, err = ddb.RestoreTableToPointInTime(ctx, &dynamodb.RestoreTableToPointInTimeInput{
+		SourceTableName: aws.String("pokemons"),
+		TargetTableName: aws.String("pokemons-too-old"),
+		RestoreDateTime: aws.Time(healthy.Add(-time.Hour)),
+	})
+
+	var restoreTimeErr *ddbtypes.InvalidRestoreTimeException
+	c.True(errors.As(err, &restoreTimeErr))
+
+	_, err = ddb.UpdateContinuousBackups(ctx, &dynamodb.UpdateContinuousBackupsInput{
+		TableName:                        aws.String("pokemons"),
+		PointInTimeRecoverySpecification: &ddbtypes.PointInTimeRecoverySpecification{PointInTimeRecoveryEnabled: aws.Bool(false)},
+	})
+	c.NoError(err)
+
+	_, err = ddb.DescribeContinuousBackups(ctx, &dynamodb.DescribeContinuousBackupsInput{TableName: aws.String("missing")})
+
+	var tableErr *ddbtypes.TableNotFoundException
+	c.True(errors.As(err, &tableErr))
+}
@@ -12,11 +12,23 @@ type AttributeValueUpdate struct {
 	Value  *AttributeValue          `json:"Value,omitempty"`
 }
 
+type BatchExecuteStatementInput struct {
+	Statements             []BatchStatementRequest         `json:"Statements,omitempty"`
+	ReturnConsumedCapacity ddbtypes.ReturnConsumedCapacity `json:"ReturnConsumedCapacity,omitempty"`
+}
+
 type BatchGetItemInput struct {
 	RequestItems           map[string]KeysAndAttributes    `json:"RequestItems,omitempty"`
 	ReturnConsumedCapacity ddbtypes.ReturnConsumedCapacity `json:"ReturnConsumedCapacity,omitempty"`
 }
 
+type BatchStatementRequest struct {
+	Statement                           *string                                      `json:"Statement,omitempty"`
+	ConsistentRead                      *bool                                        `json:"ConsistentRead,omitempty"`
+	Parameters                          []*AttributeValue                            `json:"Parameters,omitempty"`
+	ReturnValuesOnConditionCheckFailure ddbtypes.ReturnValuesOnConditionCheckFailure `json:"ReturnValuesOnConditionCheckFailure,omitempty"`
+}
+
 type BatchWriteItemInput struct {
 	RequestItems                map[string][]WriteRequest            `json:"RequestItems,omitempty"`
 	ReturnConsumedCapacity      ddbtypes.ReturnConsumedCapacity      `json:"ReturnConsumedCapacity,omitempty"`
@@ -37,6 +49,11 @@ type ConditionCheck struct {
 	ReturnValuesOnConditionCheckFailure ddbtypes.ReturnValuesOnConditionCheckFailure `json:"ReturnValuesOnConditionCheckFailure,omitempty"`
 }
 
+type CreateBackupInput struct {
+	BackupName *string `json:"BackupName,omitempty"`
+	TableName  *string `json:"TableName,omitempty"`
+}
+
 type CreateTableInput struct {
 	AttributeDefinitions      []ddbtypes.AttributeDefinition  `json:"AttributeDefinitions,omitempty"`
 	KeySchema                 []ddbtypes.KeySchemaElement     `json:"KeySchema,omitempty"`
@@ -64,6 +81,10 @@ type Delete struct {
 	ReturnValuesOnConditionCheckFailure ddbtypes.ReturnValuesOnConditionCheckFailure `json:"ReturnValuesOnConditionCheckFailure,omitempty"`
 }
 
+type DeleteBackupInput struct {
+	BackupArn *string `json:"BackupArn,omitempty"`
+}
+
 type DeleteItemInput struct {
 	Key                                 map[string]*AttributeValue                   `json:"Key,omitempty"`
 	TableName                           *string                                      `json:"TableName,omitempty"`
@@ -86,10 +107,51 @@ type DeleteTableInput struct {
 	TableName *string `json:"TableName,omitempty"`
 }
 
+type DescribeBackupInput struct {
+	BackupArn *string `json:"BackupArn,omitempty"`
+}
+
+type DescribeContinuousBackupsInput struct {
+	TableName *string `json:"TableName,omitempty"`
+}
+
+type DescribeContributorInsightsInput struct {
+	TableName *string `json:"TableName,omitempty"`
+	IndexName *string `json:"IndexName,omitempty"`
+}
+
+type DescribeExportInput struct {
+	ExportArn *string `json:"ExportArn,omitempty"`
+}
+
+type DescribeImportInput struct {
+	ImportArn *string `json:"ImportArn,omitempty"`
+}
+
 type DescribeTableInput struct {
 	TableName *string `json:"TableName,omitempty"`
 }
 
+type DescribeTimeToLiveInput struct {
+	TableName *string `json:"TableName,omitempty"`
+}
+
+type ExecuteStatementInput struct {
+	Statement                           *string                                      `json:"Statement,omitempty"`
+	ConsistentRead                      *bool                                        `json:"ConsistentRead,omitempty"`
+	Limit                               *int32                                       `json:"Limit,omitempty"`
+	NextToken                           *string                                      `json:"NextToken,omitempty"`
+	Parameters                          []*AttributeValue                            `json:"Parameters,omitempty"`
+	ReturnConsumedCapacity              ddbtypes.ReturnConsumedCapacity              `json:"ReturnConsumedCapacity,omitempty"`
+	ReturnValuesOnConditionCheckFailure ddbtypes.ReturnValuesOnConditionCheckFailure `json:"ReturnValuesOnConditionCheckFailure,omitempty"`
+}
+
+type ExecuteTransactionInput struct {
+	TransactStatements     []ParameterizedStatement        `json:"TransactStatements,omitempty"`
+	ClientRequestToken     *string                         `json:"ClientRequestToken,omitempty"`
+	ReturnConsumedCapacity ddbtypes.ReturnConsumedCapacity `json:"ReturnConsumedCapacity,omitempty"`
+}
+
 type ExpectedAttributeValue struct {
 	AttributeValueList []*AttributeValue           `json:"AttributeValueList,omitempty"`
 	ComparisonOperator ddbtypes.ComparisonOperator `json:"ComparisonOperator,omitempty"`
@@ -97,6 +159,20 @@ type ExpectedAttributeValue struct {
 	Value              *AttributeValue             `json:"Value,omitempty"`
 }
 
+type ExportTableToPointInTimeInput struct {
+	S3Bucket                       *string                         `json:"S3Bucket,omitempty"`
+	TableArn                       *string                         `json:"TableArn,omitempty"`
+	ClientToken                    *string                         `json:"ClientToken,omitempty"`
+	ExportFormat                   ddbtypes.ExportFormat           `json:"ExportFormat,omitempty"`
+	ExportTime                     *EpochTime                      `json:"ExportTime,omitempty"`
+	ExportType                     ddbtypes.ExportType             `json:"ExportType,omitempty"`
+	IncrementalExportSpecification *IncrementalExportSpecification `json:"IncrementalExportSpecification,omitempty"`
+	S3BucketOwner                  *string                         `json:"S3BucketOwner,omitempty"`
+	S3Prefix                       *string                         `json:"S3Prefix,omitempty"`
+	S3SseAlgorithm                 ddbtypes.S3SseAlgorithm         `json:"S3SseAlgorithm,omitempty"`
+	S3SseKmsKeyId                  *string                         `json:"S3SseKmsKeyId,omitempty"`
+}
+
 type Get struct {
 	Key                      map[string]*AttributeValue `json:"Key,omitempty"`
 	TableName                *string                    `json:"TableName,omitempty"`
@@ -114,6 +190,21 @@ type GetItemInput struct {
 	ReturnConsumedCapacity   ddbtypes.ReturnConsumedCapacity `json:"ReturnConsumedCapacity,omitempty"`
 }
 
+type ImportTableInput struct {
+	InputFormat             ddbtypes.InputFormat              `json:"InputFormat,omitempty"`
+	S3BucketSource          *ddbtypes.S3BucketSource          `json:"S3BucketSource,omitempty"`
+	TableCreationParameters *ddbtypes.TableCreationParameters `json:"TableCreationParameters,omitempty"`
+	ClientToken             *string                           `json:"ClientToken,omitempty"`
+	InputCompressionType    ddbtypes.InputCompressionType     `json:"InputCompressionType,omitempty"`
+	InputFormatOptions      *ddbtypes.InputFormatOptions      `json:"InputFormatOptions,omitempty"`
+}
+
+type IncrementalExportSpecification struct {
+	ExportFromTime *EpochTime              `json:"ExportFromTime,omitempty"`
+	ExportToTime   *EpochTime              `json:"ExportToTime,omitempty"`
+	ExportViewType ddbtypes.ExportViewType `json:"ExportViewType,omitempty"`
+}
+
 type KeysAndAttributes struct {
 	Keys                     []map[string]*AttributeValue `json:"Keys,omitempty"`
 	AttributesToGet          []string                     `json:"AttributesToGet,omitempty"`
@@ -122,6 +213,49 @@ type KeysAndAttributes struct {
 	ProjectionExpression     *string                      `json:"ProjectionExpression,omitempty"`
 }
 
+type ListBackupsInput struct {
+	BackupType              ddbtypes.BackupTypeFilter `json:"BackupType,omitempty"`
+	ExclusiveStartBackupArn *string                   `json:"ExclusiveStartBackupArn,omitempty"`
+	Limit                   *int32                    `json:"Limit,omitempty"`
+	TableName               *string                   `json:"TableName,omitempty"`
+	TimeRangeLowerBound     *EpochTime                `json:"TimeRangeLowerBound,omitempty"`
+	TimeRangeUpperBound     *EpochTime                `json:"TimeRangeUpperBound,omitempty"`
+}
+
+type ListContributorInsightsInput struct {
+	MaxResults int32   `json:"MaxResults,omitempty"`
+	NextToken  *string `json:"NextToken,omitempty"`
+	TableName  *string `json:"TableName,omitempty"`
+}
+
+type ListExportsInput struct {
+	MaxResults *int32  `json:"MaxResults,omitempty"`
+	NextToken  *string `json:"NextToken,omitempty"`
+	TableArn   *string `json:"TableArn,omitempty"`
+}
+
+type ListImportsInput struct {
+	NextToken *string `json:"NextToken,omitempty"`
+	PageSize  *int32  `json:"PageSize,omitempty"`
+	TableArn  *string `json:"TableArn,omitempty"`
+}
+
+type ListTablesInput struct {
+	ExclusiveStartTableName *string `json:"ExclusiveStartTableName,omitempty"`
+	Limit                   *int32  `json:"Limit,omitempty"`
+}
+
+type ListTagsOfResourceInput struct {
+	ResourceArn *string `json:"ResourceArn,omitempty"`
+	NextToken   *string `json:"NextToken,omitempty"`
+}
+
+type ParameterizedStatement struct {
+	Statement                           *string                                      `json:"Statement,omitempty"`
+	Parameters                          []*AttributeValue                            `json:"Parameters,omitempty"`
+	ReturnValuesOnConditionCheckFailure ddbtypes.ReturnValuesOnConditionCheckFailure `json:"ReturnValuesOnConditionCheckFailure,omitempty"`
+}
+
 type Put struct {
 	Item                                map[string]*AttributeValue                   `json:"Item,omitempty"`
 	TableName                           *string                                      `json:"TableName,omitempty"`
@@ -169,6 +303,31 @@ type QueryInput struct {
 	Select                    ddbtypes.Select                 `json:"Select,omitempty"`
 }
 
+type RestoreTableFromBackupInput struct {
+	BackupArn                     *string                         `json:"BackupArn,omitempty"`
+	TargetTableName               *string                         `json:"TargetTableName,omitempty"`
+	BillingModeOverride           ddbtypes.BillingMode            `json:"BillingModeOverride,omitempty"`
+	GlobalSecondaryIndexOverride  []ddbtypes.GlobalSecondaryIndex `json:"GlobalSecondaryIndexOverride,omitempty"`
+	LocalSecondaryIndexOverride   []ddbtypes.LocalSecondaryIndex  `json:"LocalSecondaryIndexOverride,omitempty"`
+	OnDemandThroughputOverride    *ddbtypes.OnDemandThroughput    `json:"OnDemandThroughputOverride,omitempty"`
+	ProvisionedThroughputOverride *ddbtypes.ProvisionedThroughput `json:"ProvisionedThroughputOverride,omitempty"`
+	SSESpecificationOverride      *ddbtypes.SSESpecification      `json:"SSESpecificationOverride,omitempty"`
+}
+
+type RestoreTableToPointInTimeInput struct {
+	TargetTableName               *string                         `json:"TargetTableName,omitempty"`
+	BillingModeOverride           ddbtypes.BillingMode            `json:"BillingModeOverride,omitempty"`
+	GlobalSecondaryIndexOverride  []ddbtypes.GlobalSecondaryIndex `json:"GlobalSecondaryIndexOverride,omitempty"`
+	LocalSecondaryIndexOverride   []ddbtypes.LocalSecondaryIndex  `json:"LocalSecondaryIndexOverride,omitempty"`
+	OnDemandThroughputOverride    *ddbtypes.OnDemandThroughput    `json:"OnDemandThroughputOverride,omitempty"`
+	ProvisionedThroughputOverride *ddbtypes.ProvisionedThroughput `json:"ProvisionedThroughputOverride,omitempty"`
+	RestoreDateTime               *EpochTime                      `json:"RestoreDateTime,omitempty"`
+	SSESpecificationOverride      *ddbtypes.SSESpecification      `json:"SSESpecificationOverride,omitempty"`
+	SourceTableArn                *string                         `json:"SourceTableArn,omitempty"`
+	SourceTableName               *string                         `json:"SourceTableName,omitempty"`
+	UseLatestRestorableTime       *bool                           `json:"UseLatestRestorableTime,omitempty"`
+}
+
 type ScanInput struct {
 	TableName                 *string                         `json:"TableName,omitempty"`
 	AttributesToGet           []string                        `json:"AttributesToGet,omitempty"`
@@ -188,6 +347,11 @@ type ScanInput struct {
 	TotalSegments             *int32                          `json:"TotalSegments,omitempty"`
 }
 
+type TagResourceInput struct {
+	ResourceArn *string        `json:"ResourceArn,omitempty"`
+	Tags        []ddbtypes.Tag `json:"Tags,omitempty"`
+}
+
 type TransactGetItem struct {
 	Get *Get `json:"Get,omitempty"`
 }
@@ -211,6 +375,11 @@ type TransactWriteItemsInput struct {
 	ReturnItemCollectionMetrics ddbtypes.ReturnItemCollectionMetrics `json:"ReturnItemCollectionMetrics,omitempty"`
 }
 
+type UntagResourceInput struct {
+	ResourceArn *string  `json:"ResourceArn,omitempty"`
+	TagKeys     []string `json:"TagKeys,omitempty"`
+}
+
 type Update struct {
 	Key                                 map[string]*AttributeValue                   `json:"Key,omitempty"`
 	TableName                           *string                                      `json:"TableName,omitempty"`
@@ -221,6 +390,17 @@ type Update struct {
 	ReturnValuesOnConditionCheckFailure ddbtypes.ReturnValuesOnConditionCheckFailure `json:"ReturnValuesOnConditionCheckFailure,omitempty"`
 }
 
+type UpdateContinuousBackupsInput struct {
+	PointInTimeRecoverySpecification *ddbtypes.PointInTimeRecoverySpecification `json:"PointInTimeRecoverySpecification,omitempty"`
+	TableName                        *string                                    `json:"TableName,omitempty"`
+}
+
+type UpdateContributorInsightsInput struct {
+	ContributorInsightsAction ddbtypes.ContributorInsightsAction `json:"ContributorInsightsAction,omitempty"`
+	TableName                 *string                            `json:"TableName,omitempty"`
+	IndexName                 *string                            `json:"IndexName,omitempty"`
+}
+
 type UpdateItemInput struct {
 	Key                                 map[string]*AttributeValue                   `json:"Key,omitempty"`
 	TableName                           *string                                      `json:"TableName,omitempty"`
@@ -253,6 +433,11 @@ type UpdateTableInput struct {
 	WarmThroughput              *ddbtypes.WarmThroughput              `json:"WarmThroughput,omitempty"`
 }
 
+type UpdateTimeToLiveInput struct {
+	TableName               *string                           `json:"TableName,omitempty"`
+	TimeToLiveSpecification *ddbtypes.TimeToLiveSpecification `json:"TimeToLiveSpecification,omitempty"`
+}
+
 type WriteRequest struct {
 	DeleteRequest *DeleteRequest `json:"DeleteRequest,omitempty"`
 	PutRequest    *PutRequest    `json:"PutRequest,omitempty"`
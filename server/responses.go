@@ -1,5 +1,7 @@
 package server
 
+import ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
 // Minimal output shapes encoded back to the client.
 // They mirror DynamoDB JSON responses closely enough for SDK decoding.
 
@@ -23,6 +25,12 @@ type DescribeTableOutput struct {
 	Table any `json:"Table,omitempty"`
 }
 
+// ListTablesOutput mirrors DynamoDB ListTablesOutput.
+type ListTablesOutput struct {
+	TableNames             []string `json:"TableNames"`
+	LastEvaluatedTableName *string  `json:"LastEvaluatedTableName,omitempty"`
+}
+
 // PutItemOutput mirrors DynamoDB PutItemOutput.
 type PutItemOutput struct {
 	Attributes map[string]*AttributeValue `json:"Attributes,omitempty"`
@@ -82,3 +90,397 @@ type ItemResponse struct {
 type TransactGetItemsOutput struct {
 	Responses []ItemResponse `json:"Responses,omitempty"`
 }
+
+// ExecuteStatementOutput mirrors DynamoDB ExecuteStatementOutput.
+type ExecuteStatementOutput struct {
+	Items            []map[string]*AttributeValue `json:"Items"`
+	LastEvaluatedKey map[string]*AttributeValue   `json:"LastEvaluatedKey,omitempty"`
+	NextToken        *string                      `json:"NextToken,omitempty"`
+}
+
+// BatchStatementError mirrors DynamoDB BatchStatementError.
+type BatchStatementError struct {
+	Code    string                     `json:"Code"`
+	Message string                     `json:"Message,omitempty"`
+	Item    map[string]*AttributeValue `json:"Item,omitempty"`
+}
+
+// BatchStatementResponse mirrors DynamoDB BatchStatementResponse.
+type BatchStatementResponse struct {
+	TableName *string                    `json:"TableName,omitempty"`
+	Item      map[string]*AttributeValue `json:"Item,omitempty"`
+	Error     *BatchStatementError       `json:"Error,omitempty"`
+}
+
+// BatchExecuteStatementOutput mirrors DynamoDB BatchExecuteStatementOutput.
+type BatchExecuteStatementOutput struct {
+	Responses []BatchStatementResponse `json:"Responses"`
+}
+
+// ExecuteTransactionOutput mirrors DynamoDB ExecuteTransactionOutput.
+type ExecuteTransactionOutput struct {
+	Responses []ItemResponse `json:"Responses,omitempty"`
+}
+
+// UpdateTimeToLiveOutput mirrors DynamoDB UpdateTimeToLiveOutput.
+type UpdateTimeToLiveOutput struct {
+	TimeToLiveSpecification *ddbtypes.TimeToLiveSpecification `json:"TimeToLiveSpecification,omitempty"`
+}
+
+// DescribeTimeToLiveOutput mirrors DynamoDB DescribeTimeToLiveOutput.
+type DescribeTimeToLiveOutput struct {
+	TimeToLiveDescription *ddbtypes.TimeToLiveDescription `json:"TimeToLiveDescription,omitempty"`
+}
+
+// StreamSummary mirrors DynamoDB Streams Stream.
+type StreamSummary struct {
+	StreamArn   string `json:"StreamArn"`
+	StreamLabel string `json:"StreamLabel"`
+	TableName   string `json:"TableName"`
+}
+
+// ListStreamsOutput mirrors DynamoDB Streams ListStreamsOutput.
+type ListStreamsOutput struct {
+	Streams                []StreamSummary `json:"Streams"`
+	LastEvaluatedStreamArn *string         `json:"LastEvaluatedStreamArn,omitempty"`
+}
+
+// SequenceNumberRange mirrors DynamoDB Streams SequenceNumberRange.
+type SequenceNumberRange struct {
+	StartingSequenceNumber string  `json:"StartingSequenceNumber"`
+	EndingSequenceNumber   *string `json:"EndingSequenceNumber,omitempty"`
+}
+
+// Shard mirrors DynamoDB Streams Shard.
+type Shard struct {
+	ShardID             string              `json:"ShardId"`
+	SequenceNumberRange SequenceNumberRange `json:"SequenceNumberRange"`
+}
+
+// StreamDescription mirrors DynamoDB Streams StreamDescription.
+type StreamDescription struct {
+	CreationRequestDateTime float64                     `json:"CreationRequestDateTime"`
+	KeySchema               []ddbtypes.KeySchemaElement `json:"KeySchema"`
+	Shards                  []Shard                     `json:"Shards"`
+	StreamArn               string                      `json:"StreamArn"`
+	StreamLabel             string                      `json:"StreamLabel"`
+	StreamStatus            string                      `json:"StreamStatus"`
+	StreamViewType          string                      `json:"StreamViewType"`
+	TableName               string                      `json:"TableName"`
+}
+
+// DescribeStreamOutput mirrors DynamoDB Streams DescribeStreamOutput.
+type DescribeStreamOutput struct {
+	StreamDescription StreamDescription `json:"StreamDescription"`
+}
+
+// GetShardIteratorOutput mirrors DynamoDB Streams GetShardIteratorOutput.
+type GetShardIteratorOutput struct {
+	ShardIterator *string `json:"ShardIterator,omitempty"`
+}
+
+// StreamRecord mirrors DynamoDB Streams StreamRecord.
+type StreamRecord struct {
+	ApproximateCreationDateTime float64                    `json:"ApproximateCreationDateTime"`
+	Keys                        map[string]*AttributeValue `json:"Keys,omitempty"`
+	NewImage                    map[string]*AttributeValue `json:"NewImage,omitempty"`
+	OldImage                    map[string]*AttributeValue `json:"OldImage,omitempty"`
+	SequenceNumber              string                     `json:"SequenceNumber"`
+	SizeBytes                   int64                      `json:"SizeBytes"`
+	StreamViewType              string                     `json:"StreamViewType"`
+}
+
+// Identity mirrors DynamoDB Streams Identity.
+type Identity struct {
+	PrincipalID string `json:"PrincipalId"`
+	Type        string `json:"Type"`
+}
+
+// Record mirrors DynamoDB Streams Record, whose members use lower camel case on the wire.
+type Record struct {
+	AwsRegion    string       `json:"awsRegion"`
+	Dynamodb     StreamRecord `json:"dynamodb"`
+	EventID      string       `json:"eventID"`
+	EventName    string       `json:"eventName"`
+	EventSource  string       `json:"eventSource"`
+	EventVersion string       `json:"eventVersion"`
+	UserIdentity *Identity    `json:"userIdentity,omitempty"`
+}
+
+// GetRecordsOutput mirrors DynamoDB Streams GetRecordsOutput.
+type GetRecordsOutput struct {
+	Records           []Record `json:"Records"`
+	NextShardIterator *string  `json:"NextShardIterator,omitempty"`
+}
+
+// TableDescription mirrors DynamoDB TableDescription, encoding its timestamps as epoch
+// seconds the way the SDK decodes them.
+type TableDescription struct {
+	*ddbtypes.TableDescription
+	BillingModeSummary *BillingModeSummary `json:"BillingModeSummary,omitempty"`
+	TableClassSummary  *TableClassSummary  `json:"TableClassSummary,omitempty"`
+	RestoreSummary     *RestoreSummary     `json:"RestoreSummary,omitempty"`
+}
+
+// BillingModeSummary mirrors DynamoDB BillingModeSummary.
+type BillingModeSummary struct {
+	BillingMode                       string   `json:"BillingMode"`
+	LastUpdateToPayPerRequestDateTime *float64 `json:"LastUpdateToPayPerRequestDateTime,omitempty"`
+}
+
+// TableClassSummary mirrors DynamoDB TableClassSummary.
+type TableClassSummary struct {
+	TableClass         string   `json:"TableClass"`
+	LastUpdateDateTime *float64 `json:"LastUpdateDateTime,omitempty"`
+}
+
+// RestoreSummary mirrors DynamoDB RestoreSummary.
+type RestoreSummary struct {
+	SourceBackupArn   *string `json:"SourceBackupArn,omitempty"`
+	SourceTableArn    *string `json:"SourceTableArn,omitempty"`
+	RestoreDateTime   float64 `json:"RestoreDateTime"`
+	RestoreInProgress bool    `json:"RestoreInProgress"`
+}
+
+// BackupDetails mirrors DynamoDB BackupDetails.
+type BackupDetails struct {
+	BackupArn              string  `json:"BackupArn"`
+	BackupName             string  `json:"BackupName"`
+	BackupCreationDateTime float64 `json:"BackupCreationDateTime"`
+	BackupSizeBytes        int64   `json:"BackupSizeBytes"`
+	BackupStatus           string  `json:"BackupStatus"`
+	BackupType             string  `json:"BackupType"`
+}
+
+// SourceTableDetails mirrors DynamoDB SourceTableDetails.
+type SourceTableDetails struct {
+	TableName      string                      `json:"TableName"`
+	TableArn       string                      `json:"TableArn"`
+	KeySchema      []ddbtypes.KeySchemaElement `json:"KeySchema"`
+	ItemCount      int64                       `json:"ItemCount"`
+	TableSizeBytes int64                       `json:"TableSizeBytes"`
+	BillingMode    string                      `json:"BillingMode,omitempty"`
+}
+
+// SourceTableFeatureDetails mirrors DynamoDB SourceTableFeatureDetails.
+type SourceTableFeatureDetails struct {
+	GlobalSecondaryIndexes []ddbtypes.GlobalSecondaryIndexInfo `json:"GlobalSecondaryIndexes,omitempty"`
+	LocalSecondaryIndexes  []ddbtypes.LocalSecondaryIndexInfo  `json:"LocalSecondaryIndexes,omitempty"`
+}
+
+// BackupDescription mirrors DynamoDB BackupDescription.
+type BackupDescription struct {
+	BackupDetails             BackupDetails             `json:"BackupDetails"`
+	SourceTableDetails        SourceTableDetails        `json:"SourceTableDetails"`
+	SourceTableFeatureDetails SourceTableFeatureDetails `json:"SourceTableFeatureDetails"`
+}
+
+// CreateBackupOutput mirrors DynamoDB CreateBackupOutput.
+type CreateBackupOutput struct {
+	BackupDetails BackupDetails `json:"BackupDetails"`
+}
+
+// DescribeBackupOutput mirrors DynamoDB DescribeBackupOutput.
+type DescribeBackupOutput struct {
+	BackupDescription BackupDescription `json:"BackupDescription"`
+}
+
+// DeleteBackupOutput mirrors DynamoDB DeleteBackupOutput.
+type DeleteBackupOutput struct {
+	BackupDescription BackupDescription `json:"BackupDescription"`
+}
+
+// BackupSummary mirrors DynamoDB BackupSummary.
+type BackupSummary struct {
+	BackupArn              string  `json:"BackupArn"`
+	BackupName             string  `json:"BackupName"`
+	BackupCreationDateTime float64 `json:"BackupCreationDateTime"`
+	BackupSizeBytes        int64   `json:"BackupSizeBytes"`
+	BackupStatus           string  `json:"BackupStatus"`
+	BackupType             string  `json:"BackupType"`
+	TableArn               string  `json:"TableArn"`
+	TableName              string  `json:"TableName"`
+}
+
+// ListBackupsOutput mirrors DynamoDB ListBackupsOutput.
+type ListBackupsOutput struct {
+	BackupSummaries        []BackupSummary `json:"BackupSummaries"`
+	LastEvaluatedBackupArn *string         `json:"LastEvaluatedBackupArn,omitempty"`
+}
+
+// RestoreTableFromBackupOutput mirrors DynamoDB RestoreTableFromBackupOutput.
+type RestoreTableFromBackupOutput struct {
+	TableDescription any `json:"TableDescription,omitempty"`
+}
+
+// PointInTimeRecoveryDescription mirrors DynamoDB PointInTimeRecoveryDescription.
+type PointInTimeRecoveryDescription struct {
+	PointInTimeRecoveryStatus  string   `json:"PointInTimeRecoveryStatus"`
+	RecoveryPeriodInDays       *int32   `json:"RecoveryPeriodInDays,omitempty"`
+	EarliestRestorableDateTime *float64 `json:"EarliestRestorableDateTime,omitempty"`
+	LatestRestorableDateTime   *float64 `json:"LatestRestorableDateTime,omitempty"`
+}
+
+// ContinuousBackupsDescription mirrors DynamoDB ContinuousBackupsDescription.
+type ContinuousBackupsDescription struct {
+	ContinuousBackupsStatus        string                         `json:"ContinuousBackupsStatus"`
+	PointInTimeRecoveryDescription PointInTimeRecoveryDescription `json:"PointInTimeRecoveryDescription"`
+}
+
+// UpdateContinuousBackupsOutput mirrors DynamoDB UpdateContinuousBackupsOutput.
+type UpdateContinuousBackupsOutput struct {
+	ContinuousBackupsDescription ContinuousBackupsDescription `json:"ContinuousBackupsDescription"`
+}
+
+// DescribeContinuousBackupsOutput mirrors DynamoDB DescribeContinuousBackupsOutput.
+type DescribeContinuousBackupsOutput struct {
+	ContinuousBackupsDescription ContinuousBackupsDescription `json:"ContinuousBackupsDescription"`
+}
+
+// RestoreTableToPointInTimeOutput mirrors DynamoDB RestoreTableToPointInTimeOutput.
+type RestoreTableToPointInTimeOutput struct {
+	TableDescription any `json:"TableDescription,omitempty"`
+}
+
+// IncrementalExportSpecificationDescription mirrors DynamoDB IncrementalExportSpecification.
+type IncrementalExportSpecificationDescription struct {
+	ExportFromTime float64 `json:"ExportFromTime"`
+	ExportToTime   float64 `json:"ExportToTime"`
+	ExportViewType string  `json:"ExportViewType"`
+}
+
+// ExportDescription mirrors DynamoDB ExportDescription.
+type ExportDescription struct {
+	ExportArn                      string                                     `json:"ExportArn"`
+	ExportStatus                   string                                     `json:"ExportStatus"`
+	ExportType                     string                                     `json:"ExportType"`
+	ExportFormat                   string                                     `json:"ExportFormat"`
+	ExportManifest                 *string                                    `json:"ExportManifest,omitempty"`
+	ExportTime                     *float64                                   `json:"ExportTime,omitempty"`
+	IncrementalExportSpecification *IncrementalExportSpecificationDescription `json:"IncrementalExportSpecification,omitempty"`
+	StartTime                      float64                                    `json:"StartTime"`
+	EndTime                        float64                                    `json:"EndTime"`
+	TableArn                       string                                     `json:"TableArn"`
+	ClientToken                    *string                                    `json:"ClientToken,omitempty"`
+	S3Bucket                       string                                     `json:"S3Bucket"`
+	S3Prefix                       *string                                    `json:"S3Prefix,omitempty"`
+	S3SseAlgorithm                 string                                     `json:"S3SseAlgorithm"`
+	ItemCount                      int64                                      `json:"ItemCount"`
+	BilledSizeBytes                int64                                      `json:"BilledSizeBytes"`
+	FailureCode                    *string                                    `json:"FailureCode,omitempty"`
+	FailureMessage                 *string                                    `json:"FailureMessage,omitempty"`
+}
+
+// ExportTableToPointInTimeOutput mirrors DynamoDB ExportTableToPointInTimeOutput.
+type ExportTableToPointInTimeOutput struct {
+	ExportDescription ExportDescription `json:"ExportDescription"`
+}
+
+// DescribeExportOutput mirrors DynamoDB DescribeExportOutput.
+type DescribeExportOutput struct {
+	ExportDescription ExportDescription `json:"ExportDescription"`
+}
+
+// ExportSummary mirrors DynamoDB ExportSummary.
+type ExportSummary struct {
+	ExportArn    string `json:"ExportArn"`
+	ExportStatus string `json:"ExportStatus"`
+	ExportType   string `json:"ExportType"`
+}
+
+// ListExportsOutput mirrors DynamoDB ListExportsOutput.
+type ListExportsOutput struct {
+	ExportSummaries []ExportSummary `json:"ExportSummaries"`
+	NextToken       *string         `json:"NextToken,omitempty"`
+}
+
+// ImportTableDescription mirrors DynamoDB ImportTableDescription.
+type ImportTableDescription struct {
+	ImportArn               string                            `json:"ImportArn"`
+	ImportStatus            string                            `json:"ImportStatus"`
+	TableArn                string                            `json:"TableArn"`
+	ClientToken             *string                           `json:"ClientToken,omitempty"`
+	S3BucketSource          *ddbtypes.S3BucketSource          `json:"S3BucketSource,omitempty"`
+	CloudWatchLogGroupArn   string                            `json:"CloudWatchLogGroupArn"`
+	InputFormat             string                            `json:"InputFormat"`
+	InputFormatOptions      *ddbtypes.InputFormatOptions      `json:"InputFormatOptions,omitempty"`
+	InputCompressionType    string                            `json:"InputCompressionType"`
+	TableCreationParameters *ddbtypes.TableCreationParameters `json:"TableCreationParameters,omitempty"`
+	StartTime               float64                           `json:"StartTime"`
+	EndTime                 float64                           `json:"EndTime"`
+	ProcessedSizeBytes      int64                             `json:"ProcessedSizeBytes"`
+	ProcessedItemCount      int64                             `json:"ProcessedItemCount"`
+	ImportedItemCount       int64                             `json:"ImportedItemCount"`
+	ErrorCount              int64                             `json:"ErrorCount"`
+	FailureCode             *string                           `json:"FailureCode,omitempty"`
+	FailureMessage          *string                           `json:"FailureMessage,omitempty"`
+}
+
+// ImportTableOutput mirrors DynamoDB ImportTableOutput.
+type ImportTableOutput struct {
+	ImportTableDescription ImportTableDescription `json:"ImportTableDescription"`
+}
+
+// DescribeImportOutput mirrors DynamoDB DescribeImportOutput.
+type DescribeImportOutput struct {
+	ImportTableDescription ImportTableDescription `json:"ImportTableDescription"`
+}
+
+// ImportSummary mirrors DynamoDB ImportSummary.
+type ImportSummary struct {
+	ImportArn             string                   `json:"ImportArn"`
+	ImportStatus          string                   `json:"ImportStatus"`
+	TableArn              string                   `json:"TableArn"`
+	S3BucketSource        *ddbtypes.S3BucketSource `json:"S3BucketSource,omitempty"`
+	CloudWatchLogGroupArn string                   `json:"CloudWatchLogGroupArn"`
+	InputFormat           string                   `json:"InputFormat"`
+	StartTime             float64                  `json:"StartTime"`
+	EndTime               float64                  `json:"EndTime"`
+}
+
+// ListImportsOutput mirrors DynamoDB ListImportsOutput.
+type ListImportsOutput struct {
+	ImportSummaryList []ImportSummary `json:"ImportSummaryList"`
+	NextToken         *string         `json:"NextToken,omitempty"`
+}
+
+// TagResourceOutput mirrors DynamoDB TagResourceOutput.
+type TagResourceOutput struct{}
+
+// UntagResourceOutput mirrors DynamoDB UntagResourceOutput.
+type UntagResourceOutput struct{}
+
+// ListTagsOfResourceOutput mirrors DynamoDB ListTagsOfResourceOutput.
+type ListTagsOfResourceOutput struct {
+	Tags      []ddbtypes.Tag `json:"Tags"`
+	NextToken *string        `json:"NextToken,omitempty"`
+}
+
+// UpdateContributorInsightsOutput mirrors DynamoDB UpdateContributorInsightsOutput.
+type UpdateContributorInsightsOutput struct {
+	TableName                 string  `json:"TableName"`
+	IndexName                 *string `json:"IndexName,omitempty"`
+	ContributorInsightsStatus string  `json:"ContributorInsightsStatus"`
+}
+
+// DescribeContributorInsightsOutput mirrors DynamoDB DescribeContributorInsightsOutput.
+type DescribeContributorInsightsOutput struct {
+	TableName                   string   `json:"TableName"`
+	IndexName                   *string  `json:"IndexName,omitempty"`
+	ContributorInsightsRuleList []string `json:"ContributorInsightsRuleList,omitempty"`
+	ContributorInsightsStatus   string   `json:"ContributorInsightsStatus"`
+	LastUpdateDateTime          *float64 `json:"LastUpdateDateTime,omitempty"`
+}
+
+// ContributorInsightsSummary mirrors DynamoDB ContributorInsightsSummary.
+type ContributorInsightsSummary struct {
+	TableName                 string  `json:"TableName"`
+	IndexName                 *string `json:"IndexName,omitempty"`
+	ContributorInsightsStatus string  `json:"ContributorInsightsStatus"`
+}
+
+// ListContributorInsightsOutput mirrors DynamoDB ListContributorInsightsOutput.
+type ListContributorInsightsOutput struct {
+	ContributorInsightsSummaries []ContributorInsightsSummary `json:"ContributorInsightsSummaries"`
+	NextToken                    *string                      `json:"NextToken,omitempty"`
+}
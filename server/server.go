@@ -15,6 +15,8 @@ import (
 // Server implements http.Handler for DynamoDB JSON API subset.
 type Server struct {
 	client *Client
+	// pinned servers serve every request from the region of their client
+	pinned bool
 }
 
 // NewServer creates an HTTP handler exposing the DynamoDB-compatible API.
@@ -47,6 +49,10 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		op = parts[len(parts)-1]
 	}
 
+	s.client.regions.replicate()
+
+	client := s.regionClient(r)
+
 	decoder := json.NewDecoder(r.Body)
 	encoder := json.NewEncoder(w)
 	encoder.SetEscapeHTML(false)
@@ -60,72 +66,222 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case "CreateTable":
 		var input CreateTableInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.CreateTable(context.Background(), &input)
+			resp, err = client.CreateTable(context.Background(), &input)
 		}
 	case "UpdateTable":
 		var input UpdateTableInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.UpdateTable(context.Background(), &input)
+			resp, err = client.UpdateTable(context.Background(), &input)
 		}
 	case "DeleteTable":
 		var input DeleteTableInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.DeleteTable(context.Background(), &input)
+			resp, err = client.DeleteTable(context.Background(), &input)
 		}
 	case "DescribeTable":
 		var input DescribeTableInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.DescribeTable(context.Background(), &input)
+			resp, err = client.DescribeTable(context.Background(), &input)
+		}
+	case "ListTables":
+		var input ListTablesInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.ListTables(context.Background(), &input)
 		}
 	case "PutItem":
 		var input PutItemInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.PutItem(context.Background(), &input)
+			resp, err = client.PutItem(context.Background(), &input)
 		}
 	case "DeleteItem":
 		var input DeleteItemInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.DeleteItem(context.Background(), &input)
+			resp, err = client.DeleteItem(context.Background(), &input)
 		}
 	case "UpdateItem":
 		var input UpdateItemInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.UpdateItem(context.Background(), &input)
+			resp, err = client.UpdateItem(context.Background(), &input)
 		}
 	case "GetItem":
 		var input GetItemInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.GetItem(context.Background(), &input)
+			resp, err = client.GetItem(context.Background(), &input)
 		}
 	case "Query":
 		var input QueryInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.Query(context.Background(), &input)
+			resp, err = client.Query(context.Background(), &input)
 		}
 	case "Scan":
 		var input ScanInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.Scan(context.Background(), &input)
+			resp, err = client.Scan(context.Background(), &input)
 		}
 	case "BatchWriteItem":
 		var input BatchWriteItemInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.BatchWriteItem(context.Background(), &input)
+			resp, err = client.BatchWriteItem(context.Background(), &input)
 		}
 	case "BatchGetItem":
 		var input BatchGetItemInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.BatchGetItem(context.Background(), &input)
+			resp, err = client.BatchGetItem(context.Background(), &input)
 		}
 	case "TransactWriteItems":
 		var input TransactWriteItemsInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.TransactWriteItems(context.Background(), &input)
+			resp, err = client.TransactWriteItems(context.Background(), &input)
 		}
 	case "TransactGetItems":
 		var input TransactGetItemsInput
 		if err = decoder.Decode(&input); err == nil {
-			resp, err = s.client.TransactGetItems(context.Background(), &input)
+			resp, err = client.TransactGetItems(context.Background(), &input)
+		}
+	case "UpdateTimeToLive":
+		var input UpdateTimeToLiveInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.UpdateTimeToLive(context.Background(), &input)
+		}
+	case "DescribeTimeToLive":
+		var input DescribeTimeToLiveInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.DescribeTimeToLive(context.Background(), &input)
+		}
+	case "ExecuteStatement":
+		var input ExecuteStatementInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.ExecuteStatement(context.Background(), &input)
+		}
+	case "BatchExecuteStatement":
+		var input BatchExecuteStatementInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.BatchExecuteStatement(context.Background(), &input)
+		}
+	case "ExecuteTransaction":
+		var input ExecuteTransactionInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.ExecuteTransaction(context.Background(), &input)
+		}
+	case "CreateBackup":
+		var input CreateBackupInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.CreateBackup(context.Background(), &input)
+		}
+	case "DescribeBackup":
+		var input DescribeBackupInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.DescribeBackup(context.Background(), &input)
+		}
+	case "ListBackups":
+		var input ListBackupsInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.ListBackups(context.Background(), &input)
+		}
+	case "DeleteBackup":
+		var input DeleteBackupInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.DeleteBackup(context.Background(), &input)
+		}
+	case "RestoreTableFromBackup":
+		var input RestoreTableFromBackupInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.RestoreTableFromBackup(context.Background(), &input)
+		}
+	case "UpdateContinuousBackups":
+		var input UpdateContinuousBackupsInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.UpdateContinuousBackups(context.Background(), &input)
+		}
+	case "DescribeContinuousBackups":
+		var input DescribeContinuousBackupsInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.DescribeContinuousBackups(context.Background(), &input)
+		}
+	case "RestoreTableToPointInTime":
+		var input RestoreTableToPointInTimeInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.RestoreTableToPointInTime(context.Background(), &input)
+		}
+	case "ExportTableToPointInTime":
+		var input ExportTableToPointInTimeInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.ExportTableToPointInTime(context.Background(), &input)
+		}
+	case "DescribeExport":
+		var input DescribeExportInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.DescribeExport(context.Background(), &input)
+		}
+	case "ListExports":
+		var input ListExportsInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.ListExports(context.Background(), &input)
+		}
+	case "ImportTable":
+		var input ImportTableInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.ImportTable(context.Background(), &input)
+		}
+	case "DescribeImport":
+		var input DescribeImportInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.DescribeImport(context.Background(), &input)
+		}
+	case "ListImports":
+		var input ListImportsInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.ListImports(context.Background(), &input)
+		}
+	case "TagResource":
+		var input TagResourceInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.TagResource(context.Background(), &input)
+		}
+	case "UntagResource":
+		var input UntagResourceInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.UntagResource(context.Background(), &input)
+		}
+	case "ListTagsOfResource":
+		var input ListTagsOfResourceInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.ListTagsOfResource(context.Background(), &input)
+		}
+	case "UpdateContributorInsights":
+		var input UpdateContributorInsightsInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.UpdateContributorInsights(context.Background(), &input)
+		}
+	case "DescribeContributorInsights":
+		var input DescribeContributorInsightsInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.DescribeContributorInsights(context.Background(), &input)
+		}
+	case "ListContributorInsights":
+		var input ListContributorInsightsInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.ListContributorInsights(context.Background(), &input)
+		}
+	case "ListStreams":
+		var input ListStreamsInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.ListStreams(context.Background(), &input)
+		}
+	case "DescribeStream":
+		var input DescribeStreamInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.DescribeStream(context.Background(), &input)
+		}
+	case "GetShardIterator":
+		var input GetShardIteratorInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.GetShardIterator(context.Background(), &input)
+		}
+	case "GetRecords":
+		var input GetRecordsInput
+		if err = decoder.Decode(&input); err == nil {
+			resp, err = client.GetRecords(context.Background(), &input)
 		}
 	default:
 		http.Error(w, "unsupported operation", http.StatusBadRequest)
@@ -144,6 +300,20 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// regionClient returns the client of the region a request is signed for, the requests
+// signed for regions the server does not host are served by the region of the server
+func (s *Server) regionClient(r *http.Request) *Client {
+	if s.pinned {
+		return s.client
+	}
+
+	if client := s.client.regions.client(signingRegion(r)); client != nil {
+		return client
+	}
+
+	return s.client
+}
+
 func writeError(w http.ResponseWriter, err error) {
 	if tce, ok := errors.AsType[*ddbtypes.TransactionCanceledException](err); ok {
 		type cancellationReasonWire struct {
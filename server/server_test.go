@@ -20,6 +20,7 @@ import (
 	"github.com/aws/smithy-go"
 	"github.com/aws/smithy-go/logging"
 	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
 )
 
 func newTestDynamoClient(t *testing.T, url string) *dynamodb.Client {
@@ -1117,6 +1118,44 @@ func TestServerDeleteTableWithSDKv2(t *testing.T) {
 	require.ErrorAs(t, err, &notFound)
 }
 
+func TestServerListTablesWithSDKv2(t *testing.T) {
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+	cli := newTestDynamoClient(t, ts.URL)
+	ctx := context.Background()
+
+	out, err := cli.ListTables(ctx, &dynamodb.ListTablesInput{})
+	require.NoError(t, err)
+	require.Empty(t, out.TableNames)
+
+	for _, name := range []string{"trainers", "badges", "pokemons", "gyms", "moves"} {
+		makeBasicTable(t, cli, name, "id")
+	}
+
+	out, err = cli.ListTables(ctx, &dynamodb.ListTablesInput{Limit: aws.Int32(3)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"badges", "gyms", "moves"}, out.TableNames)
+	require.Equal(t, "moves", aws.ToString(out.LastEvaluatedTableName))
+
+	var names []string
+
+	paginator := dynamodb.NewListTablesPaginator(cli, &dynamodb.ListTablesInput{Limit: aws.Int32(2)})
+	for paginator.HasMorePages() {
+		page, perr := paginator.NextPage(ctx)
+		require.NoError(t, perr)
+
+		names = append(names, page.TableNames...)
+	}
+
+	require.Equal(t, []string{"badges", "gyms", "moves", "pokemons", "trainers"}, names)
+
+	_, err = cli.ListTables(ctx, &dynamodb.ListTablesInput{Limit: aws.Int32(101)})
+
+	var apiErr smithy.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, "ValidationException", apiErr.ErrorCode())
+}
+
 func TestServerEmulateFailureWithSDKv2(t *testing.T) {
 	s := NewServer()
 
@@ -1502,6 +1541,270 @@ func TestServerPutItemWithConditionsValidation(t *testing.T) {
 	require.Contains(t, putValErr.ErrorMessage(), unusedExpressionAttributeValuesMsg)
 }
 
+func TestServerWriteWithExpected(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+	cli := newTestDynamoClient(t, ts.URL)
+
+	makeBasicTable(t, cli, "pokemons", "id")
+
+	item := map[string]ddbtypes.AttributeValue{
+		"id":    &ddbtypes.AttributeValueMemberS{Value: "25"},
+		"level": &ddbtypes.AttributeValueMemberN{Value: "12"},
+	}
+
+	_, err := cli.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String("pokemons"),
+		Item:      item,
+		Expected:  map[string]ddbtypes.ExpectedAttributeValue{"id": {Exists: aws.Bool(false)}},
+	})
+	c.NoError(err)
+
+	_, err = cli.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String("pokemons"),
+		Item:      item,
+		Expected:  map[string]ddbtypes.ExpectedAttributeValue{"id": {Exists: aws.Bool(false)}},
+	})
+
+	var checkErr *ddbtypes.ConditionalCheckFailedException
+	c.ErrorAs(err, &checkErr)
+
+	_, err = cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String("pokemons"),
+		Key:       map[string]ddbtypes.AttributeValue{"id": item["id"]},
+		Expected: map[string]ddbtypes.ExpectedAttributeValue{
+			"level": {ComparisonOperator: ddbtypes.ComparisonOperatorBetween, AttributeValueList: []ddbtypes.AttributeValue{
+				&ddbtypes.AttributeValueMemberN{Value: "20"},
+				&ddbtypes.AttributeValueMemberN{Value: "30"},
+			}},
+		},
+	})
+	c.ErrorAs(err, &checkErr)
+
+	_, err = cli.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String("pokemons"),
+		Key:       map[string]ddbtypes.AttributeValue{"id": item["id"]},
+		Expected: map[string]ddbtypes.ExpectedAttributeValue{
+			"level": {ComparisonOperator: ddbtypes.ComparisonOperatorIn, AttributeValueList: []ddbtypes.AttributeValue{
+				&ddbtypes.AttributeValueMemberN{Value: "12"},
+				&ddbtypes.AttributeValueMemberN{Value: "16"},
+			}},
+		},
+	})
+	c.NoError(err)
+
+	_, err = cli.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String("pokemons"),
+		Key:                 map[string]ddbtypes.AttributeValue{"id": item["id"]},
+		ConditionExpression: aws.String("attribute_exists(id)"),
+		ConditionalOperator: ddbtypes.ConditionalOperatorOr,
+	})
+
+	var apiErr smithy.APIError
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+	c.Equal("Can not use both expression and non-expression parameters in the same request: Non-expression parameters: {ConditionalOperator} Expression parameters: {ConditionExpression}", apiErr.ErrorMessage())
+}
+
+func TestServerUpdateItemWithAttributeUpdates(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+	cli := newTestDynamoClient(t, ts.URL)
+
+	makeBasicTable(t, cli, "pokemons", "id")
+
+	key := map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "133"}}
+
+	out, err := cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String("pokemons"),
+		Key:       key,
+		AttributeUpdates: map[string]ddbtypes.AttributeValueUpdate{
+			"name":  {Value: &ddbtypes.AttributeValueMemberS{Value: "eevee"}},
+			"level": {Action: ddbtypes.AttributeActionAdd, Value: &ddbtypes.AttributeValueMemberN{Value: "10"}},
+		},
+		ReturnValues: ddbtypes.ReturnValueAllNew,
+	})
+	c.NoError(err)
+	c.Equal(&ddbtypes.AttributeValueMemberN{Value: "10"}, out.Attributes["level"])
+
+	out, err = cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String("pokemons"),
+		Key:       key,
+		AttributeUpdates: map[string]ddbtypes.AttributeValueUpdate{
+			"name":  {Action: ddbtypes.AttributeActionDelete},
+			"level": {Action: ddbtypes.AttributeActionAdd, Value: &ddbtypes.AttributeValueMemberN{Value: "-3"}},
+		},
+		ReturnValues: ddbtypes.ReturnValueUpdatedOld,
+	})
+	c.NoError(err)
+	c.Equal(map[string]ddbtypes.AttributeValue{
+		"name":  &ddbtypes.AttributeValueMemberS{Value: "eevee"},
+		"level": &ddbtypes.AttributeValueMemberN{Value: "10"},
+	}, out.Attributes)
+
+	_, err = cli.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String("pokemons"),
+		Key:              key,
+		UpdateExpression: aws.String("REMOVE #level"),
+		AttributeUpdates: map[string]ddbtypes.AttributeValueUpdate{"name": {Action: ddbtypes.AttributeActionDelete}},
+	})
+
+	var apiErr smithy.APIError
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+	c.Contains(apiErr.ErrorMessage(), "Non-expression parameters: {AttributeUpdates} Expression parameters: {UpdateExpression}")
+}
+
+func TestServerSearchWithLegacyConditions(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+	cli := newTestDynamoClient(t, ts.URL)
+
+	createBackupTestTable(t, cli)
+
+	query, err := cli.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String("pokemons"),
+		IndexName: aws.String("by-type"),
+		KeyConditions: map[string]ddbtypes.Condition{
+			"type": {ComparisonOperator: ddbtypes.ComparisonOperatorEq, AttributeValueList: []ddbtypes.AttributeValue{&ddbtypes.AttributeValueMemberS{Value: "fire"}}},
+		},
+	})
+	c.NoError(err)
+	c.Len(query.Items, 1)
+	c.Equal(&ddbtypes.AttributeValueMemberS{Value: "4"}, query.Items[0]["id"])
+
+	scan, err := cli.Scan(ctx, &dynamodb.ScanInput{
+		TableName: aws.String("pokemons"),
+		ScanFilter: map[string]ddbtypes.Condition{
+			"type": {ComparisonOperator: ddbtypes.ComparisonOperatorEq, AttributeValueList: []ddbtypes.AttributeValue{&ddbtypes.AttributeValueMemberS{Value: "water"}}},
+			"id":   {ComparisonOperator: ddbtypes.ComparisonOperatorEq, AttributeValueList: []ddbtypes.AttributeValue{&ddbtypes.AttributeValueMemberS{Value: "25"}}},
+		},
+		ConditionalOperator: ddbtypes.ConditionalOperatorOr,
+	})
+	c.NoError(err)
+	c.Equal(int32(2), scan.Count)
+
+	_, err = cli.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String("pokemons"),
+		KeyConditions: map[string]ddbtypes.Condition{
+			"id": {ComparisonOperator: ddbtypes.ComparisonOperatorBeginsWith, AttributeValueList: []ddbtypes.AttributeValue{&ddbtypes.AttributeValueMemberS{Value: "2"}}},
+		},
+	})
+
+	var apiErr smithy.APIError
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+	c.Contains(apiErr.ErrorMessage(), "Query key condition not supported")
+}
+
+func TestServerReadsWithAttributesToGet(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+	cli := newTestDynamoClient(t, ts.URL)
+
+	createBackupTestTable(t, cli)
+
+	key := map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "25"}}
+
+	got, err := cli.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String("pokemons"), Key: key, AttributesToGet: []string{"type"}})
+	c.NoError(err)
+	c.Equal(map[string]ddbtypes.AttributeValue{"type": &ddbtypes.AttributeValueMemberS{Value: "electric"}}, got.Item)
+
+	batch, err := cli.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]ddbtypes.KeysAndAttributes{"pokemons": {Keys: []map[string]ddbtypes.AttributeValue{key}, AttributesToGet: []string{"id"}}},
+	})
+	c.NoError(err)
+	c.Equal([]map[string]ddbtypes.AttributeValue{key}, batch.Responses["pokemons"])
+
+	query, err := cli.Query(ctx, &dynamodb.QueryInput{
+		TableName: aws.String("pokemons"),
+		IndexName: aws.String("by-type"),
+		KeyConditions: map[string]ddbtypes.Condition{
+			"type": {ComparisonOperator: ddbtypes.ComparisonOperatorEq, AttributeValueList: []ddbtypes.AttributeValue{&ddbtypes.AttributeValueMemberS{Value: "fire"}}},
+		},
+		AttributesToGet: []string{"id"},
+	})
+	c.NoError(err)
+	c.Equal([]map[string]ddbtypes.AttributeValue{{"id": &ddbtypes.AttributeValueMemberS{Value: "4"}}}, query.Items)
+
+	scan, err := cli.Scan(ctx, &dynamodb.ScanInput{TableName: aws.String("pokemons"), AttributesToGet: []string{"type"}})
+	c.NoError(err)
+	c.Len(scan.Items, 3)
+	c.NotContains(scan.Items[0], "id")
+
+	_, err = cli.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String("pokemons"), Key: key, AttributesToGet: []string{"type"}, ProjectionExpression: aws.String("id")})
+
+	var apiErr smithy.APIError
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+	c.Contains(apiErr.ErrorMessage(), "Non-expression parameters: {AttributesToGet} Expression parameters: {ProjectionExpression}")
+}
+
+func TestServerParallelScan(t *testing.T) {
+	c := require.New(t)
+	ctx := context.Background()
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+	cli := newTestDynamoClient(t, ts.URL)
+
+	makeBasicTable(t, cli, "pokemons", "id")
+
+	for i := range 20 {
+		_, err := cli.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String("pokemons"),
+			Item:      map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: fmt.Sprint(i)}},
+		})
+		c.NoError(err)
+	}
+
+	seen := map[string]int{}
+
+	for segment := range int32(4) {
+		input := &dynamodb.ScanInput{TableName: aws.String("pokemons"), Limit: aws.Int32(3), Segment: aws.Int32(segment), TotalSegments: aws.Int32(4)}
+
+		for {
+			out, err := cli.Scan(ctx, input)
+			c.NoError(err)
+
+			for _, item := range out.Items {
+				seen[item["id"].(*ddbtypes.AttributeValueMemberS).Value]++
+			}
+
+			if len(out.LastEvaluatedKey) == 0 {
+				break
+			}
+
+			input.ExclusiveStartKey = out.LastEvaluatedKey
+		}
+	}
+
+	c.Len(seen, 20)
+
+	for id, reads := range seen {
+		c.Equal(1, reads, id)
+	}
+
+	_, err := cli.Scan(ctx, &dynamodb.ScanInput{TableName: aws.String("pokemons"), Segment: aws.Int32(1)})
+
+	var apiErr smithy.APIError
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+	c.Contains(apiErr.ErrorMessage(), "The TotalSegments parameter is required")
+}
+
 func TestServerUpdateExpressionsAddRemoveDelete(t *testing.T) {
 	ts := httptest.NewServer(NewServer())
 	defer ts.Close()
@@ -3229,6 +3532,68 @@ func TestServerTransactWriteItems(t *testing.T) {
 	})
 }
 
+func TestServerTransactWriteItemsClientRequestToken(t *testing.T) {
+	c := require.New(t)
+
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	cli := newTestDynamoClient(t, ts.URL)
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	srv.SetClock(clock)
+	srv.SetClientRequestTokenTTL(time.Hour)
+
+	makeBasicTable(t, cli, "payments", "id")
+
+	key := map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "wallet"}}
+	input := &dynamodb.TransactWriteItemsInput{
+		ClientRequestToken: aws.String("charge-1"),
+		TransactItems: []ddbtypes.TransactWriteItem{
+			{
+				Update: &ddbtypes.Update{
+					TableName:                 aws.String("payments"),
+					Key:                       key,
+					UpdateExpression:          aws.String("ADD charges :one"),
+					ExpressionAttributeValues: map[string]ddbtypes.AttributeValue{":one": &ddbtypes.AttributeValueMemberN{Value: "1"}},
+				},
+			},
+		},
+	}
+
+	charges := func() string {
+		out, err := cli.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String("payments"), Key: key})
+		c.NoError(err)
+
+		return out.Item["charges"].(*ddbtypes.AttributeValueMemberN).Value
+	}
+
+	_, err := cli.TransactWriteItems(ctx, input)
+	c.NoError(err)
+
+	clock.Advance(59 * time.Minute)
+
+	_, err = cli.TransactWriteItems(ctx, input)
+	c.NoError(err)
+	c.Equal("1", charges())
+
+	input.TransactItems[0].Update.ExpressionAttributeValues[":one"] = &ddbtypes.AttributeValueMemberS{Value: "1"}
+
+	_, err = cli.TransactWriteItems(ctx, input)
+
+	var mismatch *ddbtypes.IdempotentParameterMismatchException
+	c.ErrorAs(err, &mismatch)
+
+	input.TransactItems[0].Update.ExpressionAttributeValues[":one"] = &ddbtypes.AttributeValueMemberN{Value: "1"}
+
+	clock.Advance(time.Minute)
+
+	_, err = cli.TransactWriteItems(ctx, input)
+	c.NoError(err)
+	c.Equal("2", charges())
+}
+
 func TestServerTransactGetItems(t *testing.T) {
 	s := NewServer()
 	ts := httptest.NewServer(s)
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/truora/minidyn/core"
+)
+
+func mapOnDemandThroughput(input *ddbtypes.OnDemandThroughput) *core.Throughput {
+	if input == nil {
+		return nil
+	}
+
+	return &core.Throughput{
+		ReadUnits:  aws.ToInt64(input.MaxReadRequestUnits),
+		WriteUnits: aws.ToInt64(input.MaxWriteRequestUnits),
+	}
+}
+
+func mapWarmThroughput(input *ddbtypes.WarmThroughput) *core.Throughput {
+	if input == nil {
+		return nil
+	}
+
+	return &core.Throughput{
+		ReadUnits:  aws.ToInt64(input.ReadUnitsPerSecond),
+		WriteUnits: aws.ToInt64(input.WriteUnitsPerSecond),
+	}
+}
+
+// mapSSESpecification resolves the KMS key of an SSE specification to its ARN
+func (c *Client) mapSSESpecification(input *ddbtypes.SSESpecification) *core.SSE {
+	if input == nil {
+		return nil
+	}
+
+	sse := &core.SSE{Enabled: aws.ToBool(input.Enabled)}
+	if sse.Enabled {
+		sse.KMSMasterKeyArn = c.kmsKeyArn(aws.ToString(input.KMSMasterKeyId))
+	}
+
+	return sse
+}
+
+// kmsKeyArn returns the ARN of a KMS key given by ID, alias or ARN, the AWS managed key of
+// DynamoDB is described by its alias when no key is given
+func (c *Client) kmsKeyArn(keyID string) string {
+	switch {
+	case keyID == "":
+		keyID = "alias/aws/dynamodb"
+	case strings.HasPrefix(keyID, "arn:"):
+		return keyID
+	case !strings.HasPrefix(keyID, "alias/"):
+		keyID = "key/" + keyID
+	}
+
+	return fmt.Sprintf("arn:aws:kms:%s:%s:%s", c.region, c.accountID, keyID)
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func TestServerTableSettings(t *testing.T) {
+	c := require.New(t)
+
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	srv.SetClock(core.NewManualClock(now))
+
+	tableName := aws.String("pokemons")
+
+	created, err := ddb.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:                 tableName,
+		KeySchema:                 []ddbtypes.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: ddbtypes.KeyTypeHash}},
+		AttributeDefinitions:      []ddbtypes.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: ddbtypes.ScalarAttributeTypeS}},
+		BillingMode:               ddbtypes.BillingModeProvisioned,
+		ProvisionedThroughput:     &ddbtypes.ProvisionedThroughput{ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(5)},
+		DeletionProtectionEnabled: aws.Bool(true),
+		TableClass:                ddbtypes.TableClassStandardInfrequentAccess,
+		SSESpecification:          &ddbtypes.SSESpecification{Enabled: aws.Bool(true), KMSMasterKeyId: aws.String("pokedex")},
+	})
+	c.NoError(err)
+
+	desc := created.TableDescription
+	c.True(aws.ToBool(desc.DeletionProtectionEnabled))
+	c.Equal(ddbtypes.BillingModeProvisioned, desc.BillingModeSummary.BillingMode)
+	c.Equal(ddbtypes.TableClassStandardInfrequentAccess, desc.TableClassSummary.TableClass)
+	c.Equal(int64(5), aws.ToInt64(desc.ProvisionedThroughput.ReadCapacityUnits))
+	c.Equal("arn:aws:kms:us-east-1:000000000000:key/pokedex", aws.ToString(desc.SSEDescription.KMSMasterKeyArn))
+	c.Equal(ddbtypes.SSEStatusEnabled, desc.SSEDescription.Status)
+
+	_, err = ddb.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: tableName})
+	c.ErrorContains(err, "protected against deletion")
+
+	_, err = ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:          tableName,
+		BillingMode:        ddbtypes.BillingModePayPerRequest,
+		OnDemandThroughput: &ddbtypes.OnDemandThroughput{MaxReadRequestUnits: aws.Int64(100), MaxWriteRequestUnits: aws.Int64(-1)},
+		TableClass:         ddbtypes.TableClassStandard,
+		SSESpecification:   &ddbtypes.SSESpecification{Enabled: aws.Bool(false)},
+	})
+	c.NoError(err)
+
+	described, err := ddb.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: tableName})
+	c.NoError(err)
+
+	desc = described.Table
+	c.Equal(ddbtypes.BillingModePayPerRequest, desc.BillingModeSummary.BillingMode)
+	c.Equal(now, aws.ToTime(desc.BillingModeSummary.LastUpdateToPayPerRequestDateTime).UTC())
+	c.Equal(ddbtypes.TableClassStandard, desc.TableClassSummary.TableClass)
+	c.Equal(now, aws.ToTime(desc.TableClassSummary.LastUpdateDateTime).UTC())
+	c.Zero(aws.ToInt64(desc.ProvisionedThroughput.ReadCapacityUnits))
+	c.Equal(int64(-1), aws.ToInt64(desc.OnDemandThroughput.MaxWriteRequestUnits))
+	c.Nil(desc.SSEDescription)
+
+	_, err = ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{TableName: tableName, BillingMode: ddbtypes.BillingModeProvisioned})
+	c.ErrorContains(err, "ProvisionedThroughput must be specified")
+
+	_, err = ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{TableName: tableName, DeletionProtectionEnabled: aws.Bool(false)})
+	c.NoError(err)
+
+	_, err = ddb.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: tableName})
+	c.NoError(err)
+}
+
+func TestServerUpdateTableFailureKeepsSettings(t *testing.T) {
+	c := require.New(t)
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+
+	makeBasicTable(t, ddb, "pokemons", "id")
+
+	_, err := ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:                 aws.String("pokemons"),
+		DeletionProtectionEnabled: aws.Bool(true),
+		TableClass:                ddbtypes.TableClassStandardInfrequentAccess,
+		GlobalSecondaryIndexUpdates: []ddbtypes.GlobalSecondaryIndexUpdate{{Create: &ddbtypes.CreateGlobalSecondaryIndexAction{
+			IndexName:  aws.String("by-region"),
+			KeySchema:  []ddbtypes.KeySchemaElement{{AttributeName: aws.String("region"), KeyType: ddbtypes.KeyTypeHash}},
+			Projection: &ddbtypes.Projection{ProjectionType: ddbtypes.ProjectionTypeAll},
+		}}},
+	})
+	c.ErrorContains(err, "ValidationException")
+
+	_, err = ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:                 aws.String("pokemons"),
+		DeletionProtectionEnabled: aws.Bool(true),
+		StreamSpecification:       &ddbtypes.StreamSpecification{StreamEnabled: aws.Bool(true)},
+	})
+	c.ErrorContains(err, "StreamViewType is required")
+
+	described, err := ddb.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String("pokemons")})
+	c.NoError(err)
+	c.False(aws.ToBool(described.Table.DeletionProtectionEnabled))
+	c.Nil(described.Table.TableClassSummary)
+
+	_, err = ddb.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: aws.String("pokemons")})
+	c.NoError(err)
+}
+
+func TestServerUpdateTableFailureKeepsIndexes(t *testing.T) {
+	c := require.New(t)
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+
+	makeBasicTable(t, ddb, "pokemons", "id")
+
+	createRegionIndex := ddbtypes.GlobalSecondaryIndexUpdate{Create: &ddbtypes.CreateGlobalSecondaryIndexAction{
+		IndexName:             aws.String("by-region"),
+		KeySchema:             []ddbtypes.KeySchemaElement{{AttributeName: aws.String("region"), KeyType: ddbtypes.KeyTypeHash}},
+		Projection:            &ddbtypes.Projection{ProjectionType: ddbtypes.ProjectionTypeAll},
+		ProvisionedThroughput: &ddbtypes.ProvisionedThroughput{ReadCapacityUnits: aws.Int64(1), WriteCapacityUnits: aws.Int64(1)},
+	}}
+
+	_, err := ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:            aws.String("pokemons"),
+		AttributeDefinitions: []ddbtypes.AttributeDefinition{{AttributeName: aws.String("region"), AttributeType: ddbtypes.ScalarAttributeTypeS}},
+		GlobalSecondaryIndexUpdates: []ddbtypes.GlobalSecondaryIndexUpdate{
+			createRegionIndex,
+			{Delete: &ddbtypes.DeleteGlobalSecondaryIndexAction{IndexName: aws.String("missing")}},
+		},
+	})
+	c.ErrorContains(err, "ResourceNotFoundException")
+
+	described, err := ddb.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String("pokemons")})
+	c.NoError(err)
+	c.Empty(described.Table.GlobalSecondaryIndexes)
+
+	// the attribute definitions of the failed request are gone as well
+	_, err = ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:                   aws.String("pokemons"),
+		GlobalSecondaryIndexUpdates: []ddbtypes.GlobalSecondaryIndexUpdate{createRegionIndex},
+	})
+	c.ErrorContains(err, "ValidationException")
+}
@@ -0,0 +1,393 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+	"github.com/truora/minidyn/interpreter/partiql"
+	"github.com/truora/minidyn/types"
+)
+
+const (
+	batchExecuteStatementLimit = 25
+	executeTransactionLimit    = 100
+)
+
+// ExecuteStatement runs a PartiQL statement.
+func (c *Client) ExecuteStatement(ctx context.Context, input *ExecuteStatementInput) (*ExecuteStatementOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if input.Statement == nil {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "1 validation error detected: Value null at 'statement' failed to satisfy constraint: Member must not be null"}
+	}
+
+	if input.Limit != nil && *input.Limit < 1 {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value greater than or equal to 1", *input.Limit)}
+	}
+
+	stmt, err := partiql.Parse(aws.ToString(input.Statement), len(input.Parameters))
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	if ferr := c.failureErrFor(stmt.TableName(), ""); ferr != nil {
+		return nil, ferr
+	}
+
+	table, err := c.getTable(stmt.TableName())
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := table.ExecuteStatement(stmt, core.StatementInput{
+		Parameters: mapAttributeValueListToTypes(input.Parameters),
+		Limit:      int64(aws.ToInt32(input.Limit)),
+		NextToken:  aws.ToString(input.NextToken),
+	})
+	if err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	output := &ExecuteStatementOutput{
+		Items:            mapTypesSliceToAttributeValue(out.Items),
+		LastEvaluatedKey: mapTypesMapToAttributeValue(out.LastEvaluatedKey),
+	}
+
+	if out.NextToken != "" {
+		output.NextToken = aws.String(out.NextToken)
+	}
+
+	return output, nil
+}
+
+// BatchExecuteStatement runs up to 25 PartiQL statements, either all reads or all writes. A
+// statement that fails reports its error in its response without stopping the rest of the batch.
+func (c *Client) BatchExecuteStatement(ctx context.Context, input *BatchExecuteStatementInput) (*BatchExecuteStatementOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := validateStatementCount("statements", len(input.Statements), batchExecuteStatementLimit); err != nil {
+		return nil, err
+	}
+
+	if err := validateBatchReads(input.Statements); err != nil {
+		return nil, err
+	}
+
+	if c.forceFailureErr != nil {
+		return nil, c.forceFailureErr
+	}
+
+	responses := make([]BatchStatementResponse, len(input.Statements))
+
+	for i, req := range input.Statements {
+		resp, err := c.runBatchStatement(req)
+		if err != nil {
+			return nil, err
+		}
+
+		responses[i] = resp
+	}
+
+	return &BatchExecuteStatementOutput{Responses: responses}, nil
+}
+
+func (c *Client) runBatchStatement(req BatchStatementRequest) (BatchStatementResponse, error) {
+	stmt, err := partiql.Parse(aws.ToString(req.Statement), len(req.Parameters))
+	if err != nil {
+		return BatchStatementResponse{Error: newBatchStatementError(err)}, nil
+	}
+
+	resp := BatchStatementResponse{TableName: aws.String(stmt.TableName())}
+
+	if ferr := c.failureErrFor(stmt.TableName(), ""); ferr != nil {
+		return resp, ferr
+	}
+
+	table, err := c.getTable(stmt.TableName())
+	if err != nil {
+		resp.Error = newBatchStatementError(err)
+
+		return resp, nil
+	}
+
+	params := mapAttributeValueListToTypes(req.Parameters)
+
+	if _, err := table.StatementItemKey(stmt, params); err != nil {
+		resp.Error = newBatchStatementError(err)
+
+		return resp, nil
+	}
+
+	out, err := table.ExecuteStatement(stmt, core.StatementInput{Parameters: params})
+	if err != nil {
+		resp.Error = newBatchStatementError(err)
+
+		return resp, nil
+	}
+
+	if _, ok := stmt.(*partiql.SelectStatement); ok && len(out.Items) > 0 {
+		resp.Item = mapTypesMapToAttributeValue(out.Items[0])
+	}
+
+	return resp, nil
+}
+
+// validateBatchReads rejects batches mixing reads and writes, statements that do not parse
+// are left to report their error in their own response
+func validateBatchReads(reqs []BatchStatementRequest) error {
+	reads, writes := 0, 0
+
+	for _, req := range reqs {
+		stmt, err := partiql.Parse(aws.ToString(req.Statement), len(req.Parameters))
+		if err != nil {
+			continue
+		}
+
+		if _, ok := stmt.(*partiql.SelectStatement); ok {
+			reads++
+		} else {
+			writes++
+		}
+	}
+
+	if reads > 0 && writes > 0 {
+		return &smithy.GenericAPIError{Code: "ValidationException", Message: "The entire batch must consist of either read statements or write statements"}
+	}
+
+	return nil
+}
+
+func newBatchStatementError(err error) *BatchStatementError {
+	if notFound, ok := errors.AsType[*ddbtypes.ResourceNotFoundException](err); ok {
+		return &BatchStatementError{Code: string(ddbtypes.BatchStatementErrorCodeEnumResourceNotFound), Message: aws.ToString(notFound.Message)}
+	}
+
+	intErr, ok := errors.AsType[types.Error](err)
+	if !ok {
+		return &BatchStatementError{Code: string(ddbtypes.BatchStatementErrorCodeEnumInternalServerError), Message: err.Error()}
+	}
+
+	code := ddbtypes.BatchStatementErrorCodeEnumValidationError
+
+	switch intErr.Code() {
+	case "ConditionalCheckFailedException":
+		code = ddbtypes.BatchStatementErrorCodeEnumConditionalCheckFailed
+	case "DuplicateItemException":
+		code = ddbtypes.BatchStatementErrorCodeEnumDuplicateItem
+	case "ResourceNotFoundException":
+		code = ddbtypes.BatchStatementErrorCodeEnumResourceNotFound
+	case "InternalServerError":
+		code = ddbtypes.BatchStatementErrorCodeEnumInternalServerError
+	}
+
+	return &BatchStatementError{Code: string(code), Message: intErr.Message()}
+}
+
+// ExecuteTransaction runs up to 100 PartiQL statements as a single all-or-nothing transaction.
+// Transactions either read items with SELECT or write them with INSERT, UPDATE, DELETE and
+// EXISTS conditions. Retries with the same ClientRequestToken return the first outcome.
+func (c *Client) ExecuteTransaction(ctx context.Context, input *ExecuteTransactionInput) (*ExecuteTransactionOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := validateStatementCount("transactStatements", len(input.TransactStatements), executeTransactionLimit); err != nil {
+		return nil, err
+	}
+
+	if c.forceFailureErr != nil {
+		return nil, c.forceFailureErr
+	}
+
+	token := aws.ToString(input.ClientRequestToken)
+
+	var fingerprint string
+
+	if input.ClientRequestToken != nil {
+		fingerprint = core.RequestFingerprint(input.TransactStatements)
+
+		if err := core.ValidateClientRequestToken(token); err != nil {
+			return nil, mapKnownError(err)
+		}
+
+		out, ok, err := c.transactionTokens.Lookup(token, fingerprint, c.clock.Now())
+		if err != nil {
+			return nil, mapKnownError(err)
+		}
+
+		if ok {
+			return out, nil
+		}
+	}
+
+	statements, read, err := c.prepareTransactStatements(input.TransactStatements)
+	if err != nil {
+		return nil, err
+	}
+
+	var out *ExecuteTransactionOutput
+
+	if read {
+		out, err = c.runReadTransaction(statements)
+	} else {
+		out, err = c.runWriteTransaction(statements)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if input.ClientRequestToken != nil {
+		c.transactionTokens.Store(token, fingerprint, out, c.clock.Now())
+	}
+
+	return out, nil
+}
+
+type transactStatement struct {
+	stmt   partiql.Statement
+	table  *core.Table
+	params []*types.Item
+}
+
+// prepareTransactStatements parses the statements of a transaction and checks all of them
+// target a single item, it reports whether the transaction only reads
+func (c *Client) prepareTransactStatements(reqs []ParameterizedStatement) ([]transactStatement, bool, error) {
+	statements := make([]transactStatement, 0, len(reqs))
+	seenKeys := make(map[string]struct{}, len(reqs))
+	reads := 0
+
+	for _, req := range reqs {
+		stmt, err := partiql.Parse(aws.ToString(req.Statement), len(req.Parameters))
+		if err != nil {
+			return nil, false, mapKnownError(err)
+		}
+
+		if ferr := c.failureErrFor(stmt.TableName(), ""); ferr != nil {
+			return nil, false, ferr
+		}
+
+		table, err := c.lookupTable(stmt.TableName())
+		if err != nil {
+			return nil, false, err
+		}
+
+		params := mapAttributeValueListToTypes(req.Parameters)
+
+		key, err := table.StatementItemKey(stmt, params)
+		if err != nil {
+			return nil, false, mapKnownError(err)
+		}
+
+		if err := markTransactKey(seenKeys, stmt.TableName(), key); err != nil {
+			return nil, false, err
+		}
+
+		if _, ok := stmt.(*partiql.SelectStatement); ok {
+			reads++
+		}
+
+		statements = append(statements, transactStatement{stmt: stmt, table: table, params: params})
+	}
+
+	if reads > 0 && reads < len(statements) {
+		return nil, false, &smithy.GenericAPIError{Code: "ValidationException", Message: "Transactions must only contain read statements or only write statements"}
+	}
+
+	return statements, reads > 0, nil
+}
+
+func (c *Client) runReadTransaction(statements []transactStatement) (*ExecuteTransactionOutput, error) {
+	responses := make([]ItemResponse, len(statements))
+
+	for i, ts := range statements {
+		out, err := ts.table.ExecuteStatement(ts.stmt, core.StatementInput{Parameters: ts.params})
+		if err != nil {
+			return nil, mapKnownError(err)
+		}
+
+		if len(out.Items) > 0 {
+			responses[i].Item = mapTypesMapToAttributeValue(out.Items[0])
+		}
+	}
+
+	return &ExecuteTransactionOutput{Responses: responses}, nil
+}
+
+func (c *Client) runWriteTransaction(statements []transactStatement) (*ExecuteTransactionOutput, error) {
+	snapshots := map[string]core.TableSnapshot{}
+
+	for _, ts := range statements {
+		if _, err := c.snapshotTransactTable(snapshots, ts.stmt.TableName()); err != nil {
+			return nil, err
+		}
+	}
+
+	n := len(statements)
+
+	for i, ts := range statements {
+		var err error
+
+		if exists, ok := ts.stmt.(*partiql.ExistsStatement); ok {
+			err = ts.table.CheckExists(exists, core.StatementInput{Parameters: ts.params})
+		} else {
+			_, err = ts.table.ExecuteStatement(ts.stmt, core.StatementInput{Parameters: ts.params})
+		}
+
+		if err != nil {
+			c.restoreSnapshots(snapshots)
+
+			return nil, newStatementTransactionCancelledError(i, n, err)
+		}
+	}
+
+	return &ExecuteTransactionOutput{}, nil
+}
+
+// newStatementTransactionCancelledError extends newServerTransactionCancelledError with the
+// duplicate item reason of INSERT statements
+func newStatementTransactionCancelledError(i, n int, opErr error) error {
+	intErr, ok := errors.AsType[types.Error](opErr)
+	if !ok || intErr.Code() != "DuplicateItemException" {
+		return newServerTransactionCancelledError(i, n, opErr)
+	}
+
+	reasons := make([]ddbtypes.CancellationReason, n)
+	for j := range reasons {
+		reasons[j] = ddbtypes.CancellationReason{Code: aws.String("None")}
+	}
+
+	reasons[i] = ddbtypes.CancellationReason{
+		Code:    aws.String("DuplicateItem"),
+		Message: aws.String(intErr.Message()),
+	}
+
+	return &ddbtypes.TransactionCanceledException{
+		Message:             aws.String("Transaction cancelled, please refer cancellation reasons for specific reasons [DuplicateItem]"),
+		CancellationReasons: reasons,
+	}
+}
+
+func validateStatementCount(member string, count, limit int) error {
+	var constraint string
+
+	switch {
+	case count == 0:
+		constraint = "Member must have length greater than or equal to 1"
+	case count > limit:
+		constraint = fmt.Sprintf("Member must have length less than or equal to %d", limit)
+	default:
+		return nil
+	}
+
+	return &smithy.GenericAPIError{
+		Code:    "ValidationException",
+		Message: fmt.Sprintf("1 validation error detected: Value at '%s' failed to satisfy constraint: %s", member, constraint),
+	}
+}
@@ -0,0 +1,337 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerExecuteStatementSelect(t *testing.T) {
+	c := require.New(t)
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	ctx := context.Background()
+	cli := newTestDynamoClient(t, ts.URL)
+
+	makeBasicTable(t, cli, "pokemons", "id")
+
+	for _, p := range [][2]string{{"001", "grass"}, {"004", "fire"}, {"007", "water"}, {"025", "electric"}} {
+		_, err := cli.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String("pokemons"),
+			Item: map[string]ddbtypes.AttributeValue{
+				"id":   &ddbtypes.AttributeValueMemberS{Value: p[0]},
+				"type": &ddbtypes.AttributeValueMemberS{Value: p[1]},
+			},
+		})
+		c.NoError(err)
+	}
+
+	out, err := cli.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement:  aws.String(`SELECT "type" FROM pokemons WHERE id = ?`),
+		Parameters: []ddbtypes.AttributeValue{&ddbtypes.AttributeValueMemberS{Value: "004"}},
+	})
+	c.NoError(err)
+	c.Equal([]map[string]ddbtypes.AttributeValue{{"type": &ddbtypes.AttributeValueMemberS{Value: "fire"}}}, out.Items)
+	c.Nil(out.NextToken)
+
+	var ids []string
+
+	input := &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`SELECT * FROM pokemons WHERE "type" <> 'fire'`),
+		Limit:     aws.Int32(2),
+	}
+
+	for {
+		page, err := cli.ExecuteStatement(ctx, input)
+		c.NoError(err)
+
+		for _, item := range page.Items {
+			ids = append(ids, item["id"].(*ddbtypes.AttributeValueMemberS).Value)
+		}
+
+		if page.NextToken == nil {
+			break
+		}
+
+		c.NotEmpty(page.LastEvaluatedKey)
+
+		input.NextToken = page.NextToken
+	}
+
+	c.ElementsMatch([]string{"001", "007", "025"}, ids)
+}
+
+func TestServerExecuteStatementErrors(t *testing.T) {
+	c := require.New(t)
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	ctx := context.Background()
+	cli := newTestDynamoClient(t, ts.URL)
+
+	makeBasicTable(t, cli, "pokemons", "id")
+
+	_, err := cli.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{Statement: aws.String(`SELECT * FROM`)})
+
+	var apiErr smithy.APIError
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+	c.Contains(apiErr.ErrorMessage(), "Statement wasn't well formed")
+
+	_, err = cli.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{Statement: aws.String(`SELECT * FROM pokemons WHERE id = ?`)})
+	c.ErrorAs(err, &apiErr)
+	c.Equal("Number of parameters in request and statement don't match.", apiErr.ErrorMessage())
+
+	_, err = cli.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{Statement: aws.String(`SELECT * FROM pokemons`), Limit: aws.Int32(0)})
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+
+	_, err = cli.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{Statement: aws.String(`SELECT * FROM "missing"`)})
+
+	var notFound *ddbtypes.ResourceNotFoundException
+	c.ErrorAs(err, &notFound)
+}
+
+func TestServerExecuteStatementDML(t *testing.T) {
+	c := require.New(t)
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	ctx := context.Background()
+	cli := newTestDynamoClient(t, ts.URL)
+
+	makeBasicTable(t, cli, "pokemons", "id")
+
+	_, err := cli.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement:  aws.String(`INSERT INTO pokemons VALUE {'id': ?, 'type': 'electric', 'lvl': 5}`),
+		Parameters: []ddbtypes.AttributeValue{&ddbtypes.AttributeValueMemberS{Value: "025"}},
+	})
+	c.NoError(err)
+
+	_, err = cli.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`INSERT INTO pokemons VALUE {'id': '025'}`),
+	})
+
+	var duplicate *ddbtypes.DuplicateItemException
+	c.ErrorAs(err, &duplicate)
+
+	out, err := cli.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`UPDATE pokemons SET lvl = lvl + 1 REMOVE "type" WHERE id = '025' RETURNING ALL NEW *`),
+	})
+	c.NoError(err)
+	c.Equal([]map[string]ddbtypes.AttributeValue{{
+		"id":  &ddbtypes.AttributeValueMemberS{Value: "025"},
+		"lvl": &ddbtypes.AttributeValueMemberN{Value: "6"},
+	}}, out.Items)
+
+	_, err = cli.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`UPDATE pokemons SET lvl = 1 WHERE lvl = 6`),
+	})
+
+	var apiErr smithy.APIError
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+
+	out, err = cli.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{
+		Statement: aws.String(`DELETE FROM pokemons WHERE id = '025' RETURNING ALL OLD *`),
+	})
+	c.NoError(err)
+	c.Len(out.Items, 1)
+
+	get, err := cli.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String("pokemons"),
+		Key:       map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "025"}},
+	})
+	c.NoError(err)
+	c.Empty(get.Item)
+}
+
+func TestServerBatchExecuteStatement(t *testing.T) {
+	c := require.New(t)
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	ctx := context.Background()
+	cli := newTestDynamoClient(t, ts.URL)
+
+	makeBasicTable(t, cli, "pokemons", "id")
+
+	out, err := cli.BatchExecuteStatement(ctx, &dynamodb.BatchExecuteStatementInput{
+		Statements: []ddbtypes.BatchStatementRequest{
+			{Statement: aws.String(`INSERT INTO pokemons VALUE {'id': '001', 'type': 'grass'}`)},
+			{Statement: aws.String(`INSERT INTO pokemons VALUE {'id': ?, 'type': 'fire'}`), Parameters: []ddbtypes.AttributeValue{&ddbtypes.AttributeValueMemberS{Value: "004"}}},
+			{Statement: aws.String(`INSERT INTO pokemons VALUE {'id': '001', 'type': 'grass'}`)},
+			{Statement: aws.String(`UPDATE "missing" SET lvl = 1 WHERE id = '001'`)},
+			{Statement: aws.String(`SELECT * FROM`)},
+		},
+	})
+	c.NoError(err)
+	c.Len(out.Responses, 5)
+	c.Nil(out.Responses[0].Error)
+	c.Equal("pokemons", aws.ToString(out.Responses[0].TableName))
+	c.Nil(out.Responses[1].Error)
+	c.Equal(ddbtypes.BatchStatementErrorCodeEnumDuplicateItem, out.Responses[2].Error.Code)
+	c.Equal(ddbtypes.BatchStatementErrorCodeEnumResourceNotFound, out.Responses[3].Error.Code)
+	c.Equal(ddbtypes.BatchStatementErrorCodeEnumValidationError, out.Responses[4].Error.Code)
+
+	out, err = cli.BatchExecuteStatement(ctx, &dynamodb.BatchExecuteStatementInput{
+		Statements: []ddbtypes.BatchStatementRequest{
+			{Statement: aws.String(`SELECT * FROM pokemons WHERE id = '004'`)},
+			{Statement: aws.String(`SELECT * FROM pokemons WHERE id = '007'`)},
+			{Statement: aws.String(`SELECT * FROM pokemons WHERE "type" = 'grass'`)},
+		},
+	})
+	c.NoError(err)
+	c.Equal(map[string]ddbtypes.AttributeValue{
+		"id":   &ddbtypes.AttributeValueMemberS{Value: "004"},
+		"type": &ddbtypes.AttributeValueMemberS{Value: "fire"},
+	}, out.Responses[0].Item)
+	c.Nil(out.Responses[1].Item)
+	c.Nil(out.Responses[1].Error)
+	c.Equal(ddbtypes.BatchStatementErrorCodeEnumValidationError, out.Responses[2].Error.Code)
+
+	statements := make([]ddbtypes.BatchStatementRequest, 26)
+	for i := range statements {
+		statements[i] = ddbtypes.BatchStatementRequest{Statement: aws.String(`SELECT * FROM pokemons WHERE id = '001'`)}
+	}
+
+	_, err = cli.BatchExecuteStatement(ctx, &dynamodb.BatchExecuteStatementInput{Statements: statements})
+
+	var apiErr smithy.APIError
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+
+	// batches mixing reads and writes are rejected before running any statement
+	_, err = cli.BatchExecuteStatement(ctx, &dynamodb.BatchExecuteStatementInput{
+		Statements: []ddbtypes.BatchStatementRequest{
+			{Statement: aws.String(`DELETE FROM pokemons WHERE id = '004'`)},
+			{Statement: aws.String(`SELECT * FROM pokemons WHERE id = '004'`)},
+		},
+	})
+	c.ErrorAs(err, &apiErr)
+	c.ErrorContains(err, "The entire batch must consist of either read statements or write statements")
+
+	got, err := cli.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String("pokemons"),
+		Key:       map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "004"}},
+	})
+	c.NoError(err)
+	c.NotEmpty(got.Item)
+}
+
+func TestServerExecuteTransaction(t *testing.T) {
+	c := require.New(t)
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	ctx := context.Background()
+	cli := newTestDynamoClient(t, ts.URL)
+
+	makeBasicTable(t, cli, "pokemons", "id")
+
+	_, err := cli.ExecuteTransaction(ctx, &dynamodb.ExecuteTransactionInput{
+		TransactStatements: []ddbtypes.ParameterizedStatement{
+			{Statement: aws.String(`INSERT INTO pokemons VALUE {'id': '001', 'type': 'grass', 'lvl': 5}`)},
+			{Statement: aws.String(`INSERT INTO pokemons VALUE {'id': '004', 'type': 'fire', 'lvl': 5}`)},
+		},
+	})
+	c.NoError(err)
+
+	_, err = cli.ExecuteTransaction(ctx, &dynamodb.ExecuteTransactionInput{
+		TransactStatements: []ddbtypes.ParameterizedStatement{
+			{Statement: aws.String(`UPDATE pokemons SET lvl = lvl + 1 WHERE id = '001'`)},
+			{Statement: aws.String(`EXISTS(SELECT * FROM pokemons WHERE id = '004' AND "type" = 'water')`)},
+		},
+	})
+
+	var canceled *ddbtypes.TransactionCanceledException
+	c.ErrorAs(err, &canceled)
+	c.Len(canceled.CancellationReasons, 2)
+	c.Equal("None", aws.ToString(canceled.CancellationReasons[0].Code))
+	c.Equal("ConditionalCheckFailed", aws.ToString(canceled.CancellationReasons[1].Code))
+
+	_, err = cli.ExecuteTransaction(ctx, &dynamodb.ExecuteTransactionInput{
+		TransactStatements: []ddbtypes.ParameterizedStatement{
+			{Statement: aws.String(`INSERT INTO pokemons VALUE {'id': '007', 'type': 'water'}`)},
+			{Statement: aws.String(`INSERT INTO pokemons VALUE {'id': '004', 'type': 'fire'}`)},
+		},
+	})
+	c.ErrorAs(err, &canceled)
+	c.Equal("DuplicateItem", aws.ToString(canceled.CancellationReasons[1].Code))
+
+	read, err := cli.ExecuteTransaction(ctx, &dynamodb.ExecuteTransactionInput{
+		TransactStatements: []ddbtypes.ParameterizedStatement{
+			{Statement: aws.String(`SELECT lvl FROM pokemons WHERE id = '001'`)},
+			{Statement: aws.String(`SELECT * FROM pokemons WHERE id = '007'`)},
+		},
+	})
+	c.NoError(err)
+	c.Len(read.Responses, 2)
+	c.Equal(map[string]ddbtypes.AttributeValue{"lvl": &ddbtypes.AttributeValueMemberN{Value: "5"}}, read.Responses[0].Item)
+	c.Empty(read.Responses[1].Item)
+
+	_, err = cli.ExecuteTransaction(ctx, &dynamodb.ExecuteTransactionInput{
+		TransactStatements: []ddbtypes.ParameterizedStatement{
+			{Statement: aws.String(`SELECT * FROM pokemons WHERE id = '001'`)},
+			{Statement: aws.String(`DELETE FROM pokemons WHERE id = '004'`)},
+		},
+	})
+
+	var apiErr smithy.APIError
+	c.ErrorAs(err, &apiErr)
+	c.Equal("ValidationException", apiErr.ErrorCode())
+
+	_, err = cli.ExecuteTransaction(ctx, &dynamodb.ExecuteTransactionInput{
+		TransactStatements: []ddbtypes.ParameterizedStatement{
+			{Statement: aws.String(`UPDATE pokemons SET lvl = 6 WHERE id = '001'`)},
+			{Statement: aws.String(`DELETE FROM pokemons WHERE id = '001'`)},
+		},
+	})
+	c.ErrorAs(err, &apiErr)
+	c.Equal("Transaction request cannot include multiple operations on one item", apiErr.ErrorMessage())
+}
+
+func TestServerExecuteTransactionClientRequestToken(t *testing.T) {
+	c := require.New(t)
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	ctx := context.Background()
+	cli := newTestDynamoClient(t, ts.URL)
+
+	makeBasicTable(t, cli, "pokemons", "id")
+
+	input := &dynamodb.ExecuteTransactionInput{
+		ClientRequestToken: aws.String("catch-pikachu"),
+		TransactStatements: []ddbtypes.ParameterizedStatement{
+			{Statement: aws.String(`INSERT INTO pokemons VALUE {'id': '025', 'type': 'electric'}`)},
+		},
+	}
+
+	_, err := cli.ExecuteTransaction(ctx, input)
+	c.NoError(err)
+
+	// the retry is not run again, otherwise the INSERT would fail
+	_, err = cli.ExecuteTransaction(ctx, input)
+	c.NoError(err)
+
+	input.TransactStatements[0].Statement = aws.String(`INSERT INTO pokemons VALUE {'id': '026', 'type': 'electric'}`)
+
+	_, err = cli.ExecuteTransaction(ctx, input)
+
+	var mismatch *ddbtypes.IdempotentParameterMismatchException
+	c.ErrorAs(err, &mismatch)
+}
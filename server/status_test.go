@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func TestServerTableStatus(t *testing.T) {
+	c := require.New(t)
+
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	srv.SetClock(clock)
+	srv.SetTableStatusDelay(time.Minute)
+
+	tableName := aws.String("pokemons")
+	item := map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "25"}}
+
+	created, err := ddb.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:            tableName,
+		KeySchema:            []ddbtypes.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: ddbtypes.KeyTypeHash}},
+		AttributeDefinitions: []ddbtypes.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: ddbtypes.ScalarAttributeTypeS}},
+		BillingMode:          ddbtypes.BillingModePayPerRequest,
+	})
+	c.NoError(err)
+	c.Equal(ddbtypes.TableStatusCreating, created.TableDescription.TableStatus)
+
+	_, err = ddb.PutItem(ctx, &dynamodb.PutItemInput{TableName: tableName, Item: item})
+
+	var notFoundErr *ddbtypes.ResourceNotFoundException
+	c.True(errors.As(err, &notFoundErr))
+
+	_, err = ddb.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: tableName})
+	c.ErrorContains(err, "Table is being created: pokemons")
+
+	clock.Advance(time.Minute)
+
+	_, err = ddb.PutItem(ctx, &dynamodb.PutItemInput{TableName: tableName, Item: item})
+	c.NoError(err)
+
+	_, err = ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{TableName: tableName})
+	c.ErrorContains(err, "At least one of ProvisionedThroughput, BillingMode, UpdateStreamEnabled, GlobalSecondaryIndexUpdates or SSESpecification or ReplicaUpdates is required")
+
+	// an update that changes nothing leaves the table active
+	updated, err := ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{TableName: tableName, BillingMode: ddbtypes.BillingModePayPerRequest})
+	c.NoError(err)
+	c.Equal(ddbtypes.TableStatusActive, updated.TableDescription.TableStatus)
+
+	updated, err = ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{TableName: tableName, TableClass: ddbtypes.TableClassStandardInfrequentAccess})
+	c.NoError(err)
+	c.Equal(ddbtypes.TableStatusUpdating, updated.TableDescription.TableStatus)
+
+	_, err = ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{TableName: tableName, BillingMode: ddbtypes.BillingModePayPerRequest})
+
+	var inUseErr *ddbtypes.ResourceInUseException
+	c.True(errors.As(err, &inUseErr))
+	c.ErrorContains(err, "Table is being updated: pokemons")
+
+	_, err = ddb.GetItem(ctx, &dynamodb.GetItemInput{TableName: tableName, Key: item})
+	c.NoError(err)
+
+	clock.Advance(time.Minute)
+
+	deleted, err := ddb.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: tableName})
+	c.NoError(err)
+	c.Equal(ddbtypes.TableStatusDeleting, deleted.TableDescription.TableStatus)
+
+	described, err := ddb.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: tableName})
+	c.NoError(err)
+	c.Equal(ddbtypes.TableStatusDeleting, described.Table.TableStatus)
+
+	_, err = ddb.GetItem(ctx, &dynamodb.GetItemInput{TableName: tableName, Key: item})
+	c.True(errors.As(err, &notFoundErr))
+
+	_, err = ddb.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:            tableName,
+		KeySchema:            []ddbtypes.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: ddbtypes.KeyTypeHash}},
+		AttributeDefinitions: []ddbtypes.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: ddbtypes.ScalarAttributeTypeS}},
+		BillingMode:          ddbtypes.BillingModePayPerRequest,
+	})
+	c.True(errors.As(err, &inUseErr))
+
+	clock.Advance(time.Minute)
+
+	_, err = ddb.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: tableName})
+	c.True(errors.As(err, &notFoundErr))
+}
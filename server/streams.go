@@ -0,0 +1,432 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+const (
+	defaultRegion    = "us-east-1"
+	defaultAccountID = "000000000000"
+
+	shardIteratorTypeTrimHorizon         = "TRIM_HORIZON"
+	shardIteratorTypeLatest              = "LATEST"
+	shardIteratorTypeAtSequenceNumber    = "AT_SEQUENCE_NUMBER"
+	shardIteratorTypeAfterSequenceNumber = "AFTER_SEQUENCE_NUMBER"
+
+	defaultGetRecordsLimit = 1000
+	maxListStreamsLimit    = 100
+)
+
+// The DynamoDB Streams inputs are hand written because tools/generate_requests only
+// covers the dynamodb service. Field names follow the Streams JSON API.
+
+// ListStreamsInput mirrors DynamoDB Streams ListStreamsInput.
+type ListStreamsInput struct {
+	ExclusiveStartStreamArn *string `json:"ExclusiveStartStreamArn,omitempty"`
+	Limit                   *int32  `json:"Limit,omitempty"`
+	TableName               *string `json:"TableName,omitempty"`
+}
+
+// DescribeStreamInput mirrors DynamoDB Streams DescribeStreamInput.
+type DescribeStreamInput struct {
+	StreamArn             *string `json:"StreamArn,omitempty"`
+	ExclusiveStartShardId *string `json:"ExclusiveStartShardId,omitempty"` //nolint:revive,stylecheck // wire name
+	Limit                 *int32  `json:"Limit,omitempty"`
+}
+
+// GetShardIteratorInput mirrors DynamoDB Streams GetShardIteratorInput.
+type GetShardIteratorInput struct {
+	StreamArn         *string `json:"StreamArn,omitempty"`
+	ShardId           *string `json:"ShardId,omitempty"` //nolint:revive,stylecheck // wire name
+	ShardIteratorType string  `json:"ShardIteratorType,omitempty"`
+	SequenceNumber    *string `json:"SequenceNumber,omitempty"`
+}
+
+// GetRecordsInput mirrors DynamoDB Streams GetRecordsInput.
+type GetRecordsInput struct {
+	ShardIterator *string `json:"ShardIterator,omitempty"`
+	Limit         *int32  `json:"Limit,omitempty"`
+}
+
+// shardIterator is the decoded form of the opaque iterator handed to clients
+type shardIterator struct {
+	streamArn string
+	shardID   string
+	// next is the sequence number of the first record the iterator returns
+	next uint64
+}
+
+func (it shardIterator) encode() string {
+	raw := fmt.Sprintf("%s|%s|%d", it.streamArn, it.shardID, it.next)
+
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeShardIterator(s string) (shardIterator, error) {
+	invalid := &smithy.GenericAPIError{Code: "ValidationException", Message: "Invalid ShardIterator"}
+
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return shardIterator{}, invalid
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return shardIterator{}, invalid
+	}
+
+	next, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return shardIterator{}, invalid
+	}
+
+	return shardIterator{streamArn: parts[0], shardID: parts[1], next: next}, nil
+}
+
+func (c *Client) tableArn(tableName string) string {
+	return fmt.Sprintf("arn:aws:dynamodb:%s:%s:table/%s", c.region, c.accountID, tableName)
+}
+
+func (c *Client) streamArn(tableName string, stream *core.Stream) string {
+	return c.tableArn(tableName) + "/stream/" + stream.Label
+}
+
+// parseStreamArn splits a stream ARN into its table name and stream label
+func parseStreamArn(arn string) (string, string, bool) {
+	_, resource, ok := strings.Cut(arn, ":table/")
+	if !ok {
+		return "", "", false
+	}
+
+	return strings.Cut(resource, "/stream/")
+}
+
+func streamNotFoundError(arn string) error {
+	return &ddbtypes.ResourceNotFoundException{Message: aws.String(fmt.Sprintf("Requested resource not found: Stream: %s not found", arn))}
+}
+
+// getStream resolves a stream ARN, callers must hold c.mu
+func (c *Client) getStream(arn string) (*core.Table, *core.Stream, error) {
+	tableName, label, ok := parseStreamArn(arn)
+	if !ok {
+		return nil, nil, streamNotFoundError(arn)
+	}
+
+	table, ok := c.tables[tableName]
+	if !ok || c.tableArn(tableName)+"/stream/"+label != arn {
+		return nil, nil, streamNotFoundError(arn)
+	}
+
+	stream := table.StreamByLabel(label)
+	if stream == nil {
+		return nil, nil, streamNotFoundError(arn)
+	}
+
+	return table, stream, nil
+}
+
+// applyStreamSpecification enables or disables the table stream as requested
+func (c *Client) applyStreamSpecification(table *core.Table, spec *ddbtypes.StreamSpecification) error {
+	if spec == nil {
+		return nil
+	}
+
+	if !aws.ToBool(spec.StreamEnabled) {
+		return table.DisableStream()
+	}
+
+	if spec.StreamViewType == "" {
+		return &smithy.GenericAPIError{Code: "ValidationException", Message: "StreamViewType is required when StreamEnabled is true"}
+	}
+
+	_, err := table.EnableStream(string(spec.StreamViewType), c.clock.Now())
+
+	return err
+}
+
+// tableDescription maps the table description adding the ARNs, the stream settings and
+// the restore summary
+func (c *Client) tableDescription(tableName string, table *core.Table) *TableDescription {
+	tableDesc := table.Description(tableName)
+	desc := mapTableDescriptionToDDB(tableDesc)
+
+	out := &TableDescription{TableDescription: desc}
+
+	if summary := tableDesc.BillingModeSummary; summary != nil {
+		out.BillingModeSummary = &BillingModeSummary{
+			BillingMode:                       summary.BillingMode,
+			LastUpdateToPayPerRequestDateTime: epochSecondsPtr(summary.LastUpdateToPayPerRequestDateTime),
+		}
+	}
+
+	if summary := tableDesc.TableClassSummary; summary != nil {
+		out.TableClassSummary = &TableClassSummary{
+			TableClass:         summary.TableClass,
+			LastUpdateDateTime: epochSecondsPtr(summary.LastUpdateDateTime),
+		}
+	}
+
+	if summary := table.RestoreSummary; summary != nil {
+		out.RestoreSummary = &RestoreSummary{
+			SourceBackupArn: toStringPtr(summary.SourceBackupArn),
+			SourceTableArn:  toStringPtr(summary.SourceTableArn),
+			RestoreDateTime: epochSeconds(summary.RestoreDateTime),
+		}
+	}
+
+	stream := table.LatestStream()
+	if stream == nil {
+		return out
+	}
+
+	desc.LatestStreamArn = aws.String(c.streamArn(tableName, stream))
+	desc.LatestStreamLabel = aws.String(stream.Label)
+
+	if stream.Enabled {
+		desc.StreamSpecification = &ddbtypes.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: ddbtypes.StreamViewType(stream.ViewType),
+		}
+	}
+
+	return out
+}
+
+// ListStreams returns the streams of every table, or of a single table when TableName is set.
+func (c *Client) ListStreams(ctx context.Context, input *ListStreamsInput) (*ListStreamsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.failureErrFor(aws.ToString(input.TableName), ""); err != nil {
+		return nil, err
+	}
+
+	limit := maxListStreamsLimit
+	if input.Limit != nil {
+		if *input.Limit < 1 || *input.Limit > maxListStreamsLimit {
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value between 1 and %d", *input.Limit, maxListStreamsLimit)}
+		}
+
+		limit = int(*input.Limit)
+	}
+
+	tableNames := make([]string, 0, len(c.tables))
+
+	if name := aws.ToString(input.TableName); name != "" {
+		if _, err := c.getTable(name); err != nil {
+			return nil, err
+		}
+
+		tableNames = append(tableNames, name)
+	} else {
+		for name := range c.tables {
+			tableNames = append(tableNames, name)
+		}
+
+		sort.Strings(tableNames)
+	}
+
+	summaries := []StreamSummary{}
+
+	for _, name := range tableNames {
+		for _, stream := range c.tables[name].Streams() {
+			summaries = append(summaries, StreamSummary{
+				StreamArn:   c.streamArn(name, stream),
+				StreamLabel: stream.Label,
+				TableName:   name,
+			})
+		}
+	}
+
+	start := 0
+
+	if exclusiveStart := aws.ToString(input.ExclusiveStartStreamArn); exclusiveStart != "" {
+		for i, s := range summaries {
+			if s.StreamArn == exclusiveStart {
+				start = i + 1
+
+				break
+			}
+		}
+	}
+
+	end := min(start+limit, len(summaries))
+	output := &ListStreamsOutput{Streams: summaries[start:end]}
+
+	if end < len(summaries) {
+		output.LastEvaluatedStreamArn = aws.String(summaries[end-1].StreamArn)
+	}
+
+	return output, nil
+}
+
+// DescribeStream returns the stream details, every stream has a single shard.
+func (c *Client) DescribeStream(ctx context.Context, input *DescribeStreamInput) (*DescribeStreamOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	arn := aws.ToString(input.StreamArn)
+
+	table, stream, err := c.getStream(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.failureErrFor(table.Name, ""); err != nil {
+		return nil, err
+	}
+
+	status := "ENABLED"
+	if !stream.Enabled {
+		status = "DISABLED"
+	}
+
+	seqRange := SequenceNumberRange{StartingSequenceNumber: core.FormatSequenceNumber(1)}
+	if !stream.Enabled {
+		seqRange.EndingSequenceNumber = aws.String(core.FormatSequenceNumber(stream.LastSequence()))
+	}
+
+	shards := []Shard{{ShardID: stream.ShardID, SequenceNumberRange: seqRange}}
+	if aws.ToString(input.ExclusiveStartShardId) == stream.ShardID {
+		shards = []Shard{}
+	}
+
+	return &DescribeStreamOutput{StreamDescription: StreamDescription{
+		CreationRequestDateTime: float64(stream.CreatedAt.UnixMilli()) / 1000,
+		KeySchema:               mapTypesKeySchema(table.Description(table.Name).KeySchema),
+		Shards:                  shards,
+		StreamArn:               arn,
+		StreamLabel:             stream.Label,
+		StreamStatus:            status,
+		StreamViewType:          stream.ViewType,
+		TableName:               table.Name,
+	}}, nil
+}
+
+// GetShardIterator returns an iterator positioned in the shard of the stream.
+func (c *Client) GetShardIterator(ctx context.Context, input *GetShardIteratorInput) (*GetShardIteratorOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	arn := aws.ToString(input.StreamArn)
+
+	table, stream, err := c.getStream(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.failureErrFor(table.Name, ""); err != nil {
+		return nil, err
+	}
+
+	if aws.ToString(input.ShardId) != stream.ShardID {
+		return nil, &ddbtypes.ResourceNotFoundException{Message: aws.String(fmt.Sprintf("Requested resource not found: Shard does not exist: %s", aws.ToString(input.ShardId)))}
+	}
+
+	it := shardIterator{streamArn: arn, shardID: stream.ShardID}
+
+	switch input.ShardIteratorType {
+	case shardIteratorTypeTrimHorizon:
+		it.next = 1
+	case shardIteratorTypeLatest:
+		it.next = stream.LastSequence() + 1
+	case shardIteratorTypeAtSequenceNumber, shardIteratorTypeAfterSequenceNumber:
+		seq, err := strconv.ParseUint(aws.ToString(input.SequenceNumber), 10, 64)
+		if err != nil || seq == 0 || seq > stream.LastSequence() {
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("Invalid SequenceNumber %s for shard %s", aws.ToString(input.SequenceNumber), stream.ShardID)}
+		}
+
+		it.next = seq
+		if input.ShardIteratorType == shardIteratorTypeAfterSequenceNumber {
+			it.next++
+		}
+	default:
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%s' at 'shardIteratorType' failed to satisfy constraint: Member must satisfy enum value set: [AFTER_SEQUENCE_NUMBER, LATEST, AT_SEQUENCE_NUMBER, TRIM_HORIZON]", input.ShardIteratorType)}
+	}
+
+	return &GetShardIteratorOutput{ShardIterator: aws.String(it.encode())}, nil
+}
+
+// GetRecords returns the stream records from the iterator position. The next iterator
+// is omitted once a disabled stream has been read to the end.
+func (c *Client) GetRecords(ctx context.Context, input *GetRecordsInput) (*GetRecordsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	limit := defaultGetRecordsLimit
+	if input.Limit != nil {
+		if *input.Limit < 1 || *input.Limit > defaultGetRecordsLimit {
+			return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf("1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value between 1 and %d", *input.Limit, defaultGetRecordsLimit)}
+		}
+
+		limit = int(*input.Limit)
+	}
+
+	it, err := decodeShardIterator(aws.ToString(input.ShardIterator))
+	if err != nil {
+		return nil, err
+	}
+
+	table, stream, err := c.getStream(it.streamArn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.failureErrFor(table.Name, ""); err != nil {
+		return nil, err
+	}
+
+	if it.shardID != stream.ShardID {
+		return nil, &ddbtypes.ResourceNotFoundException{Message: aws.String(fmt.Sprintf("Requested resource not found: Shard does not exist: %s", it.shardID))}
+	}
+
+	records := stream.RecordsFrom(it.next, limit)
+	output := &GetRecordsOutput{Records: make([]Record, 0, len(records))}
+
+	for _, r := range records {
+		output.Records = append(output.Records, c.mapStreamRecord(stream, r))
+		it.next = r.Sequence() + 1
+	}
+
+	if stream.Enabled || it.next <= stream.LastSequence() {
+		output.NextShardIterator = aws.String(it.encode())
+	}
+
+	return output, nil
+}
+
+func (c *Client) mapStreamRecord(stream *core.Stream, r core.StreamRecord) Record {
+	record := Record{
+		AwsRegion: c.region,
+		Dynamodb: StreamRecord{
+			ApproximateCreationDateTime: float64(r.CreatedAt.Unix()),
+			Keys:                        mapTypesMapToAttributeValue(r.Keys),
+			NewImage:                    mapTypesMapToAttributeValue(r.NewImage),
+			OldImage:                    mapTypesMapToAttributeValue(r.OldImage),
+			SequenceNumber:              r.SequenceNumber,
+			SizeBytes:                   r.SizeBytes,
+			StreamViewType:              stream.ViewType,
+		},
+		EventID:      r.EventID,
+		EventName:    r.EventName,
+		EventSource:  "aws:dynamodb",
+		EventVersion: "1.1",
+	}
+
+	if r.ServiceInitiated {
+		// the identity DynamoDB reports for the items removed by Time To Live
+		record.UserIdentity = &Identity{PrincipalID: "dynamodb.amazonaws.com", Type: "Service"}
+	}
+
+	return record
+}
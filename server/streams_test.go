@@ -0,0 +1,352 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+type streamsAPIError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// callStreams posts a DynamoDB Streams JSON API request to the server and decodes the
+// response into out, an error response is returned as a streamsAPIError.
+func callStreams(t *testing.T, url, op string, input, out any) *streamsAPIError {
+	t.Helper()
+
+	body, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "DynamoDBStreams_20120810."+op)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, resp.Body.Close()) }()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &streamsAPIError{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(apiErr))
+
+		return apiErr
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+
+	return nil
+}
+
+func createStreamTable(t *testing.T, ddb *dynamodb.Client, name string, viewType ddbtypes.StreamViewType) *ddbtypes.TableDescription {
+	t.Helper()
+
+	out, err := ddb.CreateTable(context.Background(), &dynamodb.CreateTableInput{
+		TableName:            aws.String(name),
+		AttributeDefinitions: []ddbtypes.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: ddbtypes.ScalarAttributeTypeS}},
+		KeySchema:            []ddbtypes.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: ddbtypes.KeyTypeHash}},
+		BillingMode:          ddbtypes.BillingModePayPerRequest,
+		StreamSpecification: &ddbtypes.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: viewType,
+		},
+	})
+	require.NoError(t, err)
+
+	return out.TableDescription
+}
+
+func readAllRecords(t *testing.T, url, streamArn string) []Record {
+	t.Helper()
+
+	var desc DescribeStreamOutput
+	require.Nil(t, callStreams(t, url, "DescribeStream", DescribeStreamInput{StreamArn: aws.String(streamArn)}, &desc))
+	require.Len(t, desc.StreamDescription.Shards, 1)
+
+	var it GetShardIteratorOutput
+	require.Nil(t, callStreams(t, url, "GetShardIterator", GetShardIteratorInput{
+		StreamArn:         aws.String(streamArn),
+		ShardId:           aws.String(desc.StreamDescription.Shards[0].ShardID),
+		ShardIteratorType: shardIteratorTypeTrimHorizon,
+	}, &it))
+
+	var records GetRecordsOutput
+	require.Nil(t, callStreams(t, url, "GetRecords", GetRecordsInput{ShardIterator: it.ShardIterator}, &records))
+
+	return records.Records
+}
+
+func TestStreamsRecordWritePaths(t *testing.T) {
+	c := require.New(t)
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+
+	created := createStreamTable(t, ddb, "orders", ddbtypes.StreamViewTypeNewAndOldImages)
+	c.NotNil(created.LatestStreamArn)
+	c.Equal("arn:aws:dynamodb:us-east-1:000000000000:table/orders/stream/"+aws.ToString(created.LatestStreamLabel), aws.ToString(created.LatestStreamArn))
+
+	described, err := ddb.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String("orders")})
+	c.NoError(err)
+	c.Equal(created.LatestStreamArn, described.Table.LatestStreamArn)
+	c.Equal(ddbtypes.StreamViewTypeNewAndOldImages, described.Table.StreamSpecification.StreamViewType)
+
+	streamArn := aws.ToString(created.LatestStreamArn)
+
+	_, err = ddb.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String("orders"),
+		Item: map[string]ddbtypes.AttributeValue{
+			"id":     &ddbtypes.AttributeValueMemberS{Value: "o1"},
+			"status": &ddbtypes.AttributeValueMemberS{Value: "new"},
+		},
+	})
+	c.NoError(err)
+
+	_, err = ddb.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String("orders"),
+		Key:                       map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "o1"}},
+		UpdateExpression:          aws.String("SET #s = :s"),
+		ExpressionAttributeNames:  map[string]string{"#s": "status"},
+		ExpressionAttributeValues: map[string]ddbtypes.AttributeValue{":s": &ddbtypes.AttributeValueMemberS{Value: "paid"}},
+	})
+	c.NoError(err)
+
+	_, err = ddb.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]ddbtypes.WriteRequest{
+			"orders": {
+				{PutRequest: &ddbtypes.PutRequest{Item: map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "o2"}}}},
+				{DeleteRequest: &ddbtypes.DeleteRequest{Key: map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "o1"}}}},
+			},
+		},
+	})
+	c.NoError(err)
+
+	_, err = ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []ddbtypes.TransactWriteItem{
+			{Put: &ddbtypes.Put{TableName: aws.String("orders"), Item: map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "o3"}}}},
+		},
+	})
+	c.NoError(err)
+
+	// a cancelled transaction leaves no records behind
+	_, err = ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []ddbtypes.TransactWriteItem{
+			{Put: &ddbtypes.Put{TableName: aws.String("orders"), Item: map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "o4"}}}},
+			{ConditionCheck: &ddbtypes.ConditionCheck{
+				TableName:           aws.String("orders"),
+				Key:                 map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "o1"}},
+				ConditionExpression: aws.String("attribute_exists(id)"),
+			}},
+		},
+	})
+	c.Error(err)
+
+	records := readAllRecords(t, ts.URL, streamArn)
+	c.Len(records, 5)
+
+	events := make([]string, 0, len(records))
+	for _, r := range records {
+		events = append(events, r.EventName)
+		c.Equal("aws:dynamodb", r.EventSource)
+		c.Equal("us-east-1", r.AwsRegion)
+		c.Equal("NEW_AND_OLD_IMAGES", r.Dynamodb.StreamViewType)
+		c.Positive(r.Dynamodb.ApproximateCreationDateTime)
+	}
+
+	c.Equal([]string{"INSERT", "MODIFY", "INSERT", "REMOVE", "INSERT"}, events)
+	c.Equal("new", aws.ToString(records[1].Dynamodb.OldImage["status"].S))
+	c.Equal("paid", aws.ToString(records[1].Dynamodb.NewImage["status"].S))
+	c.Equal("o1", aws.ToString(records[3].Dynamodb.Keys["id"].S))
+	c.Nil(records[3].Dynamodb.NewImage)
+	c.Less(records[0].Dynamodb.SequenceNumber, records[1].Dynamodb.SequenceNumber)
+
+	var list ListStreamsOutput
+	c.Nil(callStreams(t, ts.URL, "ListStreams", ListStreamsInput{TableName: aws.String("orders")}, &list))
+	c.Len(list.Streams, 1)
+	c.Equal(streamArn, list.Streams[0].StreamArn)
+}
+
+func TestStreamsFollowClock(t *testing.T) {
+	c := require.New(t)
+
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	srv.SetClock(clock)
+
+	created := createStreamTable(t, ddb, "orders", ddbtypes.StreamViewTypeKeysOnly)
+	c.Equal("2024-05-01T10:00:00.000", aws.ToString(created.LatestStreamLabel))
+
+	clock.Advance(time.Hour)
+
+	_, err := ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{TableName: aws.String("orders"), StreamSpecification: &ddbtypes.StreamSpecification{StreamEnabled: aws.Bool(false)}})
+	c.NoError(err)
+
+	updated, err := ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:           aws.String("orders"),
+		StreamSpecification: &ddbtypes.StreamSpecification{StreamEnabled: aws.Bool(true), StreamViewType: ddbtypes.StreamViewTypeNewImage},
+	})
+	c.NoError(err)
+	c.Equal("2024-05-01T11:00:00.000", aws.ToString(updated.TableDescription.LatestStreamLabel))
+}
+
+func TestStreamsShardIterators(t *testing.T) {
+	c := require.New(t)
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+
+	streamArn := aws.ToString(createStreamTable(t, ddb, "events", ddbtypes.StreamViewTypeKeysOnly).LatestStreamArn)
+
+	put := func(id string) {
+		_, err := ddb.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String("events"),
+			Item:      map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: id}},
+		})
+		c.NoError(err)
+	}
+
+	iterator := func(iteratorType string, seq *string) *string {
+		var desc DescribeStreamOutput
+		c.Nil(callStreams(t, ts.URL, "DescribeStream", DescribeStreamInput{StreamArn: aws.String(streamArn)}, &desc))
+
+		var out GetShardIteratorOutput
+		c.Nil(callStreams(t, ts.URL, "GetShardIterator", GetShardIteratorInput{
+			StreamArn:         aws.String(streamArn),
+			ShardId:           aws.String(desc.StreamDescription.Shards[0].ShardID),
+			ShardIteratorType: iteratorType,
+			SequenceNumber:    seq,
+		}, &out))
+
+		return out.ShardIterator
+	}
+
+	put("e1")
+	put("e2")
+
+	var desc DescribeStreamOutput
+	c.Nil(callStreams(t, ts.URL, "DescribeStream", DescribeStreamInput{StreamArn: aws.String(streamArn)}, &desc))
+	c.Equal("ENABLED", desc.StreamDescription.StreamStatus)
+	c.Equal("KEYS_ONLY", desc.StreamDescription.StreamViewType)
+	c.Equal("events", desc.StreamDescription.TableName)
+	c.Nil(desc.StreamDescription.Shards[0].SequenceNumberRange.EndingSequenceNumber)
+
+	latest := iterator(shardIteratorTypeLatest, nil)
+
+	put("e3")
+
+	var out GetRecordsOutput
+	c.Nil(callStreams(t, ts.URL, "GetRecords", GetRecordsInput{ShardIterator: latest}, &out))
+	c.Len(out.Records, 1)
+	c.Equal("e3", aws.ToString(out.Records[0].Dynamodb.Keys["id"].S))
+	c.Nil(out.Records[0].Dynamodb.NewImage)
+
+	// the next iterator waits for new records on an enabled stream
+	next := out.NextShardIterator
+	out = GetRecordsOutput{}
+	c.Nil(callStreams(t, ts.URL, "GetRecords", GetRecordsInput{ShardIterator: next}, &out))
+	c.Empty(out.Records)
+	c.NotNil(out.NextShardIterator)
+
+	first := readAllRecords(t, ts.URL, streamArn)[0]
+
+	out = GetRecordsOutput{}
+	c.Nil(callStreams(t, ts.URL, "GetRecords", GetRecordsInput{
+		ShardIterator: iterator(shardIteratorTypeAfterSequenceNumber, aws.String(first.Dynamodb.SequenceNumber)),
+		Limit:         aws.Int32(1),
+	}, &out))
+	c.Len(out.Records, 1)
+	c.Equal("e2", aws.ToString(out.Records[0].Dynamodb.Keys["id"].S))
+
+	out = GetRecordsOutput{}
+	c.Nil(callStreams(t, ts.URL, "GetRecords", GetRecordsInput{
+		ShardIterator: iterator(shardIteratorTypeAtSequenceNumber, aws.String(first.Dynamodb.SequenceNumber)),
+	}, &out))
+	c.Len(out.Records, 3)
+
+	apiErr := callStreams(t, ts.URL, "GetRecords", GetRecordsInput{ShardIterator: aws.String("bogus")}, &out)
+	c.Equal(&streamsAPIError{Type: "ValidationException", Message: "Invalid ShardIterator"}, apiErr)
+
+	apiErr = callStreams(t, ts.URL, "GetShardIterator", GetShardIteratorInput{
+		StreamArn:         aws.String(streamArn),
+		ShardId:           aws.String("shardId-unknown"),
+		ShardIteratorType: shardIteratorTypeTrimHorizon,
+	}, &GetShardIteratorOutput{})
+	c.Equal("ResourceNotFoundException", apiErr.Type)
+
+	// disabling the stream closes the shard
+	updated, err := ddb.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:           aws.String("events"),
+		StreamSpecification: &ddbtypes.StreamSpecification{StreamEnabled: aws.Bool(false)},
+	})
+	c.NoError(err)
+	c.Nil(updated.TableDescription.StreamSpecification)
+	c.Equal(streamArn, aws.ToString(updated.TableDescription.LatestStreamArn))
+
+	desc = DescribeStreamOutput{}
+	c.Nil(callStreams(t, ts.URL, "DescribeStream", DescribeStreamInput{StreamArn: aws.String(streamArn)}, &desc))
+	c.Equal("DISABLED", desc.StreamDescription.StreamStatus)
+	c.Equal(aws.String("000000000000000000003"), desc.StreamDescription.Shards[0].SequenceNumberRange.EndingSequenceNumber)
+
+	out = GetRecordsOutput{}
+	c.Nil(callStreams(t, ts.URL, "GetRecords", GetRecordsInput{ShardIterator: iterator(shardIteratorTypeTrimHorizon, nil)}, &out))
+	c.Len(out.Records, 3)
+	c.Nil(out.NextShardIterator)
+
+	apiErr = callStreams(t, ts.URL, "DescribeStream", DescribeStreamInput{
+		StreamArn: aws.String("arn:aws:dynamodb:us-east-1:000000000000:table/missing/stream/x"),
+	}, &DescribeStreamOutput{})
+	c.Equal("ResourceNotFoundException", apiErr.Type)
+}
+
+func TestStreamsListStreamsPaging(t *testing.T) {
+	c := require.New(t)
+
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	ddb := newTestDynamoClient(t, ts.URL)
+
+	for _, name := range []string{"c", "a", "b"} {
+		createStreamTable(t, ddb, name, ddbtypes.StreamViewTypeNewImage)
+	}
+
+	var page ListStreamsOutput
+	c.Nil(callStreams(t, ts.URL, "ListStreams", ListStreamsInput{Limit: aws.Int32(2)}, &page))
+	c.Len(page.Streams, 2)
+	c.Equal("a", page.Streams[0].TableName)
+	c.Equal("b", page.Streams[1].TableName)
+	c.Equal(page.Streams[1].StreamArn, aws.ToString(page.LastEvaluatedStreamArn))
+
+	var rest ListStreamsOutput
+	c.Nil(callStreams(t, ts.URL, "ListStreams", ListStreamsInput{ExclusiveStartStreamArn: page.LastEvaluatedStreamArn}, &rest))
+	c.Len(rest.Streams, 1)
+	c.Equal("c", rest.Streams[0].TableName)
+	c.Nil(rest.LastEvaluatedStreamArn)
+
+	apiErr := callStreams(t, ts.URL, "ListStreams", ListStreamsInput{TableName: aws.String("missing")}, &rest)
+	c.Equal("ResourceNotFoundException", apiErr.Type)
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+func mapTags(in []ddbtypes.Tag) []core.Tag {
+	out := make([]core.Tag, 0, len(in))
+
+	for _, tag := range in {
+		out = append(out, core.Tag{Key: aws.ToString(tag.Key), Value: aws.ToString(tag.Value)})
+	}
+
+	return out
+}
+
+// getTaggedTable resolves the table ARN given to the tagging operations, callers must hold c.mu
+func (c *Client) getTaggedTable(arn string) (*core.Table, error) {
+	if !strings.HasPrefix(arn, "arn:aws:dynamodb:") || !strings.Contains(arn, ":table/") {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "Invalid TableArn: Invalid ResourceArn provided as input " + arn}
+	}
+
+	tableName, ok := strings.CutPrefix(arn, c.tableArn(""))
+	if ok && !strings.Contains(tableName, "/") {
+		if table, found := c.tables[tableName]; found {
+			return table, nil
+		}
+	}
+
+	// the typo in ResourcArn is part of the message DynamoDB returns
+	return nil, &ddbtypes.ResourceNotFoundException{Message: aws.String("Requested resource not found: ResourcArn: " + arn + " not found")}
+}
+
+// TagResource adds tags to a table.
+func (c *Client) TagResource(ctx context.Context, input *TagResourceInput) (*TagResourceOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	arn := aws.ToString(input.ResourceArn)
+	_, tableName, _ := strings.Cut(arn, ":table/")
+
+	if err := c.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	table, err := c.getTaggedTable(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := table.TagResource(mapTags(input.Tags)); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	return &TagResourceOutput{}, nil
+}
+
+// UntagResource removes tags from a table.
+func (c *Client) UntagResource(ctx context.Context, input *UntagResourceInput) (*UntagResourceOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	arn := aws.ToString(input.ResourceArn)
+	_, tableName, _ := strings.Cut(arn, ":table/")
+
+	if err := c.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	table, err := c.getTaggedTable(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := table.UntagResource(input.TagKeys); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	return &UntagResourceOutput{}, nil
+}
+
+// ListTagsOfResource returns the tags of a table sorted by key, all of them fit in a
+// single page.
+func (c *Client) ListTagsOfResource(ctx context.Context, input *ListTagsOfResourceInput) (*ListTagsOfResourceOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	arn := aws.ToString(input.ResourceArn)
+	_, tableName, _ := strings.Cut(arn, ":table/")
+
+	if err := c.failureErrFor(tableName, ""); err != nil {
+		return nil, err
+	}
+
+	table, err := c.getTaggedTable(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ListTagsOfResourceOutput{Tags: []ddbtypes.Tag{}}
+
+	for _, tag := range table.Tags() {
+		output.Tags = append(output.Tags, ddbtypes.Tag{Key: aws.String(tag.Key), Value: aws.String(tag.Value)})
+	}
+
+	return output, nil
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTags(t *testing.T) {
+	c := require.New(t)
+
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+
+	created, err := ddb.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:            aws.String("moves"),
+		KeySchema:            []ddbtypes.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: ddbtypes.KeyTypeHash}},
+		AttributeDefinitions: []ddbtypes.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: ddbtypes.ScalarAttributeTypeS}},
+		BillingMode:          ddbtypes.BillingModePayPerRequest,
+		Tags:                 []ddbtypes.Tag{{Key: aws.String("team"), Value: aws.String("rocket")}},
+	})
+	c.NoError(err)
+	c.Equal("arn:aws:dynamodb:us-east-1:000000000000:table/moves", aws.ToString(created.TableDescription.TableArn))
+
+	srv.SetAccount("eu-west-1", "123456789012")
+	createBackupTestTable(t, ddb)
+
+	described, err := ddb.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String("pokemons")})
+	c.NoError(err)
+
+	tableArn := described.Table.TableArn
+	c.Equal("arn:aws:dynamodb:eu-west-1:123456789012:table/pokemons", aws.ToString(tableArn))
+	c.Equal(aws.ToString(tableArn)+"/index/by-type", aws.ToString(described.Table.GlobalSecondaryIndexes[0].IndexArn))
+
+	listed, err := ddb.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{ResourceArn: aws.String("arn:aws:dynamodb:eu-west-1:123456789012:table/moves")})
+	c.NoError(err)
+	c.Equal("rocket", aws.ToString(listed.Tags[0].Value))
+
+	listed, err = ddb.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{ResourceArn: tableArn})
+	c.NoError(err)
+	c.Empty(listed.Tags)
+
+	_, err = ddb.TagResource(ctx, &dynamodb.TagResourceInput{
+		ResourceArn: tableArn,
+		Tags: []ddbtypes.Tag{
+			{Key: aws.String("owner"), Value: aws.String("oak")},
+			{Key: aws.String("cost-center"), Value: aws.String("kanto")},
+		},
+	})
+	c.NoError(err)
+
+	_, err = ddb.UntagResource(ctx, &dynamodb.UntagResourceInput{ResourceArn: tableArn, TagKeys: []string{"owner"}})
+	c.NoError(err)
+
+	listed, err = ddb.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{ResourceArn: tableArn})
+	c.NoError(err)
+	c.Equal([]ddbtypes.Tag{{Key: aws.String("cost-center"), Value: aws.String("kanto")}}, listed.Tags)
+
+	_, err = ddb.TagResource(ctx, &dynamodb.TagResourceInput{ResourceArn: tableArn, Tags: []ddbtypes.Tag{{Key: aws.String("aws:cloudformation:stack-name"), Value: aws.String("x")}}})
+	c.ErrorContains(err, "reserved")
+
+	_, err = ddb.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{ResourceArn: aws.String("arn:aws:dynamodb:us-east-1:000000000000:table/pokemons")})
+
+	var notFoundErr *ddbtypes.ResourceNotFoundException
+	c.True(errors.As(err, &notFoundErr))
+
+	_, err = ddb.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{ResourceArn: aws.String("pokemons")})
+	c.ErrorContains(err, "Invalid TableArn")
+}
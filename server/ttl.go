@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/truora/minidyn/core"
+)
+
+// UpdateTimeToLive enables or disables the Time To Live of a table.
+func (c *Client) UpdateTimeToLive(ctx context.Context, input *UpdateTimeToLiveInput) (*UpdateTimeToLiveOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	table, err := c.getTable(aws.ToString(input.TableName))
+	if err != nil {
+		return nil, err
+	}
+
+	spec := input.TimeToLiveSpecification
+	if spec == nil || spec.Enabled == nil {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "1 validation error detected: Value null at 'timeToLiveSpecification' failed to satisfy constraint: Member must not be null"}
+	}
+
+	if err := table.UpdateTimeToLive(aws.ToBool(spec.Enabled), aws.ToString(spec.AttributeName)); err != nil {
+		return nil, mapKnownError(err)
+	}
+
+	return &UpdateTimeToLiveOutput{TimeToLiveSpecification: spec}, nil
+}
+
+// DescribeTimeToLive returns the Time To Live settings of a table.
+func (c *Client) DescribeTimeToLive(ctx context.Context, input *DescribeTimeToLiveInput) (*DescribeTimeToLiveOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	table, err := c.getTable(aws.ToString(input.TableName))
+	if err != nil {
+		return nil, err
+	}
+
+	attributeName, status := table.TimeToLive()
+
+	desc := &ddbtypes.TimeToLiveDescription{TimeToLiveStatus: ddbtypes.TimeToLiveStatus(status)}
+	if attributeName != "" {
+		desc.AttributeName = aws.String(attributeName)
+	}
+
+	return &DescribeTimeToLiveOutput{TimeToLiveDescription: desc}, nil
+}
+
+func (c *Client) setClock(clock core.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clock = clock
+
+	for _, table := range c.tables {
+		table.Clock = clock
+	}
+}
+
+func (c *Client) setSweepExpiredOnAccess(sweep bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sweepExpiredOnAccess = sweep
+}
+
+// sweepExpiredItems deletes the expired items of every table
+func (c *Client) sweepExpiredItems() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	swept := 0
+	for _, table := range c.tables {
+		swept += table.SweepExpired(c.clock.Now())
+	}
+
+	return swept
+}
+
+// sweepOnAccess deletes the expired items of a table before it is used when the client
+// sweeps expired items on access
+func (c *Client) sweepOnAccess(table *core.Table) {
+	if !c.sweepExpiredOnAccess {
+		return
+	}
+
+	table.SweepExpired(c.clock.Now())
+}
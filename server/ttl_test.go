@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/require"
+	"github.com/truora/minidyn/core"
+)
+
+func putSession(t *testing.T, ddb *dynamodb.Client, id string, expiresAt time.Time) {
+	t.Helper()
+
+	_, err := ddb.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String("sessions"),
+		Item: map[string]ddbtypes.AttributeValue{
+			"id":         &ddbtypes.AttributeValueMemberS{Value: id},
+			"expires_at": &ddbtypes.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestServerTimeToLive(t *testing.T) {
+	c := require.New(t)
+
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	srv.SetClock(clock)
+	srv.SweepExpiredOnAccess(true)
+
+	streamArn := aws.ToString(createStreamTable(t, ddb, "sessions", ddbtypes.StreamViewTypeKeysOnly).LatestStreamArn)
+
+	described, err := ddb.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: aws.String("sessions")})
+	c.NoError(err)
+	c.Equal(ddbtypes.TimeToLiveStatusDisabled, described.TimeToLiveDescription.TimeToLiveStatus)
+
+	updated, err := ddb.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName:               aws.String("sessions"),
+		TimeToLiveSpecification: &ddbtypes.TimeToLiveSpecification{AttributeName: aws.String("expires_at"), Enabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+	c.True(aws.ToBool(updated.TimeToLiveSpecification.Enabled))
+
+	_, err = ddb.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName:               aws.String("sessions"),
+		TimeToLiveSpecification: &ddbtypes.TimeToLiveSpecification{AttributeName: aws.String("expires_at"), Enabled: aws.Bool(true)},
+	})
+
+	var apiErr smithy.APIError
+	c.True(errors.As(err, &apiErr))
+	c.Equal("ValidationException", apiErr.ErrorCode())
+
+	described, err = ddb.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: aws.String("sessions")})
+	c.NoError(err)
+	c.Equal(ddbtypes.TimeToLiveStatusEnabled, described.TimeToLiveDescription.TimeToLiveStatus)
+	c.Equal("expires_at", aws.ToString(described.TimeToLiveDescription.AttributeName))
+
+	putSession(t, ddb, "s1", clock.Now().Add(time.Minute))
+	putSession(t, ddb, "s2", clock.Now().Add(time.Hour))
+
+	clock.Advance(2 * time.Minute)
+
+	scan, err := ddb.Scan(ctx, &dynamodb.ScanInput{TableName: aws.String("sessions")})
+	c.NoError(err)
+	c.Len(scan.Items, 1)
+	c.Equal(&ddbtypes.AttributeValueMemberS{Value: "s2"}, scan.Items[0]["id"])
+
+	records := readAllRecords(t, ts.URL, streamArn)
+	c.Len(records, 3)
+	c.Nil(records[0].UserIdentity)
+	c.Equal("REMOVE", records[2].EventName)
+	c.Equal("s1", aws.ToString(records[2].Dynamodb.Keys["id"].S))
+	c.Equal(&Identity{PrincipalID: "dynamodb.amazonaws.com", Type: "Service"}, records[2].UserIdentity)
+}
+
+func TestServerSweepExpiredItems(t *testing.T) {
+	c := require.New(t)
+
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	ddb := newTestDynamoClient(t, ts.URL)
+	clock := core.NewManualClock(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	srv.SetClock(clock)
+
+	makeBasicTable(t, ddb, "sessions", "id")
+
+	_, err := ddb.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName:               aws.String("sessions"),
+		TimeToLiveSpecification: &ddbtypes.TimeToLiveSpecification{AttributeName: aws.String("expires_at"), Enabled: aws.Bool(true)},
+	})
+	c.NoError(err)
+
+	putSession(t, ddb, "s1", clock.Now().Add(time.Minute))
+
+	clock.Advance(time.Hour)
+
+	key := map[string]ddbtypes.AttributeValue{"id": &ddbtypes.AttributeValueMemberS{Value: "s1"}}
+
+	out, err := ddb.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String("sessions"), Key: key})
+	c.NoError(err)
+	c.NotEmpty(out.Item)
+
+	// write transactions never sweep, even when the tables are swept on access
+	srv.SweepExpiredOnAccess(true)
+
+	_, err = ddb.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []ddbtypes.TransactWriteItem{{ConditionCheck: &ddbtypes.ConditionCheck{
+			TableName:           aws.String("sessions"),
+			Key:                 key,
+			ConditionExpression: aws.String("attribute_exists(id)"),
+		}}},
+	})
+	c.NoError(err)
+
+	c.Equal(1, srv.SweepExpiredItems())
+
+	out, err = ddb.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String("sessions"), Key: key})
+	c.NoError(err)
+	c.Empty(out.Item)
+}
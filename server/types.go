@@ -1,5 +1,10 @@
 package server
 
+import (
+	"math"
+	"time"
+)
+
 // AttributeValue is a concrete representation of DynamoDB's AttributeValue
 // that works with standard json encoding/decoding.
 type AttributeValue struct {
@@ -14,3 +19,14 @@ type AttributeValue struct {
 	S    *string                    `json:"S,omitempty"`
 	SS   []*string                  `json:"SS,omitempty"`
 }
+
+// EpochTime is a timestamp encoded the way the DynamoDB JSON protocol does, as
+// seconds since the Unix epoch with an optional fractional part.
+type EpochTime float64
+
+// Time returns the timestamp as a time.Time.
+func (e EpochTime) Time() time.Time {
+	sec, frac := math.Modf(float64(e))
+
+	return time.Unix(int64(sec), int64(frac*float64(time.Second)))
+}
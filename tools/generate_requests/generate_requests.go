@@ -11,6 +11,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -22,6 +23,7 @@ var supportedInputs = []reflect.Type{
 	reflect.TypeFor[dynamodb.DeleteTableInput](),
 	reflect.TypeFor[dynamodb.UpdateTableInput](),
 	reflect.TypeFor[dynamodb.DescribeTableInput](),
+	reflect.TypeFor[dynamodb.ListTablesInput](),
 	reflect.TypeFor[dynamodb.PutItemInput](),
 	reflect.TypeFor[dynamodb.DeleteItemInput](),
 	reflect.TypeFor[dynamodb.UpdateItemInput](),
@@ -32,9 +34,37 @@ var supportedInputs = []reflect.Type{
 	reflect.TypeFor[dynamodb.BatchGetItemInput](),
 	reflect.TypeFor[dynamodb.TransactWriteItemsInput](),
 	reflect.TypeFor[dynamodb.TransactGetItemsInput](),
+	reflect.TypeFor[dynamodb.UpdateTimeToLiveInput](),
+	reflect.TypeFor[dynamodb.DescribeTimeToLiveInput](),
+	reflect.TypeFor[dynamodb.ExecuteStatementInput](),
+	reflect.TypeFor[dynamodb.BatchExecuteStatementInput](),
+	reflect.TypeFor[dynamodb.ExecuteTransactionInput](),
+	reflect.TypeFor[dynamodb.CreateBackupInput](),
+	reflect.TypeFor[dynamodb.DescribeBackupInput](),
+	reflect.TypeFor[dynamodb.ListBackupsInput](),
+	reflect.TypeFor[dynamodb.DeleteBackupInput](),
+	reflect.TypeFor[dynamodb.RestoreTableFromBackupInput](),
+	reflect.TypeFor[dynamodb.UpdateContinuousBackupsInput](),
+	reflect.TypeFor[dynamodb.DescribeContinuousBackupsInput](),
+	reflect.TypeFor[dynamodb.RestoreTableToPointInTimeInput](),
+	reflect.TypeFor[dynamodb.ExportTableToPointInTimeInput](),
+	reflect.TypeFor[dynamodb.DescribeExportInput](),
+	reflect.TypeFor[dynamodb.ListExportsInput](),
+	reflect.TypeFor[dynamodb.ImportTableInput](),
+	reflect.TypeFor[dynamodb.DescribeImportInput](),
+	reflect.TypeFor[dynamodb.ListImportsInput](),
+	reflect.TypeFor[dynamodb.TagResourceInput](),
+	reflect.TypeFor[dynamodb.UntagResourceInput](),
+	reflect.TypeFor[dynamodb.ListTagsOfResourceInput](),
+	reflect.TypeFor[dynamodb.UpdateContributorInsightsInput](),
+	reflect.TypeFor[dynamodb.DescribeContributorInsightsInput](),
+	reflect.TypeFor[dynamodb.ListContributorInsightsInput](),
 }
 
-var attributeValueType = reflect.TypeFor[ddbtypes.AttributeValue]()
+var (
+	attributeValueType = reflect.TypeFor[ddbtypes.AttributeValue]()
+	timeType           = reflect.TypeFor[time.Time]()
+)
 
 type generated struct {
 	order []string
@@ -157,6 +187,11 @@ func renderType(gen *generated, t reflect.Type) (string, []string) { //nolint:go
 		return "*AttributeValue", nil
 	}
 
+	// timestamps travel as epoch seconds, which time.Time can't decode.
+	if t == timeType {
+		return "EpochTime", nil
+	}
+
 	// handle named types from dynamodb packages (including enums) early.
 	if t.PkgPath() == "github.com/aws/aws-sdk-go-v2/service/dynamodb/types" {
 		if t.Kind() == reflect.Struct && needsGeneration(t) {
@@ -234,7 +269,7 @@ func needsGeneration(t reflect.Type) bool {
 			continue
 		}
 
-		if containsAttributeValue(f.Type) {
+		if containsConvertedType(f.Type) {
 			return true
 		}
 	}
@@ -242,15 +277,21 @@ func needsGeneration(t reflect.Type) bool {
 	return false
 }
 
-func containsAttributeValue(t reflect.Type) bool {
+// containsConvertedType reports whether t holds an AttributeValue or a timestamp, which are
+// rendered with our own JSON-friendly types.
+func containsConvertedType(t reflect.Type) bool {
+	if t == timeType {
+		return true
+	}
+
 	switch t.Kind() {
 	case reflect.Pointer, reflect.Slice, reflect.Map:
-		return containsAttributeValue(t.Elem())
+		return containsConvertedType(t.Elem())
 	case reflect.Interface:
 		return t == attributeValueType
 	case reflect.Struct:
 		for field := range t.Fields() {
-			if containsAttributeValue(field.Type) {
+			if containsConvertedType(field.Type) {
 				return true
 			}
 		}
@@ -30,4 +30,7 @@ func TestRunGeneratesRequestsFile(t *testing.T) {
 	if !strings.Contains(content, "*AttributeValue") {
 		t.Fatalf("expected concrete AttributeValue usage")
 	}
+	if !strings.Contains(content, "*EpochTime") {
+		t.Fatalf("expected timestamps to use EpochTime")
+	}
 }
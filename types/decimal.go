@@ -0,0 +1,259 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// decimalMaxDigits is the number of significant digits DynamoDB keeps for a number
+	decimalMaxDigits = 38
+	// decimalMinExponent and decimalMaxExponent bound the exponent of 0.digits * 10^exp,
+	// covering magnitudes from 1E-130 to 9.9999999999999999999999999999999999999E+125
+	decimalMinExponent = -129
+	decimalMaxExponent = 126
+)
+
+//nolint:revive,staticcheck,stylecheck,ST1005 // DynamoDB ValidationException wording (parity)
+var (
+	// ErrInvalidNumber is returned when a value cannot be parsed as a DynamoDB number
+	ErrInvalidNumber = errors.New("A value provided cannot be converted into a number")
+	// ErrNumberPrecision is returned when a number has more than 38 significant digits
+	ErrNumberPrecision = errors.New("Attempting to store more than 38 significant digits in a Number")
+	// ErrNumberOverflow is returned when the magnitude of a number is above 9.99E+125
+	ErrNumberOverflow = errors.New("Number overflow. Attempting to store a number with magnitude larger than supported range")
+	// ErrNumberUnderflow is returned when the magnitude of a non zero number is below 1E-130
+	ErrNumberUnderflow = errors.New("Number underflow. Attempting to store a number with magnitude smaller than supported range")
+)
+
+// Decimal is an exact decimal number with the precision and range of the DynamoDB N type.
+// Decimals are kept normalized, so numerically equal values compare equal with ==
+// and can be used as map keys.
+type Decimal struct {
+	neg bool
+	// digits holds the significant digits without leading or trailing zeros, empty for zero
+	digits string
+	// exp is the exponent of the value 0.digits * 10^exp
+	exp int
+}
+
+// ParseDecimal parses a DynamoDB number, it fails when the value is not a number or
+// it exceeds the DynamoDB precision or range.
+func ParseDecimal(s string) (Decimal, error) {
+	rest := s
+	d := Decimal{}
+
+	if rest != "" && (rest[0] == '-' || rest[0] == '+') {
+		d.neg = rest[0] == '-'
+		rest = rest[1:]
+	}
+
+	if pos := strings.IndexAny(rest, "eE"); pos >= 0 {
+		e, err := strconv.Atoi(rest[pos+1:])
+		if err != nil {
+			return Decimal{}, ErrInvalidNumber
+		}
+
+		d.exp = e
+		rest = rest[:pos]
+	}
+
+	intPart, fracPart, _ := strings.Cut(rest, ".")
+	if intPart == "" && fracPart == "" || !isDecimalDigits(intPart) || !isDecimalDigits(fracPart) {
+		return Decimal{}, ErrInvalidNumber
+	}
+
+	d.digits = intPart + fracPart
+	d.exp += len(intPart)
+
+	return d.normalize()
+}
+
+// NewDecimalFromInt returns the decimal representation of n
+func NewDecimalFromInt(n int64) Decimal {
+	d, _ := ParseDecimal(strconv.FormatInt(n, 10))
+
+	return d
+}
+
+func isDecimalDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalize trims the zeros around the digits and validates the DynamoDB limits
+func (d Decimal) normalize() (Decimal, error) {
+	trimmed := strings.TrimLeft(d.digits, "0")
+	d.exp -= len(d.digits) - len(trimmed)
+	d.digits = strings.TrimRight(trimmed, "0")
+
+	if d.digits == "" {
+		return Decimal{}, nil
+	}
+
+	switch {
+	case len(d.digits) > decimalMaxDigits:
+		return Decimal{}, ErrNumberPrecision
+	case d.exp > decimalMaxExponent:
+		return Decimal{}, ErrNumberOverflow
+	case d.exp < decimalMinExponent:
+		return Decimal{}, ErrNumberUnderflow
+	}
+
+	return d, nil
+}
+
+// Parts returns the sign, the significant digits and the exponent of the value
+// 0.digits * 10^exp. Zero is reported with empty digits.
+func (d Decimal) Parts() (bool, string, int) {
+	return d.neg, d.digits, d.exp
+}
+
+// IsZero reports whether the decimal is zero
+func (d Decimal) IsZero() bool {
+	return d.digits == ""
+}
+
+// String returns the normalized plain notation of the decimal
+func (d Decimal) String() string {
+	if d.IsZero() {
+		return "0"
+	}
+
+	var sb strings.Builder
+
+	if d.neg {
+		sb.WriteString("-")
+	}
+
+	switch {
+	case d.exp <= 0:
+		sb.WriteString("0.")
+		sb.WriteString(strings.Repeat("0", -d.exp))
+		sb.WriteString(d.digits)
+	case d.exp >= len(d.digits):
+		sb.WriteString(d.digits)
+		sb.WriteString(strings.Repeat("0", d.exp-len(d.digits)))
+	default:
+		sb.WriteString(d.digits[:d.exp])
+		sb.WriteString(".")
+		sb.WriteString(d.digits[d.exp:])
+	}
+
+	return sb.String()
+}
+
+// Int64 returns the integer part of the decimal, it reports false when it does not fit in an int64
+func (d Decimal) Int64() (int64, bool) {
+	if d.exp <= 0 {
+		return 0, true
+	}
+
+	integer := d.digits
+	if d.exp < len(integer) {
+		integer = integer[:d.exp]
+	} else {
+		integer += strings.Repeat("0", d.exp-len(integer))
+	}
+
+	if d.neg {
+		integer = "-" + integer
+	}
+
+	n, err := strconv.ParseInt(integer, 10, 64)
+
+	return n, err == nil
+}
+
+// Cmp compares the decimals and returns -1, 0 or +1
+func (d Decimal) Cmp(o Decimal) int {
+	ds, os := d.sign(), o.sign()
+	if ds != os {
+		if ds < os {
+			return -1
+		}
+
+		return 1
+	}
+
+	return ds * d.cmpAbs(o)
+}
+
+func (d Decimal) sign() int {
+	switch {
+	case d.IsZero():
+		return 0
+	case d.neg:
+		return -1
+	}
+
+	return 1
+}
+
+func (d Decimal) cmpAbs(o Decimal) int {
+	if d.exp != o.exp {
+		if d.exp < o.exp {
+			return -1
+		}
+
+		return 1
+	}
+
+	// both digits have the same exponent and no trailing zeros, so they compare as strings
+	return strings.Compare(d.digits, o.digits)
+}
+
+// Add returns d + o, it fails when the result exceeds the DynamoDB precision or range
+func (d Decimal) Add(o Decimal) (Decimal, error) {
+	scale := min(d.scale(), o.scale())
+
+	sum := new(big.Int).Add(d.coefficient(scale), o.coefficient(scale))
+
+	return decimalFromCoefficient(sum, scale)
+}
+
+// Sub returns d - o, it fails when the result exceeds the DynamoDB precision or range
+func (d Decimal) Sub(o Decimal) (Decimal, error) {
+	o.neg = !o.neg
+
+	return d.Add(o)
+}
+
+// scale is the exponent of the last significant digit
+func (d Decimal) scale() int {
+	return d.exp - len(d.digits)
+}
+
+// coefficient returns the integer c such that d = c * 10^scale, scale must not exceed d.scale()
+func (d Decimal) coefficient(scale int) *big.Int {
+	c := new(big.Int)
+	if d.IsZero() {
+		return c
+	}
+
+	c.SetString(d.digits, 10)
+
+	shift := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.scale()-scale)), nil)
+	c.Mul(c, shift)
+
+	if d.neg {
+		c.Neg(c)
+	}
+
+	return c
+}
+
+func decimalFromCoefficient(c *big.Int, scale int) (Decimal, error) {
+	digits := c.String()
+	neg := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+
+	return Decimal{neg: neg, digits: digits, exp: scale + len(digits)}.normalize()
+}
@@ -0,0 +1,182 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr error
+	}{
+		{input: "0", want: "0"},
+		{input: "-0.000", want: "0"},
+		{input: "+10", want: "10"},
+		{input: "10.50", want: "10.5"},
+		{input: "0010", want: "10"},
+		{input: "1e3", want: "1000"},
+		{input: "1.5E-3", want: "0.0015"},
+		{input: "-123.456e2", want: "-12345.6"},
+		{input: ".5", want: "0.5"},
+		{input: "5.", want: "5"},
+		{input: "12345678901234567890123456789012345678", want: "12345678901234567890123456789012345678"},
+		{input: "12345678901234567890123456789012345678000", want: "12345678901234567890123456789012345678000"},
+		{input: "0.12345678901234567890123456789012345678", want: "0.12345678901234567890123456789012345678"},
+		{input: "1E-130", want: "0." + strings.Repeat("0", 129) + "1"},
+		{input: "9.9999999999999999999999999999999999999E+125", want: "99999999999999999999999999999999999999" + strings.Repeat("0", 88)},
+		{input: "123456789012345678901234567890123456789", wantErr: ErrNumberPrecision},
+		{input: "1.00000000000000000000000000000000000001", wantErr: ErrNumberPrecision},
+		{input: "1E+126", wantErr: ErrNumberOverflow},
+		{input: "-1E+126", wantErr: ErrNumberOverflow},
+		{input: "9.9E-131", wantErr: ErrNumberUnderflow},
+		{input: "", wantErr: ErrInvalidNumber},
+		{input: "abc", wantErr: ErrInvalidNumber},
+		{input: "1.2.3", wantErr: ErrInvalidNumber},
+		{input: ".", wantErr: ErrInvalidNumber},
+		{input: "1e", wantErr: ErrInvalidNumber},
+		{input: "NaN", wantErr: ErrInvalidNumber},
+		{input: " 1", wantErr: ErrInvalidNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := ParseDecimal(tt.input)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, d.String())
+		})
+	}
+}
+
+func mustParseDecimal(t *testing.T, s string) Decimal {
+	t.Helper()
+
+	d, err := ParseDecimal(s)
+	require.NoError(t, err)
+
+	return d
+}
+
+func TestDecimalEquality(t *testing.T) {
+	t.Parallel()
+
+	c := require.New(t)
+
+	c.Equal(mustParseDecimal(t, "10"), mustParseDecimal(t, "1E+1"))
+	c.Equal(mustParseDecimal(t, "0"), mustParseDecimal(t, "-0"))
+	c.Equal(NewDecimalFromInt(-42), mustParseDecimal(t, "-42.0"))
+
+	set := map[Decimal]bool{mustParseDecimal(t, "1.50"): true}
+	c.True(set[mustParseDecimal(t, "15e-1")])
+}
+
+func TestDecimalCmp(t *testing.T) {
+	t.Parallel()
+
+	ordered := []string{
+		"-1E+125", "-100", "-99.99", "-1", "-0.001", "0", "0.001", "1", "1.5", "9.99", "10", "12345678901234567890123456789012345678E+80",
+	}
+
+	for i, a := range ordered {
+		for j, b := range ordered {
+			want := 0
+
+			switch {
+			case i < j:
+				want = -1
+			case i > j:
+				want = 1
+			}
+
+			require.Equal(t, want, mustParseDecimal(t, a).Cmp(mustParseDecimal(t, b)), "%s cmp %s", a, b)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		left    string
+		right   string
+		sub     bool
+		want    string
+		wantErr error
+	}{
+		{name: "big counters", left: "9007199254740993", right: "1", want: "9007199254740994"},
+		{name: "money", left: "0.1", right: "0.2", want: "0.3"},
+		{name: "38 digits", left: "12345678901234567890123456789012345678", right: "1", want: "12345678901234567890123456789012345679"},
+		{name: "negative result", left: "1.5", right: "3", sub: true, want: "-1.5"},
+		{name: "cancel out", left: "-7.25", right: "7.25", want: "0"},
+		{name: "subtract negative", left: "1", right: "-1E-5", sub: true, want: "1.00001"},
+		{name: "zero operand", left: "0", right: "1E+125", want: "1" + strings.Repeat("0", 125)},
+		{name: "precision lost", left: "1E+20", right: "1E-20", wantErr: ErrNumberPrecision},
+		{name: "overflow", left: "9E+125", right: "9E+125", wantErr: ErrNumberOverflow},
+		{name: "underflow", left: "1.1E-130", right: "1E-130", sub: true, wantErr: ErrNumberUnderflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			left, right := mustParseDecimal(t, tt.left), mustParseDecimal(t, tt.right)
+
+			op := left.Add
+			if tt.sub {
+				op = left.Sub
+			}
+
+			got, err := op(right)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestDecimalInt64(t *testing.T) {
+	t.Parallel()
+
+	c := require.New(t)
+
+	for input, want := range map[string]int64{"0": 0, "0.9": 0, "3": 3, "3.7": 3, "-12.5": -12, "1E+3": 1000} {
+		got, ok := mustParseDecimal(t, input).Int64()
+		c.True(ok, input)
+		c.Equal(want, got, input)
+	}
+
+	_, ok := mustParseDecimal(t, "1E+20").Int64()
+	c.False(ok)
+}
+
+func TestValidateItemAttributeValue_numbers(t *testing.T) {
+	t.Parallel()
+
+	c := require.New(t)
+
+	c.NoError(ValidateItemAttributeValue(&Item{N: new("1E+125")}))
+	c.ErrorIs(ValidateItemAttributeValue(&Item{N: new("ten")}), ErrInvalidNumber)
+	c.ErrorIs(ValidateItemAttributeValue(&Item{NS: []*string{new("1"), new("1E+126")}}), ErrNumberOverflow)
+	c.ErrorIs(ValidateItemAttributeValue(&Item{M: map[string]*Item{
+		"amount": {L: []*Item{{N: new("0.123456789012345678901234567890123456789")}}},
+	}}), ErrNumberPrecision)
+}
@@ -1,7 +1,10 @@
 // Package types contains general types to use in core and interpreter
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Item describes the DynamoDB item structure
 type Item struct {
@@ -237,16 +240,17 @@ func (s *ConditionalCheckFailedException) RequestID() string {
 
 // PutItemInput represents the input of a PutItem operation.
 type PutItemInput struct {
-	_                           struct{}          `type:"structure"`
-	ConditionExpression         *string           `type:"string"`
-	ConditionalOperator         *string           `type:"string" enum:"ConditionalOperator"`
-	ExpressionAttributeNames    map[string]string `type:"map"`
-	ExpressionAttributeValues   map[string]*Item  `type:"map"`
-	Item                        map[string]*Item  `type:"map" required:"true"`
-	ReturnConsumedCapacity      *string           `type:"string" enum:"ReturnConsumedCapacity"`
-	ReturnItemCollectionMetrics *string           `type:"string" enum:"ReturnItemCollectionMetrics"`
-	ReturnValues                *string           `type:"string" enum:"ReturnValue"`
-	TableName                   *string           `min:"3" type:"string" required:"true"`
+	_                           struct{}                           `type:"structure"`
+	ConditionExpression         *string                            `type:"string"`
+	ConditionalOperator         *string                            `type:"string" enum:"ConditionalOperator"`
+	Expected                    map[string]*ExpectedAttributeValue `type:"map"`
+	ExpressionAttributeNames    map[string]string                  `type:"map"`
+	ExpressionAttributeValues   map[string]*Item                   `type:"map"`
+	Item                        map[string]*Item                   `type:"map" required:"true"`
+	ReturnConsumedCapacity      *string                            `type:"string" enum:"ReturnConsumedCapacity"`
+	ReturnItemCollectionMetrics *string                            `type:"string" enum:"ReturnItemCollectionMetrics"`
+	ReturnValues                *string                            `type:"string" enum:"ReturnValue"`
+	TableName                   *string                            `min:"3" type:"string" required:"true"`
 }
 
 // UpdateItemInput represents the input of an UpdateItem operation.
@@ -291,29 +295,90 @@ type ExpectedAttributeValue struct {
 	Value              *Item    `type:"structure"`
 }
 
+// Condition represents the selection criteria of a Query or Scan operation
+type Condition struct {
+	_                  struct{} `type:"structure"`
+	AttributeValueList []*Item  `type:"list"`
+	ComparisonOperator *string  `type:"string" required:"true" enum:"ComparisonOperator"`
+}
+
 // AttributeValueUpdate represents the attributes to be modified, the
 // action to perform on each, and the new value for each.
 type AttributeValueUpdate struct {
 	_      struct{} `type:"structure"`
 	Action *string  `type:"string" enum:"AttributeAction"`
-	Value  Item     `type:"structure"`
+	Value  *Item    `type:"structure"`
 }
 
 // TableDescription represents the properties of a table.
 type TableDescription struct {
-	_                      struct{}                          `type:"structure"`
-	GlobalSecondaryIndexes []GlobalSecondaryIndexDescription `type:"list"`
-	GlobalTableVersion     string                            `type:"string"`
-	ItemCount              int64                             `type:"long"`
-	KeySchema              []KeySchemaElement                `min:"1" type:"list"`
-	LatestStreamArn        string                            `min:"37" type:"string"`
-	LatestStreamLabel      string                            `type:"string"`
-	LocalSecondaryIndexes  []LocalSecondaryIndexDescription  `type:"list"`
-	TableArn               string                            `type:"string"`
-	TableID                string                            `type:"string"`
-	TableName              string                            `min:"3" type:"string"`
-	TableSizeBytes         int64                             `type:"long"`
-	TableStatus            string                            `type:"string" enum:"TableStatus"`
+	_                         struct{}                          `type:"structure"`
+	BillingModeSummary        *BillingModeSummary               `type:"structure"`
+	DeletionProtectionEnabled bool                              `type:"boolean"`
+	GlobalSecondaryIndexes    []GlobalSecondaryIndexDescription `type:"list"`
+	GlobalTableVersion        string                            `type:"string"`
+	ItemCount                 int64                             `type:"long"`
+	KeySchema                 []KeySchemaElement                `min:"1" type:"list"`
+	LatestStreamArn           string                            `min:"37" type:"string"`
+	LatestStreamLabel         string                            `type:"string"`
+	LocalSecondaryIndexes     []LocalSecondaryIndexDescription  `type:"list"`
+	OnDemandThroughput        *OnDemandThroughput               `type:"structure"`
+	ProvisionedThroughput     *ProvisionedThroughputDescription `type:"structure"`
+	SSEDescription            *SSEDescription                   `type:"structure"`
+	TableArn                  string                            `type:"string"`
+	TableClassSummary         *TableClassSummary                `type:"structure"`
+	TableID                   string                            `type:"string"`
+	TableName                 string                            `min:"3" type:"string"`
+	TableSizeBytes            int64                             `type:"long"`
+	TableStatus               string                            `type:"string" enum:"TableStatus"`
+	WarmThroughput            *WarmThroughputDescription        `type:"structure"`
+}
+
+// BillingModeSummary represents the billing mode of a table and when it last switched to on-demand
+type BillingModeSummary struct {
+	_                                 struct{}   `type:"structure"`
+	BillingMode                       string     `type:"string" enum:"BillingMode"`
+	LastUpdateToPayPerRequestDateTime *time.Time `type:"timestamp"`
+}
+
+// ProvisionedThroughputDescription represents the provisioned throughput of a table, zero for
+// on-demand tables
+type ProvisionedThroughputDescription struct {
+	_                      struct{} `type:"structure"`
+	NumberOfDecreasesToday int64    `type:"long"`
+	ReadCapacityUnits      int64    `type:"long"`
+	WriteCapacityUnits     int64    `type:"long"`
+}
+
+// OnDemandThroughput represents the maximum request units of an on-demand table, -1 meaning
+// no maximum
+type OnDemandThroughput struct {
+	_                    struct{} `type:"structure"`
+	MaxReadRequestUnits  int64    `type:"long"`
+	MaxWriteRequestUnits int64    `type:"long"`
+}
+
+// WarmThroughputDescription represents the throughput a table is ready to serve instantly
+type WarmThroughputDescription struct {
+	_                   struct{} `type:"structure"`
+	ReadUnitsPerSecond  int64    `type:"long"`
+	WriteUnitsPerSecond int64    `type:"long"`
+	Status              string   `type:"string" enum:"TableStatus"`
+}
+
+// SSEDescription represents the server-side encryption of a table that uses a KMS key
+type SSEDescription struct {
+	_               struct{} `type:"structure"`
+	KMSMasterKeyArn string   `type:"string"`
+	SSEType         string   `type:"string" enum:"SSEType"`
+	Status          string   `type:"string" enum:"SSEStatus"`
+}
+
+// TableClassSummary represents the table class of a table
+type TableClassSummary struct {
+	_                  struct{}   `type:"structure"`
+	LastUpdateDateTime *time.Time `type:"timestamp"`
+	TableClass         string     `type:"string" enum:"TableClass"`
 }
 
 // StringValue returns the string value of a string pointer
@@ -9,7 +9,8 @@ import (
 
 // ValidateItemAttributeValue walks an attribute value, including nested List (L) and Map (M)
 // entries, and returns an error when any String Set (SS), Number Set (NS), or Binary Set (BS)
-// contains duplicate members. The error message matches the DynamoDB ValidationException body:
+// contains duplicate members, or when a number (N or NS member) is not a valid DynamoDB number.
+// The error message matches the DynamoDB ValidationException body:
 // Callers typically wrap the result with NewError("ValidationException", msg, nil).
 //
 // Nil av is valid. NS duplicates are detected by exact wire string equality of each element
@@ -20,6 +21,10 @@ func ValidateItemAttributeValue(av *Item) error {
 		return nil
 	}
 
+	if err := validateItemNumbers(av); err != nil {
+		return err
+	}
+
 	if err := validateItemScalarSets(av); err != nil {
 		return err
 	}
@@ -27,6 +32,22 @@ func ValidateItemAttributeValue(av *Item) error {
 	return validateItemNested(av)
 }
 
+func validateItemNumbers(av *Item) error {
+	if av.N != nil {
+		if _, err := ParseDecimal(*av.N); err != nil {
+			return err
+		}
+	}
+
+	for _, n := range av.NS {
+		if _, err := ParseDecimal(StringValue(n)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func validateItemScalarSets(av *Item) error {
 	if err := validateStringSet(av.SS); err != nil {
 		return err